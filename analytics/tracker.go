@@ -73,6 +73,7 @@ const (
 	successfulValue                = "successful"
 	buildFailedValue               = "build_failed"
 	runIfValue                     = "run_if"
+	pluginValue                    = "plugin"
 	customTimeoutValue             = "timeout"
 	noOutputTimeoutValue           = "no_output_timeout"
 	ToolSnapshotEndOfWorkflowValue = "end_of_workflow"
@@ -309,7 +310,7 @@ func mapStepResultToEvent(result StepResult) (string, analytics.Properties, erro
 		eventName = stepFinishedEventName
 		extraProperties = analytics.Properties{statusProperty: successfulValue}
 		extraProperties.AppendIfNotEmpty(stepIDProperty, result.Info.StepID)
-	case models.StepRunStatusCodeFailed, models.StepRunStatusCodeFailedSkippable:
+	case models.StepRunStatusCodeFailed, models.StepRunStatusCodeFailedSkippable, models.StepRunStatusCodeServiceNotReady:
 		eventName = stepFinishedEventName
 		extraProperties = analytics.Properties{statusProperty: failedValue}
 		extraProperties.AppendIfNotEmpty(stepIDProperty, result.Info.StepID)
@@ -332,13 +333,16 @@ func mapStepResultToEvent(result StepResult) (string, analytics.Properties, erro
 		eventName = stepPreparationFailedEventName
 		extraProperties = prepareStartProperties(result.Info)
 		extraProperties.AppendIfNotEmpty(errorMessageProperty, result.ErrorMessage)
-	case models.StepRunStatusCodeSkipped, models.StepRunStatusCodeSkippedWithRunIf:
+	case models.StepRunStatusCodeSkipped, models.StepRunStatusCodeSkippedWithRunIf, models.StepRunStatusCodeSkippedByPlugin:
 		eventName = stepSkippedEventName
 		extraProperties = prepareStartProperties(result.Info)
 
-		if result.Status == models.StepRunStatusCodeSkipped {
+		switch result.Status {
+		case models.StepRunStatusCodeSkipped:
 			extraProperties[reasonProperty] = buildFailedValue
-		} else {
+		case models.StepRunStatusCodeSkippedByPlugin:
+			extraProperties[reasonProperty] = pluginValue
+		default:
 			extraProperties[reasonProperty] = runIfValue
 		}
 	default:
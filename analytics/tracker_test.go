@@ -41,6 +41,15 @@ func Test_mapStepResultToEvent(t *testing.T) {
 				"step_id":       "ID",
 			},
 		},
+		{
+			name: "Step service not ready",
+			result: StepResult{
+				Status:       models.StepRunStatusCodeServiceNotReady,
+				ErrorMessage: "msg",
+			},
+			expectedEvent:      "step_finished",
+			expectedExtraProps: analytics.Properties{"status": "failed", "error_message": "msg", "runtime": int64(0)},
+		},
 		{
 			name: "Step failed, skippable",
 			result: StepResult{
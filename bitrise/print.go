@@ -98,7 +98,7 @@ func getRunningStepFooterMainSection(stepRunResult models.StepRunResultsModel) s
 	case models.StepRunStatusCodeSuccess:
 		icon = "✓"
 		coloringFunc = colorstring.Green
-	case models.StepRunStatusCodeFailed, models.StepRunStatusCodePreparationFailed:
+	case models.StepRunStatusCodeFailed, models.StepRunStatusCodePreparationFailed, models.StepRunStatusCodeServiceNotReady:
 		icon = "x"
 		coloringFunc = colorstring.Red
 	case models.StepRunStatusAbortedWithCustomTimeout, models.StepRunStatusAbortedWithNoOutputTimeout:
@@ -107,7 +107,7 @@ func getRunningStepFooterMainSection(stepRunResult models.StepRunResultsModel) s
 	case models.StepRunStatusCodeFailedSkippable:
 		icon = "!"
 		coloringFunc = colorstring.Yellow
-	case models.StepRunStatusCodeSkipped, models.StepRunStatusCodeSkippedWithRunIf:
+	case models.StepRunStatusCodeSkipped, models.StepRunStatusCodeSkippedWithRunIf, models.StepRunStatusCodeSkippedByPlugin:
 		icon = "-"
 		coloringFunc = colorstring.Blue
 	default:
@@ -144,6 +144,37 @@ func getRunningStepFooterMainSection(stepRunResult models.StepRunResultsModel) s
 	return fmt.Sprintf("|%s|%s|%s|", iconBox, titleBox, timeBox)
 }
 
+func getContainerResultRow(result models.ContainerRunResultModel) string {
+	iconBoxWidth := len("   ")
+	timeBoxWidth := len(" time (s) ")
+	titleBoxWidth := stepRunSummaryBoxWidthInChars - 4 - iconBoxWidth - timeBoxWidth - 1
+
+	icon := "✓"
+	coloringFunc := colorstring.Green
+	if result.IsFailed() {
+		icon = "x"
+		coloringFunc = colorstring.Red
+	}
+
+	var state string
+	switch {
+	case result.OOMKilled:
+		state = fmt.Sprintf("OOM killed, exit code: %d", result.ExitCode)
+	case result.Running:
+		state = "running"
+	default:
+		state = fmt.Sprintf("exit code: %d", result.ExitCode)
+	}
+
+	title := trimTitle(fmt.Sprintf("%s container: %s", result.Type, result.ID), fmt.Sprintf("(%s)", state), titleBoxWidth)
+
+	iconBox := fmt.Sprintf(" %s ", coloringFunc(icon))
+	titleBox := fmt.Sprintf(" %s%s", coloringFunc(title), strings.Repeat(" ", titleBoxWidth-len(title)))
+	timeBox := strings.Repeat(" ", timeBoxWidth)
+
+	return fmt.Sprintf("|%s|%s|%s|", iconBox, titleBox, timeBox)
+}
+
 func getDeprecateNotesRows(notes string) string {
 	colorDeprecateNote := func(line string) string {
 		if strings.HasPrefix(line, "Removal notes:") {
@@ -473,6 +504,10 @@ func PrintSummary(buildRunResults models.BuildRunResultsModel) {
 			}
 		}
 	}
+	for _, containerResult := range buildRunResults.ContainerResults {
+		log.Print(getContainerResultRow(containerResult))
+		log.Printf("+%s+%s+%s+", strings.Repeat("-", iconBoxWidth), strings.Repeat("-", titleBoxWidth), strings.Repeat("-", timeBoxWidth))
+	}
 	runtime := tmpTime.Sub(time.Time{})
 
 	runTimeStr, err := utils.FormattedSecondsToMax8Chars(runtime)
@@ -142,6 +142,35 @@ func Test_getRunningStepFooterMainSection(t *testing.T) {
 	}
 }
 
+func Test_getContainerResultRow(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   models.ContainerRunResultModel
+		expected string
+	}{
+		{
+			name:     "running service container",
+			result:   models.ContainerRunResultModel{ID: "postgres", Type: models.ContainerTypeService, Running: true},
+			expected: "| \x1b[32;1m✓\x1b[0m | \x1b[32;1mservice container: postgres (running)\x1b[0m                         |          |",
+		},
+		{
+			name:     "failed execution container",
+			result:   models.ContainerRunResultModel{ID: "ruby", Type: models.ContainerTypeExecution, ExitCode: 2},
+			expected: "| \x1b[31;1mx\x1b[0m | \x1b[31;1mexecution container: ruby (exit code: 2)\x1b[0m                      |          |",
+		},
+		{
+			name:     "OOM killed container",
+			result:   models.ContainerRunResultModel{ID: "redis", Type: models.ContainerTypeService, ExitCode: 137, OOMKilled: true},
+			expected: "| \x1b[31;1mx\x1b[0m | \x1b[31;1mservice container: redis (OOM killed, exit code: 137)\x1b[0m         |          |",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, getContainerResultRow(tt.result))
+		})
+	}
+}
+
 func TestGetDeprecateNotesRows(t *testing.T) {
 	notes := "Removal notes: " + longStr
 	actual := getDeprecateNotesRows(notes)
@@ -384,6 +413,9 @@ func TestPrintSummary(t *testing.T) {
 		StartTime:      time.Now(),
 		StepmanUpdates: map[string]int{},
 		SuccessSteps:   []models.StepRunResultsModel{result1, result2},
+		ContainerResults: []models.ContainerRunResultModel{
+			{ID: "postgres", Type: models.ContainerTypeService, Running: true},
+		},
 	}
 
 	PrintSummary(buildResults)
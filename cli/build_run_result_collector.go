@@ -41,7 +41,7 @@ func (r buildRunResultCollector) registerStepRunResults(
 	isLastStep bool,
 	printStepHeader bool,
 	redactedStepInputs map[string]string,
-	properties coreanalytics.Properties) {
+	properties coreanalytics.Properties) *models.StepRunResultsModel {
 
 	stepRuntime := time.Since(stepStartTime)
 
@@ -119,7 +119,7 @@ func (r buildRunResultCollector) registerStepRunResults(
 	switch status {
 	case models.StepRunStatusCodeSuccess:
 		buildRunResults.SuccessSteps = append(buildRunResults.SuccessSteps, stepResults)
-	case models.StepRunStatusCodePreparationFailed:
+	case models.StepRunStatusCodePreparationFailed, models.StepRunStatusCodeServiceNotReady:
 		buildRunResults.FailedSteps = append(buildRunResults.FailedSteps, stepResults)
 	case models.StepRunStatusCodeFailed:
 		buildRunResults.FailedSteps = append(buildRunResults.FailedSteps, stepResults)
@@ -129,13 +129,15 @@ func (r buildRunResultCollector) registerStepRunResults(
 		buildRunResults.FailedSteps = append(buildRunResults.FailedSteps, stepResults)
 	case models.StepRunStatusCodeSkipped:
 		buildRunResults.SkippedSteps = append(buildRunResults.SkippedSteps, stepResults)
-	case models.StepRunStatusCodeSkippedWithRunIf:
+	case models.StepRunStatusCodeSkippedWithRunIf, models.StepRunStatusCodeSkippedByPlugin:
 		buildRunResults.SkippedSteps = append(buildRunResults.SkippedSteps, stepResults)
 	default:
-		return
+		return nil
 	}
 
 	logStepFinished(stepResults, stepExecutionId, isLastStep)
+
+	return &stepResults
 }
 
 func logStepFinished(stepResults models.StepRunResultsModel, stepExecutionID string, isLastStep bool) {
@@ -74,6 +74,11 @@ func newRootCommand() *cobra.Command {
 		pluginCommand,
 		envmanCommand,
 		mergeConfigCommand,
+		lspCommand,
+		schemaCommand,
+		migrateCommand,
+		fmtCommand,
+		lintCommand,
 	)
 
 	// Register the help command eagerly so it shows up in the command list
@@ -0,0 +1,163 @@
+package containermanager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/bitrise-io/bitrise/v2/cli/docker"
+	"github.com/bitrise-io/bitrise/v2/configs"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/log/logwriter"
+	"github.com/bitrise-io/bitrise/v2/models"
+)
+
+// LogsDirName is the directory under BITRISE_DEPLOY_DIR the container logs are written to.
+const LogsDirName = "container_logs"
+
+type startedContainer struct {
+	id            string
+	containerType models.ContainerType
+	image         string
+
+	logPath    string
+	logFile    *os.File
+	logOutput  io.WriteCloser
+	logStreams []io.Closer
+	waitLogs   func() error
+}
+
+// EnableLogStreaming makes the manager stream the container logs into the build log too,
+// with a producer of their own, besides writing them to the log files.
+func (m *Manager) EnableLogStreaming() {
+	m.streamLogs = true
+}
+
+// ContainerResults returns the state of the removed containers in the order they were removed.
+func (m *Manager) ContainerResults() []models.ContainerRunResultModel {
+	// DestroyAllContainers can record results from the signal handler goroutine
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.containerResults)
+}
+
+func (m *Manager) trackContainer(containerID string, t docker.ContainerType, containerDef models.Container, runningContainer *docker.RunningContainer) {
+	started := &startedContainer{
+		id:            containerID,
+		containerType: models.ContainerType(t),
+		image:         containerDef.Image,
+	}
+	m.startedContainers[runningContainer] = started
+
+	if err := m.startLogCollection(started, runningContainer); err != nil {
+		m.logger.Warnf("Failed to collect the logs of container (%s): %s", runningContainer.Name, err)
+	}
+}
+
+func (m *Manager) startLogCollection(started *startedContainer, runningContainer *docker.RunningContainer) error {
+	var outputs []io.Writer
+
+	if deployDir := os.Getenv(configs.BitriseDeployDirEnvKey); deployDir != "" {
+		logsDir := filepath.Join(deployDir, LogsDirName)
+		if err := os.MkdirAll(logsDir, 0755); err != nil {
+			return fmt.Errorf("create logs dir: %w", err)
+		}
+
+		// Appended, the container might have been started before (recreated or restarted for a later step)
+		logPath := filepath.Join(logsDir, runningContainer.Name+".log")
+		logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("open log file: %w", err)
+		}
+		started.logPath = logPath
+		started.logFile = logFile
+		outputs = append(outputs, logFile)
+	}
+
+	if m.streamLogs {
+		opts := log.GetGlobalLoggerOpts()
+		opts.Producer = log.Container
+		opts.ProducerID = runningContainer.Name
+		logWriter := logwriter.NewLogWriter(log.NewLogger(opts))
+		started.logStreams = append(started.logStreams, logWriter)
+		outputs = append(outputs, logWriter)
+	}
+
+	if len(outputs) == 0 {
+		return nil
+	}
+
+	started.logOutput = m.logger.RedactWriter(io.MultiWriter(outputs...))
+	waitLogs, err := m.runtime.FollowLogs(runningContainer.Name, started.logOutput)
+	if err != nil {
+		m.finishLogCollection(started)
+		return err
+	}
+	started.waitLogs = waitLogs
+	return nil
+}
+
+// destroyContainer records the state of the container, removes it and finishes the collection of its logs.
+func (m *Manager) destroyContainer(runningContainer *docker.RunningContainer) error {
+	started := m.startedContainers[runningContainer]
+	if started != nil {
+		m.recordContainerResult(started, runningContainer)
+	}
+
+	err := runningContainer.Destroy()
+
+	if started != nil {
+		if started.waitLogs != nil {
+			if err := started.waitLogs(); err != nil {
+				m.logger.Debugf("Following the logs of container (%s) finished with: %s", runningContainer.Name, err)
+			}
+		}
+		m.finishLogCollection(started)
+		delete(m.startedContainers, runningContainer)
+	}
+
+	return err
+}
+
+func (m *Manager) recordContainerResult(started *startedContainer, runningContainer *docker.RunningContainer) {
+	state, err := m.runtime.ContainerState(runningContainer.Name)
+	if err != nil {
+		m.logger.Warnf("Failed to get the state of container (%s): %s", runningContainer.Name, err)
+		return
+	}
+
+	result := models.ContainerRunResultModel{
+		ID:        started.id,
+		Type:      started.containerType,
+		Image:     started.image,
+		Running:   state.Running,
+		ExitCode:  state.ExitCode,
+		OOMKilled: state.OOMKilled,
+		LogPath:   started.logPath,
+	}
+	if result.IsFailed() {
+		m.logger.Errorf("❌ Container (%s) exited with %d (OOM killed: %t)", runningContainer.Name, state.ExitCode, state.OOMKilled)
+	}
+	m.containerResults = append(m.containerResults, result)
+}
+
+func (m *Manager) finishLogCollection(started *startedContainer) {
+	if started.logOutput != nil {
+		if err := started.logOutput.Close(); err != nil {
+			m.logger.Debugf("Failed to flush container logs: %s", err)
+		}
+	}
+	for _, stream := range started.logStreams {
+		if err := stream.Close(); err != nil {
+			m.logger.Debugf("Failed to flush container logs: %s", err)
+		}
+	}
+	if started.logFile != nil {
+		if err := started.logFile.Close(); err != nil {
+			m.logger.Debugf("Failed to close container log file: %s", err)
+		}
+	}
+}
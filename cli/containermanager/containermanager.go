@@ -16,8 +16,8 @@ type Manager struct {
 	executionContainerDefinitions map[string]models.Container
 	serviceContainerDefinitions   map[string]models.Container
 
-	dockerManager *docker.ContainerManager
-	logger        *docker.Logger
+	runtime docker.Runtime
+	logger  *docker.Logger
 
 	workflowRunPlan        models.WorkflowRunPlan
 	legacyContainerisation bool
@@ -32,15 +32,26 @@ type Manager struct {
 	runningExecutionContainer map[string]*docker.RunningContainer
 	runningServiceContainers  map[string]*docker.RunningContainer
 
+	// Keeps track of the service containers which did not pass their readiness probe, by the service ID.
+	notReadyServices map[string]error
+
+	// Keeps track of the images built for the execution containers in this run, by the container ID.
+	builtImages map[string]string
+
+	// Keeps track of the started containers to collect their logs and their state when they are removed.
+	startedContainers map[*docker.RunningContainer]*startedContainer
+	containerResults  []models.ContainerRunResultModel
+	streamLogs        bool
+
 	mu       sync.Mutex
 	released bool
 }
 
-func NewManager(executionContainers map[string]models.Container, serviceContainers map[string]models.Container, dockerManager *docker.ContainerManager, logger *docker.Logger) *Manager {
+func NewManager(executionContainers map[string]models.Container, serviceContainers map[string]models.Container, runtime docker.Runtime, logger *docker.Logger) *Manager {
 	return &Manager{
 		executionContainerDefinitions: executionContainers,
 		serviceContainerDefinitions:   serviceContainers,
-		dockerManager:                 dockerManager,
+		runtime:                       runtime,
 		logger:                        logger,
 		// Legacy containerisation mode
 		currentWithGroupID:  "",
@@ -49,6 +60,9 @@ func NewManager(executionContainers map[string]models.Container, serviceContaine
 		// New containerisation mode
 		runningExecutionContainer: make(map[string]*docker.RunningContainer),
 		runningServiceContainers:  make(map[string]*docker.RunningContainer),
+		notReadyServices:          make(map[string]error),
+		builtImages:               make(map[string]string),
+		startedContainers:         make(map[*docker.RunningContainer]*startedContainer),
 	}
 }
 
@@ -66,7 +80,9 @@ func (m *Manager) SetWorkflowRunPlan(plan models.WorkflowRunPlan) {
 	}
 }
 
-func (m *Manager) UpdateWithStepStarted(stepPlan models.StepExecutionPlan, environments []envmanModels.EnvironmentItemModel) {
+// UpdateWithStepStarted starts the containers required by the step,
+// it returns an error if a service container required by the step is not ready.
+func (m *Manager) UpdateWithStepStarted(stepPlan models.StepExecutionPlan, environments []envmanModels.EnvironmentItemModel) error {
 	if m.legacyContainerisation {
 		if stepPlan.WithGroupUUID != m.currentWithGroupID {
 			if stepPlan.WithGroupUUID != "" {
@@ -77,7 +93,10 @@ func (m *Manager) UpdateWithStepStarted(stepPlan models.StepExecutionPlan, envir
 
 			m.currentWithGroupID = stepPlan.WithGroupUUID
 		}
-		return
+		if stepPlan.WithGroupUUID == "" {
+			return nil
+		}
+		return m.checkServicesReady(stepPlan.ServiceIDs)
 	}
 
 	// In the new containerisation mode, containers are not tied to a step group,
@@ -105,6 +124,21 @@ func (m *Manager) UpdateWithStepStarted(stepPlan models.StepExecutionPlan, envir
 
 	m.debugLogRunningContainers(stepPlan)
 	m.startContainers(executionContainerToStart, serviceContainersToStart, environments)
+
+	var serviceIDs []string
+	for _, serviceContainerConfig := range stepPlan.ServiceContainers {
+		serviceIDs = append(serviceIDs, serviceContainerConfig.ContainerID)
+	}
+	return m.checkServicesReady(serviceIDs)
+}
+
+func (m *Manager) checkServicesReady(serviceIDs []string) error {
+	for _, serviceID := range serviceIDs {
+		if err, notReady := m.notReadyServices[serviceID]; notReady {
+			return err
+		}
+	}
+	return nil
 }
 
 func (m *Manager) UpdateWithStepFinished(stepIDX int, plan models.WorkflowExecutionPlan, stepPlan models.StepExecutionPlan) {
@@ -130,7 +164,7 @@ func (m *Manager) UpdateWithStepFinished(stepIDX int, plan models.WorkflowExecut
 			if m.shouldStopExecutionContainer(containerID, stepPlan) {
 				if container := m.runningExecutionContainer[containerID]; container != nil {
 					m.logger.Infof("ℹ️ Removing execution container: %s", containerID)
-					if err := container.Destroy(); err != nil {
+					if err := m.destroyContainer(container); err != nil {
 						m.logger.Errorf("Attempted to stop execution container: %s", err)
 					}
 					delete(m.runningExecutionContainer, containerID)
@@ -145,10 +179,11 @@ func (m *Manager) UpdateWithStepFinished(stepIDX int, plan models.WorkflowExecut
 			if m.shouldStopServiceContainer(containerID, stepPlan) {
 				if container := m.runningServiceContainers[containerID]; container != nil {
 					m.logger.Infof("ℹ️ Removing service container: %s", container.Name)
-					if err := container.Destroy(); err != nil {
+					if err := m.destroyContainer(container); err != nil {
 						m.logger.Errorf("Attempted to stop service container: %s", err)
 					}
 					delete(m.runningServiceContainers, containerID)
+					delete(m.notReadyServices, containerID)
 				}
 			}
 		}
@@ -167,29 +202,29 @@ func (m *Manager) GetExecutionContainerForStep(UUID string) (*models.Container,
 			return nil, nil
 		}
 
-		containerDefinition, ok := m.executionContainerDefinitions[stepPlan.ContainerID]
-		if !ok {
+		containerDefinition := m.getExecutionContainerDefinition(stepPlan.ContainerID)
+		if containerDefinition == nil {
 			// This should not happen, but in case it does, we return nil to avoid breaking the execution.
 			return nil, nil
 		}
 		runningContainer := m.executionContainers[stepPlan.WithGroupUUID]
 
-		return &containerDefinition, runningContainer
+		return containerDefinition, runningContainer
 	}
 
 	if stepPlan.ExecutionContainer == nil || stepPlan.ExecutionContainer.ContainerID == "" {
 		return nil, nil
 	}
 
-	containerDefinition, ok := m.executionContainerDefinitions[stepPlan.ExecutionContainer.ContainerID]
-	if !ok {
+	containerDefinition := m.getExecutionContainerDefinition(stepPlan.ExecutionContainer.ContainerID)
+	if containerDefinition == nil {
 		// This should not happen, but in case it does, we return nil to avoid breaking the execution.
 		return nil, nil
 	}
 
 	runningContainer := m.runningExecutionContainer[stepPlan.ExecutionContainer.ContainerID]
 
-	return &containerDefinition, runningContainer
+	return containerDefinition, runningContainer
 }
 
 func (m *Manager) DestroyAllContainers() error {
@@ -202,7 +237,7 @@ func (m *Manager) DestroyAllContainers() error {
 			continue
 		}
 		m.logger.Infof("ℹ️ Removing execution container: %s", executionContainer.Name)
-		if err := executionContainer.Destroy(); err != nil {
+		if err := m.destroyContainer(executionContainer); err != nil {
 			return fmt.Errorf("destroy execution container: %w", err)
 		}
 	}
@@ -213,7 +248,7 @@ func (m *Manager) DestroyAllContainers() error {
 				continue
 			}
 			m.logger.Infof("Removing service container: %s", serviceContainer.Name)
-			if err := serviceContainer.Destroy(); err != nil {
+			if err := m.destroyContainer(serviceContainer); err != nil {
 				return fmt.Errorf("destroy service container: %w", err)
 			}
 		}
@@ -224,7 +259,7 @@ func (m *Manager) DestroyAllContainers() error {
 			continue
 		}
 		m.logger.Infof("ℹ️ Removing execution container: %s", executionContainer.Name)
-		if err := executionContainer.Destroy(); err != nil {
+		if err := m.destroyContainer(executionContainer); err != nil {
 			return fmt.Errorf("destroy execution container: %w", err)
 		}
 	}
@@ -234,7 +269,7 @@ func (m *Manager) DestroyAllContainers() error {
 			continue
 		}
 		m.logger.Infof("Removing service container: %s", serviceContainer.Name)
-		if err := serviceContainer.Destroy(); err != nil {
+		if err := m.destroyContainer(serviceContainer); err != nil {
 			return fmt.Errorf("destroy service container: %w", err)
 		}
 	}
@@ -250,13 +285,15 @@ func (m *Manager) startContainersForStepGroup(containerID string, serviceIDs []s
 	envList := m.initEnvs(environments)
 
 	if containerID != "" {
-		containerDef := m.getExecutionContainerDefinition(containerID)
-		if containerDef != nil {
-			m.logger.Infof("ℹ️ Running step group in docker container: %s", containerDef.Image)
+		containerDef, err := m.buildExecutionContainerImage(containerID, envList)
+		if err != nil {
+			m.logger.Errorf("Could not build the image of the container (%s): %s", containerID, err)
+		} else if containerDef != nil {
+			m.logger.Infof("ℹ️ Running step group in %s container: %s", m.runtime.Name(), containerDef.Image)
 
-			_, err := m.startExecutionContainerForStepGroup(*containerDef, groupID, envList)
+			_, err := m.startExecutionContainerForStepGroup(*containerDef, containerID, groupID, envList)
 			if err != nil {
-				m.logger.Errorf("Could not start the specified docker image: %s", containerDef.Image)
+				m.logger.Errorf("Could not start the specified container image: %s", containerDef.Image)
 			}
 		}
 	}
@@ -278,13 +315,15 @@ func (m *Manager) startContainers(containerID string, serviceIDs []string, envir
 	envList := m.initEnvs(environments)
 
 	if containerID != "" {
-		containerDef := m.getExecutionContainerDefinition(containerID)
-		if containerDef != nil {
-			m.logger.Infof("ℹ️ Running step in docker container: %s", containerDef.Image)
+		containerDef, err := m.buildExecutionContainerImage(containerID, envList)
+		if err != nil {
+			m.logger.Errorf("Could not build the image of the container (%s): %s", containerID, err)
+		} else if containerDef != nil {
+			m.logger.Infof("ℹ️ Running step in %s container: %s", m.runtime.Name(), containerDef.Image)
 
 			_, err := m.startExecutionContainer(*containerDef, containerID, envList)
 			if err != nil {
-				m.logger.Errorf("Could not start the specified docker image: %s", containerDef.Image)
+				m.logger.Errorf("Could not start the specified container image: %s", containerDef.Image)
 			}
 		}
 	}
@@ -298,11 +337,11 @@ func (m *Manager) startContainers(containerID string, serviceIDs []string, envir
 	}
 }
 
-func (m *Manager) startExecutionContainerForStepGroup(container models.Container, groupID string, envs map[string]string) (*docker.RunningContainer, error) {
+func (m *Manager) startExecutionContainerForStepGroup(container models.Container, containerID, groupID string, envs map[string]string) (*docker.RunningContainer, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	runningContainer, err := m.loginAndRunContainer(docker.ExecutionContainerType, container, fmt.Sprintf("bitrise-workflow-%s", groupID), envs)
+	runningContainer, err := m.loginAndRunContainer(docker.ExecutionContainerType, containerID, container, fmt.Sprintf("bitrise-workflow-%s", groupID), envs)
 	// Even on failure we save the reference to make sure containers will be cleaned up
 	if runningContainer != nil {
 		m.executionContainers[groupID] = runningContainer
@@ -314,7 +353,7 @@ func (m *Manager) startExecutionContainer(container models.Container, containerI
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	runningContainer, err := m.loginAndRunContainer(docker.ExecutionContainerType, container, containerID, envs)
+	runningContainer, err := m.loginAndRunContainer(docker.ExecutionContainerType, containerID, container, containerID, envs)
 	// Even on failure we save the reference to make sure containers will be cleaned up
 	if runningContainer != nil {
 		m.runningExecutionContainer[containerID] = runningContainer
@@ -332,10 +371,13 @@ func (m *Manager) startServiceContainersForStepGroup(containers map[string]model
 	for containerID := range containers {
 		serviceContainer := containers[containerID]
 
-		runningContainer, err := m.loginAndRunContainer(docker.ServiceContainerType, serviceContainer, containerID, envs)
+		runningContainer, err := m.loginAndRunContainer(docker.ServiceContainerType, containerID, serviceContainer, containerID, envs)
 		if runningContainer != nil {
 			runningContainers = append(runningContainers, runningContainer)
 		}
+		if err == nil {
+			err = m.waitForReadiness(containerID, serviceContainer, runningContainer)
+		}
 		if err != nil {
 			failedServices[containerID] = err
 		}
@@ -366,12 +408,15 @@ func (m *Manager) startServiceContainers(containers map[string]models.Container,
 	for _, containerID := range containerIDs {
 		serviceContainer := containers[containerID]
 
-		runningContainer, err := m.loginAndRunContainer(docker.ServiceContainerType, serviceContainer, containerID, envs)
+		runningContainer, err := m.loginAndRunContainer(docker.ServiceContainerType, containerID, serviceContainer, containerID, envs)
 		if runningContainer != nil {
 			runningContainers = append(runningContainers, runningContainer)
 			// Even on failure we save the references to make sure containers will be cleaned up
 			m.runningServiceContainers[containerID] = runningContainer
 		}
+		if err == nil {
+			err = m.waitForReadiness(containerID, serviceContainer, runningContainer)
+		}
 		if err != nil {
 			failedServices[containerID] = err
 		}
@@ -388,29 +433,48 @@ func (m *Manager) startServiceContainers(containers map[string]models.Container,
 	return runningContainers, nil
 }
 
-func (m *Manager) loginAndRunContainer(t docker.ContainerType, containerDef models.Container, containerName string, envs map[string]string) (*docker.RunningContainer, error) {
+func (m *Manager) loginAndRunContainer(t docker.ContainerType, containerID string, containerDef models.Container, containerName string, envs map[string]string) (*docker.RunningContainer, error) {
 	if m.released {
 		return nil, fmt.Errorf("container manager was released already")
 	}
 
-	return m.dockerManager.LoginAndRunContainer(t, containerDef, containerName, envs)
+	runningContainer, err := m.runtime.LoginAndRunContainer(t, containerDef, containerName, envs)
+	if runningContainer != nil {
+		m.trackContainer(containerID, t, containerDef, runningContainer)
+	}
+	return runningContainer, err
+}
+
+// waitForReadiness runs the readiness probe of the service container if it has one,
+// a failing probe marks the service as not ready, so the steps using it are not run.
+func (m *Manager) waitForReadiness(serviceID string, serviceContainer models.Container, runningContainer *docker.RunningContainer) error {
+	if serviceContainer.Readiness == nil || runningContainer == nil {
+		return nil
+	}
+
+	if err := docker.WaitForReadiness(m.runtime, runningContainer, *serviceContainer.Readiness, m.logger); err != nil {
+		m.notReadyServices[serviceID] = err
+		return err
+	}
+	return nil
 }
 
 func (m *Manager) stopContainersForStepGroup(groupID string) {
 	if container := m.getExecutionContainerForStepGroup(groupID); container != nil {
 		// TODO: Feature idea, make this configurable, so that we can keep the container for debugging purposes.
 		m.logger.Infof("ℹ️ Removing execution container: %s", container.Name)
-		if err := container.Destroy(); err != nil {
-			m.logger.Errorf("Attempted to stop the docker container for step group: %s", err)
+		if err := m.destroyContainer(container); err != nil {
+			m.logger.Errorf("Attempted to stop the container for step group: %s", err)
 		}
 	}
 
 	if services := m.getServiceContainersForStepGroup(groupID); services != nil {
 		for _, container := range services {
 			m.logger.Infof("ℹ️ Removing service container: %s", container.Name)
-			if err := container.Destroy(); err != nil {
-				m.logger.Errorf("Attempted to stop the docker container for service: %s: %s", container.Name, err)
+			if err := m.destroyContainer(container); err != nil {
+				m.logger.Errorf("Attempted to stop the container for service: %s: %s", container.Name, err)
 			}
+			delete(m.notReadyServices, container.Name)
 		}
 	}
 }
@@ -544,14 +608,42 @@ func (m *Manager) initEnvs(environments []envmanModels.EnvironmentItemModel) env
 	return envList
 }
 
+// getExecutionContainerDefinition returns the definition of an execution container,
+// the image of a container with a build is the image built in this run.
 func (m *Manager) getExecutionContainerDefinition(id string) *models.Container {
 	container, ok := m.executionContainerDefinitions[id]
 	if ok {
+		if tag, built := m.builtImages[id]; built {
+			container.Image = tag
+		}
 		return &container
 	}
 	return nil
 }
 
+// buildExecutionContainerImage builds the image of an execution container with a build once per run,
+// and returns the container definition with the built image.
+func (m *Manager) buildExecutionContainerImage(id string, envs map[string]string) (*models.Container, error) {
+	container := m.getExecutionContainerDefinition(id)
+	if container == nil || container.Build == nil || container.Image != "" {
+		return container, nil
+	}
+
+	tag, err := docker.ImageBuildTag(id, *container)
+	if err != nil {
+		return nil, err
+	}
+
+	m.logger.Infof("ℹ️ Building image for container (%s): %s", id, tag)
+	if err := m.runtime.BuildImage(*container, tag, envs); err != nil {
+		return nil, err
+	}
+
+	m.builtImages[id] = tag
+	container.Image = tag
+	return container, nil
+}
+
 func (m *Manager) getServiceContainerDefinitions(ids ...string) map[string]models.Container {
 	services := map[string]models.Container{}
 	for _, id := range ids {
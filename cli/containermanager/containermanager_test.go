@@ -0,0 +1,214 @@
+package containermanager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/cli/docker"
+	"github.com/bitrise-io/bitrise/v2/cli/docker/dockertest"
+	"github.com/bitrise-io/bitrise/v2/configs"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_startsAndRemovesContainersWithRuntime(t *testing.T) {
+	configs.InputEnvstorePath = filepath.Join(t.TempDir(), "envstore.yml")
+
+	steps := []models.StepExecutionPlan{
+		{UUID: "step-1", ExecutionContainer: &models.ContainerConfig{ContainerID: "ubuntu"}, ServiceContainers: []models.ContainerConfig{{ContainerID: "redis"}}},
+		{UUID: "step-2", ExecutionContainer: &models.ContainerConfig{ContainerID: "ubuntu"}},
+		{UUID: "step-3"},
+	}
+	runtime := dockertest.NewFakeRuntime()
+	manager := newTestManager(runtime, steps)
+
+	require.NoError(t, manager.UpdateWithStepStarted(steps[0], nil))
+	require.Equal(t, []string{"ubuntu", "redis"}, runtime.Started())
+
+	definition, runningContainer := manager.GetExecutionContainerForStep("step-1")
+	require.NotNil(t, definition)
+	require.Equal(t, "ubuntu:24.04", definition.Image)
+	require.Equal(t, "ubuntu", runningContainer.Name)
+
+	name, args := runningContainer.ExecuteCommand([]string{"A=b"})
+	require.Equal(t, "env", name)
+	require.Equal(t, []string{"A=b"}, args)
+
+	manager.UpdateWithStepFinished(0, manager.workflowRunPlan.ExecutionPlan[0], steps[0])
+	require.Equal(t, []string{"redis"}, runtime.Removed())
+
+	require.NoError(t, manager.UpdateWithStepStarted(steps[1], nil))
+	require.Equal(t, []string{"ubuntu", "redis"}, runtime.Started(), "the execution container should be reused")
+
+	manager.UpdateWithStepFinished(1, manager.workflowRunPlan.ExecutionPlan[0], steps[1])
+	require.Equal(t, []string{"redis", "ubuntu"}, runtime.Removed())
+
+	require.NoError(t, manager.DestroyAllContainers())
+	require.Equal(t, []string{"redis", "ubuntu"}, runtime.Removed())
+}
+
+func TestManager_runtimeFailure(t *testing.T) {
+	configs.InputEnvstorePath = filepath.Join(t.TempDir(), "envstore.yml")
+
+	steps := []models.StepExecutionPlan{
+		{UUID: "step-1", ExecutionContainer: &models.ContainerConfig{ContainerID: "ubuntu"}},
+	}
+	runtime := dockertest.NewFakeRuntime()
+	runtime.RunErr = errors.New("podman is not installed")
+	manager := newTestManager(runtime, steps)
+
+	require.NoError(t, manager.UpdateWithStepStarted(steps[0], nil))
+
+	_, runningContainer := manager.GetExecutionContainerForStep("step-1")
+	require.Nil(t, runningContainer)
+	require.Empty(t, runtime.Started())
+}
+
+func TestManager_serviceNotReady(t *testing.T) {
+	configs.InputEnvstorePath = filepath.Join(t.TempDir(), "envstore.yml")
+
+	steps := []models.StepExecutionPlan{
+		{UUID: "step-1", ServiceContainers: []models.ContainerConfig{{ContainerID: "postgres"}}},
+		{UUID: "step-2", ServiceContainers: []models.ContainerConfig{{ContainerID: "redis"}}},
+	}
+	containers := map[string]models.Container{
+		"postgres": {Type: models.ContainerTypeService, Image: "postgres:16", Readiness: &models.ReadinessProbe{Command: "exit 1", Retries: 1}},
+		"redis":    {Type: models.ContainerTypeService, Image: "redis:7", Readiness: &models.ReadinessProbe{Command: "exit 0"}},
+	}
+	runtime := dockertest.NewFakeRuntime()
+	manager := NewManager(containers, nil, runtime, docker.NewLogger(log.NewLogger(log.GetGlobalLoggerOpts()), nil))
+	manager.SetWorkflowRunPlan(models.WorkflowRunPlan{
+		ExecutionPlan: []models.WorkflowExecutionPlan{{UUID: "wf", WorkflowID: "primary", Steps: steps}},
+	})
+
+	err := manager.UpdateWithStepStarted(steps[0], nil)
+	require.ErrorContains(t, err, "service container (postgres) is not ready after 1 attempts: command exited with 1")
+
+	manager.UpdateWithStepFinished(0, manager.workflowRunPlan.ExecutionPlan[0], steps[0])
+	require.Equal(t, []string{"postgres"}, runtime.Removed())
+
+	require.NoError(t, manager.UpdateWithStepStarted(steps[1], nil))
+}
+
+func TestManager_buildsExecutionContainerImageOnce(t *testing.T) {
+	configs.InputEnvstorePath = filepath.Join(t.TempDir(), "envstore.yml")
+	contextDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(contextDir, "Dockerfile"), []byte("FROM golang:1.25\n"), 0644))
+
+	steps := []models.StepExecutionPlan{
+		{UUID: "step-1", ExecutionContainer: &models.ContainerConfig{ContainerID: "builder"}},
+		{UUID: "step-2", ExecutionContainer: &models.ContainerConfig{ContainerID: "builder", Recreate: true}},
+	}
+	containers := map[string]models.Container{
+		"builder": {Type: models.ContainerTypeExecution, Build: &models.ContainerBuild{Context: contextDir}},
+	}
+	runtime := dockertest.NewFakeRuntime()
+	manager := NewManager(containers, nil, runtime, docker.NewLogger(log.NewLogger(log.GetGlobalLoggerOpts()), nil))
+	manager.SetWorkflowRunPlan(models.WorkflowRunPlan{
+		ExecutionPlan: []models.WorkflowExecutionPlan{{UUID: "wf", WorkflowID: "primary", Steps: steps}},
+	})
+
+	require.NoError(t, manager.UpdateWithStepStarted(steps[0], nil))
+	manager.UpdateWithStepFinished(0, manager.workflowRunPlan.ExecutionPlan[0], steps[0])
+	require.NoError(t, manager.UpdateWithStepStarted(steps[1], nil))
+
+	tag, err := docker.ImageBuildTag("builder", containers["builder"])
+	require.NoError(t, err)
+	require.Equal(t, []string{tag}, runtime.Built())
+	require.Equal(t, []string{"builder", "builder"}, runtime.Started())
+
+	definition, _ := manager.GetExecutionContainerForStep("step-2")
+	require.Equal(t, tag, definition.Image)
+}
+
+func TestManager_collectsContainerLogsAndResults(t *testing.T) {
+	configs.InputEnvstorePath = filepath.Join(t.TempDir(), "envstore.yml")
+	deployDir := t.TempDir()
+	t.Setenv(configs.BitriseDeployDirEnvKey, deployDir)
+
+	steps := []models.StepExecutionPlan{
+		{UUID: "step-1", ExecutionContainer: &models.ContainerConfig{ContainerID: "ubuntu"}, ServiceContainers: []models.ContainerConfig{{ContainerID: "redis"}}},
+	}
+	runtime := dockertest.NewFakeRuntime()
+	runtime.Logs = map[string]string{"redis": "Ready to accept connections\n"}
+	runtime.States = map[string]docker.ContainerState{"redis": {ExitCode: 137, OOMKilled: true}}
+	manager := newTestManager(runtime, steps)
+
+	require.NoError(t, manager.UpdateWithStepStarted(steps[0], nil))
+	manager.UpdateWithStepFinished(0, manager.workflowRunPlan.ExecutionPlan[0], steps[0])
+	require.NoError(t, manager.DestroyAllContainers())
+
+	logPath := filepath.Join(deployDir, LogsDirName, "redis.log")
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Equal(t, "Ready to accept connections\n", string(content))
+
+	require.Equal(t, []models.ContainerRunResultModel{
+		{ID: "ubuntu", Type: models.ContainerTypeExecution, Image: "ubuntu:24.04", Running: true, LogPath: filepath.Join(deployDir, LogsDirName, "ubuntu.log")},
+		{ID: "redis", Type: models.ContainerTypeService, Image: "redis:7", ExitCode: 137, OOMKilled: true, LogPath: logPath},
+	}, manager.ContainerResults())
+}
+
+func TestManager_appendsLogsOfRecreatedContainer(t *testing.T) {
+	configs.InputEnvstorePath = filepath.Join(t.TempDir(), "envstore.yml")
+	deployDir := t.TempDir()
+	t.Setenv(configs.BitriseDeployDirEnvKey, deployDir)
+
+	steps := []models.StepExecutionPlan{
+		{UUID: "step-1", ExecutionContainer: &models.ContainerConfig{ContainerID: "ubuntu"}},
+		{UUID: "step-2", ExecutionContainer: &models.ContainerConfig{ContainerID: "ubuntu", Recreate: true}},
+	}
+	runtime := dockertest.NewFakeRuntime()
+	runtime.Logs = map[string]string{"ubuntu": "started\n"}
+	manager := newTestManager(runtime, steps)
+
+	require.NoError(t, manager.UpdateWithStepStarted(steps[0], nil))
+	manager.UpdateWithStepFinished(0, manager.workflowRunPlan.ExecutionPlan[0], steps[0])
+	require.NoError(t, manager.UpdateWithStepStarted(steps[1], nil))
+	manager.UpdateWithStepFinished(1, manager.workflowRunPlan.ExecutionPlan[0], steps[1])
+	require.NoError(t, manager.DestroyAllContainers())
+	require.Equal(t, []string{"ubuntu", "ubuntu"}, runtime.Started())
+
+	content, err := os.ReadFile(filepath.Join(deployDir, LogsDirName, "ubuntu.log"))
+	require.NoError(t, err)
+	require.Equal(t, "started\nstarted\n", string(content))
+}
+
+// TestManager_containerResultsWhileDestroying reads the results while the containers are removed on abort,
+// run it with -race.
+func TestManager_containerResultsWhileDestroying(t *testing.T) {
+	configs.InputEnvstorePath = filepath.Join(t.TempDir(), "envstore.yml")
+
+	steps := []models.StepExecutionPlan{
+		{UUID: "step-1", ExecutionContainer: &models.ContainerConfig{ContainerID: "ubuntu"}, ServiceContainers: []models.ContainerConfig{{ContainerID: "redis"}}},
+	}
+	manager := newTestManager(dockertest.NewFakeRuntime(), steps)
+	require.NoError(t, manager.UpdateWithStepStarted(steps[0], nil))
+
+	done := make(chan error)
+	go func() {
+		done <- manager.DestroyAllContainers()
+	}()
+	results := manager.ContainerResults()
+	require.NoError(t, <-done)
+
+	require.LessOrEqual(t, len(results), 2)
+	require.Len(t, manager.ContainerResults(), 2)
+}
+
+func newTestManager(runtime docker.Runtime, steps []models.StepExecutionPlan) *Manager {
+	containers := map[string]models.Container{
+		"ubuntu": {Type: models.ContainerTypeExecution, Image: "ubuntu:24.04"},
+		"redis":  {Type: models.ContainerTypeService, Image: "redis:7"},
+	}
+	logger := docker.NewLogger(log.NewLogger(log.GetGlobalLoggerOpts()), nil)
+
+	manager := NewManager(containers, nil, runtime, logger)
+	manager.SetWorkflowRunPlan(models.WorkflowRunPlan{
+		ExecutionPlan: []models.WorkflowExecutionPlan{{UUID: "wf", WorkflowID: "primary", Steps: steps}},
+	})
+	return manager
+}
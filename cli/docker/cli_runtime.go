@@ -1,20 +1,15 @@
 package docker
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/api/types/filters"
-	"github.com/docker/docker/api/types/image"
-	"github.com/docker/docker/api/types/network"
-	"github.com/docker/docker/client"
-
 	"github.com/bitrise-io/bitrise/v2/log"
 	"github.com/bitrise-io/bitrise/v2/models"
 	"github.com/bitrise-io/go-utils/command"
@@ -29,26 +24,91 @@ const (
 	ServiceContainerType   ContainerType = "service"
 )
 
-type ContainerManager struct {
+// cliRuntime runs the containers with a Docker compatible CLI (docker or podman),
+// the state of the images, containers and networks is queried through the runtime's engine.
+type cliRuntime struct {
+	binary string
 	logger *Logger
-	client *client.Client
+	engine engine
+}
+
+// engine queries and manages the state of the images, containers and networks of a runtime.
+type engine interface {
+	imageExists(image string) (bool, error)
+	// containerStatus returns the ID of the container with the given name and whether it is running.
+	containerStatus(name string) (string, bool, error)
+	containerLogs(id string) (string, error)
+	// containerHealth returns the health status of the container, or an empty string if the container has no healthcheck.
+	containerHealth(id string) (string, error)
+	ensureNetwork(name string) error
+	containerState(name string) (ContainerState, error)
 }
 
-func NewContainerManager(logger *Logger) *ContainerManager {
-	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+// logsFollowTimeout is how long FollowLogs waits for the logs command to exit after the container stopped.
+const logsFollowTimeout = 10 * time.Second
+
+func (cm *cliRuntime) Name() string {
+	return cm.binary
+}
+
+func (cm *cliRuntime) RemoveContainer(name string) error {
+	_, err := command.New(cm.binary, "rm", "--force", "--volumes", name).RunAndReturnTrimmedCombinedOutput()
 	if err != nil {
-		logger.Warnf("Docker client failed to initialize (possibly running on unsupported environment): %s", err)
+		return fmt.Errorf("remove %s container: %w", cm.binary, err)
 	}
+	return nil
+}
 
-	return &ContainerManager{
-		logger: logger,
-		client: dockerClient,
+func (cm *cliRuntime) ContainerState(name string) (ContainerState, error) {
+	state, err := cm.engine.containerState(name)
+	if err != nil {
+		return ContainerState{}, fmt.Errorf("inspect container (%s): %w", name, err)
 	}
+	return state, nil
 }
 
-func (cm *ContainerManager) LoginAndRunContainer(t ContainerType, containerDef models.Container, containerName string, envs map[string]string) (*RunningContainer, error) {
+func (cm *cliRuntime) FollowLogs(containerName string, output io.Writer) (func() error, error) {
+	cmd := exec.Command(cm.binary, "logs", "--follow", containerName)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("follow container (%s) logs: %w", containerName, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	return func() error {
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(logsFollowTimeout):
+			if err := cmd.Process.Kill(); err != nil {
+				return fmt.Errorf("stop following container (%s) logs: %w", containerName, err)
+			}
+			<-done
+			return nil
+		}
+	}, nil
+}
+
+func (cm *cliRuntime) ExecCommand(containerName string, envs []string) (string, []string) {
+	args := []string{"exec"}
+
+	for _, env := range envs {
+		args = append(args, "-e", env)
+	}
+
+	args = append(args, containerName)
+
+	return cm.binary, args
+}
+
+func (cm *cliRuntime) LoginAndRunContainer(t ContainerType, containerDef models.Container, containerName string, envs map[string]string) (*RunningContainer, error) {
 	if err := cm.login(containerDef, envs); err != nil {
-		log.Errorf("docker credentials provided, but the authentication failed.")
+		log.Errorf("%s credentials provided, but the authentication failed.", cm.binary)
 		return nil, fmt.Errorf("authentication failed: %w", err)
 	}
 
@@ -58,7 +118,10 @@ func (cm *ContainerManager) LoginAndRunContainer(t ContainerType, containerDef m
 	switch t {
 	case ExecutionContainerType:
 		// TODO: handle default mounts if BITRISE_DOCKER_MOUNT_OVERRIDES is not provided
-		dockerMountOverrides := strings.Split(os.Getenv("BITRISE_DOCKER_MOUNT_OVERRIDES"), ",")
+		var dockerMountOverrides []string
+		if overrides := os.Getenv("BITRISE_DOCKER_MOUNT_OVERRIDES"); overrides != "" {
+			dockerMountOverrides = strings.Split(overrides, ",")
+		}
 
 		runningContainer, err = cm.runContainer(containerDef, containerCreateOptions{
 			name:       containerName,
@@ -79,7 +142,7 @@ func (cm *ContainerManager) LoginAndRunContainer(t ContainerType, containerDef m
 	}
 
 	if runningContainer != nil {
-		if err := cm.healthCheckContainer(context.Background(), runningContainer); err != nil {
+		if err := cm.healthCheckContainer(runningContainer); err != nil {
 			return runningContainer, fmt.Errorf("container health check: %w", err)
 		}
 	}
@@ -87,9 +150,9 @@ func (cm *ContainerManager) LoginAndRunContainer(t ContainerType, containerDef m
 	return runningContainer, nil
 }
 
-func (cm *ContainerManager) login(container models.Container, envs map[string]string) error {
+func (cm *cliRuntime) login(container models.Container, envs map[string]string) error {
 	if container.Credentials.Username != "" && container.Credentials.Password != "" {
-		cm.logger.Infof("ℹ️ Logging into docker registry: %s", container.Image)
+		cm.logger.Infof("ℹ️ Logging into container registry: %s", container.Image)
 
 		resolvedPassword := resolveEnvVariable(container.Credentials.Password, envs)
 		resolvedUsername := resolveEnvVariable(container.Credentials.Username, envs)
@@ -101,12 +164,12 @@ func (cm *ContainerManager) login(container models.Container, envs map[string]st
 			args = append(args, container.Image)
 		}
 
-		cm.logger.Infof("ℹ️ Running command: docker %s", strings.Join(args, " "))
+		cm.logger.Infof("ℹ️ Running command: %s %s", cm.binary, strings.Join(args, " "))
 
-		out, err := command.New("docker", args...).RunAndReturnTrimmedCombinedOutput()
+		out, err := command.New(cm.binary, args...).RunAndReturnTrimmedCombinedOutput()
 		if err != nil {
 			cm.logger.Errorf("%s", out)
-			return fmt.Errorf("run docker login: %w", err)
+			return fmt.Errorf("run %s login: %w", cm.binary, err)
 		}
 	}
 	return nil
@@ -120,77 +183,76 @@ type containerCreateOptions struct {
 	user       string
 }
 
-// We are not using the docker sdk for pull, start and create commands because:
+// We are not using the docker sdk (or the engine in general) for pull, start and create commands because:
 //   - We want to make sure the end user can easily debug using the same docker command we issue
 //     (hard to convert between sdk and cli api)
 //   - We'd like to support options generically,
 //     with the SDK we would need to parse the string ourselves to convert them properly to their own type
 //   - SDK differs from the CLI in some cases, for example pulling from private registry requires the exact token
 //     it can't automatically use the docker config
-func (cm *ContainerManager) runContainer(container models.Container, options containerCreateOptions, envs map[string]string) (*RunningContainer, error) {
-	if err := cm.ensureNetwork(); err != nil {
-		return nil, fmt.Errorf("ensure bitrise docker network: %w", err)
+func (cm *cliRuntime) runContainer(container models.Container, options containerCreateOptions, envs map[string]string) (*RunningContainer, error) {
+	if err := cm.engine.ensureNetwork(bitriseNetwork); err != nil {
+		return nil, fmt.Errorf("ensure bitrise %s network: %w", cm.binary, err)
 	}
 
-	cm.logger.Infof("ℹ️ Pulling docker image: %s", container.Image)
+	cm.logger.Infof("ℹ️ Pulling %s image: %s", cm.binary, container.Image)
 	err := cm.pullImageWithRetry(container)
 	if err != nil {
-		return nil, fmt.Errorf("pull docker image: %w", err)
+		return nil, fmt.Errorf("pull %s image: %w", cm.binary, err)
 	}
-	cm.logger.Infof("✅ Docker image pulled: %s", container.Image)
+	cm.logger.Infof("✅ Image pulled: %s", container.Image)
 
-	cm.logger.Infof("ℹ️ Creating docker container: %s", container.Image)
+	cm.logger.Infof("ℹ️ Creating %s container: %s", cm.binary, container.Image)
 	err = cm.createContainer(container, options, envs)
 	if err != nil {
-		return nil, fmt.Errorf("create docker container: %w", err)
+		return nil, fmt.Errorf("create %s container: %w", cm.binary, err)
 	}
-	cm.logger.Infof("✅ Docker container created: %s", container.Image)
+	cm.logger.Infof("✅ Container created: %s", container.Image)
 
-	cm.logger.Infof("ℹ️ Starting docker container: %s", container.Image)
+	cm.logger.Infof("ℹ️ Starting %s container: %s", cm.binary, container.Image)
 	runningContainer, err := cm.startContainer(options)
 	if err != nil {
-		return runningContainer, fmt.Errorf("start docker container: %w", err)
+		return runningContainer, fmt.Errorf("start %s container: %w", cm.binary, err)
 	}
 	cm.logger.Infof("✅ Container (%s) is running (%s)", runningContainer.Name, runningContainer.ID)
 
 	return runningContainer, nil
 }
 
-func (cm *ContainerManager) startContainer(options containerCreateOptions) (*RunningContainer, error) {
+func (cm *cliRuntime) startContainer(options containerCreateOptions) (*RunningContainer, error) {
 	// At this point the container has been created, but it's not running yet
 	// Even if we can't start it we need to return the container reference to make sure it will be cleaned up
 	runningContainer := &RunningContainer{
-		Name: options.name,
+		Name:    options.name,
+		runtime: cm,
 	}
 
-	cm.logger.Infof("ℹ️ Running command: docker start %s", options.name)
-	out, err := command.New("docker", "start", options.name).RunAndReturnTrimmedCombinedOutput()
+	cm.logger.Infof("ℹ️ Running command: %s start %s", cm.binary, options.name)
+	out, err := command.New(cm.binary, "start", options.name).RunAndReturnTrimmedCombinedOutput()
 	if err != nil {
 		cm.logger.Errorf("%s", out)
-		return runningContainer, fmt.Errorf("start docker container (%s): %w", options.name, err)
+		return runningContainer, fmt.Errorf("start %s container (%s): %w", cm.binary, options.name, err)
 	}
 
 	// We need to get the container ID to be able to check the health
 	// This also serves as a validation that the container is running
-	result, err := cm.getRunningContainer(context.Background(), options.name)
-	if result != nil {
-		runningContainer.ID = result.ID
-	}
+	id, err := cm.checkContainerRunning(options.name)
+	runningContainer.ID = id
 	if err != nil {
 		return runningContainer, fmt.Errorf("container (%s) unable to start properly: %w", options.name, err)
 	}
 	return runningContainer, nil
 }
 
-func (cm *ContainerManager) createContainer(container models.Container, options containerCreateOptions, envs map[string]string) error {
+func (cm *cliRuntime) createContainer(container models.Container, options containerCreateOptions, envs map[string]string) error {
 	dockerRunArgs, err := buildCreateContainerCommandArgs(container, options, envs)
 	if err != nil {
 		return err
 	}
 
-	cm.logger.Infof("ℹ️ Running command: docker %s", strings.Join(dockerRunArgs, " "))
+	cm.logger.Infof("ℹ️ Running command: %s %s", cm.binary, strings.Join(dockerRunArgs, " "))
 
-	out, err := command.New("docker", dockerRunArgs...).RunAndReturnTrimmedCombinedOutput()
+	out, err := command.New(cm.binary, dockerRunArgs...).RunAndReturnTrimmedCombinedOutput()
 	if err != nil {
 		cm.logger.Errorf("%s", out)
 		return fmt.Errorf("create container (%s): %w", options.name, err)
@@ -199,7 +261,7 @@ func (cm *ContainerManager) createContainer(container models.Container, options
 	return nil
 }
 
-func (cm *ContainerManager) pullImageWithRetry(container models.Container) error {
+func (cm *cliRuntime) pullImageWithRetry(container models.Container) error {
 	pulling := true
 	defer func() {
 		pulling = false
@@ -231,20 +293,18 @@ func (cm *ContainerManager) pullImageWithRetry(container models.Container) error
 	return err
 }
 
-func (cm *ContainerManager) pullImage(container models.Container) error {
-	images, err := cm.client.ImageList(context.Background(), image.ListOptions{
-		Filters: filters.NewArgs(filters.Arg("reference", container.Image)),
-	})
+func (cm *cliRuntime) pullImage(container models.Container) error {
+	exists, err := cm.engine.imageExists(container.Image)
 	if err != nil {
 		cm.logger.Warnf("Failed to check whether local image exist already, pulling...: %s", err.Error())
-	} else if len(images) > 0 {
+	} else if exists {
 		cm.logger.Infof("ℹ️ Image (%s) already exists locally", container.Image)
 		return nil
 	}
 
-	dockerRunArgs := []string{"pull", "--platform", "linux/amd64", container.Image}
-	cm.logger.Infof("ℹ️ Running command: docker %s", strings.Join(dockerRunArgs, " "))
-	out, err := command.New("docker", dockerRunArgs...).RunAndReturnTrimmedCombinedOutput()
+	dockerRunArgs := []string{"pull", "--platform", container.GetPlatform(), container.Image}
+	cm.logger.Infof("ℹ️ Running command: %s %s", cm.binary, strings.Join(dockerRunArgs, " "))
+	out, err := command.New(cm.binary, dockerRunArgs...).RunAndReturnTrimmedCombinedOutput()
 	if err != nil {
 		cm.logger.Errorf("%s", out)
 		return fmt.Errorf("pull container (%s): %w", container.Image, err)
@@ -252,51 +312,37 @@ func (cm *ContainerManager) pullImage(container models.Container) error {
 	return nil
 }
 
-func (cm *ContainerManager) getRunningContainer(ctx context.Context, name string) (*container.Summary, error) {
-	containers, err := cm.client.ContainerList(ctx, container.ListOptions{
-		Filters: filters.NewArgs(filters.Arg("name", name)),
-	})
+func (cm *cliRuntime) checkContainerRunning(name string) (string, error) {
+	id, running, err := cm.engine.containerStatus(name)
 	if err != nil {
-		return nil, fmt.Errorf("list containers: %w", err)
-	}
-
-	if len(containers) != 1 {
-		return nil, fmt.Errorf("multiple containers with the same name found: %s", name)
+		return id, err
 	}
 
-	if containers[0].State != "running" {
-		logs, err := cm.client.ContainerLogs(ctx, containers[0].ID, container.LogsOptions{
-			ShowStdout: true,
-			ShowStderr: true,
-		})
-		if err != nil {
-			return &containers[0], fmt.Errorf("container is not running: failed to get container logs: %w", err)
-		}
-
-		content, err := io.ReadAll(logs)
+	if !running {
+		logs, err := cm.engine.containerLogs(id)
 		if err != nil {
-			return &containers[0], fmt.Errorf("container is not running: failed to read container logs: %w", err)
+			return id, fmt.Errorf("container is not running: failed to get container logs: %w", err)
 		}
-		cm.logger.Errorf("Failed container (%s) logs:\n %s\n", name, string(content))
-		return &containers[0], fmt.Errorf("container (%s) is not running", name)
+		cm.logger.Errorf("Failed container (%s) logs:\n %s\n", name, logs)
+		return id, fmt.Errorf("container (%s) is not running", name)
 	}
-	return &containers[0], nil
+	return id, nil
 }
 
-func (cm *ContainerManager) healthCheckContainer(ctx context.Context, container *RunningContainer) error {
-	inspect, err := cm.client.ContainerInspect(ctx, container.ID)
+func (cm *cliRuntime) healthCheckContainer(container *RunningContainer) error {
+	status, err := cm.engine.containerHealth(container.ID)
 	if err != nil {
 		return fmt.Errorf("inspect container (%s): %w", container.Name, err)
 	}
 
-	if inspect.State.Health == nil {
+	if status == "" {
 		cm.logger.Infof("✅ No healthcheck is defined for container (%s), assuming healthy...", container.Name)
 		return nil
 	}
 
 	retries := 0
-	for inspect.State.Health.Status != "healthy" {
-		if inspect.State.Health.Status == "unhealthy" {
+	for status != "healthy" {
+		if status == "unhealthy" {
 			cm.logger.Errorf("❌ Container (%s) is unhealthy...", container.Name)
 			return fmt.Errorf("container (%s) is unhealthy", container.Name)
 		}
@@ -308,7 +354,7 @@ func (cm *ContainerManager) healthCheckContainer(ctx context.Context, container
 		time.Sleep(time.Duration(sleep) * time.Second)
 
 		cm.logger.Infof("⏳ Waiting for container (%s) to be healthy... (retry: %ds)", container.Name, sleep)
-		inspect, err = cm.client.ContainerInspect(context.Background(), container.ID)
+		status, err = cm.engine.containerHealth(container.ID)
 		if err != nil {
 			return fmt.Errorf("inspect container (%s): %w", container.Name, err)
 		}
@@ -320,29 +366,10 @@ func (cm *ContainerManager) healthCheckContainer(ctx context.Context, container
 	return nil
 }
 
-func (cm *ContainerManager) ensureNetwork() error {
-	networks, err := cm.client.NetworkList(context.Background(), network.ListOptions{
-		Filters: filters.NewArgs(filters.Arg("name", bitriseNetwork)),
-	})
-	if err != nil {
-		return fmt.Errorf("list networks: %w", err)
-	}
-
-	if len(networks) > 0 {
-		return nil
-	}
-
-	if _, err := cm.client.NetworkCreate(context.Background(), bitriseNetwork, network.CreateOptions{}); err != nil {
-		return fmt.Errorf("create network: %w", err)
-	}
-
-	return nil
-}
-
 func buildCreateContainerCommandArgs(container models.Container, options containerCreateOptions, envs map[string]string) ([]string, error) {
 	dockerRunArgs := []string{
 		"create",
-		"--platform", "linux/amd64",
+		"--platform", container.GetPlatform(),
 		fmt.Sprintf("--network=%s", bitriseNetwork),
 	}
 
@@ -350,6 +377,14 @@ func buildCreateContainerCommandArgs(container models.Container, options contain
 		dockerRunArgs = append(dockerRunArgs, "-v", o)
 	}
 
+	for _, volume := range container.Volumes {
+		dockerRunArgs = append(dockerRunArgs, "-v", volume)
+	}
+
+	for _, tmpfs := range container.Tmpfs {
+		dockerRunArgs = append(dockerRunArgs, "--tmpfs", tmpfs)
+	}
+
 	for _, env := range container.Envs {
 		name, value, err := env.GetKeyValuePair()
 		if err != nil {
@@ -363,12 +398,33 @@ func buildCreateContainerCommandArgs(container models.Container, options contain
 		dockerRunArgs = append(dockerRunArgs, "-p", port)
 	}
 
-	if options.workingDir != "" {
-		dockerRunArgs = append(dockerRunArgs, "-w", options.workingDir)
+	if container.CPUs > 0 {
+		dockerRunArgs = append(dockerRunArgs, "--cpus", strconv.FormatFloat(container.CPUs, 'f', -1, 64))
+	}
+
+	if container.Memory != "" {
+		dockerRunArgs = append(dockerRunArgs, "--memory", container.Memory)
 	}
 
-	if options.user != "" {
-		dockerRunArgs = append(dockerRunArgs, "-u", options.user)
+	if container.Entrypoint != "" {
+		dockerRunArgs = append(dockerRunArgs, "--entrypoint", container.Entrypoint)
+	}
+
+	// The fields of the container definition take precedence over the defaults of the container type
+	workingDir := options.workingDir
+	if container.Workdir != "" {
+		workingDir = container.Workdir
+	}
+	if workingDir != "" {
+		dockerRunArgs = append(dockerRunArgs, "-w", workingDir)
+	}
+
+	user := options.user
+	if container.User != "" {
+		user = container.User
+	}
+	if user != "" {
+		dockerRunArgs = append(dockerRunArgs, "-u", user)
 	}
 
 	if container.Options != "" {
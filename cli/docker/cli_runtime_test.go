@@ -0,0 +1,74 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_buildCreateArgs_optsKeyIsNotPassedAsEnv(t *testing.T) {
+	isFalse := false
+	env := envmanModels.EnvironmentItemModel{
+		"MYSQL_ROOT_PASSWORD": "password",
+		envmanModels.OptionsKey: envmanModels.EnvironmentItemOptionsModel{
+			IsExpand: &isFalse,
+		},
+	}
+	container := models.Container{
+		Image: "mysql:8",
+		Envs:  []envmanModels.EnvironmentItemModel{env},
+	}
+
+	args, err := buildCreateContainerCommandArgs(container, containerCreateOptions{name: "mysql"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"create",
+		"--platform", "linux/amd64",
+		"--network=bitrise",
+		"-e", "MYSQL_ROOT_PASSWORD=password",
+		"--name=mysql",
+		"mysql:8",
+	}, args)
+}
+
+func Test_buildCreateArgs_typedFields(t *testing.T) {
+	container := models.Container{
+		Image:      "ubuntu:24.04",
+		Volumes:    []string{"cache:/root/.cache"},
+		Tmpfs:      []string{"/tmp:size=64m"},
+		CPUs:       1.5,
+		Memory:     "2g",
+		Platform:   "linux/arm64",
+		Entrypoint: "/bin/sh",
+		Workdir:    "/workspace",
+		User:       "1000",
+	}
+	options := containerCreateOptions{
+		name:       "bitrise-workflow",
+		volumes:    []string{"/bitrise:/bitrise"},
+		command:    "sleep infinity",
+		workingDir: "/bitrise/src",
+		user:       "root",
+	}
+
+	args, err := buildCreateContainerCommandArgs(container, options, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"create",
+		"--platform", "linux/arm64",
+		"--network=bitrise",
+		"-v", "/bitrise:/bitrise",
+		"-v", "cache:/root/.cache",
+		"--tmpfs", "/tmp:size=64m",
+		"--cpus", "1.5",
+		"--memory", "2g",
+		"--entrypoint", "/bin/sh",
+		"-w", "/workspace",
+		"-u", "1000",
+		"--name=bitrise-workflow",
+		"ubuntu:24.04",
+		"sleep", "infinity",
+	}, args)
+}
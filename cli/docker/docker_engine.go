@@ -0,0 +1,121 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+)
+
+// NewDockerRuntime returns a Runtime which runs the containers with the docker CLI
+// and queries their state through the Docker Engine API.
+func NewDockerRuntime(logger *Logger) Runtime {
+	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		logger.Warnf("Docker client failed to initialize (possibly running on unsupported environment): %s", err)
+	}
+
+	return &cliRuntime{
+		binary: RuntimeDocker,
+		logger: logger,
+		engine: dockerEngine{client: dockerClient},
+	}
+}
+
+type dockerEngine struct {
+	client *client.Client
+}
+
+func (e dockerEngine) imageExists(ref string) (bool, error) {
+	images, err := e.client.ImageList(context.Background(), image.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("reference", ref)),
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(images) > 0, nil
+}
+
+func (e dockerEngine) containerStatus(name string) (string, bool, error) {
+	containers, err := e.client.ContainerList(context.Background(), container.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("name", name)),
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("list containers: %w", err)
+	}
+
+	if len(containers) != 1 {
+		return "", false, fmt.Errorf("multiple containers with the same name found: %s", name)
+	}
+
+	return containers[0].ID, containers[0].State == "running", nil
+}
+
+func (e dockerEngine) containerLogs(id string) (string, error) {
+	logs, err := e.client.ContainerLogs(context.Background(), id, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = logs.Close() }()
+
+	content, err := io.ReadAll(logs)
+	if err != nil {
+		return "", fmt.Errorf("read container logs: %w", err)
+	}
+	return string(content), nil
+}
+
+func (e dockerEngine) containerHealth(id string) (string, error) {
+	inspect, err := e.client.ContainerInspect(context.Background(), id)
+	if err != nil {
+		return "", err
+	}
+
+	if inspect.State == nil || inspect.State.Health == nil {
+		return "", nil
+	}
+	return inspect.State.Health.Status, nil
+}
+
+func (e dockerEngine) containerState(name string) (ContainerState, error) {
+	inspect, err := e.client.ContainerInspect(context.Background(), name)
+	if err != nil {
+		return ContainerState{}, err
+	}
+	if inspect.State == nil {
+		return ContainerState{}, fmt.Errorf("container has no state")
+	}
+
+	return ContainerState{
+		Running:   inspect.State.Running,
+		ExitCode:  inspect.State.ExitCode,
+		OOMKilled: inspect.State.OOMKilled,
+	}, nil
+}
+
+func (e dockerEngine) ensureNetwork(name string) error {
+	networks, err := e.client.NetworkList(context.Background(), network.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("name", name)),
+	})
+	if err != nil {
+		return fmt.Errorf("list networks: %w", err)
+	}
+
+	if len(networks) > 0 {
+		return nil
+	}
+
+	if _, err := e.client.NetworkCreate(context.Background(), name, network.CreateOptions{}); err != nil {
+		return fmt.Errorf("create network: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+// Package dockertest provides a container runtime for the tests of the packages running containers.
+package dockertest
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/bitrise-io/bitrise/v2/cli/docker"
+	"github.com/bitrise-io/bitrise/v2/models"
+)
+
+// FakeRuntime is an in-memory docker.Runtime for tests, it records the started and removed containers
+// without running anything.
+type FakeRuntime struct {
+	// RunErr is returned by LoginAndRunContainer if set.
+	RunErr error
+	// RemoveErr is returned by RemoveContainer if set.
+	RemoveErr error
+	// BuildErr is returned by BuildImage if set.
+	BuildErr error
+	// States are returned by ContainerState by the container name, a missing container is running.
+	States map[string]docker.ContainerState
+	// Logs are written by FollowLogs by the container name.
+	Logs map[string]string
+
+	mu      sync.Mutex
+	started []string
+	removed []string
+	built   []string
+}
+
+func NewFakeRuntime() *FakeRuntime {
+	return &FakeRuntime{}
+}
+
+func (r *FakeRuntime) Name() string {
+	return "fake"
+}
+
+func (r *FakeRuntime) LoginAndRunContainer(t docker.ContainerType, containerDef models.Container, containerName string, envs map[string]string) (*docker.RunningContainer, error) {
+	if r.RunErr != nil {
+		return nil, r.RunErr
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.started = append(r.started, containerName)
+
+	return docker.NewRunningContainer(fmt.Sprintf("fake-%s", containerName), containerName, r), nil
+}
+
+func (r *FakeRuntime) BuildImage(containerDef models.Container, tag string, envs map[string]string) error {
+	if r.BuildErr != nil {
+		return r.BuildErr
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.built = append(r.built, tag)
+	return nil
+}
+
+func (r *FakeRuntime) RemoveContainer(name string) error {
+	if r.RemoveErr != nil {
+		return r.RemoveErr
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.removed = append(r.removed, name)
+	return nil
+}
+
+func (r *FakeRuntime) ContainerState(name string) (docker.ContainerState, error) {
+	if state, ok := r.States[name]; ok {
+		return state, nil
+	}
+	return docker.ContainerState{Running: true}, nil
+}
+
+func (r *FakeRuntime) FollowLogs(containerName string, output io.Writer) (func() error, error) {
+	if _, err := io.WriteString(output, r.Logs[containerName]); err != nil {
+		return nil, err
+	}
+	return func() error { return nil }, nil
+}
+
+func (r *FakeRuntime) ExecCommand(containerName string, envs []string) (string, []string) {
+	return "env", envs
+}
+
+// Started returns the names of the started containers in order.
+func (r *FakeRuntime) Started() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.started...)
+}
+
+// Built returns the tags of the built images in order.
+func (r *FakeRuntime) Built() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.built...)
+}
+
+// Removed returns the names of the removed containers in order.
+func (r *FakeRuntime) Removed() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.removed...)
+}
@@ -0,0 +1,198 @@
+package docker
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/go-utils/command"
+)
+
+const builtImageRepositoryPrefix = "bitrise-build-"
+
+var invalidImageNameChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// ImageBuildTag returns the tag of the image built for a container definition.
+// The tag is a hash of the build context, the Dockerfile, the build settings and the platform,
+// so an unchanged build environment resolves to the same tag and is built only once.
+func ImageBuildTag(containerID string, container models.Container) (string, error) {
+	if container.Build == nil {
+		return "", fmt.Errorf("container (%s) has no build defined", containerID)
+	}
+	build := *container.Build
+
+	hash := sha256.New()
+	writeField := func(name, value string) {
+		_, _ = fmt.Fprintf(hash, "%s=%q\n", name, value)
+	}
+
+	writeField("platform", container.GetPlatform())
+	writeField("dockerfile", build.GetDockerfile())
+	writeField("target", build.Target)
+	argKeys := make([]string, 0, len(build.Args))
+	for key := range build.Args {
+		argKeys = append(argKeys, key)
+	}
+	sort.Strings(argKeys)
+	for _, key := range argKeys {
+		writeField("arg:"+key, build.Args[key])
+	}
+
+	if err := hashFile(hash, build.GetDockerfile(), "dockerfile-content"); err != nil {
+		return "", err
+	}
+	if err := hashBuildContext(hash, build.GetContext()); err != nil {
+		return "", err
+	}
+
+	repository := builtImageRepositoryPrefix + strings.Trim(invalidImageNameChars.ReplaceAllString(strings.ToLower(containerID), "-"), "-._")
+	return fmt.Sprintf("%s:%s", repository, hex.EncodeToString(hash.Sum(nil))[:12]), nil
+}
+
+// hashBuildContext hashes the paths, modes and contents of the files sent to the build,
+// the files excluded by the .dockerignore file of the context are skipped.
+func hashBuildContext(w io.Writer, contextDir string) error {
+	ignorePatterns, err := readDockerignore(filepath.Join(contextDir, ".dockerignore"))
+	if err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(contextDir, func(pth string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(contextDir, pth)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if entry.Name() == ".git" || isDockerignored(rel, ignorePatterns) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if entry.IsDir() || !entry.Type().IsRegular() {
+			_, err := fmt.Fprintf(w, "entry=%q %s\n", rel, entry.Type())
+			return err
+		}
+		return hashFile(w, pth, "file:"+rel)
+	})
+}
+
+func hashFile(w io.Writer, pth, name string) error {
+	file, err := os.Open(pth)
+	if err != nil {
+		return fmt.Errorf("hash build input: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("hash build input: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "%s %s %d\n", name, info.Mode(), info.Size()); err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, file); err != nil {
+		return fmt.Errorf("hash build input (%s): %w", pth, err)
+	}
+	return nil
+}
+
+// readDockerignore reads the patterns of a .dockerignore file, exceptions (!pattern) are not supported
+// and only make the hash more conservative.
+func readDockerignore(pth string) ([]string, error) {
+	file, err := os.Open(pth)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read .dockerignore: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		patterns = append(patterns, strings.Trim(filepath.ToSlash(filepath.Clean(line)), "/"))
+	}
+	return patterns, scanner.Err()
+}
+
+func isDockerignored(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, rel); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildImage builds the image of the container definition with the given tag, unless it exists already.
+func (cm *cliRuntime) BuildImage(containerDef models.Container, tag string, envs map[string]string) error {
+	exists, err := cm.engine.imageExists(tag)
+	if err != nil {
+		cm.logger.Warnf("Failed to check whether local image exist already, building...: %s", err.Error())
+	} else if exists {
+		cm.logger.Infof("ℹ️ Image (%s) is already built", tag)
+		return nil
+	}
+
+	if err := cm.login(containerDef, envs); err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+
+	args := buildImageCommandArgs(containerDef, tag, envs)
+	cm.logger.Infof("ℹ️ Running command: %s %s", cm.binary, strings.Join(args, " "))
+	out, err := command.New(cm.binary, args...).RunAndReturnTrimmedCombinedOutput()
+	if err != nil {
+		cm.logger.Errorf("%s", out)
+		return fmt.Errorf("build image (%s): %w", tag, err)
+	}
+	cm.logger.Infof("✅ Image built: %s", tag)
+	return nil
+}
+
+func buildImageCommandArgs(containerDef models.Container, tag string, envs map[string]string) []string {
+	build := *containerDef.Build
+	args := []string{
+		"build",
+		"--platform", containerDef.GetPlatform(),
+		"--file", build.GetDockerfile(),
+		"--tag", tag,
+	}
+
+	argKeys := make([]string, 0, len(build.Args))
+	for key := range build.Args {
+		argKeys = append(argKeys, key)
+	}
+	sort.Strings(argKeys)
+	for _, key := range argKeys {
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", key, resolveEnvVariable(build.Args[key], envs)))
+	}
+
+	if build.Target != "" {
+		args = append(args, "--target", build.Target)
+	}
+
+	return append(args, build.GetContext())
+}
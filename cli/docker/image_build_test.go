@@ -0,0 +1,76 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageBuildTag(t *testing.T) {
+	contextDir := t.TempDir()
+	writeFile(t, filepath.Join(contextDir, "Dockerfile"), "FROM ubuntu:24.04\n")
+	writeFile(t, filepath.Join(contextDir, "setup.sh"), "apt-get update\n")
+	writeFile(t, filepath.Join(contextDir, ".dockerignore"), "# build outputs\nbuild\n*.log\n")
+
+	container := models.Container{Build: &models.ContainerBuild{Context: contextDir, Args: map[string]string{"GO_VERSION": "1.25"}}}
+
+	tag, err := ImageBuildTag("Go_Builder", container)
+	require.NoError(t, err)
+	require.Regexp(t, `^bitrise-build-go_builder:[0-9a-f]{12}$`, tag)
+
+	sameTag, err := ImageBuildTag("Go_Builder", container)
+	require.NoError(t, err)
+	require.Equal(t, tag, sameTag, "the tag should be stable")
+
+	writeFile(t, filepath.Join(contextDir, "build", "output.bin"), "binary")
+	writeFile(t, filepath.Join(contextDir, "debug.log"), "log")
+	ignoredTag, err := ImageBuildTag("Go_Builder", container)
+	require.NoError(t, err)
+	require.Equal(t, tag, ignoredTag, "ignored files should not change the tag")
+
+	container.Build.Args["GO_VERSION"] = "1.24"
+	argsTag, err := ImageBuildTag("Go_Builder", container)
+	require.NoError(t, err)
+	require.NotEqual(t, tag, argsTag, "build args should change the tag")
+	container.Build.Args["GO_VERSION"] = "1.25"
+
+	writeFile(t, filepath.Join(contextDir, "setup.sh"), "apt-get update && apt-get install -y git\n")
+	contentTag, err := ImageBuildTag("Go_Builder", container)
+	require.NoError(t, err)
+	require.NotEqual(t, tag, contentTag, "context files should change the tag")
+
+	_, err = ImageBuildTag("Go_Builder", models.Container{Build: &models.ContainerBuild{Context: contextDir, Dockerfile: "missing.Dockerfile"}})
+	require.ErrorContains(t, err, "hash build input")
+}
+
+func Test_buildImageCommandArgs(t *testing.T) {
+	container := models.Container{
+		Platform: "linux/arm64",
+		Build: &models.ContainerBuild{
+			Context:    "ci",
+			Dockerfile: "builder.Dockerfile",
+			Args:       map[string]string{"TOKEN": "$NPM_TOKEN", "GO_VERSION": "1.25"},
+			Target:     "builder",
+		},
+	}
+
+	args := buildImageCommandArgs(container, "bitrise-build-go:abc", map[string]string{"NPM_TOKEN": "secret"})
+	require.Equal(t, []string{
+		"build",
+		"--platform", "linux/arm64",
+		"--file", "ci/builder.Dockerfile",
+		"--tag", "bitrise-build-go:abc",
+		"--build-arg", "GO_VERSION=1.25",
+		"--build-arg", "TOKEN=secret",
+		"--target", "builder",
+		"ci",
+	}, args)
+}
+
+func writeFile(t *testing.T, pth, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+	require.NoError(t, os.WriteFile(pth, []byte(content), 0644))
+}
@@ -42,6 +42,12 @@ func (dl *Logger) Debugf(format string, args ...interface{}) {
 	dl.logger.Debug(redacted)
 }
 
+// RedactWriter returns a writer which redacts the secrets before writing to the target.
+func (dl *Logger) RedactWriter(target io.Writer) io.WriteCloser {
+	logger := log.NewUtilsLogAdapter()
+	return redactwriter.New(dl.secrets, target, &logger)
+}
+
 func (dl *Logger) Redact(s string) (string, error) {
 	src := bytes.NewReader([]byte(s))
 	dstBuf := new(bytes.Buffer)
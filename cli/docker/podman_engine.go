@@ -0,0 +1,106 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/command"
+)
+
+// NewPodmanRuntime returns a Runtime which runs the containers with the podman CLI.
+// Podman is daemonless, so the state of the containers is queried through the CLI too,
+// this makes it usable on agents where only rootless Podman is available.
+func NewPodmanRuntime(logger *Logger) Runtime {
+	return &cliRuntime{
+		binary: RuntimePodman,
+		logger: logger,
+		engine: podmanEngine{binary: RuntimePodman},
+	}
+}
+
+type podmanEngine struct {
+	binary string
+}
+
+func (e podmanEngine) imageExists(image string) (bool, error) {
+	return e.exists("image", "exists", image)
+}
+
+func (e podmanEngine) containerStatus(name string) (string, bool, error) {
+	out, err := command.New(e.binary, "container", "inspect", "--format", "{{.Id}} {{.State.Running}}", name).RunAndReturnTrimmedCombinedOutput()
+	if err != nil {
+		return "", false, fmt.Errorf("inspect container (%s): %s: %w", name, out, err)
+	}
+
+	id, running, found := strings.Cut(out, " ")
+	if !found {
+		return "", false, fmt.Errorf("unexpected container inspect output: %s", out)
+	}
+	return id, running == "true", nil
+}
+
+func (e podmanEngine) containerLogs(id string) (string, error) {
+	return command.New(e.binary, "logs", id).RunAndReturnTrimmedCombinedOutput()
+}
+
+func (e podmanEngine) containerHealth(id string) (string, error) {
+	out, err := command.New(e.binary, "container", "inspect", "--format", "{{if .State.Health}}{{.State.Health.Status}}{{end}}", id).RunAndReturnTrimmedCombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", out, err)
+	}
+	return out, nil
+}
+
+func (e podmanEngine) containerState(name string) (ContainerState, error) {
+	out, err := command.New(e.binary, "container", "inspect", "--format", "{{.State.Running}} {{.State.ExitCode}} {{.State.OOMKilled}}", name).RunAndReturnTrimmedCombinedOutput()
+	if err != nil {
+		return ContainerState{}, fmt.Errorf("%s: %w", out, err)
+	}
+
+	fields := strings.Fields(out)
+	if len(fields) != 3 {
+		return ContainerState{}, fmt.Errorf("unexpected container inspect output: %s", out)
+	}
+	exitCode, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return ContainerState{}, fmt.Errorf("unexpected container exit code: %s", fields[1])
+	}
+
+	return ContainerState{
+		Running:   fields[0] == "true",
+		ExitCode:  exitCode,
+		OOMKilled: fields[2] == "true",
+	}, nil
+}
+
+func (e podmanEngine) ensureNetwork(name string) error {
+	exists, err := e.exists("network", "exists", name)
+	if err != nil {
+		return fmt.Errorf("check network: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	if out, err := command.New(e.binary, "network", "create", name).RunAndReturnTrimmedCombinedOutput(); err != nil {
+		return fmt.Errorf("create network: %s: %w", out, err)
+	}
+	return nil
+}
+
+// exists runs one of podman's exists subcommands, these exit with 1 if the object does not exist.
+func (e podmanEngine) exists(args ...string) (bool, error) {
+	out, err := command.New(e.binary, args...).RunAndReturnTrimmedCombinedOutput()
+	if err == nil {
+		return true, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("%s: %w", out, err)
+}
@@ -0,0 +1,79 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+)
+
+// WaitForReadiness runs the readiness probe of a service container until it succeeds or runs out of retries.
+func WaitForReadiness(runtime Runtime, container *RunningContainer, probe models.ReadinessProbe, logger *Logger) error {
+	retries := probe.GetRetries()
+
+	var err error
+	for attempt := 1; attempt <= retries; attempt++ {
+		if err = runReadinessProbe(runtime, container, probe); err == nil {
+			logger.Infof("✅ Service container (%s) is ready", container.Name)
+			return nil
+		}
+
+		if attempt < retries {
+			logger.Infof("⏳ Waiting for service container (%s) to be ready... (attempt %d/%d: %s)", container.Name, attempt, retries, err)
+			time.Sleep(probe.GetInterval())
+		}
+	}
+
+	logger.Errorf("❌ Service container (%s) is not ready after %d attempts: %s", container.Name, retries, err)
+	return fmt.Errorf("service container (%s) is not ready after %d attempts: %w", container.Name, retries, err)
+}
+
+func runReadinessProbe(runtime Runtime, container *RunningContainer, probe models.ReadinessProbe) error {
+	ctx, cancel := context.WithTimeout(context.Background(), probe.GetTimeout())
+	defer cancel()
+
+	switch {
+	case probe.TCP != "":
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", probe.TCP)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case probe.HTTP != "":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.HTTP, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		if err := resp.Body.Close(); err != nil {
+			return err
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		return nil
+	case probe.Command != "":
+		name, args := runtime.ExecCommand(container.Name, nil)
+		args = append(args, "sh", "-c", probe.Command)
+		out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return fmt.Errorf("command exited with %d: %s", exitErr.ExitCode(), strings.TrimSpace(string(out)))
+			}
+			return err
+		}
+		return nil
+	}
+	return fmt.Errorf("no readiness probe defined")
+}
@@ -0,0 +1,60 @@
+package docker_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/cli/docker"
+	"github.com/bitrise-io/bitrise/v2/cli/docker/dockertest"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForReadiness(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, listener.Close()) }()
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closedListener.Addr().String()
+	require.NoError(t, closedListener.Close())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		probe   models.ReadinessProbe
+		wantErr string
+	}{
+		{name: "tcp port open", probe: models.ReadinessProbe{TCP: listener.Addr().String()}},
+		{name: "tcp port closed", probe: models.ReadinessProbe{TCP: closedAddress, Retries: 2, Interval: 1}, wantErr: "service container (postgres) is not ready after 2 attempts"},
+		{name: "http 2xx", probe: models.ReadinessProbe{HTTP: server.URL + "/health"}},
+		{name: "http 503", probe: models.ReadinessProbe{HTTP: server.URL + "/other", Retries: 1}, wantErr: "service container (postgres) is not ready after 1 attempts: unexpected status code: 503"},
+		{name: "command exits with 0", probe: models.ReadinessProbe{Command: "exit 0"}},
+		{name: "command exits with 1", probe: models.ReadinessProbe{Command: "echo starting && exit 1", Retries: 1}, wantErr: "service container (postgres) is not ready after 1 attempts: command exited with 1: starting"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := dockertest.NewFakeRuntime()
+			container, err := runtime.LoginAndRunContainer(docker.ServiceContainerType, models.Container{Image: "postgres:16"}, "postgres", nil)
+			require.NoError(t, err)
+			logger := docker.NewLogger(log.NewLogger(log.GetGlobalLoggerOpts()), nil)
+
+			err = docker.WaitForReadiness(runtime, container, tt.probe, logger)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
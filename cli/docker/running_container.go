@@ -1,32 +1,22 @@
 package docker
 
-import (
-	"fmt"
-
-	"github.com/bitrise-io/go-utils/command"
-)
-
 type RunningContainer struct {
 	ID   string
 	Name string
-}
 
-func (rc *RunningContainer) Destroy() error {
-	_, err := command.New("docker", "rm", "--force", "--volumes", rc.Name).RunAndReturnTrimmedCombinedOutput()
-	if err != nil {
-		return fmt.Errorf("remove docker container: %w", err)
-	}
-	return nil
+	runtime Runtime
 }
 
-func (rc *RunningContainer) ExecuteCommandArgs(envs []string) []string {
-	args := []string{"exec"}
-
-	for _, env := range envs {
-		args = append(args, "-e", env)
-	}
+// NewRunningContainer returns the handle of a container started by the runtime.
+func NewRunningContainer(id, name string, runtime Runtime) *RunningContainer {
+	return &RunningContainer{ID: id, Name: name, runtime: runtime}
+}
 
-	args = append(args, rc.Name)
+func (rc *RunningContainer) Destroy() error {
+	return rc.runtime.RemoveContainer(rc.Name)
+}
 
-	return args
+// ExecuteCommand returns the command name and args which run a command inside the container.
+func (rc *RunningContainer) ExecuteCommand(envs []string) (string, []string) {
+	return rc.runtime.ExecCommand(rc.Name, envs)
 }
@@ -0,0 +1,19 @@
+package docker_test
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/cli/docker"
+	"github.com/bitrise-io/bitrise/v2/cli/docker/dockertest"
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunningContainer_Destroy(t *testing.T) {
+	runtime := dockertest.NewFakeRuntime()
+	container, err := runtime.LoginAndRunContainer(docker.ServiceContainerType, models.Container{Image: "redis:7"}, "redis", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, container.Destroy())
+	require.Equal(t, []string{"redis"}, runtime.Removed())
+}
@@ -0,0 +1,67 @@
+package docker
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+)
+
+const (
+	RuntimeDocker = "docker"
+	RuntimePodman = "podman"
+
+	// RuntimeEnvKey selects the container runtime, it takes precedence over the agent config.
+	RuntimeEnvKey = "BITRISE_CONTAINER_RUNTIME"
+)
+
+// Runtime runs the execution and service containers of a build.
+type Runtime interface {
+	// Name returns the name of the runtime, for example docker or podman.
+	Name() string
+	LoginAndRunContainer(t ContainerType, containerDef models.Container, containerName string, envs map[string]string) (*RunningContainer, error)
+	// BuildImage builds the image of a container definition with a build, tagged with the given tag.
+	BuildImage(containerDef models.Container, tag string, envs map[string]string) error
+	RemoveContainer(name string) error
+	// ContainerState returns the state of a container, it should be called before the container is removed.
+	ContainerState(name string) (ContainerState, error)
+	// FollowLogs streams the stdout and stderr of a container to the output until the container stops,
+	// the returned wait function blocks until the streaming is finished.
+	FollowLogs(containerName string, output io.Writer) (func() error, error)
+	// ExecCommand returns the command name and args which run a command inside the given container.
+	// The command to run inside the container should be appended to the returned args.
+	ExecCommand(containerName string, envs []string) (string, []string)
+}
+
+// ContainerState is the state of a container.
+type ContainerState struct {
+	Running   bool
+	ExitCode  int
+	OOMKilled bool
+}
+
+// NewRuntime returns the Runtime with the given name.
+func NewRuntime(name string, logger *Logger) (Runtime, error) {
+	switch name {
+	case RuntimeDocker:
+		return NewDockerRuntime(logger), nil
+	case RuntimePodman:
+		return NewPodmanRuntime(logger), nil
+	default:
+		return nil, fmt.Errorf("unknown container runtime: %s, supported runtimes: %s", name, strings.Join([]string{RuntimeDocker, RuntimePodman}, ", "))
+	}
+}
+
+// SelectRuntimeName returns the name of the container runtime to use:
+// the value of BITRISE_CONTAINER_RUNTIME, the runtime set in the agent config or docker by default.
+func SelectRuntimeName(configuredRuntime string) string {
+	if name := strings.TrimSpace(os.Getenv(RuntimeEnvKey)); name != "" {
+		return name
+	}
+	if name := strings.TrimSpace(configuredRuntime); name != "" {
+		return name
+	}
+	return RuntimeDocker
+}
@@ -0,0 +1,43 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectRuntimeName(t *testing.T) {
+	tests := []struct {
+		name              string
+		env               string
+		configuredRuntime string
+		want              string
+	}{
+		{name: "defaults to docker", want: RuntimeDocker},
+		{name: "agent config", configuredRuntime: RuntimePodman, want: RuntimePodman},
+		{name: "env takes precedence over agent config", env: RuntimeDocker, configuredRuntime: RuntimePodman, want: RuntimeDocker},
+		{name: "env", env: RuntimePodman, want: RuntimePodman},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(RuntimeEnvKey, tt.env)
+			require.Equal(t, tt.want, SelectRuntimeName(tt.configuredRuntime))
+		})
+	}
+}
+
+func TestNewRuntime(t *testing.T) {
+	logger := NewLogger(log.NewLogger(log.GetGlobalLoggerOpts()), nil)
+
+	runtime, err := NewRuntime(RuntimePodman, logger)
+	require.NoError(t, err)
+	require.Equal(t, RuntimePodman, runtime.Name())
+
+	name, args := runtime.ExecCommand("bitrise-workflow", []string{"A=b"})
+	require.Equal(t, "podman", name)
+	require.Equal(t, []string{"exec", "-e", "A=b", "bitrise-workflow"}, args)
+
+	_, err = NewRuntime("containerd", logger)
+	require.EqualError(t, err, "unknown container runtime: containerd, supported runtimes: docker, podman")
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/configfmt"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/spf13/cobra"
+)
+
+var fmtCommand = &cobra.Command{
+	Use:   "fmt",
+	Short: "Formats bitrise.yml files.",
+	Long: `Formats bitrise.yml files to a canonical layout:
+- the keys of the config's objects are ordered as defined by the config format
+- step references drop the StepLib source if it is the config's default_step_lib_source
+- options of the app and workflow envs which are set to their default value are removed
+
+Comments and anchors are preserved, formatting a formatted config leaves it unchanged.
+By default, the command formats the bitrise.yml in the current directory, custom paths can be specified as arguments.
+Use the --check flag in CI to fail if a config is not formatted, without modifying it.`,
+	RunE: formatConfigs,
+}
+
+func init() {
+	fmtCommand.Flags().Bool("check", false, "Only check if the configs are formatted, exit with a non-zero status if any of them is not.")
+}
+
+func formatConfigs(cmd *cobra.Command, args []string) error {
+	logCommandParameters(cmd)
+
+	configPths := args
+	if len(configPths) == 0 {
+		configPths = []string{"bitrise.yml"}
+	}
+	check, _ := cmd.Flags().GetBool("check")
+
+	var unformatted []string
+	for _, configPth := range configPths {
+		formatted, err := formatConfig(configPth, check)
+		if err != nil {
+			return err
+		}
+		if !formatted {
+			unformatted = append(unformatted, configPth)
+		}
+	}
+
+	if check && len(unformatted) > 0 {
+		return errors.New("configs are not formatted, run bitrise fmt to format them: " + strings.Join(unformatted, ", "))
+	}
+
+	return nil
+}
+
+// formatConfig formats the given config file and returns true if it was already formatted.
+// In check mode, the config file is not modified.
+func formatConfig(configPth string, check bool) (bool, error) {
+	content, err := os.ReadFile(configPth)
+	if err != nil {
+		return false, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	result, err := configfmt.Format(content)
+	if err != nil {
+		return false, fmt.Errorf("failed to format %s: %w", configPth, err)
+	}
+	for _, warning := range result.Warnings {
+		log.Warnf("%s: %s", configPth, warning)
+	}
+
+	if result.IsFormatted(content) {
+		log.Donef("%s is formatted", configPth)
+		return true, nil
+	}
+
+	if check {
+		log.Warnf("%s is not formatted", configPth)
+		return false, nil
+	}
+
+	if err := fileutil.WriteBytesToFile(configPth, result.Content); err != nil {
+		return false, fmt.Errorf("failed to write formatted config: %w", err)
+	}
+	log.Donef("Formatted %s", configPth)
+
+	return false, nil
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bitrise-io/bitrise/v2/configlint"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/version"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/spf13/cobra"
+)
+
+const (
+	lintFormatConsole = "console"
+	lintFormatJSON    = "json"
+	lintFormatSARIF   = "sarif"
+)
+
+var lintCommand = &cobra.Command{
+	Use:   "lint",
+	Short: "Checks a bitrise.yml for likely mistakes and bad practices.",
+	Long: fmt.Sprintf(`Checks a bitrise.yml for likely mistakes and bad practices, which don't make the config invalid.
+Every problem is reported by a rule with an ID and a severity (error, warning or info), use --list-rules to list the rules.
+
+Rules can be suppressed with comments:
+- on a single line: a '# %s rule-id' comment on the reported line or in the line above it
+- in the whole config: a '# %s rule-id' comment anywhere in the config
+Multiple rule IDs can be listed, without a rule ID every rule is suppressed.
+
+The problems can be printed to the console, or written as JSON or SARIF (to annotate pull requests).
+By default, the command lints the bitrise.yml in the current directory, custom path can be specified as an argument.`,
+		configlint.DisableDirective, configlint.DisableFileDirective),
+	RunE: lintConfig,
+}
+
+func init() {
+	lintCommand.Flags().String("format", lintFormatConsole, "Output format. Accepted: console, json, sarif.")
+	lintCommand.Flags().StringP("output", "o", "", "Write the report to the given file instead of the standard output.")
+	lintCommand.Flags().StringSlice("disable", nil, "IDs of the rules to disable.")
+	lintCommand.Flags().String("fail-on", string(configlint.SeverityError), "Exit with a non-zero status if a problem with the given or higher severity is found. Accepted: error, warning, info.")
+	lintCommand.Flags().Bool("list-rules", false, "List the available rules.")
+}
+
+func lintConfig(cmd *cobra.Command, args []string) error {
+	logCommandParameters(cmd)
+
+	rules := configlint.DefaultRules()
+	if listRules, _ := cmd.Flags().GetBool("list-rules"); listRules {
+		for _, rule := range rules {
+			log.Printf("%s (%s): %s", rule.ID, rule.Severity, rule.Description)
+		}
+		return nil
+	}
+
+	configPth := "bitrise.yml"
+	if len(args) > 0 {
+		configPth = args[0]
+	}
+	format, _ := cmd.Flags().GetString("format")
+	outputPth, _ := cmd.Flags().GetString("output")
+	disabled, _ := cmd.Flags().GetStringSlice("disable")
+	failOnName, _ := cmd.Flags().GetString("fail-on")
+
+	failOn, err := configlint.ParseSeverity(failOnName)
+	if err != nil {
+		return err
+	}
+	rules, err = configlint.FilterRules(rules, disabled)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(configPth)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	result, err := configlint.Lint(content, rules)
+	if err != nil {
+		return fmt.Errorf("failed to lint %s: %w", configPth, err)
+	}
+
+	var report []byte
+	switch format {
+	case lintFormatConsole:
+		if outputPth == "" {
+			printLintProblems(configPth, result)
+		} else {
+			for _, problem := range result.Problems {
+				report = append(report, problem.String(configPth)+"\n"...)
+			}
+		}
+	case lintFormatJSON:
+		report, err = result.JSON()
+	case lintFormatSARIF:
+		report, err = result.SARIF(rules, filepath.ToSlash(configPth), version.VERSION)
+	default:
+		return fmt.Errorf("invalid format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+
+	if outputPth != "" {
+		if err := fileutil.WriteBytesToFile(outputPth, report); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	} else if report != nil {
+		if _, err := os.Stdout.Write(append(report, '\n')); err != nil {
+			return err
+		}
+	}
+
+	if result.HasProblems(failOn) {
+		return errors.New("lint problems found")
+	}
+	return nil
+}
+
+func printLintProblems(configPth string, result configlint.Result) {
+	if len(result.Problems) == 0 {
+		log.Donef("No problems found in %s", configPth)
+		return
+	}
+
+	for _, problem := range result.Problems {
+		switch problem.Severity {
+		case configlint.SeverityError:
+			log.Errorf("%s", problem.String(configPth))
+		case configlint.SeverityWarning:
+			log.Warnf("%s", problem.String(configPth))
+		default:
+			log.Printf("%s", problem.String(configPth))
+		}
+	}
+}
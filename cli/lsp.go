@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/lsp"
+	"github.com/spf13/cobra"
+)
+
+var lspCommand = &cobra.Command{
+	Use:   "lsp",
+	Short: "Starts a Language Server Protocol server for bitrise.yml files.",
+	Long: `Starts a Language Server Protocol server for bitrise.yml files.
+
+The server communicates over stdin and stdout, it is meant to be started by an editor.
+It provides diagnostics, completion of workflow, step bundle, container and step input references,
+go-to-definition and hover documentation. Step information is read from the local steplib cache.`,
+	RunE: runLSP,
+}
+
+func runLSP(cmd *cobra.Command, _ []string) error {
+	logCommandParameters(cmd)
+
+	// stdout is reserved for the protocol messages, logs are written to stderr.
+	opts := log.GetGlobalLoggerOpts()
+	opts.Writer = os.Stderr
+	log.InitGlobalLogger(opts)
+
+	server := lsp.NewServer(os.Stdin, os.Stdout, log.NewLogger(opts), lsp.NewLocalStepLibProvider())
+	if err := server.Serve(); err != nil {
+		return fmt.Errorf("language server: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bitrise-io/bitrise/v2/configmigrate"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/spf13/cobra"
+)
+
+var migrateCommand = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrates a bitrise.yml to the latest format version.",
+	Long: fmt.Sprintf(`Migrates a bitrise.yml to the latest format version (%s).
+
+Deprecated constructs are rewritten to their current equivalents:
+- with groups are replaced by their steps, with the group's container and services set as the steps' execution_container and service_containers
+- services are moved to the containers map as service type containers
+- trigger_map items with the pattern and is_pull_request_allowed keys are converted to push_branch and pull_request_source_branch items
+
+Comments and key order are preserved. Only the given file is migrated, config modules included by it are not.
+By default, the command migrates the bitrise.yml in the current directory, custom path can be specified as an argument.`, models.FormatVersion),
+	RunE: migrateConfig,
+}
+
+func init() {
+	migrateCommand.Flags().Bool("dry-run", false, "Print the migrated config instead of overwriting the config file.")
+}
+
+func migrateConfig(cmd *cobra.Command, args []string) error {
+	logCommandParameters(cmd)
+
+	configPth := "bitrise.yml"
+	if len(args) > 0 {
+		configPth = args[0]
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	content, err := os.ReadFile(configPth)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	result, err := configmigrate.Migrate(content)
+	if err != nil {
+		return fmt.Errorf("failed to migrate config: %w", err)
+	}
+
+	if !result.HasChanges() {
+		log.Donef("%s already uses the latest format version (%s)", configPth, models.FormatVersion)
+		return nil
+	}
+
+	log.Infof("Changes:")
+	for _, change := range result.Changes {
+		log.Printf("- %s", change)
+	}
+
+	if dryRun {
+		log.Print()
+		log.Print(string(result.Content))
+		return nil
+	}
+
+	if err := fileutil.WriteBytesToFile(configPth, result.Content); err != nil {
+		return fmt.Errorf("failed to write migrated config: %w", err)
+	}
+	log.Donef("Migrated %s to format version %s", configPth, models.FormatVersion)
+
+	return nil
+}
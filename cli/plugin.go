@@ -21,6 +21,8 @@ func init() {
 		pluginDeleteCommand,
 		pluginInfoCommand,
 		pluginListCommand,
+		pluginLockCommand,
+		pluginSearchCommand,
 	)
 }
 
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/plugins"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+)
+
+// triggerPluginEvent delivers the event to the plugins registered for it and returns the replies of the persistent plugins,
+// a failing plugin is reported but it does not fail the build.
+func (r WorkflowRunner) triggerPluginEvent(name plugins.TriggerEventName, payload interface{}) plugins.EventResponse {
+	response, err := r.pluginSession.TriggerEvent(name, payload)
+	if err != nil {
+		log.Warnf("Failed to trigger %s: %s", name, err)
+	}
+
+	for _, annotation := range response.Annotations {
+		switch annotation.Level {
+		case "error":
+			log.Errorf("[%s] %s", annotation.Plugin, annotation.Message)
+		case "warning":
+			log.Warnf("[%s] %s", annotation.Plugin, annotation.Message)
+		default:
+			log.Infof("[%s] %s", annotation.Plugin, annotation.Message)
+		}
+	}
+
+	return response
+}
+
+// pluginEnvironments returns the envs set by the plugins in their reply.
+func pluginEnvironments(response plugins.EventResponse) []envmanModels.EnvironmentItemModel {
+	keys := make([]string, 0, len(response.Envs))
+	for key := range response.Envs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var environments []envmanModels.EnvironmentItemModel
+	for _, key := range keys {
+		environments = append(environments, envmanModels.EnvironmentItemModel{key: response.Envs[key]})
+	}
+	return environments
+}
@@ -3,15 +3,20 @@ package cli
 import (
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/models"
 	"github.com/bitrise-io/bitrise/v2/plugins"
 	"github.com/spf13/cobra"
 )
 
 var pluginInstallCommand = &cobra.Command{
-	Use:   "install <plugin_source_remote_or_local_url>",
+	Use:   "install <plugin_source_remote_or_local_url | plugin_name>",
 	Short: "Install bitrise plugin.",
+	Long: `Install bitrise plugin from a remote or local source, or by its name in the plugin index.
+
+See ` + "`bitrise plugin search --help`" + ` about the plugin index.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logCommandParameters(cmd)
 
@@ -26,6 +31,8 @@ var pluginInstallCommand = &cobra.Command{
 func init() {
 	pluginInstallCommand.Flags().String("version", "", "Plugin version tag.")
 	pluginInstallCommand.Flags().String("source", "", "Deprecated!!! Specify as arg instead - Plugin source url (can be local path or remote url).")
+	pluginInstallCommand.Flags().String(pluginIndexKey, "", "Plugin index location used to install a plugin by name (file, directory, git repository or URL).")
+	pluginInstallCommand.Flags().String(pluginLockfileKey, "", "Install the plugins pinned in the lockfile (created by `bitrise plugin lock`).")
 }
 
 func pluginInstall(cmd *cobra.Command, args []string) error {
@@ -39,10 +46,27 @@ func pluginInstall(cmd *cobra.Command, args []string) error {
 
 	pluginVersionTag, _ := cmd.Flags().GetString("version")
 
+	if lockfilePth, _ := cmd.Flags().GetString(pluginLockfileKey); lockfilePth != "" {
+		if pluginSource != "" {
+			return fmt.Errorf("plugin source and lockfile can't be defined at the same time")
+		}
+		return pluginInstallFromLockfile(lockfilePth)
+	}
+
 	if pluginSource == "" {
 		showSubcommandHelp(cmd)
 		return fmt.Errorf("plugin source not defined")
 	}
+
+	if plugins.IsPluginName(pluginSource) {
+		indexLocation, _ := cmd.Flags().GetString(pluginIndexKey)
+		source, err := resolvePluginSourceFromIndex(indexLocation, pluginSource)
+		if err != nil {
+			return err
+		}
+		log.Infof("Plugin (%s) found in the plugin index: %s", pluginSource, source)
+		pluginSource = source
+	}
 	// ---
 
 	// Install
@@ -69,3 +93,55 @@ func pluginInstall(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func pluginInstallFromLockfile(lockfilePth string) error {
+	lockfile, err := plugins.ReadLockfile(lockfilePth)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range lockfile.Names() {
+		locked := lockfile.Plugins[name]
+		log.Infof("Installing plugin (%s) from lockfile", name)
+
+		if _, err := plugins.InstallLockedPlugin(name, locked); err != nil {
+			return fmt.Errorf("failed to install plugin (%s), error: %s", name, err)
+		}
+
+		if locked.Version == "" {
+			log.Donef("Local plugin (%s) installed ", name)
+		} else {
+			log.Donef("Plugin (%s) with version (%s) installed ", name, locked.Version)
+		}
+	}
+
+	return nil
+}
+
+// installRequiredPlugins installs and validates the plugins declared in the plugins section of the config.
+func installRequiredPlugins(required map[string]models.PluginModel) error {
+	if len(required) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(required))
+	for name := range required {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		plugin, installed, err := plugins.EnsureRequiredPlugin(name, required[name])
+		if err != nil {
+			return fmt.Errorf("plugin (%s): %w", name, err)
+		}
+
+		if installed {
+			log.Donef("Plugin (%s) installed", plugin.Name)
+		} else {
+			log.Debugf("Plugin (%s) already installed", plugin.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/plugins"
+	"github.com/spf13/cobra"
+)
+
+const pluginLockfileKey = "lockfile"
+
+var pluginLockCommand = &cobra.Command{
+	Use:   "lock",
+	Short: "Write the versions and checksums of the installed plugins into a lockfile.",
+	Long: `Write the versions and checksums of the installed plugins into a lockfile.
+
+The same plugins can be installed on another machine with:
+  bitrise plugin install --lockfile <lockfile>`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		logCommandParameters(cmd)
+
+		if err := pluginLock(cmd); err != nil {
+			log.Errorf("Plugin lock failed, error: %s", err)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func init() {
+	pluginLockCommand.Flags().String(pluginLockfileKey, plugins.DefaultLockfileName, "Path of the lockfile.")
+}
+
+func pluginLock(cmd *cobra.Command) error {
+	lockfilePth, _ := cmd.Flags().GetString(pluginLockfileKey)
+
+	lockfile, err := plugins.NewLockfileFromInstalledPlugins()
+	if err != nil {
+		return fmt.Errorf("failed to read installed plugins, error: %s", err)
+	}
+
+	if len(lockfile.Plugins) == 0 {
+		log.Warnf("No installed plugin found")
+	}
+
+	for _, name := range lockfile.Names() {
+		if lockfile.Plugins[name].ExecutableSHA256 == "" {
+			log.Warnf("Plugin (%s) was installed without a recorded checksum, reinstall it to lock its executable", name)
+		}
+	}
+
+	if err := lockfile.Write(lockfilePth); err != nil {
+		return fmt.Errorf("failed to write lockfile (%s), error: %s", lockfilePth, err)
+	}
+
+	log.Donef("%d plugin(s) locked in %s", len(lockfile.Plugins), lockfilePth)
+	return nil
+}
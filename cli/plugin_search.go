@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/bitrise"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/output"
+	"github.com/bitrise-io/bitrise/v2/plugins"
+	"github.com/spf13/cobra"
+)
+
+const pluginIndexKey = "index"
+
+var pluginSearchCommand = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search the plugin index.",
+	Long: `Search the plugin index by plugin name and description.
+
+The plugin index is set with the --index flag or the BITRISE_PLUGIN_INDEX env var, it can be a local YAML or JSON file,
+a directory or git repository with an index.yml in its root, or a YAML or JSON file served over HTTP(S).
+Without an index only the official plugins are listed.
+
+The plugins found can be installed with:
+  bitrise plugin install <name>`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logCommandParameters(cmd)
+
+		if err := pluginSearch(cmd, args); err != nil {
+			log.Errorf("Plugin search failed, error: %s", err)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func init() {
+	pluginSearchCommand.Flags().String(pluginIndexKey, "", "Plugin index location (file, directory, git repository or URL).")
+	pluginSearchCommand.Flags().String(output.FormatKey, "", "Output format. Accepted: raw, json.")
+}
+
+func pluginSearch(cmd *cobra.Command, args []string) error {
+	// Input validation
+	format, _ := cmd.Flags().GetString(output.FormatKey)
+	if format == "" {
+		format = output.FormatRaw
+	}
+	if format != output.FormatRaw && format != output.FormatJSON {
+		showSubcommandHelp(cmd)
+		return fmt.Errorf("invalid format: %s", format)
+	}
+
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+	}
+
+	var logger Logger
+	logger = NewDefaultRawLogger()
+	if format == output.FormatJSON {
+		logger = NewDefaultJSONLogger()
+	}
+	// ---
+
+	indexLocation, _ := cmd.Flags().GetString(pluginIndexKey)
+	index, err := loadPluginIndex(indexLocation)
+	if err != nil {
+		return err
+	}
+
+	results := index.Search(query)
+	if len(results) == 0 && format == output.FormatRaw {
+		log.Warnf("No plugin found")
+		return nil
+	}
+
+	logger.Print(results)
+
+	return nil
+}
+
+// loadPluginIndex reads the plugin index from the given location, the BITRISE_PLUGIN_INDEX env var
+// or falls back to the index of the official plugins.
+func loadPluginIndex(location string) (plugins.PluginIndex, error) {
+	if location == "" {
+		location = os.Getenv(plugins.PluginIndexEnvKey)
+	}
+	if location == "" {
+		return defaultPluginIndex(), nil
+	}
+	return plugins.ReadPluginIndex(location)
+}
+
+func defaultPluginIndex() plugins.PluginIndex {
+	index := plugins.PluginIndex{FormatVersion: "1", Plugins: map[string]plugins.IndexedPlugin{}}
+	for name, dependency := range bitrise.PluginDependencyMap {
+		index.Plugins[name] = plugins.IndexedPlugin{Name: name, Source: dependency.Source}
+	}
+	return index
+}
+
+// resolvePluginSourceFromIndex returns the source of the plugin listed in the index with the given name.
+func resolvePluginSourceFromIndex(indexLocation, name string) (string, error) {
+	index, err := loadPluginIndex(indexLocation)
+	if err != nil {
+		return "", err
+	}
+
+	plugin, ok := index.Lookup(name)
+	if !ok {
+		return "", fmt.Errorf("plugin (%s) not found in the plugin index, use `bitrise plugin search` to list the available plugins", name)
+	}
+	if !plugin.IsSupportedOnCurrentPlatform() {
+		return "", fmt.Errorf("plugin (%s) is only available for: %s", name, strings.Join(plugin.Platforms(), ", "))
+	}
+	return plugin.Source, nil
+}
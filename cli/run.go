@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"sort"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -38,6 +39,7 @@ const (
 
 	depManagerBrew      = "brew"
 	secretFilteringFlag = "secret-filtering"
+	updateToolsFlag     = "update-tools"
 )
 
 var errWorkflowNotSpecified = errors.New("workflow not specified")
@@ -49,6 +51,8 @@ type RunConfig struct {
 	Config   models.BitriseDataModel
 	Workflow string
 	Secrets  []envmanModels.EnvironmentItemModel
+	// UpdateTools ignores the tools lockfile, the requested tool versions are resolved again
+	UpdateTools bool
 }
 
 var runCommand = &cobra.Command{
@@ -72,6 +76,7 @@ func init() {
 
 	addJSONParamsFlags(flags)
 	flags.String(OutputFormatKey, "", "Log format. Available values: json, console")
+	flags.Bool(updateToolsFlag, false, "Ignore the tools lockfile and resolve the requested tool versions again.")
 
 	flags.String(ConfigBase64Key, "", "base64 encoded config data.")
 	flags.String(InventoryBase64Key, "", "base64 encoded inventory data.")
@@ -112,15 +117,33 @@ func run(cmd *cobra.Command, args []string) error {
 		agentConfig = nil
 	}
 
-	runner := NewWorkflowRunner(*config, agentConfig, globalTracker)
+	runner, err := NewWorkflowRunner(*config, agentConfig, globalTracker)
+	if err != nil {
+		failf("Failed to create workflow runner: %s", err)
+	}
 
 	go func() {
-		<-signalInterruptChan
+		sig := <-signalInterruptChan
 		shouldWaitForCleanup = true
 		log.Info("Cancelling bitrise run...")
-		if err := runner.containerManager.DestroyAllContainers(); err != nil {
-			log.Warnf("Failed to destroy all containers: %s", err)
-		}
+
+		// The containers are removed while the plugins handle the abort event, a slow plugin must not delay
+		// the cleanup until the process gets killed.
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := runner.containerManager.DestroyAllContainers(); err != nil {
+				log.Warnf("Failed to destroy all containers: %s", err)
+			}
+		}()
+		runner.triggerPluginEvent(plugins.BuildWillAbort, models.BuildAbortModel{
+			EventName:  string(plugins.BuildWillAbort),
+			WorkflowID: config.Workflow,
+			Signal:     sig.String(),
+			AbortTime:  time.Now(),
+		})
+		wg.Wait()
 		cleanupSynchronCancelFunc()
 	}()
 
@@ -179,9 +202,12 @@ type WorkflowRunner struct {
 	// agentConfig is only non-nil if the CLI is configured to run in agent mode
 	agentConfig      *configs.AgentConfig
 	containerManager *containermanager.Manager
+	pluginSession    *plugins.Session
+	// toolsLockfile pins the versions of the tools declared by steps, set when the workflows start
+	toolsLockfile *toolprovider.ToolsLockfile
 }
 
-func NewWorkflowRunner(config RunConfig, agentConfig *configs.AgentConfig, tracker analytics.Tracker) WorkflowRunner {
+func NewWorkflowRunner(config RunConfig, agentConfig *configs.AgentConfig, tracker analytics.Tracker) (WorkflowRunner, error) {
 	_, stepSecretValues := tools.GetSecretKeysAndValues(config.Secrets)
 	loggerOpts := log.GetGlobalLoggerOpts()
 	if isContainerDebugLoggingEnabled(config.Secrets) {
@@ -189,8 +215,14 @@ func NewWorkflowRunner(config RunConfig, agentConfig *configs.AgentConfig, track
 	}
 	logger := log.NewLogger(loggerOpts)
 	dockerLogger := docker.NewLogger(logger, stepSecretValues)
-	dockerManager := docker.NewContainerManager(dockerLogger)
-	containerManager := containermanager.NewManager(config.Config.Containers, config.Config.Services, dockerManager, dockerLogger)
+	containerRuntime, err := newContainerRuntime(agentConfig, dockerLogger)
+	if err != nil {
+		return WorkflowRunner{}, err
+	}
+	containerManager := containermanager.NewManager(config.Config.Containers, config.Config.Services, containerRuntime, dockerLogger)
+	if isContainerLogStreamingEnabled(config.Secrets) {
+		containerManager.EnableLogStreaming()
+	}
 
 	return WorkflowRunner{
 		logger:           logger,
@@ -198,7 +230,17 @@ func NewWorkflowRunner(config RunConfig, agentConfig *configs.AgentConfig, track
 		tracker:          tracker,
 		containerManager: containerManager,
 		agentConfig:      agentConfig,
+		pluginSession:    plugins.NewSession(),
+	}, nil
+}
+
+func newContainerRuntime(agentConfig *configs.AgentConfig, logger *docker.Logger) (docker.Runtime, error) {
+	configuredRuntime := ""
+	if agentConfig != nil {
+		configuredRuntime = agentConfig.Containers.Runtime
 	}
+
+	return docker.NewRuntime(docker.SelectRuntimeName(configuredRuntime), logger)
 }
 
 func (r WorkflowRunner) RunWorkflowsWithSetupAndCheckForUpdate() (int, error) {
@@ -299,15 +341,24 @@ func (r WorkflowRunner) runWorkflows() (models.BuildRunResultsModel, error) {
 		}
 	}
 
+	if err := installRequiredPlugins(r.config.Config.Plugins); err != nil {
+		return models.BuildRunResultsModel{}, fmt.Errorf("failed to install required plugins: %w", err)
+	}
+
+	defer func() {
+		if err := r.pluginSession.Close(); err != nil {
+			log.Warnf("Failed to shut down plugins: %s", err)
+		}
+	}()
+
 	// Trigger WillStartRun
 	buildRunStartModel := models.BuildRunStartModel{
 		EventName:   string(plugins.WillStartRun),
 		StartTime:   startTime,
 		ProjectType: r.config.Config.ProjectType,
 	}
-	if err := plugins.TriggerEvent(plugins.WillStartRun, buildRunStartModel); err != nil {
-		log.Warnf("Failed to trigger WillStartRun: %s", err)
-	}
+	startResponse := r.triggerPluginEvent(plugins.WillStartRun, buildRunStartModel)
+	environments = append(environments, pluginEnvironments(startResponse)...)
 
 	// Prepare workflow run parameters
 	buildRunResults := models.NewBuildRunResultsModel(r.config.Workflow, startTime, r.config.Config.ProjectType)
@@ -337,6 +388,15 @@ func (r WorkflowRunner) runWorkflows() (models.BuildRunResultsModel, error) {
 		log.Print()
 	}
 
+	var toolsLockfile *toolprovider.ToolsLockfile
+	if !r.config.UpdateTools {
+		toolsLockfile, err = toolprovider.ReadToolsLockfile(toolprovider.DefaultToolsLockfileName)
+		if err != nil {
+			return models.BuildRunResultsModel{}, fmt.Errorf("set up tools: %w", err)
+		}
+	}
+	r.toolsLockfile = toolsLockfile
+
 	// Run workflows
 	for i, workflowRunPlan := range plan.ExecutionPlan {
 		bitrise.PrintRunningWorkflow(workflowRunPlan.WorkflowTitle)
@@ -346,23 +406,34 @@ func (r WorkflowRunner) runWorkflows() (models.BuildRunResultsModel, error) {
 		environments = append(environments, workflowToRun.Environments...)
 
 		// Toolprovider entrypoint
-		toolEnvs, err := toolprovider.RunDeclarativeSetup(r.config.Config, r.tracker, r.config.Modes.CIMode, workflowRunPlan.WorkflowID, false, nil, nil, envsToMap(environments))
+		toolSetupStartTime := time.Now()
+		toolEnvs, err := toolprovider.RunDeclarativeSetup(r.config.Config, r.tracker, r.config.Modes.CIMode, workflowRunPlan.WorkflowID, false, nil, nil, envsToMap(environments), toolsLockfile)
+		toolSetupResult := models.ToolSetupFinishModel{
+			EventName:  string(plugins.ToolSetupDidFinish),
+			WorkflowID: workflowRunPlan.WorkflowID,
+			RunTime:    time.Since(toolSetupStartTime),
+		}
+		if err != nil {
+			toolSetupResult.ErrorStr = err.Error()
+		}
+		toolSetupResponse := r.triggerPluginEvent(plugins.ToolSetupDidFinish, toolSetupResult)
 		if err != nil {
 			return models.BuildRunResultsModel{}, fmt.Errorf("set up tools: %w", err)
 		}
 		environments = append(environments, toolprovider.ConvertToEnvmanEnvs(toolEnvs)...)
+		environments = append(environments, pluginEnvironments(toolSetupResponse)...)
 
 		buildRunResults = r.runWorkflow(workflowRunPlan, r.config.Config.DefaultStepLibSource, buildRunResults, &environments, r.config.Secrets, isLastWorkflow, buildIDProperties)
 	}
 
 	// Build finished
+	buildRunResults.ContainerResults = r.containerManager.ContainerResults()
+	buildRunResults.Annotations = r.pluginSession.Annotations()
 	bitrise.PrintSummary(buildRunResults)
 
-	// Trigger WorkflowRunDidFinish
+	// Trigger DidFinishRun
 	buildRunResults.EventName = string(plugins.DidFinishRun)
-	if err := plugins.TriggerEvent(plugins.DidFinishRun, buildRunResults); err != nil {
-		log.Warnf("Failed to trigger WorkflowRunDidFinish: %s", err)
-	}
+	r.triggerPluginEvent(plugins.DidFinishRun, buildRunResults)
 
 	return buildRunResults, nil
 }
@@ -387,6 +458,8 @@ func processArgs(cmd *cobra.Command, args []string) (*RunConfig, error) {
 	jsonParams, _ := cmd.Flags().GetString(JSONParamsKey)
 	jsonParamsBase64, _ := cmd.Flags().GetString(JSONParamsBase64Key)
 
+	updateTools, _ := cmd.Flags().GetBool(updateToolsFlag)
+
 	runParams, err := parseRunParams(
 		workflowToRunID,
 		bitriseConfigPath, bitriseConfigBase64Data,
@@ -449,9 +522,10 @@ func processArgs(cmd *cobra.Command, args []string) (*RunConfig, error) {
 			SecretEnvsFilteringMode: enabledEnvsFiltering,
 			IsSteplibOfflineMode:    isSteplibOfflineMode,
 		},
-		Config:   bitriseConfig,
-		Workflow: runParams.WorkflowToRunID,
-		Secrets:  inventoryEnvironments,
+		Config:      bitriseConfig,
+		Workflow:    runParams.WorkflowToRunID,
+		Secrets:     inventoryEnvironments,
+		UpdateTools: updateTools,
 	}, nil
 }
 
@@ -543,13 +617,21 @@ func createWorkflowRunStatusMessage(exitCode int) string {
 }
 
 func isContainerDebugLoggingEnabled(Secrets []envmanModels.EnvironmentItemModel) bool {
+	return isSecretEnabled(Secrets, "ENABLE_CONTAINER_DEBUG_LOGGING")
+}
+
+func isContainerLogStreamingEnabled(Secrets []envmanModels.EnvironmentItemModel) bool {
+	return isSecretEnabled(Secrets, "ENABLE_CONTAINER_LOG_STREAMING")
+}
+
+func isSecretEnabled(Secrets []envmanModels.EnvironmentItemModel, key string) bool {
 	for _, secret := range Secrets {
 		k, v, err := secret.GetKeyValuePair()
 		if err != nil {
 			continue
 		}
 
-		if k == "ENABLE_CONTAINER_DEBUG_LOGGING" && v == "true" {
+		if k == key && v == "true" {
 			return true
 		}
 	}
@@ -12,6 +12,9 @@ import (
 
 	cliAnalytics "github.com/bitrise-io/bitrise/v2/analytics"
 	"github.com/bitrise-io/bitrise/v2/bitrise"
+	"github.com/bitrise-io/bitrise/v2/cli/containermanager"
+	"github.com/bitrise-io/bitrise/v2/cli/docker"
+	"github.com/bitrise-io/bitrise/v2/cli/docker/dockertest"
 	"github.com/bitrise-io/bitrise/v2/configs"
 	"github.com/bitrise-io/bitrise/v2/log"
 	"github.com/bitrise-io/bitrise/v2/models"
@@ -60,7 +63,8 @@ workflows:
 		require.NoError(t, configs.InitPaths())
 
 		runConfig := RunConfig{Config: config, Workflow: "skip_if_empty"}
-		runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+		runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+		require.NoError(t, err)
 		buildRunResults, err := runner.runWorkflows()
 		require.NoError(t, err)
 		require.Equal(t, 1, len(buildRunResults.SuccessSteps))
@@ -101,7 +105,8 @@ workflows:
 		require.NoError(t, configs.InitPaths())
 
 		runConfig := RunConfig{Config: config, Workflow: "skip_if_empty"}
-		runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+		runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+		require.NoError(t, err)
 		buildRunResults, err := runner.runWorkflows()
 		require.NoError(t, err)
 		require.Equal(t, 1, len(buildRunResults.SuccessSteps))
@@ -161,7 +166,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "test"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 4, len(buildRunResults.SuccessSteps))
@@ -223,7 +229,8 @@ step_bundles:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "test", Secrets: nil}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 3, len(buildRunResults.SuccessSteps)) // script; run_if_test_1.script; run_if_test_3.script
@@ -269,7 +276,8 @@ step_bundles:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "test", Secrets: nil}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 2, len(buildRunResults.SuccessSteps)) // both bundle steps run; the flipped FLAG must not re-gate the second
@@ -321,7 +329,8 @@ workflows:
 			require.NoError(t, configs.InitPaths())
 
 			runConfig := RunConfig{Config: config, Workflow: "test"}
-			runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+			runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+			require.NoError(t, err)
 			buildRunResults, _ := runner.runWorkflows()
 			require.Equal(t, tt.wantSkip, len(buildRunResults.SkippedSteps) > 0)
 			require.Equal(t, tt.wantFailed, len(buildRunResults.FailedSteps) > 0)
@@ -329,6 +338,82 @@ workflows:
 	}
 }
 
+// TestServiceNotReady verifies that a step whose service container is not ready is skipped when its
+// run_if or the previous step failures would skip it anyway, and that it fails the build only if it is not skippable.
+func TestServiceNotReady(t *testing.T) {
+	stepDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(stepDir, "step.yml"), []byte("title: Test step\n"), 0644))
+
+	tests := []struct {
+		name              string
+		stepProperties    string
+		wantStatus        models.StepRunStatus
+		wantBuildFailed   bool
+		wantFailedSteps   int
+		wantSkippedSteps  int
+		wantFailedSkipped int
+	}{
+		{
+			name:             "run_if false skips the step",
+			stepProperties:   `run_if: "false"`,
+			wantStatus:       models.StepRunStatusCodeSkippedWithRunIf,
+			wantSkippedSteps: 1,
+		},
+		{
+			name:              "skippable step does not fail the build",
+			stepProperties:    "is_skippable: true",
+			wantStatus:        models.StepRunStatusCodeFailedSkippable,
+			wantFailedSkipped: 1,
+		},
+		{
+			name:            "step fails the build",
+			stepProperties:  "title: Test step",
+			wantStatus:      models.StepRunStatusCodeServiceNotReady,
+			wantBuildFailed: true,
+			wantFailedSteps: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configStr := fmt.Sprintf(`
+format_version: "17"
+default_step_lib_source: "https://github.com/bitrise-io/bitrise-steplib.git"
+containers:
+  postgres:
+    type: service
+    image: postgres:16
+    readiness:
+      command: exit 1
+      retries: 1
+workflows:
+  test:
+    steps:
+    - path::%s:
+        service_containers:
+        - postgres
+        %s
+`, stepDir, tt.stepProperties)
+			config, warnings, err := bitrise.ConfigModelFromYAMLBytes([]byte(configStr))
+			require.NoError(t, err)
+			require.Empty(t, warnings)
+			require.NoError(t, configs.InitPaths())
+
+			runner, err := NewWorkflowRunner(RunConfig{Config: config, Workflow: "test"}, nil, noOpTracker{})
+			require.NoError(t, err)
+			runner.containerManager = containermanager.NewManager(config.Containers, config.Services, dockertest.NewFakeRuntime(), docker.NewLogger(log.NewLogger(log.GetGlobalLoggerOpts()), nil))
+
+			buildRunResults, err := runner.runWorkflows()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantBuildFailed, buildRunResults.IsBuildFailed())
+			require.Equal(t, tt.wantFailedSteps, len(buildRunResults.FailedSteps))
+			require.Equal(t, tt.wantSkippedSteps, len(buildRunResults.SkippedSteps))
+			require.Equal(t, tt.wantFailedSkipped, len(buildRunResults.FailedSkippableSteps))
+			require.Equal(t, tt.wantStatus, buildRunResults.OrderedResults()[0].Status)
+		})
+	}
+}
+
 func TestStepOutputsInTemplate(t *testing.T) {
 	inventoryStr := `
 envs:
@@ -386,7 +471,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "test", Secrets: inventory.Envs}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 5, len(buildRunResults.SuccessSteps))
@@ -431,7 +517,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "test"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 1, len(buildRunResults.SuccessSteps))
@@ -494,7 +581,8 @@ workflows:
 		require.NoError(t, configs.InitPaths())
 
 		runConfig := RunConfig{Config: config, Workflow: "test", Secrets: inventory.Envs}
-		runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+		runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+		require.NoError(t, err)
 		_, err = runner.runWorkflows()
 		require.NoError(t, err)
 	}
@@ -537,7 +625,8 @@ workflows:
 		require.NoError(t, configs.InitPaths())
 
 		runConfig := RunConfig{Config: config, Workflow: "test", Secrets: inventory.Envs}
-		runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+		runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+		require.NoError(t, err)
 		_, err = runner.runWorkflows()
 		require.NoError(t, err)
 	}
@@ -582,7 +671,8 @@ workflows:
 		require.NoError(t, configs.InitPaths())
 
 		runConfig := RunConfig{Config: config, Workflow: "test", Secrets: inventory.Envs}
-		runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+		runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+		require.NoError(t, err)
 		_, err = runner.runWorkflows()
 		require.NoError(t, err)
 	}
@@ -633,7 +723,8 @@ workflows:
 		require.NoError(t, configs.InitPaths())
 
 		runConfig := RunConfig{Config: config, Workflow: "test", Secrets: inventory.Envs}
-		runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+		runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+		require.NoError(t, err)
 		_, err = runner.runWorkflows()
 		require.NoError(t, err)
 	}
@@ -1132,7 +1223,8 @@ workflows:
 			require.NoError(t, configs.InitPaths())
 
 			runConfig := RunConfig{Config: config, Workflow: "test", Secrets: inventory.Envs}
-			runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+			runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+			require.NoError(t, err)
 			res, err := runner.runWorkflows()
 			require.NoError(t, err)
 			require.False(t, res.IsBuildFailed())
@@ -1158,7 +1250,8 @@ func Test0Steps1Workflows(t *testing.T) {
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "zero_steps"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 0, len(buildRunResults.SuccessSteps))
@@ -1193,7 +1286,8 @@ func Test0Steps3WorkflowsBeforeAfter(t *testing.T) {
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "target"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 
 	require.NoError(t, err)
@@ -1244,7 +1338,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "trivial_fail"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 
 	require.NoError(t, err)
@@ -1324,7 +1419,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "target"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 3, len(buildRunResults.SuccessSteps))
@@ -1429,7 +1525,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "target"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 3, len(buildRunResults.SuccessSteps))
@@ -1483,7 +1580,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "target"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 3, len(buildRunResults.SuccessSteps))
@@ -1515,7 +1613,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "target"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 1, len(buildRunResults.SuccessSteps))
@@ -1570,7 +1669,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "target"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 1, len(buildRunResults.SuccessSteps))
@@ -1618,7 +1718,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "test"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 
@@ -1681,7 +1782,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "target"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 2, len(buildRunResults.SuccessSteps))
@@ -1739,7 +1841,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "target"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 2, len(buildRunResults.SuccessSteps))
@@ -1785,7 +1888,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "target"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 1, len(buildRunResults.SuccessSteps))
@@ -1839,7 +1943,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "target"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 2, len(buildRunResults.SuccessSteps))
@@ -1897,7 +2002,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "out-test"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, _ := runner.runWorkflows()
 	require.Equal(t, 0, len(buildRunResults.SkippedSteps))
 	require.Equal(t, 3, len(buildRunResults.SuccessSteps))
@@ -1943,7 +2049,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "test"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.NoError(t, err)
 	require.Equal(t, 2, len(buildRunResults.SuccessSteps))
@@ -1997,7 +2104,8 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "test"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	buildRunResults, err := runner.runWorkflows()
 	require.Equal(t, nil, err)
 	require.Equal(t, 0, len(buildRunResults.SkippedSteps))
@@ -2027,11 +2135,19 @@ workflows:
 	require.NoError(t, configs.InitPaths())
 
 	runConfig := RunConfig{Config: config, Workflow: "target"}
-	runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+	require.NoError(t, err)
 	results, _ := runner.runWorkflows()
 	require.Equal(t, 1, len(results.StepmanUpdates))
 }
 
+func TestNewWorkflowRunner_unknownContainerRuntime(t *testing.T) {
+	t.Setenv(docker.RuntimeEnvKey, "podmn")
+
+	_, err := NewWorkflowRunner(RunConfig{Workflow: "target"}, nil, noOpTracker{})
+	require.EqualError(t, err, "unknown container runtime: podmn, supported runtimes: docker, podman")
+}
+
 func TestPluginTriggered(t *testing.T) {
 	bitriseYML := `
   format_version: 1.3.0
@@ -2120,8 +2236,9 @@ route_map:
 			log.InitGlobalLogger(opts)
 
 			runConfig := RunConfig{Config: config, Workflow: "test"}
-			runner := NewWorkflowRunner(runConfig, nil, noOpTracker{})
-			_, err := runner.runWorkflows()
+			runner, err := NewWorkflowRunner(runConfig, nil, noOpTracker{})
+			require.NoError(t, err)
+			_, err = runner.runWorkflows()
 			opts.Writer = origWiter
 
 			// Then
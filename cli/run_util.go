@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -20,7 +21,9 @@ import (
 	"github.com/bitrise-io/bitrise/v2/log"
 	"github.com/bitrise-io/bitrise/v2/log/logwriter"
 	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/plugins"
 	"github.com/bitrise-io/bitrise/v2/stepruncmd"
+	"github.com/bitrise-io/bitrise/v2/toolprovider"
 	"github.com/bitrise-io/bitrise/v2/tools"
 	envman "github.com/bitrise-io/envman/v2/cli"
 	envmanEnv "github.com/bitrise-io/envman/v2/env"
@@ -49,10 +52,31 @@ func (r WorkflowRunner) runWorkflow(
 	workflowIDProperties := coreanalytics.Properties{analytics.WorkflowExecutionID: plan.UUID}
 	r.tracker.SendWorkflowStarted(buildIDProperties.Merge(workflowIDProperties), plan.WorkflowID, plan.WorkflowTitle)
 
+	workflowStartTime := time.Now()
+	workflowStartResponse := r.triggerPluginEvent(plugins.WorkflowWillStart, models.WorkflowRunStartModel{
+		EventName:     string(plugins.WorkflowWillStart),
+		ExecutionID:   plan.UUID,
+		WorkflowID:    plan.WorkflowID,
+		WorkflowTitle: plan.WorkflowTitle,
+		StartTime:     workflowStartTime,
+	})
+	*environments = append(*environments, pluginEnvironments(workflowStartResponse)...)
+
 	results := r.activateAndRunSteps(plan, steplibSource, buildRunResults, environments, secrets, isLastWorkflow, workflowIDProperties)
 
 	r.tracker.SendWorkflowFinished(workflowIDProperties, results.IsBuildFailed())
 
+	workflowFinishResponse := r.triggerPluginEvent(plugins.WorkflowDidFinish, models.WorkflowRunFinishModel{
+		EventName:     string(plugins.WorkflowDidFinish),
+		ExecutionID:   plan.UUID,
+		WorkflowID:    plan.WorkflowID,
+		WorkflowTitle: plan.WorkflowTitle,
+		StartTime:     workflowStartTime,
+		RunTime:       time.Since(workflowStartTime),
+		IsFailed:      results.IsBuildFailed(),
+	})
+	*environments = append(*environments, pluginEnvironments(workflowFinishResponse)...)
+
 	return results
 }
 
@@ -86,7 +110,21 @@ func (r WorkflowRunner) activateAndRunSteps(
 	// ------------------------------------------
 	// Main - Preparing & running the steps
 	for idx, stepPlan := range plan.Steps {
-		r.containerManager.UpdateWithStepStarted(stepPlan, *environments)
+		stepStartResponse := r.triggerPluginEvent(plugins.StepWillStart, models.StepRunStartModel{
+			EventName:   string(plugins.StepWillStart),
+			ExecutionID: stepPlan.UUID,
+			WorkflowID:  plan.WorkflowID,
+			StepID:      stepPlan.StepID,
+			Idx:         idx,
+			StartTime:   time.Now(),
+		})
+		stepStartPluginEnvs := pluginEnvironments(stepStartResponse)
+		*environments = append(*environments, stepStartPluginEnvs...)
+		if currentStepBundleUUID != "" {
+			currentStepBundleEnvVars = append(currentStepBundleEnvVars, stepStartPluginEnvs...)
+		}
+
+		servicesErr := r.containerManager.UpdateWithStepStarted(stepPlan, *environments)
 
 		workflowEnvironments := append([]envmanModels.EnvironmentItemModel{}, *environments...)
 
@@ -109,21 +147,29 @@ func (r WorkflowRunner) activateAndRunSteps(
 		stepIDProperties := coreanalytics.Properties{analytics.StepExecutionID: stepPlan.UUID}
 		stepStartedProperties := workflowIDProperties.Merge(stepIDProperties)
 
-		result := r.activateAndRunStep(
-			stepPlan.Step,
-			stepPlan.StepID,
-			idx,
-			defaultStepLibSource,
-			stepPlan.UUID,
-			envsForStepRun,
-			secrets,
-			buildRunResults,
-			plan.IsSteplibOfflineMode,
-			stepStartTime,
-			stepStartedProperties,
-			stepPlan.StepBundleRunIfs,
-			stepBundleRunIfResults,
-		)
+		var result activateAndRunStepResult
+		if stepStartResponse.Veto {
+			stepInfoPtr, _, _ := newStepInfoPtr(stepPlan.StepID, defaultStepLibSource, stepPlan.Step)
+			result = newActivateAndRunStepResult(stepPlan.Step, stepInfoPtr, models.StepRunStatusCodeSkippedByPlugin, 0, errors.New(stepStartResponse.VetoReason), true, map[string]string{}, nil)
+		} else {
+			result = r.activateAndRunStep(
+				stepPlan.Step,
+				stepPlan.StepID,
+				idx,
+				defaultStepLibSource,
+				stepPlan.UUID,
+				envsForStepRun,
+				secrets,
+				buildRunResults,
+				plan.IsSteplibOfflineMode,
+				stepStartTime,
+				stepStartedProperties,
+				stepPlan.StepBundleRunIfs,
+				stepBundleRunIfResults,
+				stepPlan.Tools,
+				servicesErr,
+			)
+		}
 
 		*environments = append(*environments, result.OutputEnvironments...)
 		if currentStepBundleUUID != "" {
@@ -135,8 +181,22 @@ func (r WorkflowRunner) activateAndRunSteps(
 
 		previousBuildRunResult := buildRunResults
 
-		runResultCollector.registerStepRunResults(&buildRunResults, stepPlan.UUID, stepStartTime, stepmanModels.StepModel{}, result.StepInfoPtr, idx,
+		stepResults := runResultCollector.registerStepRunResults(&buildRunResults, stepPlan.UUID, stepStartTime, stepmanModels.StepModel{}, result.StepInfoPtr, idx,
 			result.StepRunStatus, result.StepRunExitCode, result.StepRunErr, isLastStep, result.PrintStepHeader, result.RedactedStepInputs, stepStartedProperties)
+		if stepResults != nil {
+			stepFinishResponse := r.triggerPluginEvent(plugins.StepDidFinish, models.StepRunFinishModel{
+				EventName:   string(plugins.StepDidFinish),
+				ExecutionID: stepPlan.UUID,
+				WorkflowID:  plan.WorkflowID,
+				StepID:      stepPlan.StepID,
+				Result:      *stepResults,
+			})
+			stepFinishPluginEnvs := pluginEnvironments(stepFinishResponse)
+			*environments = append(*environments, stepFinishPluginEnvs...)
+			if currentStepBundleUUID != "" {
+				currentStepBundleEnvVars = append(currentStepBundleEnvVars, stepFinishPluginEnvs...)
+			}
+		}
 
 		r.containerManager.UpdateWithStepFinished(idx, plan, stepPlan)
 
@@ -192,6 +252,8 @@ func (r WorkflowRunner) activateAndRunStep(
 	stepStartedProperties coreanalytics.Properties,
 	stepBundleRunIfs []models.StepBundleRunIf,
 	stepBundleRunIfResults map[string]bool,
+	stepTools models.ToolsModel,
+	servicesErr error,
 ) activateAndRunStepResult {
 	stepInfoPtr, stepIDData, err := newStepInfoPtr(stepID, defaultStepLibSource, step)
 	if err != nil {
@@ -291,6 +353,25 @@ func (r WorkflowRunner) activateAndRunStep(
 		return newActivateAndRunStepResult(mergedStep, stepInfoPtr, models.StepRunStatusCodeSkipped, 0, nil, false, map[string]string{}, nil)
 	}
 
+	// The step would fail anyway without the services it depends on, so it is not run.
+	// This is checked only after the skip conditions above: a skipped step doesn't need its services.
+	if servicesErr != nil {
+		if *mergedStep.IsSkippable {
+			return newActivateAndRunStepResult(mergedStep, stepInfoPtr, models.StepRunStatusCodeFailedSkippable, 1, servicesErr, false, map[string]string{}, nil)
+		}
+		return newActivateAndRunStepResult(mergedStep, stepInfoPtr, models.StepRunStatusCodeServiceNotReady, 1, servicesErr, false, map[string]string{}, nil)
+	}
+
+	// Install the tools declared by the step (and its step bundles), they are activated only in the step's environment
+	if len(stepTools) > 0 {
+		toolEnvs, err := toolprovider.RunStepToolSetup(r.config.Config, stepTools, r.tracker, envsToMap(environments), r.toolsLockfile)
+		if err != nil {
+			err = fmt.Errorf("set up step tools: %w", err)
+			return newActivateAndRunStepResult(mergedStep, stepInfoPtr, models.StepRunStatusCodePreparationFailed, 1, err, false, map[string]string{}, nil)
+		}
+		environments = append(slices.Clip(environments), toolEnvs...)
+	}
+
 	// Prepare envs for the step run
 	prepareEnvsResult := r.prepareEnvsForStepRun(stepExecutionID, stepDir, mergedStep.Inputs, secrets, buildRunResults, environments)
 	if prepareEnvsResult.Err != nil {
@@ -740,8 +821,7 @@ func (r WorkflowRunner) executeStep(
 			return 1, fmt.Errorf("failed to read command environment: %w", err)
 		}
 
-		name = "docker"
-		args = runningContainer.ExecuteCommandArgs(envs)
+		name, args = runningContainer.ExecuteCommand(envs)
 		args = append(args, cmdArgs...)
 
 		cmd := stepruncmd.New(name, args, bitriseSourceDir, envs, stepSecrets, timeout, noOutputTimeout, stdout, logV2.NewLogger())
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bitrise-io/bitrise/v2/configschema"
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/spf13/cobra"
+)
+
+var schemaCommand = &cobra.Command{
+	Use:   "schema",
+	Short: "Prints the JSON Schema of the bitrise.yml.",
+	Long: fmt.Sprintf(`Prints the JSON Schema of the bitrise.yml (format version %s).
+
+The schema is generated from the config model of this bitrise CLI version,
+it can be used by editors and pre-commit hooks to validate configs without the bitrise CLI.`, models.FormatVersion),
+	RunE: printSchema,
+}
+
+func init() {
+	schemaCommand.Flags().StringP("output", "o", "", "Write the schema to the given file instead of the standard output.")
+}
+
+func printSchema(cmd *cobra.Command, _ []string) error {
+	logCommandParameters(cmd)
+
+	outputPth, _ := cmd.Flags().GetString("output")
+
+	content, err := configschema.Marshal(configschema.Generate())
+	if err != nil {
+		return fmt.Errorf("failed to generate schema: %w", err)
+	}
+
+	if outputPth != "" {
+		if err := fileutil.WriteBytesToFile(outputPth, content); err != nil {
+			return fmt.Errorf("failed to write schema to file: %w", err)
+		}
+		return nil
+	}
+
+	if _, err := os.Stdout.Write(content); err != nil {
+		return fmt.Errorf("failed to print schema: %w", err)
+	}
+	return nil
+}
@@ -7,13 +7,18 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/bitrise-io/bitrise/v2/analytics"
 	"github.com/bitrise-io/bitrise/v2/bitrise"
 	"github.com/bitrise-io/bitrise/v2/configs"
 	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/models"
 	"github.com/bitrise-io/bitrise/v2/toolprovider"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/catalog"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/versionfile"
 	"github.com/bitrise-io/bitrise/v2/tools"
 	"github.com/bitrise-io/colorstring"
 	"github.com/spf13/cobra"
@@ -24,12 +29,22 @@ const (
 	outputFormatJSON      = "json"
 	outputFormatBash      = "bash"
 
+	outputFormatZsh           = "zsh"
+	outputFormatFish          = "fish"
+	outputFormatPowerShell    = "powershell"
+	outputFormatDotenv        = "dotenv"
+	outputFormatDirenv        = "direnv"
+	outputFormatGitHubActions = "github-actions"
+
 	toolsSetupSubcommandName    = "setup"
 	toolsInstallSubcommandName  = "install"
 	toolsLatestSubcommandName   = "latest"
 	toolsInfoCommandName        = "info"
 	toolsVersionsSubcommandName = "versions"
 	toolsCatalogSubcommandName  = "catalog"
+	toolsLockSubcommandName     = "lock"
+	toolsOutdatedSubcommandName = "outdated"
+	toolsPruneSubcommandName    = "prune"
 
 	toolsConfigKey      = "config"
 	toolsConfigShortKey = "c"
@@ -46,6 +61,14 @@ const (
 	toolsActiveShortKey = "a"
 
 	toolsFastInstallKey = "fast-install"
+
+	toolsLockfileKey = "lockfile"
+	toolsUpdateKey   = "update"
+
+	toolsExportFileKey = "export-file"
+
+	toolsUnusedDaysKey = "unused-days"
+	toolsDryRunKey     = "dry-run"
 )
 
 const toolsConfigFlagUsage = `Config or version file paths to install tools from. Can be specified multiple times. If not provided, detects files in the working directory. Supported file names and formats:
@@ -54,12 +77,25 @@ const toolsConfigFlagUsage = `Config or version file paths to install tools from
 	- .nvmrc (NVM): Node.js version
 	- .fvmrc (FVM 3.x): Flutter version from JSON {"flutter": "<version>"}
 	- .fvm/fvm_config.json (legacy FVM): Flutter version from {"flutterSdkVersion": "<version>"}
-	- bitrise.yml: tools defined in the "tools" section`
+	- mise.toml, .mise.toml: tools defined in the [tools] table
+	- .sdkmanrc (SDKMAN!): one "<candidate>=<version>" per line
+	- rust-toolchain.toml, rust-toolchain: Rust toolchain channel
+	- package.json: Node.js version constraint from the engines field
+	- go.mod: Go version from the toolchain or go directive
+	- pyproject.toml: Python version constraint from requires-python or the Poetry dependencies
+	- Gemfile: Ruby version from the ruby directive
+	- bitrise.yml: tools defined in the "tools" section
+If several files specify the same tool, mise.toml and .tool-versions win over tool specific version files, which win over project manifests (package.json, go.mod, pyproject.toml, Gemfile).`
+
+// toolsProviderOptions lists the accepted --provider values in the flag usages.
+var toolsProviderOptions = strings.Join(models.ToolProviders, "/")
+
+var toolsProviderFlagUsage = fmt.Sprintf(`Tool provider to use (%s). If not specified, uses the default.`, toolsProviderOptions)
 
 const (
-	toolsProviderFlagUsage = `Tool provider to use (asdf/mise). If not specified, uses the default.`
 	// activation commands (info/install/latest/setup) emit env vars that activate tools.
-	toolsActivationFormatFlagUsage = `Output format of the env vars that activate installed tools. Options: plaintext, json, bash`
+	toolsActivationFormatFlagUsage = `Output format of the env vars that activate installed tools. Options: plaintext, json, bash, zsh, fish, powershell, dotenv, direnv (.envrc), github-actions (appends to $GITHUB_ENV and $GITHUB_PATH)`
+	toolsExportFileFlagUsage       = `Write the env vars that activate installed tools to this file instead of the standard output, in the --format format (e.g. --format dotenv --export-file .env)`
 	// list commands (catalog/versions) only print data.
 	toolsListFormatFlagUsage = `Output format. Options: plaintext, json`
 )
@@ -86,7 +122,7 @@ EXAMPLES:
 }
 
 var toolsInstallSubcommand = &cobra.Command{
-	Use:   toolsInstallSubcommandName + " [--provider PROVIDER] [--format FORMAT] <TOOL> <VERSION>[:SUFFIX]",
+	Use:   toolsInstallSubcommandName + " [--provider PROVIDER] [--format FORMAT] [--export-file FILE] <TOOL> <VERSION>[:SUFFIX]",
 	Short: "Install a specific tool version",
 	Long: `Install a specific version of a tool using the configured tool provider.
 
@@ -96,7 +132,8 @@ VERSION: specific version (20.10.0), prefix (22), latest, or installed.
 EXAMPLES:
    bitrise tools install nodejs 20.10.0
    bitrise tools install nodejs 22:latest
-   eval "$(bitrise tools install ruby 3.2.0 --format bash)"  # activate in shell`,
+   eval "$(bitrise tools install ruby 3.2.0 --format bash)"  # activate in shell
+   bitrise tools install ruby 3.2.0 --format dotenv --export-file .env`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logCommandParameters(cmd)
 		if err := toolsInstall(cmd, args); err != nil {
@@ -130,9 +167,9 @@ EXAMPLES:
 }
 
 var toolsSetupSubcommand = &cobra.Command{
-	Use:   toolsSetupSubcommandName + " [--provider PROVIDER] [--fast-install true|false] [--config FILE] [--format FORMAT] [--workflow WORKFLOW]",
+	Use:   toolsSetupSubcommandName + " [--provider PROVIDER] [--fast-install true|false] [--config FILE] [--format FORMAT] [--export-file FILE] [--workflow WORKFLOW]",
 	Short: "Install tools from version files or bitrise.yml",
-	Long: `Install tools from version files (e.g. .tool-versions, .node-version, .nvmrc, .fvmrc, .fvm/fvm_config.json, package.json) or bitrise.yml.
+	Long: `Install tools from version files (e.g. .tool-versions, mise.toml, .node-version, .nvmrc, .fvmrc, .sdkmanrc, rust-toolchain.toml, package.json, go.mod, pyproject.toml, Gemfile) or bitrise.yml.
 
 EXAMPLES:
    bitrise tools setup --config .tool-versions
@@ -140,11 +177,19 @@ EXAMPLES:
    bitrise tools setup --config .fvmrc
    bitrise tools setup --config .fvm/fvm_config.json
    bitrise tools setup --config package.json
+   bitrise tools setup --config go.mod --config .sdkmanrc
    bitrise tools setup --config bitrise.yml
    bitrise tools setup --provider mise --fast-install true
 
    Setup and activate in current shell session:
-   eval "$(bitrise tools setup --config .tool-versions --format bash)"`,
+   eval "$(bitrise tools setup --config .tool-versions --format bash)"
+   bitrise tools setup --config .tool-versions --format fish | source
+   bitrise tools setup --config .tool-versions --format powershell | Invoke-Expression
+
+   Setup for other environments:
+   bitrise tools setup --config .tool-versions --format dotenv --export-file .env
+   bitrise tools setup --config .tool-versions --format direnv --export-file .envrc
+   bitrise tools setup --config .tool-versions --format github-actions  # in a GitHub Actions job`,
 	RunE: func(cmd *cobra.Command, _ []string) error {
 		logCommandParameters(cmd)
 		if err := toolsSetup(cmd); err != nil {
@@ -155,10 +200,98 @@ EXAMPLES:
 	},
 }
 
+var toolsLockSubcommand = &cobra.Command{
+	Use:   toolsLockSubcommandName + " [--provider PROVIDER] [--fast-install true|false] [--config FILE] [--workflow WORKFLOW] [--lockfile FILE]",
+	Short: "Pin the tool versions of bitrise.yml in a lockfile",
+	Long: `Install the tools of the bitrise.yml tools section and write the concrete versions they resolved to into a lockfile.
+
+The lockfile (` + toolprovider.DefaultToolsLockfileName + ` in the working directory by default) is honoured by ` + "`bitrise run`" + ` and
+` + "`bitrise tools setup`" + `, so :latest, :installed and version constraints resolve to the same versions in every build.
+Both ignore the lockfile if the --update-tools (run) or --update (tools setup) flag is passed.
+Run this command again to update the pinned versions. The tools of every workflow are locked unless --workflow is given,
+in that case the existing pins of the other workflows are kept in the lockfile.
+
+EXAMPLES:
+   bitrise tools lock
+   bitrise tools lock --config bitrise.yml --workflow primary`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		logCommandParameters(cmd)
+		if err := toolsLock(cmd); err != nil {
+			log.Errorf("Tool lock failed: %s", err)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+var toolsOutdatedSubcommand = &cobra.Command{
+	Use:   toolsOutdatedSubcommandName + " [--provider PROVIDER] [--config FILE] [--workflow WORKFLOW] [--lockfile FILE] [--format FORMAT]",
+	Short: "Report newer releases and end-of-life versions of the declared tools",
+	Long: `Report the newer releases and the end-of-life status of the tools declared in bitrise.yml and version files.
+
+For each declared tool the version it resolves to (the version pinned by the tools lockfile for bitrise.yml tools),
+the newest patch, minor and major releases and whether the version reached its end-of-life are reported.
+End-of-life dates are queried from endoflife.date, the status is unknown for tools not tracked there.
+If --config is not given, bitrise.yml and the version files of the working directory are checked.
+
+EXAMPLES:
+   bitrise tools outdated
+   bitrise tools outdated --config bitrise.yml --workflow primary
+   bitrise tools outdated --config .tool-versions --format json`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		logCommandParameters(cmd)
+		if err := toolsOutdated(cmd); err != nil {
+			log.Errorf("Tool outdated report failed: %s", err)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+var toolsPruneSubcommand = &cobra.Command{
+	Use:   toolsPruneSubcommandName + " [--provider PROVIDER] [--unused-days DAYS] [--lockfile FILE] [--dry-run] [--format FORMAT]",
+	Short: "Remove unused tool versions",
+	Long: `List the installed tool versions with their size and last use, and remove the unused ones.
+
+The last use of a version is the last time ` + "`bitrise run`" + ` or ` + "`bitrise tools`" + ` activated it, or its install time
+if it was not activated since the tracking was introduced.
+
+Versions not used for --unused-days days are removed. If only --lockfile is given, the versions pinned by none of the
+lockfiles are removed. Versions pinned by any of the given lockfiles are always kept.
+Without --unused-days and --lockfile the installed versions are only listed.
+
+EXAMPLES:
+   bitrise tools prune
+   bitrise tools prune --unused-days 30 --dry-run
+   bitrise tools prune --unused-days 30 --lockfile project-a/bitrise-tools.lock --lockfile project-b/bitrise-tools.lock
+   bitrise tools prune --lockfile bitrise-tools.lock`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		logCommandParameters(cmd)
+		if err := toolsPrune(cmd); err != nil {
+			log.Errorf("Tool prune failed: %s", err)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
 var toolsCatalogSubcommand = &cobra.Command{
 	Use:   toolsCatalogSubcommandName + " [--format FORMAT]",
 	Short: "List officially supported tools",
-	Long: `List officially supported tools
+	Long: `List officially supported tools with their aliases, supported tool providers and default version resolution.
+
+The built-in catalog can be extended and overridden by a catalog file: $` + catalog.OverridePathEnvKey + ` if set,
+~/.bitrise/tools-catalog.yml otherwise. The tools of the file are merged into the built-in catalog:
+
+format_version: "1"
+tools:
+  golang:
+    aliases: [go, gol]           # tool names accepted in place of the canonical name
+  tuist:
+    providers: [asdf, mise]      # tool providers able to install the tool, any if empty
+    default_resolution: latest   # resolution of versions without a suffix: strict, latest or installed
+    extra_plugins:               # plugin URL per provider, unless tool_config.extra_plugins sets one
+      asdf: https://github.com/tuist/asdf-tuist.git
 
 EXAMPLES:
    bitrise tools catalog
@@ -209,18 +342,23 @@ func init() {
 		toolsLatestSubcommand,
 		toolsVersionsSubcommand,
 		toolsCatalogSubcommand,
+		toolsLockSubcommand,
+		toolsOutdatedSubcommand,
+		toolsPruneSubcommand,
+		toolsBundleCommand,
 	)
 
 	infoFlags := toolsInfoSubcommand.Flags()
 	infoFlags.BoolP(toolsActiveKey, toolsActiveShortKey, false, `Show only currently active tools in the shell context (based on config files in current directory)`)
-	infoFlags.StringP(toolsOutputFormatKey, toolsOutputFormatShortKey, outputFormatPlaintext, toolsActivationFormatFlagUsage)
+	infoFlags.StringP(toolsOutputFormatKey, toolsOutputFormatShortKey, outputFormatPlaintext, toolsListFormatFlagUsage)
 
 	installFlags := toolsInstallSubcommand.Flags()
 	installFlags.StringP(toolsProviderKey, toolsProviderShortKey, "", toolsProviderFlagUsage)
 	installFlags.StringP(toolsOutputFormatKey, toolsOutputFormatShortKey, outputFormatPlaintext, toolsActivationFormatFlagUsage)
+	installFlags.String(toolsExportFileKey, "", toolsExportFileFlagUsage)
 
 	latestFlags := toolsLatestSubcommand.Flags()
-	latestFlags.StringP(toolsOutputFormatKey, toolsOutputFormatShortKey, outputFormatPlaintext, toolsActivationFormatFlagUsage)
+	latestFlags.StringP(toolsOutputFormatKey, toolsOutputFormatShortKey, outputFormatPlaintext, toolsListFormatFlagUsage)
 	latestFlags.StringP(toolsProviderKey, toolsProviderShortKey, "", toolsProviderFlagUsage)
 
 	setupFlags := toolsSetupSubcommand.Flags()
@@ -231,13 +369,37 @@ func init() {
 	// matching urfave's StringSlice semantics.
 	setupFlags.StringArrayP(toolsConfigKey, toolsConfigShortKey, nil, toolsConfigFlagUsage)
 	setupFlags.StringP(toolsOutputFormatKey, toolsOutputFormatShortKey, outputFormatPlaintext, toolsActivationFormatFlagUsage)
+	setupFlags.String(toolsExportFileKey, "", toolsExportFileFlagUsage)
 	setupFlags.StringP(toolsWorkflowKey, "w", "", "Workflow ID to use when installing from bitrise.yml (optional, uses global tools if not specified)")
+	setupFlags.String(toolsLockfileKey, toolprovider.DefaultToolsLockfileName, "Tools lockfile (created by `bitrise tools lock`) pinning the versions of the bitrise.yml tools, used if it exists.")
+	setupFlags.Bool(toolsUpdateKey, false, "Ignore the tools lockfile and resolve the requested versions again.")
+
+	lockFlags := toolsLockSubcommand.Flags()
+	lockFlags.StringP(toolsProviderKey, toolsProviderShortKey, "", toolsProviderFlagUsage)
+	lockFlags.String(toolsFastInstallKey, "", `Override fast install setting (true/false). If not specified, uses the default for the used stack.`)
+	lockFlags.StringP(toolsConfigKey, toolsConfigShortKey, DefaultBitriseConfigFileName, "bitrise.yml path to lock the tools of.")
+	lockFlags.StringP(toolsWorkflowKey, "w", "", "Workflow ID to lock the tools of (optional, locks the tools of every workflow if not specified)")
+	lockFlags.String(toolsLockfileKey, toolprovider.DefaultToolsLockfileName, "Path of the tools lockfile.")
+
+	outdatedFlags := toolsOutdatedSubcommand.Flags()
+	outdatedFlags.StringP(toolsProviderKey, toolsProviderShortKey, "", toolsProviderFlagUsage)
+	outdatedFlags.StringArrayP(toolsConfigKey, toolsConfigShortKey, nil, "bitrise.yml or version file paths to check the tools of. Can be specified multiple times.")
+	outdatedFlags.StringP(toolsWorkflowKey, "w", "", "Workflow ID to check the tools of (optional, checks the tools of every workflow if not specified)")
+	outdatedFlags.String(toolsLockfileKey, toolprovider.DefaultToolsLockfileName, "Tools lockfile pinning the versions of the bitrise.yml tools, used if it exists.")
+	outdatedFlags.StringP(toolsOutputFormatKey, toolsOutputFormatShortKey, outputFormatPlaintext, toolsListFormatFlagUsage)
+
+	pruneFlags := toolsPruneSubcommand.Flags()
+	pruneFlags.StringP(toolsProviderKey, toolsProviderShortKey, "", fmt.Sprintf(`Tool provider to prune the installs of (%s) (default: "mise")`, toolsProviderOptions))
+	pruneFlags.Int(toolsUnusedDaysKey, 0, "Remove the versions not used for this many days.")
+	pruneFlags.StringArray(toolsLockfileKey, nil, "Tools lockfile pinning versions to keep. Can be specified multiple times.")
+	pruneFlags.Bool(toolsDryRunKey, false, "Only report the versions to remove.")
+	pruneFlags.StringP(toolsOutputFormatKey, toolsOutputFormatShortKey, outputFormatPlaintext, toolsListFormatFlagUsage)
 
 	catalogFlags := toolsCatalogSubcommand.Flags()
 	catalogFlags.StringP(toolsOutputFormatKey, toolsOutputFormatShortKey, outputFormatPlaintext, toolsListFormatFlagUsage)
 
 	versionsFlags := toolsVersionsSubcommand.Flags()
-	versionsFlags.StringP(toolsProviderKey, toolsProviderShortKey, "", `Tool provider to use (asdf/mise) (default: "mise")`)
+	versionsFlags.StringP(toolsProviderKey, toolsProviderShortKey, "", fmt.Sprintf(`Tool provider to use (%s) (default: "mise")`, toolsProviderOptions))
 	versionsFlags.StringP(toolsOutputFormatKey, toolsOutputFormatShortKey, outputFormatPlaintext, toolsListFormatFlagUsage)
 }
 
@@ -247,35 +409,23 @@ func toolsSetup(cmd *cobra.Command) error {
 	format, _ := cmd.Flags().GetString(toolsOutputFormatKey)
 	providerFlag, _ := cmd.Flags().GetString(toolsProviderKey)
 	fastInstallFlag, _ := cmd.Flags().GetString(toolsFastInstallKey)
-	silent := false
+	lockfilePath, _ := cmd.Flags().GetString(toolsLockfileKey)
+	update, _ := cmd.Flags().GetBool(toolsUpdateKey)
+	exportFile, _ := cmd.Flags().GetString(toolsExportFileKey)
 
-	switch format {
-	case outputFormatJSON, outputFormatBash:
-		// Valid formats.
-		silent = true
-	case outputFormatPlaintext:
-		// Valid format.
-	default:
-		return fmt.Errorf("invalid --format: %s", format)
+	if err := validateActivationOutputFormat(format, exportFile); err != nil {
+		return err
 	}
+	silent := format != outputFormatPlaintext
 
 	var providerOverride *string
 	if providerFlag != "" {
 		providerOverride = &providerFlag
 	}
 
-	var fastInstallOverride *bool
-	val := false
-	if fastInstallFlag != "" {
-		switch fastInstallFlag {
-		case "true":
-			val = true
-			fastInstallOverride = &val
-		case "false":
-			fastInstallOverride = &val
-		default:
-			return fmt.Errorf("invalid --fast-install: %s (must be 'true' or 'false')", fastInstallFlag)
-		}
+	fastInstallOverride, err := parseFastInstallFlag(fastInstallFlag)
+	if err != nil {
+		return err
 	}
 
 	var bitriseConfigPath string
@@ -300,6 +450,7 @@ func toolsSetup(cmd *cobra.Command) error {
 		versionFilePaths = append(versionFilePaths, file)
 	}
 
+	var activations []provider.EnvironmentActivation
 	if bitriseConfigPath != "" {
 		config, warnings, err := CreateBitriseConfigFromCLIParams("", bitriseConfigPath, bitrise.ValidationTypeFull)
 		if err != nil {
@@ -310,42 +461,367 @@ func toolsSetup(cmd *cobra.Command) error {
 			log.Warnf("Config warning: %s", warning)
 		}
 
+		var lockfile *toolprovider.ToolsLockfile
+		if !update {
+			lockfile, err = toolprovider.ReadToolsLockfile(lockfilePath)
+			if err != nil {
+				return err
+			}
+		}
+
 		tracker := analytics.NewDefaultTracker()
 		defer tracker.Wait()
-		envs, err := toolprovider.RunDeclarativeSetup(config, tracker, false, workflowID, silent, providerOverride, fastInstallOverride, nil)
+		envs, err := toolprovider.RunDeclarativeSetup(config, tracker, false, workflowID, silent, providerOverride, fastInstallOverride, nil, lockfile)
 		if err != nil {
 			return err
 		}
-
-		exposedWithEnvman := exposeEnvsWithEnvman(envs, silent)
-
-		output, err := convertToOutputFormat(envs, format, exposedWithEnvman)
-		if err != nil {
-			return fmt.Errorf("convert to output format: %w", err)
-		}
-		fmt.Println(output)
+		activations = append(activations, envs...)
 	}
 
 	// Setting up from all the other requested version files or auto-detecting from directory.
-	if len(versionFilePaths) > 0 || len(configFiles) == 0 {
+	setupFromVersionFiles := len(versionFilePaths) > 0 || len(configFiles) == 0
+	if setupFromVersionFiles {
 		tracker := analytics.NewDefaultTracker()
 		defer tracker.Wait()
 		envs, err := toolprovider.RunVersionFileSetup(versionFilePaths, tracker, silent, providerOverride, fastInstallOverride)
 		if err != nil {
 			return err
 		}
+		activations = append(activations, envs...)
+	}
+
+	if bitriseConfigPath == "" && !setupFromVersionFiles {
+		return nil
+	}
+
+	// The activations of all sources are written at once, the export file would only keep the last source otherwise
+	exposedWithEnvman := exposeEnvsWithEnvman(activations, silent)
+	return outputActivationEnvs(activations, format, exportFile, exposedWithEnvman)
+}
+
+func toolsLock(cmd *cobra.Command) error {
+	configPath, _ := cmd.Flags().GetString(toolsConfigKey)
+	workflowID, _ := cmd.Flags().GetString(toolsWorkflowKey)
+	lockfilePath, _ := cmd.Flags().GetString(toolsLockfileKey)
+	providerFlag, _ := cmd.Flags().GetString(toolsProviderKey)
+	fastInstallFlag, _ := cmd.Flags().GetString(toolsFastInstallKey)
+
+	var providerOverride *string
+	if providerFlag != "" {
+		providerOverride = &providerFlag
+	}
+
+	fastInstallOverride, err := parseFastInstallFlag(fastInstallFlag)
+	if err != nil {
+		return err
+	}
+
+	config, warnings, err := CreateBitriseConfigFromCLIParams("", configPath, bitrise.ValidationTypeFull)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	for _, warning := range warnings {
+		log.Warnf("Config warning: %s", warning)
+	}
 
-		exposedWithEnvman := exposeEnvsWithEnvman(envs, silent)
+	tracker := analytics.NewDefaultTracker()
+	defer tracker.Wait()
+	lockfile, err := toolprovider.LockDeclarativeSetup(config, tracker, workflowID, false, providerOverride, fastInstallOverride)
+	if err != nil {
+		return err
+	}
 
-		output, err := convertToOutputFormat(envs, format, exposedWithEnvman)
+	lockfileToWrite := lockfile
+	if workflowID != "" {
+		// Only the tools of the workflow were resolved, the pins of the other workflows are kept
+		existing, err := toolprovider.ReadToolsLockfile(lockfilePath)
 		if err != nil {
-			return fmt.Errorf("convert to output format: %w", err)
+			return err
 		}
-		fmt.Println(output)
+		if existing != nil {
+			lockfileToWrite = existing.Merge(lockfile)
+		}
+	}
+
+	if err := lockfileToWrite.Write(lockfilePath); err != nil {
+		return fmt.Errorf("write tools lockfile: %w", err)
+	}
+
+	for _, tool := range lockfile.Tools {
+		log.Printf("%s %s → %s", tool.Tool, tool.Requested, colorstring.Green("%s", tool.Version))
 	}
+	log.Donef("Tool versions locked in %s", lockfilePath)
+
 	return nil
 }
 
+func toolsOutdated(cmd *cobra.Command) error {
+	configFiles, _ := cmd.Flags().GetStringArray(toolsConfigKey)
+	workflowID, _ := cmd.Flags().GetString(toolsWorkflowKey)
+	lockfilePath, _ := cmd.Flags().GetString(toolsLockfileKey)
+	providerFlag, _ := cmd.Flags().GetString(toolsProviderKey)
+	format, _ := cmd.Flags().GetString(toolsOutputFormatKey)
+
+	if format != outputFormatPlaintext && format != outputFormatJSON {
+		return fmt.Errorf("invalid --format: %s", format)
+	}
+	silent := format == outputFormatJSON
+
+	var providerOverride *string
+	if providerFlag != "" {
+		providerOverride = &providerFlag
+	}
+
+	if len(configFiles) == 0 {
+		if _, err := os.Stat(DefaultBitriseConfigFileName); err == nil {
+			configFiles = append(configFiles, DefaultBitriseConfigFileName)
+		}
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("get working directory: %w", err)
+		}
+		versionFiles, err := versionfile.FindVersionFiles(cwd)
+		if err != nil {
+			return fmt.Errorf("find version files: %w", err)
+		}
+		for _, pth := range versionFiles {
+			if rel, err := filepath.Rel(cwd, pth); err == nil {
+				pth = rel
+			}
+			configFiles = append(configFiles, pth)
+		}
+	}
+
+	var config *models.BitriseDataModel
+	var configPath string
+	var versionFilePaths []string
+	for _, file := range configFiles {
+		if !isBitriseConfig(file) {
+			versionFilePaths = append(versionFilePaths, file)
+			continue
+		}
+		if config != nil {
+			return fmt.Errorf("multiple bitrise config files specified: %s and %s (only one bitrise.yml can be used)", configPath, file)
+		}
+
+		bitriseConfig, warnings, err := CreateBitriseConfigFromCLIParams("", file, bitrise.ValidationTypeFull)
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		if !silent {
+			for _, warning := range warnings {
+				log.Warnf("Config warning: %s", warning)
+			}
+		}
+		config, configPath = &bitriseConfig, file
+	}
+
+	var lockfile *toolprovider.ToolsLockfile
+	if config != nil {
+		var err error
+		lockfile, err = toolprovider.ReadToolsLockfile(lockfilePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	report, err := toolprovider.ReportOutdatedTools(config, configPath, workflowID, versionFilePaths, lockfile, providerOverride, silent)
+	if err != nil {
+		return err
+	}
+
+	if format == outputFormatJSON {
+		data, err := json.MarshalIndent(map[string]any{
+			"tools": report,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(report) == 0 {
+		log.Infof("No tools declared")
+		return nil
+	}
+	printOutdatedTools(report)
+	return nil
+}
+
+func printOutdatedTools(report []toolprovider.OutdatedTool) {
+	var builder strings.Builder
+	w := tabwriter.NewWriter(&builder, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "Tool\tSource\tRequested\tPinned\tLatest patch\tLatest minor\tLatest major\tEOL")
+	for _, tool := range report {
+		// The colored column is the last one, color codes would break the alignment of the following columns
+		eol := "unknown"
+		switch {
+		case tool.EOL == nil:
+		case *tool.EOL && tool.EOLDate != "":
+			eol = colorstring.Red("since %s", tool.EOLDate)
+		case *tool.EOL:
+			eol = colorstring.Red("yes")
+		case tool.EOLDate != "":
+			eol = colorstring.Green("on %s", tool.EOLDate)
+		default:
+			eol = colorstring.Green("no")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", tool.Tool, tool.Source, tool.Requested, tool.Pinned,
+			valueOrDash(tool.LatestPatch), valueOrDash(tool.LatestMinor), valueOrDash(tool.LatestMajor), eol)
+	}
+	_ = w.Flush()
+
+	log.Printf("%s", builder.String())
+
+	outdated := 0
+	for _, tool := range report {
+		if tool.Outdated {
+			outdated++
+		}
+	}
+	if outdated > 0 {
+		log.Warnf("%d of %d tools have newer releases", outdated, len(report))
+	} else {
+		log.Donef("All tools are up to date")
+	}
+}
+
+func toolsPrune(cmd *cobra.Command) error {
+	providerID, _ := cmd.Flags().GetString(toolsProviderKey)
+	unusedDays, _ := cmd.Flags().GetInt(toolsUnusedDaysKey)
+	lockfilePaths, _ := cmd.Flags().GetStringArray(toolsLockfileKey)
+	dryRun, _ := cmd.Flags().GetBool(toolsDryRunKey)
+	format, _ := cmd.Flags().GetString(toolsOutputFormatKey)
+
+	if format != outputFormatPlaintext && format != outputFormatJSON {
+		return fmt.Errorf("invalid --format: %s", format)
+	}
+	silent := format == outputFormatJSON
+	if providerID == "" {
+		providerID = "mise"
+	}
+	if unusedDays < 0 {
+		return fmt.Errorf("invalid --%s: %d", toolsUnusedDaysKey, unusedDays)
+	}
+
+	opts := toolprovider.PruneOptions{
+		UnusedFor: time.Duration(unusedDays) * 24 * time.Hour,
+		DryRun:    dryRun,
+	}
+	for _, pth := range lockfilePaths {
+		lockfile, err := toolprovider.ReadToolsLockfile(pth)
+		if err != nil {
+			return err
+		}
+		if lockfile == nil {
+			return fmt.Errorf("tools lockfile not found: %s", pth)
+		}
+		opts.Lockfiles = append(opts.Lockfiles, *lockfile)
+	}
+
+	installs, err := toolprovider.PruneToolInstalls(providerID, opts, silent)
+	if err != nil {
+		return err
+	}
+
+	if format == outputFormatJSON {
+		data, err := json.MarshalIndent(map[string]any{
+			"installs": installs,
+			"dry_run":  dryRun,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(installs) == 0 {
+		log.Infof("No tools installed")
+		return nil
+	}
+	printToolInstalls(installs, unusedDays > 0 || len(lockfilePaths) > 0, dryRun)
+	return nil
+}
+
+func printToolInstalls(installs []toolprovider.ToolInstall, pruning, dryRun bool) {
+	var builder strings.Builder
+	w := tabwriter.NewWriter(&builder, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "Tool\tVersion\tSize\tLast used\tStatus")
+
+	var totalSize, prunedSize int64
+	prunedCount := 0
+	for _, install := range installs {
+		totalSize += install.SizeBytes
+
+		status := ""
+		switch {
+		case install.Prune && dryRun:
+			status = colorstring.Yellow("would remove")
+		case install.Prune:
+			status = colorstring.Red("removed")
+		case install.Locked:
+			status = "locked"
+		case pruning:
+			status = "kept"
+		}
+		if install.Prune {
+			prunedSize += install.SizeBytes
+			prunedCount++
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", install.Tool, install.Version, formatByteSize(install.SizeBytes), install.LastUsed.Format(time.DateOnly), status)
+	}
+	_ = w.Flush()
+
+	log.Printf("%s", builder.String())
+	log.Printf("%d versions installed, %s in total", len(installs), formatByteSize(totalSize))
+	if !pruning {
+		return
+	}
+	if dryRun {
+		log.Warnf("%d versions (%s) would be removed", prunedCount, formatByteSize(prunedSize))
+	} else {
+		log.Donef("%d versions (%s) removed", prunedCount, formatByteSize(prunedSize))
+	}
+}
+
+func formatByteSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func parseFastInstallFlag(fastInstallFlag string) (*bool, error) {
+	var val bool
+	switch fastInstallFlag {
+	case "":
+		return nil, nil
+	case "true":
+		val = true
+	case "false":
+		val = false
+	default:
+		return nil, fmt.Errorf("invalid --fast-install: %s (must be 'true' or 'false')", fastInstallFlag)
+	}
+	return &val, nil
+}
+
 func isBitriseConfig(path string) bool {
 	base := strings.ToLower(filepath.Base(path))
 	return strings.HasSuffix(base, ".yml") || strings.HasSuffix(base, ".yaml")
@@ -377,27 +853,35 @@ func convertToOutputFormat(envs []provider.EnvironmentActivation, format string,
 			return "", fmt.Errorf("marshal JSON: %w", err)
 		}
 		return string(data), nil
-	case outputFormatBash:
+	case outputFormatBash, outputFormatZsh:
 		if len(envs) == 0 {
 			return "# No new tools were installed.", nil
 		}
 		// Sort K=V pairs for deterministic output (mostly for our own tests, but also generally useful).
-		sortedKeys := make([]string, 0, len(envMap))
-		for k := range envMap {
-			sortedKeys = append(sortedKeys, k)
-		}
-		slices.Sort(sortedKeys)
-		for _, k := range sortedKeys {
-			v := envMap[k]
-			builder.WriteString(fmt.Sprintf("export %s=\"%s\"\n", k, v))
+		for _, k := range sortedEnvKeys(envMap) {
+			builder.WriteString(fmt.Sprintf("export %s=%s\n", k, posixQuote(envMap[k])))
 		}
 		message := fmt.Sprintf(
 			"# %s\n# Make sure to run %s instead\n",
 			colorstring.Yellow("NOTE: Tools have been installed, but they need to be activated for the current shell session."),
-			colorstring.Cyan("eval \"$(bitrise tools setup --format bash ...)\""),
+			colorstring.Cyan("eval \"$(bitrise tools setup --format %s ...)\"", format),
 		)
 		builder.WriteString(message)
 		return builder.String(), nil
+	case outputFormatFish, outputFormatPowerShell, outputFormatDotenv, outputFormatDirenv:
+		if len(envs) == 0 {
+			return "# No new tools were installed.", nil
+		}
+		switch format {
+		case outputFormatFish:
+			return fishActivation(envMap), nil
+		case outputFormatPowerShell:
+			return powerShellActivation(envMap), nil
+		case outputFormatDotenv:
+			return dotenvActivation(envMap), nil
+		default:
+			return direnvActivation(envs, envMap), nil
+		}
 	default:
 		return "", fmt.Errorf("unsupported output format: %s", format)
 	}
@@ -568,25 +1052,24 @@ func parseToolCommand(cmd *cobra.Command, args []string, isInstall bool) (reques
 		versionString = args[1]
 	}
 
-	switch format {
-	case outputFormatJSON:
+	switch {
+	case format == outputFormatJSON:
 		silent = true
-	case outputFormatPlaintext:
+	case format == outputFormatPlaintext:
 		// Valid format.
-	case outputFormatBash:
-		// Install allows bash format for activation in shell.
-		if isInstall {
-			silent = true
-			break
-		}
-		// If not install, fallthrough to error.
-		fallthrough
+	case isInstall && slices.Contains(activationOutputFormats, format):
+		// Install allows the shell and env file formats for activation.
+		exportFile, _ := cmd.Flags().GetString(toolsExportFileKey)
+		if err = validateActivationOutputFormat(format, exportFile); err != nil {
+			return
+		}
+		silent = true
 	default:
 		err = fmt.Errorf("invalid --format: %s", format)
 		return
 	}
 
-	version, resolutionStrategy, parseErr := toolprovider.ParseVersionString(versionString)
+	version, resolutionStrategy, parseErr := toolprovider.ParseToolVersionString(provider.ToolID(toolName), versionString)
 	if parseErr != nil {
 		err = fmt.Errorf("parse version string: %w", parseErr)
 		return
@@ -603,8 +1086,8 @@ func parseToolCommand(cmd *cobra.Command, args []string, isInstall bool) (reques
 		providerID = "mise"
 	}
 
-	if providerID != "asdf" && providerID != "mise" {
-		err = fmt.Errorf("invalid provider: %s (must be 'asdf' or 'mise')", providerID)
+	if !slices.Contains(models.ToolProviders, providerID) {
+		err = fmt.Errorf("invalid provider: %s (must be one of: %s)", providerID, strings.Join(models.ToolProviders, ", "))
 		return
 	}
 
@@ -658,19 +1141,17 @@ func toolsInstall(cmd *cobra.Command, args []string) error {
 
 	exposedWithEnvman := exposeEnvsWithEnvman(envs, silent)
 
-	output, err := convertToOutputFormat(envs, format, exposedWithEnvman)
-	if err != nil {
-		return fmt.Errorf("convert to output format: %w", err)
-	}
-	fmt.Println(output)
-
-	return nil
+	exportFile, _ := cmd.Flags().GetString(toolsExportFileKey)
+	return outputActivationEnvs(envs, format, exportFile, exposedWithEnvman)
 }
 
 func toolsListTools(cmd *cobra.Command) error {
 	format, _ := cmd.Flags().GetString(toolsOutputFormatKey)
 
-	tools := toolprovider.SupportedTools()
+	tools, err := toolprovider.LoadToolsCatalog()
+	if err != nil {
+		return err
+	}
 
 	switch format {
 	case outputFormatJSON:
@@ -682,12 +1163,17 @@ func toolsListTools(cmd *cobra.Command) error {
 		}
 		fmt.Println(string(data))
 	case outputFormatPlaintext:
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "Tool\tAliases\tProviders\tDefault resolution")
 		for _, t := range tools {
-			if len(t.Aliases) > 0 {
-				fmt.Printf("%s (alias: %s)\n", t.Name, strings.Join(t.Aliases, ", "))
-			} else {
-				fmt.Println(t.Name)
+			defaultResolution := t.DefaultResolution
+			if defaultResolution == "" {
+				defaultResolution = catalog.ResolutionStrict
 			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.Name, valueOrDash(strings.Join(t.Aliases, ", ")), valueOrDash(strings.Join(t.Providers, ", ")), defaultResolution)
+		}
+		if err := w.Flush(); err != nil {
+			return err
 		}
 	default:
 		return fmt.Errorf("invalid --format: %s", format)
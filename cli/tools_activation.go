@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/toolprovider"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// githubEnvDelimiter is the heredoc delimiter of multiline values in the $GITHUB_ENV file.
+const githubEnvDelimiter = "BITRISE_TOOLS_EOF"
+
+// activationOutputFormats are the --format options of the commands emitting the env vars that activate the installed tools.
+var activationOutputFormats = []string{
+	outputFormatPlaintext,
+	outputFormatJSON,
+	outputFormatBash,
+	outputFormatZsh,
+	outputFormatFish,
+	outputFormatPowerShell,
+	outputFormatDotenv,
+	outputFormatDirenv,
+	outputFormatGitHubActions,
+}
+
+func validateActivationOutputFormat(format, exportFile string) error {
+	if !slices.Contains(activationOutputFormats, format) {
+		return fmt.Errorf("invalid --format: %s", format)
+	}
+	if exportFile == "" {
+		return nil
+	}
+	switch format {
+	case outputFormatPlaintext:
+		return fmt.Errorf("--%s requires an env var format (--format), plaintext output has no env vars", toolsExportFileKey)
+	case outputFormatGitHubActions:
+		return fmt.Errorf("--%s is not supported with the %s format, the env vars are written to $GITHUB_ENV and $GITHUB_PATH", toolsExportFileKey, outputFormatGitHubActions)
+	}
+	return nil
+}
+
+// outputActivationEnvs prints the activation env vars in the given format, or writes them to the export file if given.
+// The github-actions format appends the env vars to the $GITHUB_ENV and $GITHUB_PATH files of the running job instead.
+func outputActivationEnvs(envs []provider.EnvironmentActivation, format, exportFile string, exposedWithEnvman bool) error {
+	if format == outputFormatGitHubActions {
+		return writeGitHubActionsEnvs(envs, os.Getenv("GITHUB_ENV"), os.Getenv("GITHUB_PATH"))
+	}
+
+	output, err := convertToOutputFormat(envs, format, exposedWithEnvman)
+	if err != nil {
+		return fmt.Errorf("convert to output format: %w", err)
+	}
+
+	if exportFile == "" {
+		fmt.Println(output)
+		return nil
+	}
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	if err := os.WriteFile(exportFile, []byte(output), 0644); err != nil {
+		return fmt.Errorf("write export file: %w", err)
+	}
+	if format != outputFormatJSON {
+		log.Donef("Tool activation written to %s", exportFile)
+	}
+	return nil
+}
+
+func sortedEnvKeys(envMap map[string]string) []string {
+	keys := make([]string, 0, len(envMap))
+	for k := range envMap {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// posixQuote double-quotes the value for POSIX shells (bash, zsh) and direnv, keeping it literal.
+func posixQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`").Replace(value) + `"`
+}
+
+func fishQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(value) + `"`
+}
+
+func powerShellQuote(value string) string {
+	return `"` + strings.NewReplacer("`", "``", `"`, "`\"", `$`, "`$").Replace(value) + `"`
+}
+
+func dotenvQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+func fishActivation(envMap map[string]string) string {
+	var builder strings.Builder
+	for _, k := range sortedEnvKeys(envMap) {
+		if k == "PATH" {
+			// PATH is a list in fish
+			entries := strings.Split(envMap[k], ":")
+			quoted := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				quoted = append(quoted, fishQuote(entry))
+			}
+			builder.WriteString(fmt.Sprintf("set -gx PATH %s\n", strings.Join(quoted, " ")))
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("set -gx %s %s\n", k, fishQuote(envMap[k])))
+	}
+	return builder.String()
+}
+
+func powerShellActivation(envMap map[string]string) string {
+	var builder strings.Builder
+	for _, k := range sortedEnvKeys(envMap) {
+		builder.WriteString(fmt.Sprintf("$env:%s = %s\n", k, powerShellQuote(envMap[k])))
+	}
+	return builder.String()
+}
+
+func dotenvActivation(envMap map[string]string) string {
+	var builder strings.Builder
+	for _, k := range sortedEnvKeys(envMap) {
+		builder.WriteString(fmt.Sprintf("%s=%s\n", k, dotenvQuote(envMap[k])))
+	}
+	return builder.String()
+}
+
+// direnvActivation renders an .envrc, the tool paths are added with direnv's PATH_add instead of overriding PATH.
+func direnvActivation(envs []provider.EnvironmentActivation, envMap map[string]string) string {
+	var builder strings.Builder
+	for _, k := range sortedEnvKeys(envMap) {
+		if k == "PATH" {
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("export %s=%s\n", k, posixQuote(envMap[k])))
+	}
+
+	paths := toolprovider.ContributedPaths(envs)
+	if len(paths) > 0 {
+		quoted := make([]string, 0, len(paths))
+		for _, p := range paths {
+			quoted = append(quoted, posixQuote(p))
+		}
+		builder.WriteString(fmt.Sprintf("PATH_add %s\n", strings.Join(quoted, " ")))
+	}
+	return builder.String()
+}
+
+// writeGitHubActionsEnvs exposes the activation env vars to the subsequent steps of a GitHub Actions job.
+func writeGitHubActionsEnvs(envs []provider.EnvironmentActivation, githubEnvPath, githubPathPath string) error {
+	if githubEnvPath == "" || githubPathPath == "" {
+		return errors.New("GITHUB_ENV and GITHUB_PATH are not set, the github-actions format can only be used in GitHub Actions jobs")
+	}
+
+	envMap := toolprovider.ConvertToEnvMap(envs)
+	var envContent strings.Builder
+	for _, k := range sortedEnvKeys(envMap) {
+		if k == "PATH" {
+			continue
+		}
+		v := envMap[k]
+		if strings.Contains(v, "\n") {
+			envContent.WriteString(fmt.Sprintf("%s<<%s\n%s\n%s\n", k, githubEnvDelimiter, v, githubEnvDelimiter))
+			continue
+		}
+		envContent.WriteString(fmt.Sprintf("%s=%s\n", k, v))
+	}
+
+	// Every $GITHUB_PATH line is prepended to PATH, the entries are written in reverse to keep their precedence.
+	var pathContent strings.Builder
+	paths := toolprovider.ContributedPaths(envs)
+	for i := len(paths) - 1; i >= 0; i-- {
+		pathContent.WriteString(paths[i] + "\n")
+	}
+
+	if err := appendToFile(githubEnvPath, envContent.String()); err != nil {
+		return fmt.Errorf("write GITHUB_ENV: %w", err)
+	}
+	if err := appendToFile(githubPathPath, pathContent.String()); err != nil {
+		return fmt.Errorf("write GITHUB_PATH: %w", err)
+	}
+	return nil
+}
+
+func appendToFile(pth, content string) error {
+	if content == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(pth, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
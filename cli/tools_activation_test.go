@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cliAnalytics "github.com/bitrise-io/bitrise/v2/analytics"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/native"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateActivationOutputFormat(t *testing.T) {
+	require.NoError(t, validateActivationOutputFormat(outputFormatPlaintext, ""))
+	require.NoError(t, validateActivationOutputFormat(outputFormatGitHubActions, ""))
+	require.NoError(t, validateActivationOutputFormat(outputFormatDotenv, ".env"))
+	require.EqualError(t, validateActivationOutputFormat("csh", ""), "invalid --format: csh")
+	require.EqualError(t, validateActivationOutputFormat(outputFormatPlaintext, ".env"), "--export-file requires an env var format (--format), plaintext output has no env vars")
+	require.EqualError(t, validateActivationOutputFormat(outputFormatGitHubActions, ".env"), "--export-file is not supported with the github-actions format, the env vars are written to $GITHUB_ENV and $GITHUB_PATH")
+}
+
+func TestOutputActivationEnvs_ExportFile(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	exportFile := filepath.Join(t.TempDir(), ".env")
+	envs := []provider.EnvironmentActivation{
+		{
+			ContributedEnvVars: map[string]string{"GO_VERSION": "1.21.0"},
+			ContributedPaths:   []string{"/usr/local/go/bin"},
+		},
+	}
+
+	require.NoError(t, outputActivationEnvs(envs, outputFormatDotenv, exportFile, false))
+
+	content, err := os.ReadFile(exportFile)
+	require.NoError(t, err)
+	require.Equal(t, "GO_VERSION=\"1.21.0\"\nPATH=\"/usr/local/go/bin:/usr/bin\"\n", string(content))
+}
+
+func TestWriteGitHubActionsEnvs(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	tmpDir := t.TempDir()
+	githubEnvPath := filepath.Join(tmpDir, "github_env")
+	githubPathPath := filepath.Join(tmpDir, "github_path")
+	require.NoError(t, os.WriteFile(githubEnvPath, []byte("EXISTING=1\n"), 0644))
+
+	envs := []provider.EnvironmentActivation{
+		{
+			ContributedEnvVars: map[string]string{
+				"JAVA_HOME": "/usr/lib/jvm/java-17",
+				"MULTILINE": "line1\nline2",
+			},
+			ContributedPaths: []string{"/usr/lib/jvm/java-17/bin"},
+		},
+		{
+			ContributedPaths: []string{"/usr/local/node/bin"},
+		},
+	}
+	require.NoError(t, writeGitHubActionsEnvs(envs, githubEnvPath, githubPathPath))
+
+	content, err := os.ReadFile(githubEnvPath)
+	require.NoError(t, err)
+	require.Equal(t, "EXISTING=1\nJAVA_HOME=/usr/lib/jvm/java-17\nMULTILINE<<BITRISE_TOOLS_EOF\nline1\nline2\nBITRISE_TOOLS_EOF\n", string(content))
+
+	content, err = os.ReadFile(githubPathPath)
+	require.NoError(t, err)
+	require.Equal(t, "/usr/local/node/bin\n/usr/lib/jvm/java-17/bin\n", string(content))
+
+	require.EqualError(t, writeGitHubActionsEnvs(envs, "", ""), "GITHUB_ENV and GITHUB_PATH are not set, the github-actions format can only be used in GitHub Actions jobs")
+}
+
+func TestToolsSetup_ExportFileWithConfigAndVersionFile(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	dataDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataDir)
+	t.Setenv(cliAnalytics.DisabledEnvKey, "true")
+	// Preinstalled exact versions are activated without fetching the release index
+	goInstallDir := filepath.Join(native.DataDir(), "installs", "golang", "1.23.4")
+	nodeInstallDir := filepath.Join(native.DataDir(), "installs", "nodejs", "22.11.0")
+	require.NoError(t, os.MkdirAll(goInstallDir, 0755))
+	require.NoError(t, os.MkdirAll(nodeInstallDir, 0755))
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "bitrise.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte("format_version: \"13\"\ntools:\n  golang: 1.23.4\nworkflows:\n  primary: {}\n"), 0644))
+	toolVersionsPath := filepath.Join(tmpDir, ".tool-versions")
+	require.NoError(t, os.WriteFile(toolVersionsPath, []byte("nodejs 22.11.0\n"), 0644))
+	exportFile := filepath.Join(tmpDir, ".env")
+
+	cmd := toolsSetupSubcommand
+	t.Cleanup(func() { resetFlags(t, cmd) })
+	require.NoError(t, cmd.ParseFlags([]string{
+		"--config", configPath,
+		"--config", toolVersionsPath,
+		"--provider", "native",
+		"--lockfile", filepath.Join(tmpDir, "bitrise-tools.lock"),
+		"--format", outputFormatDotenv,
+		"--export-file", exportFile,
+	}))
+	require.NoError(t, toolsSetup(cmd))
+
+	content, err := os.ReadFile(exportFile)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "GOROOT=\""+goInstallDir+"\"\n")
+	require.Contains(t, string(content), filepath.Join(nodeInstallDir, "bin"))
+	require.Contains(t, string(content), filepath.Join(goInstallDir, "bin"))
+}
+
+// resetFlags restores the default values of the flags of a package level command parsed by a test.
+func resetFlags(t *testing.T, cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+			require.NoError(t, sliceValue.Replace(nil))
+		} else {
+			require.NoError(t, flag.Value.Set(flag.DefValue))
+		}
+		flag.Changed = false
+	})
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bitrise-io/bitrise/v2/analytics"
+	"github.com/bitrise-io/bitrise/v2/bitrise"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/toolprovider"
+	"github.com/bitrise-io/colorstring"
+	"github.com/spf13/cobra"
+)
+
+const (
+	toolsBundleCommandName          = "bundle"
+	toolsBundleExportSubcommandName = "export"
+	toolsBundleImportSubcommandName = "import"
+
+	toolsBundleOutputKey      = "output"
+	toolsBundleOutputShortKey = "o"
+)
+
+var toolsBundleCommand = &cobra.Command{
+	Use:   toolsBundleCommandName,
+	Short: "Export and import tool installs for machines without network access",
+	RunE:  requireKnownSubcommand,
+}
+
+var toolsBundleExportSubcommand = &cobra.Command{
+	Use:   toolsBundleExportSubcommandName + " [--provider PROVIDER] [--config FILE] [--workflow WORKFLOW] [--lockfile FILE] [--output FILE]",
+	Short: "Package the tools of bitrise.yml into a bundle archive",
+	Long: `Install the tools of the bitrise.yml tools section and package the installs into a bundle archive.
+
+The bundle can be imported with ` + "`bitrise tools bundle import`" + ` on machines of the same platform (OS and CPU architecture)
+that can not reach the tool download sources. The versions pinned by the tools lockfile are bundled if it exists.
+The tools of every workflow are bundled unless --workflow is given.
+
+EXAMPLES:
+   bitrise tools bundle export
+   bitrise tools bundle export --config bitrise.yml --workflow primary --output tools.tar.gz`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		logCommandParameters(cmd)
+		if err := toolsBundleExport(cmd); err != nil {
+			log.Errorf("Tool bundle export failed: %s", err)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+var toolsBundleImportSubcommand = &cobra.Command{
+	Use:   toolsBundleImportSubcommandName + " BUNDLE [--lockfile FILE]",
+	Short: "Unpack a tools bundle created by `bitrise tools bundle export`",
+	Long: `Unpack a tools bundle created by ` + "`bitrise tools bundle export`" + ` into the data dir of the tool provider.
+
+The bundled versions are pinned in the tools lockfile, so ` + "`bitrise run`" + ` and ` + "`bitrise tools setup`" + ` find them
+as already installed, without network access.
+
+BUNDLE: path of the bundle archive
+
+EXAMPLES:
+   bitrise tools bundle import ` + toolprovider.DefaultToolsBundleFileName,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logCommandParameters(cmd)
+		if err := toolsBundleImport(cmd, args[0]); err != nil {
+			log.Errorf("Tool bundle import failed: %s", err)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func init() {
+	toolsBundleCommand.AddCommand(
+		toolsBundleExportSubcommand,
+		toolsBundleImportSubcommand,
+	)
+
+	exportFlags := toolsBundleExportSubcommand.Flags()
+	exportFlags.StringP(toolsProviderKey, toolsProviderShortKey, "", toolsProviderFlagUsage)
+	exportFlags.StringP(toolsConfigKey, toolsConfigShortKey, DefaultBitriseConfigFileName, "bitrise.yml path to bundle the tools of.")
+	exportFlags.StringP(toolsWorkflowKey, "w", "", "Workflow ID to bundle the tools of (optional, bundles the tools of every workflow if not specified)")
+	exportFlags.String(toolsLockfileKey, toolprovider.DefaultToolsLockfileName, "Tools lockfile pinning the versions to bundle, used if it exists.")
+	exportFlags.StringP(toolsBundleOutputKey, toolsBundleOutputShortKey, toolprovider.DefaultToolsBundleFileName, "Path of the bundle archive.")
+
+	importFlags := toolsBundleImportSubcommand.Flags()
+	importFlags.String(toolsLockfileKey, toolprovider.DefaultToolsLockfileName, "Tools lockfile to pin the bundled versions in.")
+}
+
+func toolsBundleExport(cmd *cobra.Command) error {
+	configPath, _ := cmd.Flags().GetString(toolsConfigKey)
+	workflowID, _ := cmd.Flags().GetString(toolsWorkflowKey)
+	lockfilePath, _ := cmd.Flags().GetString(toolsLockfileKey)
+	providerFlag, _ := cmd.Flags().GetString(toolsProviderKey)
+	outputPath, _ := cmd.Flags().GetString(toolsBundleOutputKey)
+
+	var providerOverride *string
+	if providerFlag != "" {
+		providerOverride = &providerFlag
+	}
+
+	config, warnings, err := CreateBitriseConfigFromCLIParams("", configPath, bitrise.ValidationTypeFull)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	for _, warning := range warnings {
+		log.Warnf("Config warning: %s", warning)
+	}
+
+	lockfile, err := toolprovider.ReadToolsLockfile(lockfilePath)
+	if err != nil {
+		return err
+	}
+
+	tracker := analytics.NewDefaultTracker()
+	defer tracker.Wait()
+	manifest, err := toolprovider.ExportToolsBundle(config, tracker, workflowID, lockfile, providerOverride, outputPath)
+	if err != nil {
+		return err
+	}
+
+	for _, tool := range manifest.Tools {
+		log.Printf("%s %s → %s", tool.Tool, tool.Requested, colorstring.Green("%s", tool.Version))
+	}
+	log.Donef("Tools bundled in %s (%s, %s)", outputPath, manifest.Provider, manifest.Platform)
+
+	return nil
+}
+
+func toolsBundleImport(cmd *cobra.Command, archivePath string) error {
+	lockfilePath, _ := cmd.Flags().GetString(toolsLockfileKey)
+
+	manifest, err := toolprovider.ImportToolsBundle(archivePath)
+	if err != nil {
+		return err
+	}
+
+	if err := toolprovider.LockBundledTools(manifest, lockfilePath); err != nil {
+		return fmt.Errorf("update tools lockfile: %w", err)
+	}
+
+	for _, tool := range manifest.Tools {
+		log.Printf("%s %s", tool.Tool, colorstring.Green("%s", tool.Version))
+	}
+	log.Donef("Tools imported from %s, versions pinned in %s", archivePath, lockfilePath)
+
+	return nil
+}
@@ -117,6 +117,79 @@ func TestConvertToOutputFormat(t *testing.T) {
 			format: outputFormatBash,
 			want:   "export VAR_WITH_SPACE=\"value with spaces\"\n# \x1b[33;1mNOTE: Tools have been installed, but they need to be activated for the current shell session.\x1b[0m\n# Make sure to run \x1b[36;1meval \"$(bitrise tools setup --format bash ...)\"\x1b[0m instead\n",
 		},
+		{
+			name: "zsh format escapes shell expansions",
+			envs: []provider.EnvironmentActivation{
+				{
+					ContributedEnvVars: map[string]string{
+						"VAR": `a "quoted" $value`,
+					},
+				},
+			},
+			format: outputFormatZsh,
+			want:   "export VAR=\"a \\\"quoted\\\" \\$value\"\n# \x1b[33;1mNOTE: Tools have been installed, but they need to be activated for the current shell session.\x1b[0m\n# Make sure to run \x1b[36;1meval \"$(bitrise tools setup --format zsh ...)\"\x1b[0m instead\n",
+		},
+		{
+			name: "fish format with env vars and paths",
+			envs: []provider.EnvironmentActivation{
+				{
+					ContributedEnvVars: map[string]string{
+						"JAVA_HOME": "/usr/lib/jvm/java-17",
+					},
+					ContributedPaths: []string{"/usr/lib/jvm/java-17/bin"},
+				},
+			},
+			format: outputFormatFish,
+			want:   "set -gx JAVA_HOME \"/usr/lib/jvm/java-17\"\nset -gx PATH \"/usr/lib/jvm/java-17/bin\" \"/usr/bin\"\n",
+		},
+		{
+			name: "powershell format with env vars and paths",
+			envs: []provider.EnvironmentActivation{
+				{
+					ContributedEnvVars: map[string]string{
+						"JAVA_HOME": "/usr/lib/jvm/java-17",
+					},
+					ContributedPaths: []string{"/usr/lib/jvm/java-17/bin"},
+				},
+			},
+			format: outputFormatPowerShell,
+			want:   "$env:JAVA_HOME = \"/usr/lib/jvm/java-17\"\n$env:PATH = \"/usr/lib/jvm/java-17/bin:/usr/bin\"\n",
+		},
+		{
+			name: "dotenv format with env vars and paths",
+			envs: []provider.EnvironmentActivation{
+				{
+					ContributedEnvVars: map[string]string{
+						"JAVA_HOME": "/usr/lib/jvm/java-17",
+					},
+					ContributedPaths: []string{"/usr/lib/jvm/java-17/bin"},
+				},
+			},
+			format: outputFormatDotenv,
+			want:   "JAVA_HOME=\"/usr/lib/jvm/java-17\"\nPATH=\"/usr/lib/jvm/java-17/bin:/usr/bin\"\n",
+		},
+		{
+			name: "direnv format adds paths with PATH_add",
+			envs: []provider.EnvironmentActivation{
+				{
+					ContributedEnvVars: map[string]string{
+						"JAVA_HOME": "/usr/lib/jvm/java-17",
+					},
+					ContributedPaths: []string{"/usr/lib/jvm/java-17/bin"},
+				},
+				{
+					ContributedPaths: []string{"/usr/local/node/bin"},
+				},
+			},
+			format: outputFormatDirenv,
+			want:   "export JAVA_HOME=\"/usr/lib/jvm/java-17\"\nPATH_add \"/usr/lib/jvm/java-17/bin\" \"/usr/local/node/bin\"\n",
+		},
+		{
+			name:   "empty envs and direnv format",
+			envs:   []provider.EnvironmentActivation{},
+			format: outputFormatDirenv,
+			want:   "# No new tools were installed.",
+		},
 	}
 
 	for _, tt := range tests {
@@ -171,7 +171,10 @@ func trigger(cmd *cobra.Command, args []string) error {
 		failf("Failed to process agent config: %s", err)
 	}
 
-	runner := NewWorkflowRunner(runConfig, agentConfig, globalTracker)
+	runner, err := NewWorkflowRunner(runConfig, agentConfig, globalTracker)
+	if err != nil {
+		failf("Failed to create workflow runner: %s", err)
+	}
 	exitCode, err := runner.RunWorkflowsWithSetupAndCheckForUpdate()
 	if err != nil {
 		if err == errWorkflowRunFailed {
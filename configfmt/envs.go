@@ -0,0 +1,129 @@
+package configfmt
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/yamlnode"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	appKey       = "app"
+	workflowsKey = "workflows"
+	envsKey      = "envs"
+)
+
+// formatEnvironmentItem moves the env's key before its options and orders the options.
+func (f *formatter) formatEnvironmentItem(node *yaml.Node, path string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	if options, _ := yamlnode.MappingValue(node, envmanModels.OptionsKey); options != nil {
+		f.format(options, environmentItemOptionsType, joinPath(path, envmanModels.OptionsKey))
+	}
+	f.orderMapping(node, func(key string) int {
+		switch key {
+		case mergeKey:
+			return -1
+		case envmanModels.OptionsKey:
+			return 1
+		default:
+			return 0
+		}
+	}, path)
+}
+
+// removeRedundantFields removes the env options set to their default value from the app and workflow envs,
+// the same way as models.BitriseDataModel.RemoveRedundantFields does.
+// Step inputs are not touched: their defaults are defined by the step, not by envman.
+func (f *formatter) removeRedundantFields(root *yaml.Node) error {
+	app, _ := yamlnode.MappingValue(root, appKey)
+	envs, _ := yamlnode.MappingValue(app, envsKey)
+	if err := f.removeEnvsRedundantFields(envs, joinPath(appKey, envsKey)); err != nil {
+		return err
+	}
+
+	workflows, _ := yamlnode.MappingValue(root, workflowsKey)
+	if workflows == nil || workflows.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(workflows.Content); i += 2 {
+		envs, _ := yamlnode.MappingValue(workflows.Content[i+1], envsKey)
+		if err := f.removeEnvsRedundantFields(envs, joinPath(joinPath(workflowsKey, workflows.Content[i].Value), envsKey)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *formatter) removeEnvsRedundantFields(envs *yaml.Node, path string) error {
+	if envs == nil || envs.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	for idx, item := range envs.Content {
+		options, optionsIdx := yamlnode.MappingValue(item, envmanModels.OptionsKey)
+		if options == nil || options.Kind == yaml.AliasNode {
+			continue
+		}
+		if yamlnode.IsNull(options) {
+			yamlnode.RemoveMappingKey(item, optionsIdx)
+			continue
+		}
+		if options.Kind != yaml.MappingNode {
+			continue
+		}
+
+		// Decoding into the named map type would decode the options with the same type, which is not handled by envman
+		var values map[string]any
+		if err := item.Decode(&values); err != nil {
+			return fmt.Errorf("%s[%d]: %w", path, idx, err)
+		}
+		env := envmanModels.EnvironmentItemModel(values)
+		if err := models.RemoveEnvironmentRedundantFields(&env); err != nil {
+			return fmt.Errorf("%s[%d]: %w", path, idx, err)
+		}
+
+		cleaned, ok := env[envmanModels.OptionsKey].(envmanModels.EnvironmentItemOptionsModel)
+		if !ok {
+			yamlnode.RemoveMappingKey(item, optionsIdx)
+			continue
+		}
+
+		kept, err := optionKeys(cleaned)
+		if err != nil {
+			return fmt.Errorf("%s[%d]: %w", path, idx, err)
+		}
+		var content []*yaml.Node
+		for i := 0; i+1 < len(options.Content); i += 2 {
+			if kept[options.Content[i].Value] {
+				content = append(content, options.Content[i], options.Content[i+1])
+			}
+		}
+		options.Content = content
+	}
+
+	return nil
+}
+
+// optionKeys returns the keys of the options which are serialized.
+func optionKeys(options envmanModels.EnvironmentItemOptionsModel) (map[string]bool, error) {
+	out, err := yaml.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]any
+	if err := yaml.Unmarshal(out, &values); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for key := range values {
+		keys[key] = true
+	}
+	return keys, nil
+}
@@ -0,0 +1,271 @@
+// Package configfmt formats a Bitrise config (bitrise.yml) to a canonical layout: the keys of the config's objects
+// are ordered as defined by their models, step references use a consistent syntax and redundant env options are removed.
+// The config is edited as a YAML node tree, so comments and anchors are preserved.
+package configfmt
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/yamlnode"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	mergeKey                = "<<"
+	defaultStepLibSourceKey = "default_step_lib_source"
+	stepSourceSeparator     = "::"
+)
+
+var (
+	stepListItemType             = reflect.TypeOf(models.StepListItemModel{})
+	stepListItemStepOrBundleType = reflect.TypeOf(models.StepListItemStepOrBundleModel{})
+	stepListStepItemType         = reflect.TypeOf(models.StepListStepItemModel{})
+	environmentItemType          = reflect.TypeOf(envmanModels.EnvironmentItemModel{})
+	environmentItemOptionsType   = reflect.TypeOf(envmanModels.EnvironmentItemOptionsModel{})
+	withType                     = reflect.TypeOf(models.WithModel{})
+	stepBundleListItemType       = reflect.TypeOf(models.StepBundleListItemModel{})
+	stepType                     = reflect.TypeOf(stepmanModels.StepModel{})
+	nonStepLibSources            = []string{"path", "git", "_"}
+)
+
+// Result is the formatted config and the list of warnings about the parts of the config which could not be formatted.
+type Result struct {
+	Content  []byte
+	Warnings []string
+}
+
+// IsFormatted returns true if the formatted config is the same as the given content.
+func (r Result) IsFormatted(content []byte) bool {
+	return bytes.Equal(r.Content, content)
+}
+
+type formatter struct {
+	defaultStepLibSource string
+	warnings             []string
+}
+
+// Format formats the given config. Formatting a formatted config leaves it unchanged.
+func Format(content []byte) (Result, error) {
+	doc, err := yamlnode.ParseMapping(content)
+	if err != nil {
+		return Result{}, err
+	}
+
+	f := formatter{}
+	if source, _ := yamlnode.MappingValue(doc.Root, defaultStepLibSourceKey); source != nil && source.Kind == yaml.ScalarNode {
+		f.defaultStepLibSource = source.Value
+	}
+
+	f.format(doc.Root, reflect.TypeOf(models.BitriseDataModel{}), "")
+	if err := f.removeRedundantFields(doc.Root); err != nil {
+		return Result{}, err
+	}
+
+	formatted, err := doc.Encode()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to serialize formatted config: %w", err)
+	}
+
+	return Result{Content: formatted, Warnings: f.warnings}, nil
+}
+
+// format formats the node representing a value of the given model type, path is the node's location used in warnings.
+func (f *formatter) format(node *yaml.Node, t reflect.Type, path string) {
+	if node == nil || node.Kind == yaml.AliasNode {
+		// Aliased nodes are formatted at their anchor
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case stepListItemType, stepListItemStepOrBundleType:
+		f.formatStepListItem(node, path, true)
+		return
+	case stepListStepItemType:
+		f.formatStepListItem(node, path, false)
+		return
+	case environmentItemType:
+		f.formatEnvironmentItem(node, path)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind == yaml.MappingNode {
+			f.formatStruct(node, t, path)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == mergeKey {
+				continue
+			}
+			f.format(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value))
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for idx, item := range node.Content {
+			f.format(item, t.Elem(), fmt.Sprintf("%s[%d]", path, idx))
+		}
+	default:
+	}
+}
+
+// formatStruct orders the keys of the mapping as the fields of the model and formats the field values.
+// The merge key stays first, unknown keys are kept after the known ones in their original order.
+func (f *formatter) formatStruct(node *yaml.Node, t reflect.Type, path string) {
+	fields := map[string]reflect.Type{}
+	var order []string
+	collectFields(t, fields, &order)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if fieldType, ok := fields[key]; ok {
+			f.format(node.Content[i+1], fieldType, joinPath(path, key))
+		}
+	}
+
+	rank := map[string]int{mergeKey: -1}
+	for idx, name := range order {
+		rank[name] = idx
+	}
+	f.orderMapping(node, func(key string) int {
+		if r, ok := rank[key]; ok {
+			return r
+		}
+		return len(order)
+	}, path)
+}
+
+// orderMapping stably sorts the entries of the mapping by their rank. The original order is kept
+// (with a warning) if the new order would move an alias in front of its anchor.
+func (f *formatter) orderMapping(node *yaml.Node, rank func(key string) int, path string) {
+	type entry struct {
+		key, value *yaml.Node
+		rank       int
+	}
+	var entries []entry
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		entries = append(entries, entry{key: node.Content[i], value: node.Content[i+1], rank: rank(node.Content[i].Value)})
+	}
+
+	sorted := make([]entry, len(entries))
+	copy(sorted, entries)
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && sorted[j].rank < sorted[j-1].rank; j-- {
+			sorted[j], sorted[j-1] = sorted[j-1], sorted[j]
+		}
+	}
+
+	// Only the anchors of the mapping's own entries are affected by the reordering
+	local := map[*yaml.Node]bool{}
+	for _, e := range entries {
+		collectAnchors(e.key, local)
+		collectAnchors(e.value, local)
+	}
+
+	var ordered []*yaml.Node
+	defined := map[*yaml.Node]bool{}
+	for _, e := range sorted {
+		for _, n := range []*yaml.Node{e.key, e.value} {
+			if aliased := undefinedAlias(n, local, defined); aliased != "" {
+				if path == "" {
+					path = "the config root"
+				}
+				f.warnings = append(f.warnings, fmt.Sprintf("keys of %s are not reordered, because the alias (*%s) would precede its anchor", path, aliased))
+				return
+			}
+		}
+		ordered = append(ordered, e.key, e.value)
+	}
+	node.Content = ordered
+}
+
+func collectAnchors(node *yaml.Node, anchors map[*yaml.Node]bool) {
+	if node == nil || node.Kind == yaml.AliasNode {
+		return
+	}
+	if node.Anchor != "" {
+		anchors[node] = true
+	}
+	for _, child := range node.Content {
+		collectAnchors(child, anchors)
+	}
+}
+
+// undefinedAlias walks the node in document order and returns the name of the first alias of a local anchor
+// which was not yet defined, the anchors walked are added to defined.
+func undefinedAlias(node *yaml.Node, local, defined map[*yaml.Node]bool) string {
+	if node == nil {
+		return ""
+	}
+	if node.Kind == yaml.AliasNode {
+		if local[node.Alias] && !defined[node.Alias] {
+			return node.Value
+		}
+		return ""
+	}
+	if node.Anchor != "" {
+		defined[node] = true
+	}
+	for _, child := range node.Content {
+		if aliased := undefinedAlias(child, local, defined); aliased != "" {
+			return aliased
+		}
+	}
+	return ""
+}
+
+func collectFields(t reflect.Type, fields map[string]reflect.Type, order *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, inline := yamlFieldName(field)
+		if name == "-" {
+			continue
+		}
+		if inline {
+			collectFields(field.Type, fields, order)
+			continue
+		}
+		fields[name] = field.Type
+		*order = append(*order, name)
+	}
+}
+
+func yamlFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("yaml")
+	name, options, _ := strings.Cut(tag, ",")
+	inline := false
+	for _, option := range strings.Split(options, ",") {
+		if option == "inline" {
+			inline = true
+		}
+	}
+	if name == "" {
+		// yaml.v2 uses the lowercased field name by default
+		name = strings.ToLower(field.Name)
+	}
+	return name, inline
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
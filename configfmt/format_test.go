@@ -0,0 +1,221 @@
+package configfmt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       string
+		want         string
+		wantWarnings []string
+		wantErr      string
+	}{
+		{
+			name: "key order",
+			config: `workflows:
+  # Primary workflow
+  primary:
+    steps:
+    - script@1:
+        inputs:
+        - content: echo "Hello"
+        title: Hello # greeting
+    title: Primary
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+
+format_version: "13"
+`,
+			want: `format_version: "13"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+workflows:
+  # Primary workflow
+  primary:
+    title: Primary
+    steps:
+      - script@1:
+          title: Hello # greeting
+          inputs:
+            - content: echo "Hello"
+`,
+		},
+		{
+			name: "step references",
+			config: `format_version: "13"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+step_bundles:
+  build:
+    steps:
+    - https://github.com/bitrise-io/bitrise-steplib.git::script@1: {}
+workflows:
+  primary:
+    steps:
+    - https://github.com/bitrise-io/bitrise-steplib.git::git-clone@8: {}
+    - https://github.com/my-org/steplib.git::custom@1: {}
+    - path::./steps/local: {}
+    - git::https://github.com/bitrise-steplib/steps-script.git@master: {}
+    - bundle::build:
+        inputs:
+        - input: value
+        title: Build
+    - with:
+        steps:
+        - https://github.com/bitrise-io/bitrise-steplib.git::script@1:
+            inputs:
+            - content: echo
+            title: Test
+        container: golang
+`,
+			want: `format_version: "13"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+workflows:
+  primary:
+    steps:
+      - git-clone@8: {}
+      - https://github.com/my-org/steplib.git::custom@1: {}
+      - path::./steps/local: {}
+      - git::https://github.com/bitrise-steplib/steps-script.git@master: {}
+      - bundle::build:
+          title: Build
+          inputs:
+            - input: value
+      - with:
+          container: golang
+          steps:
+            - script@1:
+                title: Test
+                inputs:
+                  - content: echo
+step_bundles:
+  build:
+    steps:
+      - script@1: {}
+`,
+		},
+		{
+			name: "redundant env options",
+			config: `format_version: "13"
+app:
+  envs:
+  - opts:
+      is_expand: true
+    A: a
+  - B: b
+    opts:
+      title: B
+      is_expand: false
+      is_sensitive: false
+  - C: c
+    opts:
+workflows:
+  primary:
+    envs:
+    - D: d
+      opts: {is_expand: true, skip_if_empty: false}
+    steps:
+    - script@1:
+        inputs:
+        - content: echo
+          opts:
+            is_expand: true
+`,
+			want: `format_version: "13"
+app:
+  envs:
+    - A: a
+    - B: b
+      opts:
+        is_expand: false
+        title: B
+    - C: c
+workflows:
+  primary:
+    envs:
+      - D: d
+    steps:
+      - script@1:
+          inputs:
+            - content: echo
+              opts:
+                is_expand: true
+`,
+		},
+		{
+			name: "anchors and merge keys",
+			config: `format_version: "13"
+workflows:
+  _base: &base
+    steps:
+    - script@1: {}
+    title: Base
+  primary:
+    summary: Primary
+    <<: *base
+    title: Primary
+`,
+			want: `format_version: "13"
+workflows:
+  _base: &base
+    title: Base
+    steps:
+      - script@1: {}
+  primary:
+    <<: *base
+    title: Primary
+    summary: Primary
+`,
+		},
+		{
+			name: "alias preceding its anchor",
+			config: `format_version: "13"
+workflows:
+  primary:
+    description: &description Primary workflow
+    title: *description
+`,
+			want: `format_version: "13"
+workflows:
+  primary:
+    description: &description Primary workflow
+    title: *description
+`,
+			wantWarnings: []string{"keys of workflows.primary are not reordered, because the alias (*description) would precede its anchor"},
+		},
+		{
+			name: "unknown keys",
+			config: `workflows: {}
+custom: value
+format_version: "13"
+`,
+			want: `format_version: "13"
+workflows: {}
+custom: value
+`,
+		},
+		{
+			name:    "not a map",
+			config:  "- test\n",
+			wantErr: "config should be a YAML map",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Format([]byte(tt.config))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(result.Content))
+			require.Equal(t, tt.wantWarnings, result.Warnings)
+
+			// Formatting is idempotent
+			reformatted, err := Format(result.Content)
+			require.NoError(t, err)
+			require.True(t, reformatted.IsFormatted(result.Content), string(reformatted.Content))
+		})
+	}
+}
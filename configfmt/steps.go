@@ -0,0 +1,37 @@
+package configfmt
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	"gopkg.in/yaml.v3"
+)
+
+// formatStepListItem formats a step list item: a step, a with group or a step bundle (if allowBundles is set).
+func (f *formatter) formatStepListItem(node *yaml.Node, path string, allowBundles bool) {
+	if node.Kind != yaml.MappingNode || len(node.Content) != 2 {
+		return
+	}
+	key, value := node.Content[0], node.Content[1]
+
+	switch {
+	case allowBundles && key.Value == models.StepListItemWithKey:
+		f.format(value, withType, joinPath(path, key.Value))
+	case allowBundles && strings.HasPrefix(key.Value, models.StepListItemStepBundleKeyPrefix):
+		f.format(value, stepBundleListItemType, joinPath(path, key.Value))
+	default:
+		key.Value = f.canonicalStepReference(key.Value)
+		f.format(value, stepType, joinPath(path, key.Value))
+	}
+}
+
+// canonicalStepReference drops the StepLib source from the step reference if it is the config's default StepLib source,
+// for example https://github.com/bitrise-io/bitrise-steplib.git::script@1 becomes script@1.
+func (f *formatter) canonicalStepReference(reference string) string {
+	source, composite, found := strings.Cut(reference, stepSourceSeparator)
+	if !found || source == "" || composite == "" || source != f.defaultStepLibSource || slices.Contains(nonStepLibSources, source) {
+		return reference
+	}
+	return composite
+}
@@ -0,0 +1,209 @@
+package configlint
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/yamlnode"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+	"gopkg.in/yaml.v3"
+)
+
+const mergeKey = "<<"
+
+// Path locates a value in the config by the mapping keys (string) and sequence indexes (int) leading to it.
+type Path []any
+
+func (p Path) String() string {
+	var b strings.Builder
+	for _, elem := range p {
+		switch elem := elem.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", elem)
+		default:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			fmt.Fprintf(&b, "%v", elem)
+		}
+	}
+	return b.String()
+}
+
+// Append returns a new path with the given elements appended.
+func (p Path) Append(elems ...any) Path {
+	return append(slices.Clip(p), elems...)
+}
+
+// Config is the linted config: the validated model and the YAML node tree used to locate the findings.
+type Config struct {
+	Model   models.BitriseDataModel
+	Content []byte
+
+	root *yaml.Node
+}
+
+// Step is a step of a workflow, a with group or a step bundle.
+type Step struct {
+	Path Path
+	// Reference is the step list item key, for example script@1.
+	Reference string
+	Step      stepmanModels.StepModel
+}
+
+// EnvList is a list of envs or inputs defined in the config.
+type EnvList struct {
+	Path Path
+	Envs []envmanModels.EnvironmentItemModel
+}
+
+// locate returns the node of the given path: the key node if the path ends with a mapping key.
+func (c *Config) locate(path Path) *yaml.Node {
+	node := c.root
+	var located *yaml.Node
+	for _, elem := range path {
+		node = resolveAlias(node)
+		switch elem := elem.(type) {
+		case int:
+			if node == nil || node.Kind != yaml.SequenceNode || elem >= len(node.Content) {
+				return located
+			}
+			node = node.Content[elem]
+			located = node
+		default:
+			key, value := mappingEntry(node, fmt.Sprintf("%v", elem))
+			if key == nil {
+				return located
+			}
+			node, located = value, key
+		}
+	}
+	return located
+}
+
+// mappingEntry returns the key and value node of the given key, looking up the merged mappings too.
+func mappingEntry(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	if value, idx := yamlnode.MappingValue(mapping, key); idx >= 0 {
+		return mapping.Content[idx], value
+	}
+
+	merged, _ := yamlnode.MappingValue(mapping, mergeKey)
+	merged = resolveAlias(merged)
+	if merged == nil {
+		return nil, nil
+	}
+	sources := []*yaml.Node{merged}
+	if merged.Kind == yaml.SequenceNode {
+		sources = merged.Content
+	}
+	for _, source := range sources {
+		if k, v := mappingEntry(resolveAlias(source), key); k != nil {
+			return k, v
+		}
+	}
+	return nil, nil
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// Steps returns the steps of the workflows (including the with groups) and the step bundles.
+func (c *Config) Steps() []Step {
+	var steps []Step
+	for _, id := range slices.Sorted(maps.Keys(c.Model.Workflows)) {
+		workflowPath := Path{"workflows", id, "steps"}
+		for idx, item := range c.Model.Workflows[id].Steps {
+			key, itemType, err := item.GetKeyAndType()
+			if err != nil {
+				continue
+			}
+			switch itemType {
+			case models.StepListItemTypeStep:
+				if step, err := item.GetStep(); err == nil {
+					steps = append(steps, Step{Path: workflowPath.Append(idx, key), Reference: key, Step: *step})
+				}
+			case models.StepListItemTypeWith:
+				with, err := item.GetWith()
+				if err != nil {
+					continue
+				}
+				for withIdx, withItem := range with.Steps {
+					for reference, step := range withItem {
+						steps = append(steps, Step{Path: workflowPath.Append(idx, models.StepListItemWithKey, "steps", withIdx, reference), Reference: reference, Step: step})
+					}
+				}
+			default:
+			}
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(c.Model.StepBundles)) {
+		for idx, item := range c.Model.StepBundles[id].Steps {
+			key, itemType, err := item.GetKeyAndType()
+			if err != nil || itemType != models.StepListItemTypeStep {
+				continue
+			}
+			if step, err := item.GetStep(); err == nil {
+				steps = append(steps, Step{Path: Path{"step_bundles", id, "steps", idx, key}, Reference: key, Step: *step})
+			}
+		}
+	}
+
+	return steps
+}
+
+// EnvLists returns the env and input lists of the app, the workflows, the step bundles, the steps and the containers.
+func (c *Config) EnvLists() []EnvList {
+	lists := []EnvList{{Path: Path{"app", "envs"}, Envs: c.Model.App.Environments}}
+
+	for _, id := range slices.Sorted(maps.Keys(c.Model.Workflows)) {
+		lists = append(lists, EnvList{Path: Path{"workflows", id, "envs"}, Envs: c.Model.Workflows[id].Environments})
+		for idx, item := range c.Model.Workflows[id].Steps {
+			key, itemType, err := item.GetKeyAndType()
+			if err != nil || itemType != models.StepListItemTypeBundle {
+				continue
+			}
+			if bundle, err := item.GetBundle(); err == nil {
+				bundlePath := Path{"workflows", id, "steps", idx, models.StepListItemStepBundleKeyPrefix + key}
+				lists = append(lists,
+					EnvList{Path: bundlePath.Append("inputs"), Envs: bundle.Inputs},
+					EnvList{Path: bundlePath.Append("envs"), Envs: bundle.Environments},
+				)
+			}
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(c.Model.StepBundles)) {
+		bundle := c.Model.StepBundles[id]
+		lists = append(lists,
+			EnvList{Path: Path{"step_bundles", id, "inputs"}, Envs: bundle.Inputs},
+			EnvList{Path: Path{"step_bundles", id, "envs"}, Envs: bundle.Environments},
+		)
+	}
+
+	for _, step := range c.Steps() {
+		lists = append(lists, EnvList{Path: step.Path.Append("inputs"), Envs: step.Step.Inputs})
+	}
+
+	for _, containers := range []struct {
+		key        string
+		containers map[string]models.Container
+	}{{"containers", c.Model.Containers}, {"services", c.Model.Services}} {
+		for _, id := range slices.Sorted(maps.Keys(containers.containers)) {
+			lists = append(lists, EnvList{Path: Path{containers.key, id, "envs"}, Envs: containers.containers[id].Envs})
+		}
+	}
+
+	return lists
+}
@@ -0,0 +1,140 @@
+// Package configlint checks a Bitrise config (bitrise.yml) for problems which don't make the config invalid,
+// but are likely mistakes or bad practices: unpinned step versions, unused definitions, hard-coded secrets and so on.
+// Every problem is reported by a rule, identified by its ID, which can be suppressed by a comment in the config.
+package configlint
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/bitrise-io/bitrise/v2/bitrise"
+	"github.com/bitrise-io/bitrise/v2/yamlnode"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+var severities = []Severity{SeverityInfo, SeverityWarning, SeverityError}
+
+// ParseSeverity returns the severity with the given name.
+func ParseSeverity(name string) (Severity, error) {
+	for _, severity := range severities {
+		if string(severity) == name {
+			return severity, nil
+		}
+	}
+	return "", fmt.Errorf("invalid severity (%s), valid values: %s, %s, %s", name, SeverityError, SeverityWarning, SeverityInfo)
+}
+
+// AtLeast returns true if the severity is the same or more severe than the other one.
+func (s Severity) AtLeast(other Severity) bool {
+	return slices.Index(severities, s) >= slices.Index(severities, other)
+}
+
+// Rule checks the config for a kind of problem.
+type Rule struct {
+	ID          string
+	Description string
+	Severity    Severity
+	Check       func(config *Config) []Finding
+}
+
+// Finding is a problem found by a rule, located by its path in the config.
+type Finding struct {
+	Path    Path
+	Message string
+}
+
+// Problem is a finding of a rule, with its location in the config file.
+type Problem struct {
+	RuleID   string   `json:"rule_id"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Path     string   `json:"path,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+}
+
+// Result is the list of problems found in the config.
+type Result struct {
+	Problems []Problem `json:"problems"`
+}
+
+// HasProblems returns true if there is a problem with the given or higher severity.
+func (r Result) HasProblems(minSeverity Severity) bool {
+	for _, problem := range r.Problems {
+		if problem.Severity.AtLeast(minSeverity) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint checks the given config with the given rules. The config needs to be valid.
+func Lint(content []byte, rules []Rule) (Result, error) {
+	model, _, err := bitrise.ConfigModelFromYAMLBytesWithValidation(content, bitrise.ValidationTypeFull)
+	if err != nil {
+		return Result{}, fmt.Errorf("config is invalid: %w", err)
+	}
+	doc, err := yamlnode.ParseMapping(content)
+	if err != nil {
+		return Result{}, err
+	}
+
+	config := &Config{Model: model, Content: content, root: doc.Root}
+	suppressions := parseSuppressions(&doc.Node)
+
+	problems := []Problem{}
+	for _, rule := range rules {
+		for _, finding := range rule.Check(config) {
+			problem := Problem{
+				RuleID:   rule.ID,
+				Severity: rule.Severity,
+				Message:  finding.Message,
+				Path:     finding.Path.String(),
+			}
+			if node := config.locate(finding.Path); node != nil {
+				problem.Line, problem.Column = node.Line, node.Column
+			}
+			if suppressions.isSuppressed(rule.ID, problem.Line) {
+				continue
+			}
+			problems = append(problems, problem)
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		if problems[i].Column != problems[j].Column {
+			return problems[i].Column < problems[j].Column
+		}
+		return problems[i].RuleID < problems[j].RuleID
+	})
+
+	return Result{Problems: problems}, nil
+}
+
+// FilterRules returns the rules without the disabled ones. An error is returned for unknown rule IDs.
+func FilterRules(rules []Rule, disabledIDs []string) ([]Rule, error) {
+	for _, id := range disabledIDs {
+		if !slices.ContainsFunc(rules, func(rule Rule) bool { return rule.ID == id }) {
+			return nil, fmt.Errorf("unknown lint rule: %s", id)
+		}
+	}
+
+	var filtered []Rule
+	for _, rule := range rules {
+		if !slices.Contains(disabledIDs, rule.ID) {
+			filtered = append(filtered, rule)
+		}
+	}
+	return filtered, nil
+}
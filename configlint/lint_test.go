@@ -0,0 +1,277 @@
+package configlint
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint_rules(t *testing.T) {
+	tests := []struct {
+		name    string
+		ruleIDs []string
+		config  string
+		want    []Problem
+	}{
+		{
+			name:    "unpinned step version",
+			ruleIDs: []string{"unpinned-step-version"},
+			config: `format_version: "13"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+workflows:
+  primary:
+    steps:
+    - script@1: {}
+    - script: {}
+    - git::https://github.com/bitrise-steplib/steps-script.git: {}
+    - path::./step: {}
+`,
+			want: []Problem{
+				{RuleID: "unpinned-step-version", Severity: SeverityWarning, Message: "step (script) is not pinned to a version", Path: "workflows.primary.steps[1].script", Line: 7, Column: 7},
+				{RuleID: "unpinned-step-version", Severity: SeverityWarning, Message: "step (git::https://github.com/bitrise-steplib/steps-script.git) is not pinned to a version", Path: "workflows.primary.steps[2].git::https://github.com/bitrise-steplib/steps-script.git", Line: 8, Column: 7},
+			},
+		},
+		{
+			name:    "missing step timeout",
+			ruleIDs: []string{"missing-step-timeout"},
+			config: `format_version: "13"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+step_bundles:
+  test:
+    steps:
+    - script@1: {}
+workflows:
+  primary:
+    steps:
+    - script@1:
+        timeout: 60
+    - with:
+        container: golang
+        steps:
+        - script@1: {}
+containers:
+  golang:
+    image: golang:1.22
+`,
+			want: []Problem{
+				{RuleID: "missing-step-timeout", Severity: SeverityInfo, Message: "step (script@1) has no timeout", Path: "step_bundles.test.steps[0].script@1", Line: 6, Column: 7},
+				{RuleID: "missing-step-timeout", Severity: SeverityInfo, Message: "step (script@1) has no timeout", Path: "workflows.primary.steps[1].with.steps[0].script@1", Line: 15, Column: 11},
+			},
+		},
+		{
+			name:    "unused definitions",
+			ruleIDs: []string{"unused-workflow", "unused-step-bundle", "unused-container"},
+			config: `format_version: "13"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+containers:
+  golang:
+    type: execution
+    image: golang:1.22
+  redis:
+    type: service
+    image: redis:7
+step_bundles:
+  used: {}
+  unused: {}
+workflows:
+  _used:
+    after_run:
+    - _used_by_utility
+  _used_by_utility: {}
+  _unused: {}
+  primary:
+    before_run:
+    - _used
+    steps:
+    - bundle::used: {}
+    - script@1:
+        timeout: 10
+        execution_container: golang
+`,
+			want: []Problem{
+				{RuleID: "unused-container", Severity: SeverityWarning, Message: "container (redis) is not used by any step, step bundle or with group", Path: "containers.redis", Line: 7, Column: 3},
+				{RuleID: "unused-step-bundle", Severity: SeverityWarning, Message: "step bundle (unused) is not used by any workflow or step bundle", Path: "step_bundles.unused", Line: 12, Column: 3},
+				{RuleID: "unused-workflow", Severity: SeverityWarning, Message: "utility workflow (_unused) is not used by any workflow or pipeline", Path: "workflows._unused", Line: 18, Column: 3},
+			},
+		},
+		{
+			name:    "always run misuse",
+			ruleIDs: []string{"always-run-misuse"},
+			config: `format_version: "13"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+workflows:
+  primary:
+    steps:
+    - script@1:
+        is_always_run: true
+        run_if: "{{ not .IsBuildFailed }}"
+    - script@1:
+        is_always_run: true
+        run_if: .IsBuildFailed
+`,
+			want: []Problem{
+				{RuleID: "always-run-misuse", Severity: SeverityWarning, Message: "step (script@1) is set to always run, but its run_if ({{ not .IsBuildFailed }}) prevents it from running after a failed step", Path: "workflows.primary.steps[0].script@1.is_always_run", Line: 7, Column: 9},
+			},
+		},
+		{
+			name:    "plain secret",
+			ruleIDs: []string{"plain-secret"},
+			config: `format_version: "13"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+app:
+  envs:
+  - API_TOKEN: abcdef123456
+  - GITHUB_TOKEN: $SECRET_GITHUB_TOKEN
+  - TOKEN_ENABLED: true
+workflows:
+  primary:
+    steps:
+    - slack@4:
+        inputs:
+        - webhook_secret: abcdef123456
+`,
+			want: []Problem{
+				{RuleID: "plain-secret", Severity: SeverityError, Message: "API_TOKEN looks like a secret, but its value is set in the config, reference a secret env var instead", Path: "app.envs[0].API_TOKEN", Line: 5, Column: 5},
+				{RuleID: "plain-secret", Severity: SeverityError, Message: "webhook_secret looks like a secret, but its value is set in the config, reference a secret env var instead", Path: "workflows.primary.steps[0].slack@4.inputs[0].webhook_secret", Line: 13, Column: 11},
+			},
+		},
+		{
+			name:    "duplicate env key",
+			ruleIDs: []string{"duplicate-env-key"},
+			config: `format_version: "13"
+workflows:
+  primary:
+    envs:
+    - A: a
+    - B: b
+    - A: c
+`,
+			want: []Problem{
+				{RuleID: "duplicate-env-key", Severity: SeverityWarning, Message: "A is already defined in workflows.primary.envs[0]", Path: "workflows.primary.envs[2].A", Line: 7, Column: 7},
+			},
+		},
+		{
+			name:    "large inline script",
+			ruleIDs: []string{"large-inline-script"},
+			config: `format_version: "13"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+workflows:
+  primary:
+    steps:
+    - script@1:
+        inputs:
+        - content: |
+` + strings.Repeat("            echo\n", MaxInlineScriptLines+1),
+			want: []Problem{
+				{RuleID: "large-inline-script", Severity: SeverityInfo, Message: "step (script@1) has an inline script of 101 lines, move it to a script file", Path: "workflows.primary.steps[0].script@1.inputs[0].content", Line: 8, Column: 11},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Lint([]byte(tt.config), rulesByID(t, tt.ruleIDs...))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, result.Problems)
+		})
+	}
+}
+
+func TestLint_suppressions(t *testing.T) {
+	config := `format_version: "13"
+# bitrise-lint-disable-file unused-step-bundle
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+step_bundles:
+  unused: {}
+workflows:
+  primary:
+    steps:
+    # bitrise-lint-disable missing-step-timeout
+    - script: {}
+    - script: {} # bitrise-lint-disable
+    - script: {}
+    - script@1:
+        inputs:
+        - content: |
+            # bitrise-lint-disable-file
+`
+
+	result, err := Lint([]byte(config), DefaultRules())
+	require.NoError(t, err)
+
+	var got []string
+	for _, problem := range result.Problems {
+		got = append(got, problem.String("bitrise.yml"))
+	}
+	require.Equal(t, []string{
+		"bitrise.yml:10:7: warning: step (script) is not pinned to a version [unpinned-step-version]",
+		"bitrise.yml:12:7: info: step (script) has no timeout [missing-step-timeout]",
+		"bitrise.yml:12:7: warning: step (script) is not pinned to a version [unpinned-step-version]",
+		"bitrise.yml:13:7: info: step (script@1) has no timeout [missing-step-timeout]",
+	}, got)
+}
+
+func TestLint_invalidConfig(t *testing.T) {
+	_, err := Lint([]byte("format_version: 1\nworkflows:\n  primary:\n    before_run:\n    - missing\n"), DefaultRules())
+	require.ErrorContains(t, err, "config is invalid")
+}
+
+func TestResult_HasProblems(t *testing.T) {
+	result := Result{Problems: []Problem{{Severity: SeverityWarning}}}
+
+	require.True(t, result.HasProblems(SeverityInfo))
+	require.True(t, result.HasProblems(SeverityWarning))
+	require.False(t, result.HasProblems(SeverityError))
+	require.False(t, Result{}.HasProblems(SeverityInfo))
+}
+
+func TestFilterRules(t *testing.T) {
+	rules, err := FilterRules(DefaultRules(), []string{"missing-step-timeout"})
+	require.NoError(t, err)
+	require.Len(t, rules, len(DefaultRules())-1)
+	for _, rule := range rules {
+		require.NotEqual(t, "missing-step-timeout", rule.ID)
+	}
+
+	_, err = FilterRules(DefaultRules(), []string{"unknown"})
+	require.EqualError(t, err, "unknown lint rule: unknown")
+}
+
+func TestResult_SARIF(t *testing.T) {
+	rules := rulesByID(t, "unpinned-step-version")
+	result := Result{Problems: []Problem{
+		{RuleID: "unpinned-step-version", Severity: SeverityWarning, Message: "step (script) is not pinned to a version", Line: 6, Column: 7},
+	}}
+
+	out, err := result.SARIF(rules, "bitrise.yml", "2.0.0")
+	require.NoError(t, err)
+
+	var log map[string]any
+	require.NoError(t, json.Unmarshal(out, &log))
+	require.Equal(t, "2.1.0", log["version"])
+
+	run := log["runs"].([]any)[0].(map[string]any)
+	driver := run["tool"].(map[string]any)["driver"].(map[string]any)
+	require.Equal(t, "2.0.0", driver["version"])
+	require.Equal(t, "unpinned-step-version", driver["rules"].([]any)[0].(map[string]any)["id"])
+
+	sarifResult := run["results"].([]any)[0].(map[string]any)
+	require.Equal(t, "warning", sarifResult["level"])
+	location := sarifResult["locations"].([]any)[0].(map[string]any)["physicalLocation"].(map[string]any)
+	require.Equal(t, map[string]any{"uri": "bitrise.yml"}, location["artifactLocation"])
+	require.Equal(t, map[string]any{"startLine": float64(6), "startColumn": float64(7)}, location["region"])
+}
+
+func rulesByID(t *testing.T, ids ...string) []Rule {
+	var rules []Rule
+	for _, rule := range DefaultRules() {
+		for _, id := range ids {
+			if rule.ID == id {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	require.Len(t, rules, len(ids))
+	return rules
+}
@@ -0,0 +1,133 @@
+package configlint
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "bitrise lint"
+	toolURI      = "https://github.com/bitrise-io/bitrise"
+)
+
+// JSON returns the result as indented JSON.
+func (r Result) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// String returns the problem in the "path:line:column: severity: message [rule]" format.
+func (p Problem) String(configPth string) string {
+	location := configPth
+	if p.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", configPth, p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", location, p.Severity, p.Message, p.RuleID)
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// SARIF returns the result as a SARIF log, which can be uploaded to code scanning services to annotate pull requests.
+// The configURI is the config's path relative to the repository root, toolVersion is the version of the CLI.
+func (r Result) SARIF(rules []Rule, configURI, toolVersion string) ([]byte, error) {
+	driver := sarifDriver{
+		Name:           toolName,
+		Version:        toolVersion,
+		InformationURI: toolURI,
+		Rules:          []sarifRule{},
+	}
+	for _, rule := range rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, problem := range r.Problems {
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: configURI}}
+		if problem.Line > 0 {
+			location.Region = &sarifRegion{StartLine: problem.Line, StartColumn: problem.Column}
+		}
+		results = append(results, sarifResult{
+			RuleID:    problem.RuleID,
+			Level:     sarifLevel(problem.Severity),
+			Message:   sarifMessage{Text: problem.Message},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}, "", "  ")
+}
+
+func sarifLevel(severity Severity) string {
+	if severity == SeverityInfo {
+		return "note"
+	}
+	return string(severity)
+}
@@ -0,0 +1,344 @@
+package configlint
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+	"github.com/bitrise-io/stepman/stepid"
+)
+
+const (
+	defaultStepLibSource  = "https://github.com/bitrise-io/bitrise-steplib.git"
+	scriptStepID          = "script"
+	scriptContentInputKey = "content"
+	// MaxInlineScriptLines is the number of lines above which an inline script is reported.
+	MaxInlineScriptLines = 100
+	// minSecretLength is the length below which a value is not considered to be a secret.
+	minSecretLength = 8
+)
+
+var (
+	secretKeyPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private_?key|access_?key|credential)`)
+	buildOKRunIfs    = []string{".IsBuildOK", "not .IsBuildFailed"}
+)
+
+// DefaultRules returns the built-in rules.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			ID:          "unpinned-step-version",
+			Description: "StepLib and git steps should reference a version, otherwise the latest version (or the default branch) is used.",
+			Severity:    SeverityWarning,
+			Check:       checkUnpinnedStepVersions,
+		},
+		{
+			ID:          "missing-step-timeout",
+			Description: "Steps should set a timeout, so a hanging step doesn't block the build until the build timeout.",
+			Severity:    SeverityInfo,
+			Check:       checkMissingStepTimeouts,
+		},
+		{
+			ID:          "unused-workflow",
+			Description: "Utility workflows can't be run directly, they should be used by another workflow or a pipeline.",
+			Severity:    SeverityWarning,
+			Check:       checkUnusedWorkflows,
+		},
+		{
+			ID:          "unused-step-bundle",
+			Description: "Step bundles should be used by a workflow or another step bundle.",
+			Severity:    SeverityWarning,
+			Check:       checkUnusedStepBundles,
+		},
+		{
+			ID:          "unused-container",
+			Description: "Containers should be used by a step, a step bundle or a with group.",
+			Severity:    SeverityWarning,
+			Check:       checkUnusedContainers,
+		},
+		{
+			ID:          "always-run-misuse",
+			Description: "A step set to always run should not have a run_if which prevents it from running after a failed step.",
+			Severity:    SeverityWarning,
+			Check:       checkAlwaysRunMisuse,
+		},
+		{
+			ID:          "plain-secret",
+			Description: "Secrets should be stored as secret env vars and referenced from the config, instead of being set in the config.",
+			Severity:    SeverityError,
+			Check:       checkPlainSecrets,
+		},
+		{
+			ID:          "duplicate-env-key",
+			Description: "An env or input key should be defined once in a list, the later definitions override the earlier ones.",
+			Severity:    SeverityWarning,
+			Check:       checkDuplicateEnvKeys,
+		},
+		{
+			ID:          "large-inline-script",
+			Description: fmt.Sprintf("Scripts longer than %d lines should be moved to a script file in the repository.", MaxInlineScriptLines),
+			Severity:    SeverityInfo,
+			Check:       checkLargeInlineScripts,
+		},
+	}
+}
+
+func checkUnpinnedStepVersions(config *Config) []Finding {
+	var findings []Finding
+	for _, step := range config.Steps() {
+		id, err := stepid.CreateCanonicalIDFromString(step.Reference, config.defaultStepLibSource())
+		if err != nil || id.Version != "" || id.SteplibSource == "path" {
+			continue
+		}
+		findings = append(findings, Finding{
+			Path:    step.Path,
+			Message: fmt.Sprintf("step (%s) is not pinned to a version", step.Reference),
+		})
+	}
+	return findings
+}
+
+func checkMissingStepTimeouts(config *Config) []Finding {
+	var findings []Finding
+	for _, step := range config.Steps() {
+		if step.Step.Timeout != nil {
+			continue
+		}
+		findings = append(findings, Finding{
+			Path:    step.Path,
+			Message: fmt.Sprintf("step (%s) has no timeout", step.Reference),
+		})
+	}
+	return findings
+}
+
+func checkUnusedWorkflows(config *Config) []Finding {
+	used := map[string]bool{}
+	for _, item := range config.Model.TriggerMap {
+		used[item.WorkflowID] = true
+	}
+	for _, workflow := range config.Model.Workflows {
+		for _, id := range append(slices.Clone(workflow.BeforeRun), workflow.AfterRun...) {
+			used[id] = true
+		}
+	}
+	for _, stage := range config.Model.Stages {
+		for _, item := range stage.Workflows {
+			for id := range item {
+				used[id] = true
+			}
+		}
+	}
+	for _, pipeline := range config.Model.Pipelines {
+		for id, workflow := range pipeline.Workflows {
+			used[id] = true
+			used[workflow.Uses] = true
+		}
+	}
+
+	var findings []Finding
+	for _, id := range slices.Sorted(maps.Keys(config.Model.Workflows)) {
+		if !strings.HasPrefix(id, "_") || used[id] {
+			continue
+		}
+		findings = append(findings, Finding{
+			Path:    Path{"workflows", id},
+			Message: fmt.Sprintf("utility workflow (%s) is not used by any workflow or pipeline", id),
+		})
+	}
+	return findings
+}
+
+func checkUnusedStepBundles(config *Config) []Finding {
+	used := map[string]bool{}
+	for _, workflow := range config.Model.Workflows {
+		for _, item := range workflow.Steps {
+			if id, itemType, err := item.GetKeyAndType(); err == nil && itemType == models.StepListItemTypeBundle {
+				used[id] = true
+			}
+		}
+	}
+	for _, bundle := range config.Model.StepBundles {
+		for _, item := range bundle.Steps {
+			if id, itemType, err := item.GetKeyAndType(); err == nil && itemType == models.StepListItemTypeBundle {
+				used[id] = true
+			}
+		}
+	}
+
+	var findings []Finding
+	for _, id := range slices.Sorted(maps.Keys(config.Model.StepBundles)) {
+		if used[id] {
+			continue
+		}
+		findings = append(findings, Finding{
+			Path:    Path{"step_bundles", id},
+			Message: fmt.Sprintf("step bundle (%s) is not used by any workflow or step bundle", id),
+		})
+	}
+	return findings
+}
+
+func checkUnusedContainers(config *Config) []Finding {
+	used := map[string]bool{}
+	useReferences := func(executionContainer stepmanModels.ContainerReference, serviceContainers []stepmanModels.ContainerReference) {
+		for _, ref := range append([]stepmanModels.ContainerReference{executionContainer}, serviceContainers...) {
+			if containerConfig, err := stepmanModels.GetContainerConfig(ref); err == nil && containerConfig != nil {
+				used[containerConfig.ContainerID] = true
+			}
+		}
+	}
+
+	for _, step := range config.Steps() {
+		useReferences(step.Step.ExecutionContainer, step.Step.ServiceContainers)
+	}
+	for _, bundle := range config.Model.StepBundles {
+		useReferences(bundle.ExecutionContainer, bundle.ServiceContainers)
+	}
+	for _, workflow := range config.Model.Workflows {
+		for _, item := range workflow.Steps {
+			_, itemType, err := item.GetKeyAndType()
+			if err != nil {
+				continue
+			}
+			switch itemType {
+			case models.StepListItemTypeBundle:
+				if bundle, err := item.GetBundle(); err == nil {
+					useReferences(bundle.ExecutionContainer, bundle.ServiceContainers)
+				}
+			case models.StepListItemTypeWith:
+				if with, err := item.GetWith(); err == nil {
+					used[with.ContainerID] = true
+					for _, id := range with.ServiceIDs {
+						used[id] = true
+					}
+				}
+			default:
+			}
+		}
+	}
+
+	var findings []Finding
+	for _, key := range []string{"containers", "services"} {
+		containers := config.Model.Containers
+		if key == "services" {
+			containers = config.Model.Services
+		}
+		for _, id := range slices.Sorted(maps.Keys(containers)) {
+			if used[id] {
+				continue
+			}
+			findings = append(findings, Finding{
+				Path:    Path{key, id},
+				Message: fmt.Sprintf("container (%s) is not used by any step, step bundle or with group", id),
+			})
+		}
+	}
+	return findings
+}
+
+func checkAlwaysRunMisuse(config *Config) []Finding {
+	var findings []Finding
+	for _, step := range config.Steps() {
+		if step.Step.IsAlwaysRun == nil || !*step.Step.IsAlwaysRun || step.Step.RunIf == nil {
+			continue
+		}
+		if !slices.Contains(buildOKRunIfs, normalizeRunIf(*step.Step.RunIf)) {
+			continue
+		}
+		findings = append(findings, Finding{
+			Path:    step.Path.Append("is_always_run"),
+			Message: fmt.Sprintf("step (%s) is set to always run, but its run_if (%s) prevents it from running after a failed step", step.Reference, *step.Step.RunIf),
+		})
+	}
+	return findings
+}
+
+// normalizeRunIf strips the template delimiters and the redundant whitespace of a run_if expression.
+func normalizeRunIf(runIf string) string {
+	runIf = strings.TrimSpace(runIf)
+	runIf = strings.TrimPrefix(runIf, "{{")
+	runIf = strings.TrimSuffix(runIf, "}}")
+	return strings.Join(strings.Fields(runIf), " ")
+}
+
+func checkPlainSecrets(config *Config) []Finding {
+	var findings []Finding
+	for _, list := range config.EnvLists() {
+		for idx, env := range list.Envs {
+			key, value, err := env.GetKeyValuePairWithType()
+			if err != nil || !secretKeyPattern.MatchString(key) {
+				continue
+			}
+			str, ok := value.(string)
+			if !ok || len(str) < minSecretLength || strings.Contains(str, "$") {
+				continue
+			}
+			findings = append(findings, Finding{
+				Path:    list.Path.Append(idx, key),
+				Message: fmt.Sprintf("%s looks like a secret, but its value is set in the config, reference a secret env var instead", key),
+			})
+		}
+	}
+	return findings
+}
+
+func checkDuplicateEnvKeys(config *Config) []Finding {
+	var findings []Finding
+	for _, list := range config.EnvLists() {
+		defined := map[string]int{}
+		for idx, env := range list.Envs {
+			key, _, err := env.GetKeyValuePairWithType()
+			if err != nil {
+				continue
+			}
+			if firstIdx, ok := defined[key]; ok {
+				findings = append(findings, Finding{
+					Path:    list.Path.Append(idx, key),
+					Message: fmt.Sprintf("%s is already defined in %s", key, list.Path.Append(firstIdx)),
+				})
+				continue
+			}
+			defined[key] = idx
+		}
+	}
+	return findings
+}
+
+func checkLargeInlineScripts(config *Config) []Finding {
+	var findings []Finding
+	for _, step := range config.Steps() {
+		id, err := stepid.CreateCanonicalIDFromString(step.Reference, config.defaultStepLibSource())
+		if err != nil || id.IDorURI != scriptStepID {
+			continue
+		}
+		for idx, input := range step.Step.Inputs {
+			key, value, err := input.GetKeyValuePairWithType()
+			if err != nil || key != scriptContentInputKey {
+				continue
+			}
+			content, ok := value.(string)
+			if !ok {
+				continue
+			}
+			if lines := strings.Count(strings.TrimRight(content, "\n"), "\n") + 1; lines > MaxInlineScriptLines {
+				findings = append(findings, Finding{
+					Path:    step.Path.Append("inputs", idx, key),
+					Message: fmt.Sprintf("step (%s) has an inline script of %d lines, move it to a script file", step.Reference, lines),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func (c *Config) defaultStepLibSource() string {
+	if c.Model.DefaultStepLibSource != "" {
+		return c.Model.DefaultStepLibSource
+	}
+	return defaultStepLibSource
+}
@@ -0,0 +1,87 @@
+package configlint
+
+import (
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DisableDirective suppresses the listed rules (or all rules if none is listed) on the line of the commented node,
+	// for example:
+	//
+	//	# bitrise-lint-disable missing-step-timeout
+	//	- script@1:
+	DisableDirective = "bitrise-lint-disable"
+	// DisableFileDirective suppresses the listed rules (or all rules if none is listed) in the whole config.
+	DisableFileDirective = "bitrise-lint-disable-file"
+)
+
+// allRules is the rule list of a directive without explicit rule IDs.
+var allRules = []string{"*"}
+
+type suppressions struct {
+	file  []string
+	lines map[int][]string
+}
+
+// parseSuppressions collects the suppression directives from the comments of the YAML nodes.
+// The comments are read from the node tree (not from the raw content), so block scalars can't contain directives.
+func parseSuppressions(doc *yaml.Node) suppressions {
+	s := suppressions{lines: map[int][]string{}}
+	s.collect(doc)
+	return s
+}
+
+func (s *suppressions) collect(node *yaml.Node) {
+	if node == nil {
+		return
+	}
+	for _, comment := range []string{node.HeadComment, node.LineComment, node.FootComment} {
+		for _, line := range strings.Split(comment, "\n") {
+			directive, ruleIDs, ok := parseDirective(line)
+			if !ok {
+				continue
+			}
+			if directive == DisableFileDirective {
+				s.file = append(s.file, ruleIDs...)
+			} else {
+				s.lines[node.Line] = append(s.lines[node.Line], ruleIDs...)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		s.collect(child)
+	}
+}
+
+func parseDirective(comment string) (string, []string, bool) {
+	fields := strings.Fields(strings.TrimLeft(strings.TrimSpace(comment), "#"))
+	if len(fields) == 0 || (fields[0] != DisableDirective && fields[0] != DisableFileDirective) {
+		return "", nil, false
+	}
+
+	var ruleIDs []string
+	for _, field := range fields[1:] {
+		for _, id := range strings.Split(field, ",") {
+			if id != "" {
+				ruleIDs = append(ruleIDs, id)
+			}
+		}
+	}
+	if len(ruleIDs) == 0 {
+		ruleIDs = allRules
+	}
+	return fields[0], ruleIDs, true
+}
+
+func (s suppressions) isSuppressed(ruleID string, line int) bool {
+	matches := func(ids []string) bool {
+		return slices.Contains(ids, ruleID) || slices.Contains(ids, allRules[0])
+	}
+	if matches(s.file) {
+		return true
+	}
+	return line > 0 && matches(s.lines[line])
+}
@@ -0,0 +1,189 @@
+package configmigrate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/yamlnode"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	typeKey                  = "type"
+	stepsKey                 = "steps"
+	withContainerKey         = "container"
+	withServicesKey          = "services"
+	executionContainerKey    = "execution_container"
+	serviceContainersKey     = "service_containers"
+	duplicateServiceIDSuffix = "_service"
+)
+
+// migrateServices moves the service definitions of the top level services map to the containers map
+// as service type containers. A service is renamed if a container with the same ID exists.
+func (m *migrator) migrateServices() error {
+	services, servicesIdx := yamlnode.MappingValue(m.root, servicesKey)
+	if services == nil {
+		return nil
+	}
+	if !yamlnode.IsNull(services) && services.Kind != yaml.MappingNode {
+		return fmt.Errorf("services should be a map")
+	}
+
+	containers, _ := yamlnode.MappingValue(m.root, containersKey)
+	if containers != nil && !yamlnode.IsNull(containers) && containers.Kind != yaml.MappingNode {
+		return fmt.Errorf("containers should be a map")
+	}
+
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		key, value := services.Content[i], yamlnode.BlockMapping(services.Content[i+1])
+		services.Content[i+1] = value
+		yamlnode.PrependMappingValue(value, typeKey, yamlnode.String(string(models.ContainerTypeService)))
+
+		id := key.Value
+		if existing, _ := yamlnode.MappingValue(containers, id); existing != nil {
+			newID := id + duplicateServiceIDSuffix
+			for n := 2; ; n++ {
+				if conflicting, _ := yamlnode.MappingValue(containers, newID); conflicting == nil {
+					if conflicting, _ := yamlnode.MappingValue(services, newID); conflicting == nil {
+						break
+					}
+				}
+				newID = fmt.Sprintf("%s%s%d", id, duplicateServiceIDSuffix, n)
+			}
+			key.Value = newID
+			m.serviceIDs[id] = newID
+			m.changes = append(m.changes, fmt.Sprintf("moved service (%s) to containers as service container (%s), because a container with the same ID exists", id, newID))
+		} else {
+			m.changes = append(m.changes, fmt.Sprintf("moved service (%s) to containers as service container", id))
+		}
+	}
+
+	if containers == nil || yamlnode.IsNull(containers) {
+		// Rename the services key in place to keep its position and comments
+		m.root.Content[servicesIdx].Value = containersKey
+		if containers != nil {
+			_, containersIdx := yamlnode.MappingValue(m.root, containersKey)
+			yamlnode.RemoveMappingKey(m.root, containersIdx)
+		}
+		return nil
+	}
+
+	containers.Content = append(containers.Content, services.Content...)
+	yamlnode.RemoveMappingKey(m.root, servicesIdx)
+	return nil
+}
+
+// migrateContainerTypes sets the type of containers used by with groups (which have no type) to execution.
+func (m *migrator) migrateContainerTypes() error {
+	containers, _ := yamlnode.MappingValue(m.root, containersKey)
+	if containers == nil || containers.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(containers.Content); i += 2 {
+		value := containers.Content[i+1]
+		if containerType, _ := yamlnode.MappingValue(value, typeKey); containerType != nil {
+			continue
+		}
+		value = yamlnode.BlockMapping(value)
+		containers.Content[i+1] = value
+		yamlnode.PrependMappingValue(value, typeKey, yamlnode.String(string(models.ContainerTypeExecution)))
+		m.changes = append(m.changes, fmt.Sprintf("set type of container (%s) to %s", containers.Content[i].Value, models.ContainerTypeExecution))
+	}
+
+	return nil
+}
+
+// migrateWithGroups replaces the with groups of workflows by their steps,
+// with the group's container and services set as the steps' execution_container and service_containers.
+func (m *migrator) migrateWithGroups() error {
+	workflows, _ := yamlnode.MappingValue(m.root, workflowsKey)
+	if workflows == nil || workflows.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(workflows.Content); i += 2 {
+		workflowID := workflows.Content[i].Value
+		steps, _ := yamlnode.MappingValue(workflows.Content[i+1], stepsKey)
+		if steps == nil || steps.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		var items []*yaml.Node
+		for _, item := range steps.Content {
+			if item.Kind != yaml.MappingNode || len(item.Content) != 2 || item.Content[0].Value != models.StepListItemWithKey {
+				items = append(items, item)
+				continue
+			}
+
+			groupSteps, err := m.convertWithGroup(workflowID, item)
+			if err != nil {
+				return err
+			}
+			items = append(items, groupSteps...)
+		}
+		steps.Content = items
+	}
+
+	return nil
+}
+
+func (m *migrator) convertWithGroup(workflowID string, item *yaml.Node) ([]*yaml.Node, error) {
+	group := item.Content[1]
+	if group.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("invalid with group in workflow (%s): should be a map", workflowID)
+	}
+
+	var containerID string
+	if container, _ := yamlnode.MappingValue(group, withContainerKey); container != nil && !yamlnode.IsNull(container) {
+		containerID = container.Value
+	}
+
+	var serviceIDs []string
+	if services, _ := yamlnode.MappingValue(group, withServicesKey); services != nil && services.Kind == yaml.SequenceNode {
+		for _, service := range services.Content {
+			serviceID := service.Value
+			if newID, ok := m.serviceIDs[serviceID]; ok {
+				serviceID = newID
+			}
+			serviceIDs = append(serviceIDs, serviceID)
+		}
+	}
+
+	var stepItems []*yaml.Node
+	if steps, _ := yamlnode.MappingValue(group, stepsKey); steps != nil && steps.Kind == yaml.SequenceNode {
+		stepItems = steps.Content
+	}
+
+	for _, stepItem := range stepItems {
+		if stepItem.Kind != yaml.MappingNode || len(stepItem.Content) != 2 {
+			return nil, fmt.Errorf("invalid step in with group of workflow (%s)", workflowID)
+		}
+
+		step := yamlnode.BlockMapping(stepItem.Content[1])
+		stepItem.Content[1] = step
+		if len(serviceIDs) > 0 {
+			yamlnode.PrependMappingValue(step, serviceContainersKey, yamlnode.StringSequence(serviceIDs))
+		}
+		if containerID != "" {
+			yamlnode.PrependMappingValue(step, executionContainerKey, yamlnode.String(containerID))
+		}
+	}
+
+	if len(stepItems) > 0 {
+		// Keep the comments of the with group
+		first := stepItems[0]
+		first.HeadComment = yamlnode.JoinComments(item.HeadComment, item.Content[0].HeadComment, first.HeadComment)
+	}
+
+	description := fmt.Sprintf("converted with group in workflow (%s) to step level %s", workflowID, executionContainerKey)
+	if len(serviceIDs) > 0 {
+		description += fmt.Sprintf(" (%s) and %s (%s)", containerID, serviceContainersKey, strings.Join(serviceIDs, ", "))
+	} else {
+		description += fmt.Sprintf(" (%s)", containerID)
+	}
+	m.changes = append(m.changes, description)
+
+	return stepItems, nil
+}
@@ -0,0 +1,118 @@
+// Package configmigrate rewrites a Bitrise config (bitrise.yml) to the latest format version: deprecated constructs
+// tolerated by the CLI are converted to their current equivalents. The config is edited as a YAML node tree,
+// so comments and key order are preserved.
+package configmigrate
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/bitrise/v2/bitrise"
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/yamlnode"
+	ver "github.com/hashicorp/go-version"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatVersionKey = "format_version"
+	containersKey    = "containers"
+	servicesKey      = "services"
+	workflowsKey     = "workflows"
+	triggerMapKey    = "trigger_map"
+)
+
+// Result is the migrated config and the list of changes made.
+type Result struct {
+	Content []byte
+	Changes []string
+}
+
+// HasChanges returns true if the config was changed by the migration.
+func (r Result) HasChanges() bool {
+	return len(r.Changes) > 0
+}
+
+type migrator struct {
+	root *yaml.Node
+	// serviceIDs maps the IDs of renamed services to their container IDs
+	serviceIDs map[string]string
+	changes    []string
+}
+
+// Migrate migrates the given config to the latest format version.
+// The content is returned unchanged if the config already uses the latest format.
+func Migrate(content []byte) (Result, error) {
+	doc, err := yamlnode.ParseMapping(content)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// The migrated config must stay valid, a config which was invalid to begin with is migrated on a best effort basis
+	_, _, originalErr := bitrise.ConfigModelFromYAMLBytesWithValidation(content, bitrise.ValidationTypeFull)
+
+	m := migrator{root: doc.Root, serviceIDs: map[string]string{}}
+	if err := m.checkFormatVersion(); err != nil {
+		return Result{}, err
+	}
+	for _, migrate := range []func() error{m.migrateServices, m.migrateContainerTypes, m.migrateWithGroups, m.migrateLegacyTriggerItems, m.migrateFormatVersion} {
+		if err := migrate(); err != nil {
+			return Result{}, err
+		}
+	}
+
+	if len(m.changes) == 0 {
+		return Result{Content: content}, nil
+	}
+
+	migrated, err := doc.Encode()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to serialize migrated config: %w", err)
+	}
+
+	if originalErr == nil {
+		if _, _, err := bitrise.ConfigModelFromYAMLBytesWithValidation(migrated, bitrise.ValidationTypeFull); err != nil {
+			return Result{}, fmt.Errorf("migrated config is invalid: %w", err)
+		}
+	}
+
+	return Result{Content: migrated, Changes: m.changes}, nil
+}
+
+// checkFormatVersion makes sure the config is not newer than the latest supported format version.
+func (m *migrator) checkFormatVersion() error {
+	value, _ := yamlnode.MappingValue(m.root, formatVersionKey)
+	if value == nil {
+		return nil
+	}
+
+	latestVersion, err := ver.NewVersion(models.FormatVersion)
+	if err != nil {
+		return err
+	}
+	configVersion, err := ver.NewVersion(value.Value)
+	if err != nil {
+		return fmt.Errorf("invalid format_version (%s): %w", value.Value, err)
+	}
+	if configVersion.GreaterThan(latestVersion) {
+		return fmt.Errorf("the config has a higher format version (%s) than the bitrise CLI supported format version (%s)", value.Value, models.FormatVersion)
+	}
+	return nil
+}
+
+func (m *migrator) migrateFormatVersion() error {
+	value, _ := yamlnode.MappingValue(m.root, formatVersionKey)
+	if value == nil {
+		yamlnode.PrependMappingValue(m.root, formatVersionKey, yamlnode.QuotedString(models.FormatVersion))
+		m.changes = append(m.changes, fmt.Sprintf("set format_version to %s", models.FormatVersion))
+		return nil
+	}
+	if value.Value == models.FormatVersion {
+		return nil
+	}
+
+	m.changes = append(m.changes, fmt.Sprintf("updated format_version from %s to %s", value.Value, models.FormatVersion))
+	value.Value = models.FormatVersion
+	value.Tag = "!!str"
+	value.Style = yaml.DoubleQuotedStyle
+	return nil
+}
@@ -0,0 +1,207 @@
+package configmigrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		want        string
+		wantChanges []string
+		wantErr     string
+	}{
+		{
+			name: "with groups and services",
+			config: `format_version: "11"
+
+containers:
+  # Go build image
+  golang:
+    image: golang:1.22
+services:
+  postgres:
+    image: postgres:16
+
+workflows:
+  test:
+    steps:
+    - git-clone@8: {}
+    # Run in container
+    - with:
+        container: golang
+        services:
+        - postgres
+        steps:
+        - script@1:
+            title: Test # unit tests
+        - script@1:
+        - script@1: {}
+`,
+			want: `format_version: "26"
+
+containers:
+  # Go build image
+  golang:
+    type: execution
+    image: golang:1.22
+  postgres:
+    type: service
+    image: postgres:16
+
+workflows:
+  test:
+    steps:
+      - git-clone@8: {}
+      # Run in container
+      - script@1:
+          execution_container: golang
+          service_containers:
+            - postgres
+          title: Test # unit tests
+      - script@1:
+          execution_container: golang
+          service_containers:
+            - postgres
+      - script@1:
+          execution_container: golang
+          service_containers:
+            - postgres
+`,
+			wantChanges: []string{
+				"moved service (postgres) to containers as service container",
+				"set type of container (golang) to execution",
+				"converted with group in workflow (test) to step level execution_container (golang) and service_containers (postgres)",
+				"updated format_version from 11 to 26",
+			},
+		},
+		{
+			name: "service with the ID of a container",
+			config: `format_version: "11"
+services:
+  redis:
+    image: redis:7
+containers:
+  redis:
+    image: redis:7
+workflows:
+  test:
+    steps:
+    - with:
+        container: redis
+        services:
+        - redis
+        steps:
+        - script@1: {}
+`,
+			want: `format_version: "26"
+containers:
+  redis:
+    type: execution
+    image: redis:7
+  redis_service:
+    type: service
+    image: redis:7
+workflows:
+  test:
+    steps:
+      - script@1:
+          execution_container: redis
+          service_containers:
+            - redis_service
+`,
+			wantChanges: []string{
+				"moved service (redis) to containers as service container (redis_service), because a container with the same ID exists",
+				"set type of container (redis) to execution",
+				"converted with group in workflow (test) to step level execution_container (redis) and service_containers (redis_service)",
+				"updated format_version from 11 to 26",
+			},
+		},
+		{
+			name: "legacy trigger items",
+			config: `format_version: "11"
+trigger_map:
+# main branch
+- pattern: main
+  is_pull_request_allowed: true
+  workflow: test
+- pattern: "*"
+  workflow: test
+- tag: "*"
+  workflow: test
+workflows:
+  test: {}
+`,
+			want: `format_version: "26"
+trigger_map:
+  # main branch
+  - push_branch: main
+    workflow: test
+  - pull_request_source_branch: main
+    workflow: test
+  - push_branch: "*"
+    workflow: test
+  - tag: "*"
+    workflow: test
+workflows:
+  test: {}
+`,
+			wantChanges: []string{
+				"converted trigger item #1 (pattern: main) to a push_branch item and a pull_request_source_branch item",
+				"converted trigger item #2 (pattern: *) to a push_branch item",
+				"updated format_version from 11 to 26",
+			},
+		},
+		{
+			name: "latest format version",
+			config: `format_version: "26"
+workflows:
+    test: {}
+`,
+			want: `format_version: "26"
+workflows:
+    test: {}
+`,
+		},
+		{
+			name:    "newer format version",
+			config:  "format_version: \"1000\"\n",
+			wantErr: "the config has a higher format version (1000) than the bitrise CLI supported format version (26)",
+		},
+		{
+			name:    "not a map",
+			config:  "- test\n",
+			wantErr: "config should be a YAML map",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Migrate([]byte(tt.config))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(result.Content))
+			require.Equal(t, tt.wantChanges, result.Changes)
+		})
+	}
+}
+
+func TestMigrate_keepsConfigValid(t *testing.T) {
+	config := `format_version: "11"
+trigger_map:
+- pattern: main
+  workflow: test
+- push_branch: main
+  workflow: test
+workflows:
+  test: {}
+`
+
+	_, err := Migrate([]byte(config))
+	require.ErrorContains(t, err, "migrated config is invalid")
+}
@@ -0,0 +1,77 @@
+package configmigrate
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/bitrise/v2/yamlnode"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	legacyPatternKey              = "pattern"
+	legacyIsPullRequestAllowedKey = "is_pull_request_allowed"
+	pushBranchKey                 = "push_branch"
+	pullRequestSourceBranchKey    = "pull_request_source_branch"
+)
+
+// migrateLegacyTriggerItems converts the trigger_map items using the deprecated pattern and is_pull_request_allowed
+// keys to a push_branch item and, if pull requests are allowed, a pull_request_source_branch item.
+// This mirrors how the CLI interprets these items.
+func (m *migrator) migrateLegacyTriggerItems() error {
+	triggerMap, _ := yamlnode.MappingValue(m.root, triggerMapKey)
+	if triggerMap == nil || triggerMap.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	var items []*yaml.Node
+	for idx, item := range triggerMap.Content {
+		pattern, _ := yamlnode.MappingValue(item, legacyPatternKey)
+		if pattern == nil || pattern.Value == "" {
+			items = append(items, item)
+			continue
+		}
+
+		isPullRequestAllowed := false
+		if value, _ := yamlnode.MappingValue(item, legacyIsPullRequestAllowedKey); value != nil && !yamlnode.IsNull(value) {
+			if err := value.Decode(&isPullRequestAllowed); err != nil {
+				return fmt.Errorf("trigger item #%d: invalid %s value: %w", idx+1, legacyIsPullRequestAllowedKey, err)
+			}
+		}
+
+		items = append(items, legacyTriggerItem(item, pushBranchKey))
+		description := fmt.Sprintf("converted trigger item #%d (pattern: %s) to a %s item", idx+1, pattern.Value, pushBranchKey)
+		if isPullRequestAllowed {
+			prItem := legacyTriggerItem(item, pullRequestSourceBranchKey)
+			prItem.HeadComment = ""
+			items = append(items, prItem)
+			description += fmt.Sprintf(" and a %s item", pullRequestSourceBranchKey)
+		}
+		m.changes = append(m.changes, description)
+	}
+	triggerMap.Content = items
+
+	return nil
+}
+
+// legacyTriggerItem returns a copy of the legacy trigger item, with the pattern key replaced by the given condition key.
+func legacyTriggerItem(item *yaml.Node, conditionKey string) *yaml.Node {
+	converted := *item
+	converted.Style &^= yaml.FlowStyle
+	converted.Content = nil
+
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		key, value := item.Content[i], item.Content[i+1]
+		switch key.Value {
+		case legacyIsPullRequestAllowedKey:
+			continue
+		case legacyPatternKey:
+			conditionKeyNode := *key
+			conditionKeyNode.Value = conditionKey
+			converted.Content = append(converted.Content, &conditionKeyNode, value)
+		default:
+			converted.Content = append(converted.Content, key, value)
+		}
+	}
+
+	return &converted
+}
@@ -18,8 +18,9 @@ const (
 )
 
 type AgentConfig struct {
-	BitriseDirs BitriseDirs `yaml:"bitrise_dirs"`
-	Hooks       AgentHooks  `yaml:"hooks"`
+	BitriseDirs BitriseDirs     `yaml:"bitrise_dirs"`
+	Hooks       AgentHooks      `yaml:"hooks"`
+	Containers  AgentContainers `yaml:"containers"`
 }
 
 type BitriseDirs struct {
@@ -64,6 +65,13 @@ type AgentHooks struct {
 	DoOnBuildEnd string `yaml:"do_on_build_end"`
 }
 
+// AgentContainers configures how the execution and service containers of a build are run on the agent.
+type AgentContainers struct {
+	// Runtime is the container runtime to use (docker or podman), defaults to docker.
+	// The BITRISE_CONTAINER_RUNTIME env var takes precedence over this value.
+	Runtime string `yaml:"runtime"`
+}
+
 func GetAgentConfigPath() string {
 	return filepath.Join(GetBitriseHomeDirPath(), agentConfigFileName)
 }
@@ -39,6 +39,9 @@ func TestReadAgentConfig(t *testing.T) {
 					DoOnBuildStart:      filepath.Join(tempDir, "cleanup.sh"),
 					DoOnBuildEnd:        filepath.Join(tempDir, "cleanup.sh"),
 				},
+				AgentContainers{
+					Runtime: "podman",
+				},
 			},
 			expectedErr: false,
 		},
@@ -54,6 +57,7 @@ func TestReadAgentConfig(t *testing.T) {
 					HTMLReportDir:      "/opt/bitrise/ef7a9665e8b6408b/80b66786-d011-430f-9c68-00e9416a7325/html_reports",
 				},
 				AgentHooks{},
+				AgentContainers{},
 			},
 			expectedErr: false,
 		},
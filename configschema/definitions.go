@@ -0,0 +1,268 @@
+package configschema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+)
+
+// The config model has a few types that can't be described by reflection alone: polymorphic list items
+// (decoded by custom UnmarshalYAML methods), `any` typed fields and values validated after parsing.
+
+// customTypeSchema returns the hand-written schema builder of types with custom decoding.
+func customTypeSchema(t reflect.Type) func(g *generator) *Schema {
+	switch t {
+	case typeOf[models.StepListItemModel]():
+		return stepListItemSchema
+	case typeOf[models.StepListItemStepOrBundleModel]():
+		return stepOrBundleListItemSchema
+	case typeOf[envmanModels.EnvironmentItemModel]():
+		return environmentItemSchema
+	case typeOf[stepmanModels.ContainerReference]():
+		return containerReferenceSchema
+	case typeOf[models.ToolsModel]():
+		return toolsSchema
+	}
+	return nil
+}
+
+var enums = map[reflect.Type][]string{
+	typeOf[models.ContainerType]():              {string(models.ContainerTypeExecution), string(models.ContainerTypeService)},
+	typeOf[models.GraphPipelineAlwaysRunMode](): {string(models.GraphPipelineAlwaysRunModeOff), string(models.GraphPipelineAlwaysRunModeWorkflow)},
+	typeOf[models.TriggerItemType]():            {string(models.CodePushType), string(models.PullRequestType), string(models.TagPushType)},
+}
+
+// singleKeyMaps are map types used as list items with exactly one key (an ID) and its value.
+var singleKeyMaps = map[reflect.Type]bool{
+	typeOf[models.StageListItemModel]():         true,
+	typeOf[models.StageWorkflowListItemModel](): true,
+	typeOf[models.StepListStepItemModel]():      true,
+	typeOf[models.WorkflowListItemModel]():      true,
+}
+
+// strictTypes are decoded with an allow-list of keys, unknown keys are rejected by the CLI.
+var strictTypes = map[reflect.Type]bool{
+	typeOf[models.Triggers]():                       true,
+	typeOf[models.PushGitEventTriggerItem]():        true,
+	typeOf[models.PullRequestGitEventTriggerItem](): true,
+	typeOf[models.TagGitEventTriggerItem]():         true,
+}
+
+var fieldSchemas = map[reflect.Type]map[string]func(g *generator) *Schema{
+	typeOf[models.BitriseDataModel](): {
+		"format_version": formatVersionSchema,
+	},
+	typeOf[models.AppModel](): {
+		"status_report_name": statusReportNameSchema,
+	},
+	typeOf[models.PipelineModel](): {
+		"status_report_name": statusReportNameSchema,
+		"priority":           prioritySchema,
+	},
+	typeOf[models.WorkflowModel](): {
+		"status_report_name": statusReportNameSchema,
+		"priority":           prioritySchema,
+	},
+	typeOf[models.ToolConfigModel](): {
+		"provider": toolProviderSchema,
+	},
+	typeOf[models.Container](): {
+		"cpus":     containerCPUsSchema,
+		"memory":   containerMemorySchema,
+		"platform": containerPlatformSchema,
+	},
+	typeOf[models.TriggerMapItemModel](): {
+		"push_branch":                legacyTriggerConditionSchema,
+		"commit_message":             legacyTriggerConditionSchema,
+		"changed_files":              legacyTriggerConditionSchema,
+		"tag":                        legacyTriggerConditionSchema,
+		"pull_request_source_branch": legacyTriggerConditionSchema,
+		"pull_request_target_branch": legacyTriggerConditionSchema,
+		"pull_request_label":         legacyTriggerConditionSchema,
+		"pull_request_comment":       legacyTriggerConditionSchema,
+	},
+	typeOf[models.PushGitEventTriggerItem](): {
+		"priority":       prioritySchema,
+		"branch":         triggerFilterSchema,
+		"commit_message": lastCommitTriggerFilterSchema,
+		"changed_files":  lastCommitTriggerFilterSchema,
+	},
+	typeOf[models.PullRequestGitEventTriggerItem](): {
+		"priority":       prioritySchema,
+		"source_branch":  triggerFilterSchema,
+		"target_branch":  triggerFilterSchema,
+		"label":          triggerFilterSchema,
+		"comment":        triggerFilterSchema,
+		"commit_message": triggerFilterSchema,
+		"changed_files":  triggerFilterSchema,
+	},
+	typeOf[models.TagGitEventTriggerItem](): {
+		"priority": prioritySchema,
+		"name":     triggerFilterSchema,
+	},
+}
+
+var deprecatedFields = map[reflect.Type]map[string]string{
+	typeOf[models.TriggerMapItemModel](): {
+		"pattern":                 "Deprecated: use the push_branch, pull_request_source_branch or tag conditions instead.",
+		"is_pull_request_allowed": "Deprecated: use a separate pull_request trigger item instead.",
+	},
+}
+
+// stepListItemSchema describes a workflow's steps list item: a step, a step bundle (with the bundle:: key prefix)
+// or a with group (with the `with` key).
+func stepListItemSchema(g *generator) *Schema {
+	schema := stepOrBundleListItemSchema(g)
+	schema.Description = fmt.Sprintf("A step, a step bundle reference (%s<id>) or a '%s' group.", models.StepListItemStepBundleKeyPrefix, models.StepListItemWithKey)
+	schema.Properties = map[string]*Schema{
+		models.StepListItemWithKey: g.schemaOf(typeOf[models.WithModel]()),
+	}
+	return schema
+}
+
+// stepOrBundleListItemSchema describes a step bundle's steps list item: a step or a step bundle reference.
+func stepOrBundleListItemSchema(g *generator) *Schema {
+	return &Schema{
+		Type:          "object",
+		Description:   fmt.Sprintf("A step or a step bundle reference (%s<id>).", models.StepListItemStepBundleKeyPrefix),
+		MinProperties: intPtr(1),
+		MaxProperties: intPtr(1),
+		PatternProperties: map[string]*Schema{
+			"^" + regexp.QuoteMeta(models.StepListItemStepBundleKeyPrefix): g.valueSchema(typeOf[models.StepBundleListItemModel]()),
+		},
+		// Steps of a with group can't declare tools, they are described by the with group's StepModel items
+		AdditionalProperties: g.valueSchema(typeOf[models.StepWithToolsModel]()),
+	}
+}
+
+// environmentItemSchema describes an env var, step input or output: a single key-value pair with optional opts.
+func environmentItemSchema(g *generator) *Schema {
+	return &Schema{
+		Type:          "object",
+		MinProperties: intPtr(1),
+		MaxProperties: intPtr(2),
+		Properties: map[string]*Schema{
+			"opts": g.schemaOf(typeOf[envmanModels.EnvironmentItemOptionsModel]()),
+		},
+		AdditionalProperties: &Schema{Type: []string{"string", "number", "boolean", "null"}},
+	}
+}
+
+// containerReferenceSchema describes a container reference: the container ID, or a map with the container ID key
+// and the container's configuration.
+func containerReferenceSchema(*generator) *Schema {
+	return &Schema{
+		AnyOf: []*Schema{
+			{Type: "string", MinLength: intPtr(1)},
+			{
+				Type:          "object",
+				MinProperties: intPtr(1),
+				MaxProperties: intPtr(1),
+				AdditionalProperties: &Schema{
+					Type:                 "object",
+					Properties:           map[string]*Schema{"recreate": {Type: "boolean"}},
+					AdditionalProperties: false,
+				},
+			},
+		},
+	}
+}
+
+func toolsSchema(*generator) *Schema {
+	return &Schema{
+		Type:        "object",
+		Description: "Tool versions by tool ID.",
+		AdditionalProperties: &Schema{
+			Type:        []string{"string", "number"},
+			Description: "A version, a partial version with the :latest (latest released) or :installed (latest preinstalled) suffix, or latest/installed.",
+			Examples:    []any{"3.12.4", "3.12:latest", "22:installed", "latest"},
+		},
+	}
+}
+
+func formatVersionSchema(*generator) *Schema {
+	return &Schema{
+		// An unquoted version is decoded as a number by YAML, the CLI accepts both
+		Type:        []string{"string", "number"},
+		Description: fmt.Sprintf("The format version of the config, the highest supported format version is %s.", models.FormatVersion),
+	}
+}
+
+func statusReportNameSchema(*generator) *Schema {
+	return &Schema{
+		Type:      "string",
+		Pattern:   models.StatusReportNamePattern,
+		MaxLength: intPtr(models.MaxStatusReportNameLength),
+	}
+}
+
+func prioritySchema(*generator) *Schema {
+	return &Schema{
+		Type:    "integer",
+		Minimum: intPtr(models.MinPriority),
+		Maximum: intPtr(models.MaxPriority),
+	}
+}
+
+func containerCPUsSchema(*generator) *Schema {
+	return &Schema{Type: "number", Minimum: intPtr(0)}
+}
+
+func containerMemorySchema(*generator) *Schema {
+	return &Schema{Type: "string", Pattern: models.ContainerMemoryPattern}
+}
+
+func containerPlatformSchema(*generator) *Schema {
+	return &Schema{Type: "string", Pattern: models.ContainerPlatformPattern}
+}
+
+func toolProviderSchema(*generator) *Schema {
+	return &Schema{Type: "string", Enum: models.ToolProviders}
+}
+
+// legacyTriggerConditionSchema describes a trigger_map item condition: a glob pattern or a regex.
+func legacyTriggerConditionSchema(*generator) *Schema {
+	return &Schema{
+		AnyOf: []*Schema{
+			{Type: "string"},
+			{
+				Type:                 "object",
+				Properties:           map[string]*Schema{"regex": {Type: "string"}},
+				Required:             []string{"regex"},
+				AdditionalProperties: false,
+			},
+		},
+	}
+}
+
+// triggerFilterSchema describes a workflow or pipeline trigger filter: a glob pattern, or a map with
+// exactly one of the pattern and regex keys.
+func triggerFilterSchema(*generator) *Schema {
+	return filterSchema(false)
+}
+
+func lastCommitTriggerFilterSchema(*generator) *Schema {
+	return filterSchema(true)
+}
+
+func filterSchema(allowLastCommit bool) *Schema {
+	properties := func(key string) map[string]*Schema {
+		properties := map[string]*Schema{key: {Type: "string"}}
+		if allowLastCommit {
+			properties["last_commit"] = &Schema{Type: "boolean"}
+		}
+		return properties
+	}
+
+	return &Schema{
+		AnyOf: []*Schema{
+			{Type: "string"},
+			{Type: "object", Properties: properties("pattern"), Required: []string{"pattern"}, AdditionalProperties: false},
+			{Type: "object", Properties: properties("regex"), Required: []string{"regex"}, AdditionalProperties: false},
+		},
+	}
+}
@@ -0,0 +1,187 @@
+package configschema
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Generate returns the JSON Schema of the Bitrise config of the current format version.
+func Generate() *Schema {
+	g := newGenerator()
+
+	root := g.structSchema(typeOf[models.BitriseDataModel]())
+	root.Schema = schemaDialect
+	root.Title = "Bitrise config"
+	root.Description = fmt.Sprintf("Bitrise config (bitrise.yml), format version %s.", models.FormatVersion)
+	root.Required = []string{"format_version"}
+	root.Definitions = g.definitions
+
+	return root
+}
+
+// generator walks the model types with reflection. Named struct types and the types with a hand-written schema
+// are collected as definitions and referenced, so recursive and shared types are described only once.
+type generator struct {
+	definitions map[string]*Schema
+	names       map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{
+		definitions: map[string]*Schema{},
+		names:       map[reflect.Type]string{},
+	}
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	if build := customTypeSchema(t); build != nil {
+		return g.define(t, func() *Schema { return build(g) })
+	}
+	if values, ok := enums[t]; ok {
+		return &Schema{Type: "string", Enum: values}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaOf(t.Elem())
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+		return g.define(t, func() *Schema { return g.structSchema(t) })
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		schema := &Schema{Type: "object", AdditionalProperties: g.valueSchema(t.Elem())}
+		if singleKeyMaps[t] {
+			schema.MinProperties = intPtr(1)
+			schema.MaxProperties = intPtr(1)
+		}
+		return schema
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		panic(fmt.Sprintf("unsupported type in config model: %s", t))
+	}
+}
+
+// valueSchema returns the schema of a map value. A key without a value (for example `primary:`) is decoded
+// into the zero value of a struct, so struct values are allowed to be null.
+func (g *generator) valueSchema(t reflect.Type) *Schema {
+	schema := g.schemaOf(t)
+	if t.Kind() == reflect.Struct && t != timeType {
+		return nullable(schema)
+	}
+	return schema
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	if strictTypes[t] {
+		schema.AdditionalProperties = false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, inline := yamlFieldName(field)
+		if name == "-" {
+			continue
+		}
+		if inline {
+			for key, property := range g.structSchema(field.Type).Properties {
+				schema.Properties[key] = property
+			}
+			continue
+		}
+
+		var property *Schema
+		if build, ok := fieldSchemas[t][name]; ok {
+			property = build(g)
+		} else {
+			property = g.schemaOf(field.Type)
+		}
+		if description, ok := deprecatedFields[t][name]; ok {
+			property = &Schema{AnyOf: []*Schema{property}, Deprecated: true, Description: description}
+		}
+		schema.Properties[name] = property
+	}
+
+	return schema
+}
+
+// define registers the schema built by build as a definition of the given type and returns a reference to it.
+func (g *generator) define(t reflect.Type, build func() *Schema) *Schema {
+	name, ok := g.names[t]
+	if ok {
+		return ref(name)
+	}
+
+	name = g.definitionName(t)
+	g.names[t] = name
+	// Reserve the name before building the schema to stop recursion on self-referencing types
+	g.definitions[name] = &Schema{}
+	g.definitions[name] = build()
+
+	return ref(name)
+}
+
+func (g *generator) definitionName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		name = "Anonymous"
+	}
+	if _, taken := g.definitions[name]; !taken {
+		return name
+	}
+
+	// Types with the same name from different packages (for example the bitrise and stepman models)
+	qualified := path.Base(t.PkgPath()) + "." + name
+	if _, taken := g.definitions[qualified]; !taken {
+		return qualified
+	}
+	for i := 2; ; i++ {
+		if candidate := fmt.Sprintf("%s%d", qualified, i); g.definitions[candidate] == nil {
+			return candidate
+		}
+	}
+}
+
+func yamlFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("yaml")
+	name, options, _ := strings.Cut(tag, ",")
+	inline := false
+	for _, option := range strings.Split(options, ",") {
+		if option == "inline" {
+			inline = true
+		}
+	}
+	if name == "" {
+		// yaml.v2 uses the lowercased field name by default
+		name = strings.ToLower(field.Name)
+	}
+	return name, inline
+}
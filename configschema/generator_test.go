@@ -0,0 +1,126 @@
+package configschema
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_coversConfigModel(t *testing.T) {
+	schema := Generate()
+
+	require.Equal(t, schemaDialect, schema.Schema)
+	require.Equal(t, []string{"format_version"}, schema.Required)
+	require.Contains(t, schema.Description, models.FormatVersion)
+
+	configType := reflect.TypeOf(models.BitriseDataModel{})
+	for i := 0; i < configType.NumField(); i++ {
+		name, _ := yamlFieldName(configType.Field(i))
+		require.Contains(t, schema.Properties, name)
+	}
+
+	workflow := schema.Definitions["WorkflowModel"]
+	require.NotNil(t, workflow)
+	workflowType := reflect.TypeOf(models.WorkflowModel{})
+	for i := 0; i < workflowType.NumField(); i++ {
+		name, _ := yamlFieldName(workflowType.Field(i))
+		require.Contains(t, workflow.Properties, name)
+	}
+}
+
+func TestGenerate_referencesResolve(t *testing.T) {
+	schema := Generate()
+
+	var walk func(s *Schema)
+	walk = func(s *Schema) {
+		if s == nil {
+			return
+		}
+		if s.Ref != "" {
+			name := strings.TrimPrefix(s.Ref, "#/definitions/")
+			require.Contains(t, schema.Definitions, name, "unresolved reference: %s", s.Ref)
+		}
+		for _, sub := range s.AnyOf {
+			walk(sub)
+		}
+		walk(s.Items)
+		for _, sub := range s.Properties {
+			walk(sub)
+		}
+		for _, sub := range s.PatternProperties {
+			walk(sub)
+		}
+		if additional, ok := s.AdditionalProperties.(*Schema); ok {
+			walk(additional)
+		}
+	}
+
+	walk(schema)
+	for _, definition := range schema.Definitions {
+		walk(definition)
+	}
+}
+
+func TestGenerate_stepListItems(t *testing.T) {
+	schema := Generate()
+
+	stepListItem := schema.Definitions["StepListItemModel"]
+	require.NotNil(t, stepListItem)
+	require.Equal(t, 1, *stepListItem.MinProperties)
+	require.Equal(t, 1, *stepListItem.MaxProperties)
+	require.Equal(t, ref("WithModel"), stepListItem.Properties[models.StepListItemWithKey])
+	require.Equal(t, nullable(ref("StepWithToolsModel")), stepListItem.AdditionalProperties)
+	require.Equal(t, ref("ToolsModel"), schema.Definitions["StepWithToolsModel"].Properties["tools"])
+	require.Contains(t, schema.Definitions["StepWithToolsModel"].Properties, "inputs")
+	require.NotContains(t, schema.Definitions["StepModel"].Properties, "tools")
+
+	require.Len(t, stepListItem.PatternProperties, 1)
+	for pattern, bundle := range stepListItem.PatternProperties {
+		require.Regexp(t, regexp.MustCompile(pattern), models.StepListItemStepBundleKeyPrefix+"setup")
+		require.NotRegexp(t, regexp.MustCompile(pattern), "script@1")
+		require.Equal(t, nullable(ref("StepBundleListItemModel")), bundle)
+	}
+
+	stepBundle := schema.Definitions["StepBundleModel"]
+	require.Equal(t, &Schema{Type: "array", Items: ref("StepListItemStepOrBundleModel")}, stepBundle.Properties["steps"])
+	require.NotContains(t, schema.Definitions["StepListItemStepOrBundleModel"].Properties, models.StepListItemWithKey)
+}
+
+func TestGenerate_triggers(t *testing.T) {
+	schema := Generate()
+
+	triggers := schema.Definitions["Triggers"]
+	require.Equal(t, false, triggers.AdditionalProperties)
+	require.ElementsMatch(t, []string{"enabled", "push", "pull_request", "tag"}, keys(triggers.Properties))
+
+	push := schema.Definitions["PushGitEventTriggerItem"]
+	require.Equal(t, false, push.AdditionalProperties)
+	require.Equal(t, filterSchema(false), push.Properties["branch"])
+	require.Equal(t, filterSchema(true), push.Properties["changed_files"])
+	require.Equal(t, models.MaxPriority, *push.Properties["priority"].Maximum)
+
+	triggerMapItem := schema.Definitions["TriggerMapItemModel"]
+	require.True(t, triggerMapItem.Properties["pattern"].Deprecated)
+	require.Equal(t, []string{"push", "pull_request", "tag"}, triggerMapItem.Properties["type"].Enum)
+}
+
+func TestMarshal(t *testing.T) {
+	content, err := Marshal(Generate())
+	require.NoError(t, err)
+
+	require.Contains(t, string(content), `"$schema": "http://json-schema.org/draft-07/schema#"`)
+	require.Contains(t, string(content), ` <>[`)
+	require.NotContains(t, string(content), `\u003c`)
+}
+
+func keys(m map[string]*Schema) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
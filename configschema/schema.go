@@ -0,0 +1,69 @@
+// Package configschema generates the JSON Schema of the Bitrise config (bitrise.yml) from the Go model types,
+// so the schema always describes exactly what the CLI parses.
+package configschema
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+const schemaDialect = "http://json-schema.org/draft-07/schema#"
+
+// Schema is the subset of JSON Schema (draft-07) used to describe the Bitrise config.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Definitions map[string]*Schema `json:"definitions,omitempty"`
+
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Examples    []any  `json:"examples,omitempty"`
+
+	// Type is either a single type name or a list of type names.
+	Type  any       `json:"type,omitempty"`
+	Enum  []string  `json:"enum,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	Format    string `json:"format,omitempty"`
+	Minimum   *int   `json:"minimum,omitempty"`
+	Maximum   *int   `json:"maximum,omitempty"`
+
+	Items *Schema `json:"items,omitempty"`
+
+	Properties        map[string]*Schema `json:"properties,omitempty"`
+	PatternProperties map[string]*Schema `json:"patternProperties,omitempty"`
+	// AdditionalProperties is either a *Schema or false.
+	AdditionalProperties any      `json:"additionalProperties,omitempty"`
+	Required             []string `json:"required,omitempty"`
+	MinProperties        *int     `json:"minProperties,omitempty"`
+	MaxProperties        *int     `json:"maxProperties,omitempty"`
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/definitions/" + name}
+}
+
+func nullable(schema *Schema) *Schema {
+	return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// Marshal returns the indented JSON encoding of the schema.
+func Marshal(schema *Schema) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	// Keep patterns readable: don't escape <, > and &
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(schema); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
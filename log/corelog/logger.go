@@ -19,6 +19,8 @@ const (
 	BitriseCLI Producer = "bitrise_cli"
 	// Step ...
 	Step Producer = "step"
+	// Container is the producer of the execution and service container logs.
+	Container Producer = "container"
 )
 
 // Level ...
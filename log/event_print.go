@@ -157,7 +157,7 @@ func transformStatusToIconAndLevel(status models.StepRunStatus) (string, corelog
 	case models.StepRunStatusCodeSuccess:
 		icon = "✓"
 		level = corelog.DoneLevel
-	case models.StepRunStatusCodeFailed, models.StepRunStatusCodePreparationFailed:
+	case models.StepRunStatusCodeFailed, models.StepRunStatusCodePreparationFailed, models.StepRunStatusCodeServiceNotReady:
 		icon = "x"
 		level = corelog.ErrorLevel
 	case models.StepRunStatusAbortedWithCustomTimeout, models.StepRunStatusAbortedWithNoOutputTimeout:
@@ -166,7 +166,7 @@ func transformStatusToIconAndLevel(status models.StepRunStatus) (string, corelog
 	case models.StepRunStatusCodeFailedSkippable:
 		icon = "!"
 		level = corelog.WarnLevel
-	case models.StepRunStatusCodeSkipped, models.StepRunStatusCodeSkippedWithRunIf:
+	case models.StepRunStatusCodeSkipped, models.StepRunStatusCodeSkippedWithRunIf, models.StepRunStatusCodeSkippedByPlugin:
 		icon = "-"
 		level = corelog.InfoLevel
 	default:
@@ -27,6 +27,7 @@ type Producer corelog.Producer
 const (
 	BitriseCLI = Producer(corelog.BitriseCLI)
 	Step       = Producer(corelog.Step)
+	Container  = Producer(corelog.Container)
 )
 
 // defaultLogger ...
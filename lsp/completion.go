@@ -0,0 +1,176 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+)
+
+func (s *Server) complete(doc *document, pos Position) []CompletionItem {
+	ctx, ok := doc.contextAt(pos)
+	if !ok {
+		return nil
+	}
+
+	syms := newSymbols(doc)
+	ref := referenceAt(ctx)
+
+	switch ref.kind {
+	case referenceWorkflow:
+		return s.workflowItems(syms, syms.ids(workflowsKey))
+	case referencePipelineWorkflow:
+		var ids []string
+		for _, id := range sortedKeys(syms.pipelineWorkflows[ref.scope]) {
+			if len(ctx.parents) > 3 && id == ctx.parents[3] {
+				// A workflow can't depend on itself
+				continue
+			}
+			ids = append(ids, id)
+		}
+		return s.workflowItems(syms, ids)
+	case referencePipeline:
+		return idItems(syms.ids(pipelinesKey), "pipeline")
+	case referenceStage:
+		return idItems(syms.ids(stagesKey), "stage")
+	case referenceStepListItem:
+		var items []CompletionItem
+		for _, id := range syms.ids(stepBundlesKey) {
+			item := CompletionItem{
+				Label:  models.StepListItemStepBundleKeyPrefix + id,
+				Kind:   CompletionItemKindModule,
+				Detail: "step bundle",
+			}
+			if syms.config != nil {
+				bundle := syms.config.StepBundles[id]
+				item.Documentation = firstNonEmpty(bundle.Title, bundle.Summary)
+			}
+			items = append(items, item)
+		}
+		return items
+	case referenceExecutionContainer:
+		return idItems(syms.containerIDs(models.ContainerTypeExecution), "execution container")
+	case referenceServiceContainer:
+		return idItems(syms.containerIDs(models.ContainerTypeService), "service container")
+	case referenceWithContainer:
+		return idItems(syms.ids(containersKey), "container")
+	case referenceWithService:
+		return idItems(syms.ids(servicesKey), "service")
+	case referenceStepInput:
+		return s.inputItems(syms, ref.scope)
+	}
+
+	return nil
+}
+
+func (s *Server) workflowItems(syms symbols, ids []string) []CompletionItem {
+	var items []CompletionItem
+	for _, id := range ids {
+		item := CompletionItem{
+			Label:  id,
+			Kind:   CompletionItemKindReference,
+			Detail: "workflow",
+		}
+		if syms.config != nil {
+			workflow := syms.config.Workflows[id]
+			item.Documentation = firstNonEmpty(workflow.Summary, workflow.Title)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func idItems(ids []string, detail string) []CompletionItem {
+	var items []CompletionItem
+	for _, id := range ids {
+		items = append(items, CompletionItem{
+			Label:  id,
+			Kind:   CompletionItemKindReference,
+			Detail: detail,
+		})
+	}
+	return items
+}
+
+// stepListItemInputs returns the inputs of the given step list item: the inputs of a step bundle definition,
+// or the inputs of a step from the local steplib cache.
+func (s *Server) stepListItemInputs(syms symbols, stepListItemKey string) []envmanModels.EnvironmentItemModel {
+	if bundleID, ok := stepBundleID(stepListItemKey); ok {
+		if syms.config == nil {
+			return nil
+		}
+		return syms.config.StepBundles[bundleID].Inputs
+	}
+
+	step, err := s.stepInfo.StepInfo(stepListItemKey, defaultStepLibSourceOf(syms))
+	if err != nil {
+		s.logf("Failed to get step info (%s): %s", stepListItemKey, err)
+		return nil
+	}
+	return step.Inputs
+}
+
+func (s *Server) inputItems(syms symbols, stepListItemKey string) []CompletionItem {
+	var items []CompletionItem
+	for _, input := range s.stepListItemInputs(syms, stepListItemKey) {
+		key, value, err := input.GetKeyValuePair()
+		if err != nil {
+			continue
+		}
+		opts, err := input.GetOptions()
+		if err != nil {
+			continue
+		}
+
+		item := CompletionItem{
+			Label:         key,
+			Kind:          CompletionItemKindProperty,
+			InsertText:    key + ": ",
+			Detail:        derefString(opts.Title),
+			Documentation: inputDocumentation(value, opts),
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func inputDocumentation(defaultValue string, opts envmanModels.EnvironmentItemOptionsModel) string {
+	var parts []string
+	if summary := firstNonEmpty(derefString(opts.Summary), derefString(opts.Description)); summary != "" {
+		parts = append(parts, summary)
+	}
+	if opts.IsRequired != nil && *opts.IsRequired {
+		parts = append(parts, "Required.")
+	}
+	if len(opts.ValueOptions) > 0 {
+		parts = append(parts, fmt.Sprintf("Value options: %s", strings.Join(opts.ValueOptions, ", ")))
+	}
+	if defaultValue != "" {
+		parts = append(parts, fmt.Sprintf("Default value: %s", defaultValue))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func defaultStepLibSourceOf(syms symbols) string {
+	if syms.config == nil {
+		return ""
+	}
+	return syms.config.DefaultStepLibSource
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
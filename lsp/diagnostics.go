@@ -0,0 +1,205 @@
+package lsp
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/bitrise"
+	"github.com/bitrise-io/bitrise/v2/configmerge"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/models"
+	ver "github.com/hashicorp/go-version"
+	"gopkg.in/yaml.v2"
+)
+
+const diagnosticSource = "bitrise"
+
+var (
+	yamlErrorLinePattern     = regexp.MustCompile(`line (\d+):`)
+	validationErrorIDPattern = regexp.MustCompile(`\(([^()\s]+)\)|with name (\S+)`)
+)
+
+// overlayConfigReader serves the in-memory content of the edited document instead of its saved version,
+// and resolves local config modules relative to the main config's directory.
+type overlayConfigReader struct {
+	mainPath    string
+	mainContent []byte
+	reader      configmerge.ConfigReader
+}
+
+func (r overlayConfigReader) Read(ref configmerge.ConfigReference) ([]byte, error) {
+	if ref.IsLocalReference() {
+		if ref.Path == r.mainPath {
+			return r.mainContent, nil
+		}
+		if !filepath.IsAbs(ref.Path) {
+			ref.Path = filepath.Join(filepath.Dir(r.mainPath), ref.Path)
+		}
+	}
+	return r.reader.Read(ref)
+}
+
+func (r overlayConfigReader) CleanupRepoDirs() error {
+	return r.reader.CleanupRepoDirs()
+}
+
+func (s *Server) diagnose(doc *document) []Diagnostic {
+	diagnostics := []Diagnostic{}
+
+	content := []byte(doc.text)
+	isMerged := false
+
+	var module configmerge.ConfigModule
+	if err := yaml.Unmarshal(content, &module); err == nil && len(module.Include) > 0 {
+		merged, err := s.mergeConfig(doc, content)
+		if err != nil {
+			return append(diagnostics, Diagnostic{
+				Range:    doc.locate("include"),
+				Severity: SeverityError,
+				Source:   diagnosticSource,
+				Message:  fmt.Sprintf("failed to merge config modules: %s", err),
+			})
+		}
+		content = []byte(merged)
+		isMerged = true
+	}
+
+	config, warnings, err := parseConfig(content)
+	for _, warning := range warnings {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    doc.locateMessage(warning, false),
+			Severity: SeverityWarning,
+			Source:   diagnosticSource,
+			Message:  warning,
+		})
+	}
+	if err != nil {
+		return append(diagnostics, Diagnostic{
+			Range:    doc.locateMessage(err.Error(), !isMerged),
+			Severity: SeverityError,
+			Source:   diagnosticSource,
+			Message:  err.Error(),
+		})
+	}
+
+	if err := validateFormatVersion(config.FormatVersion); err != nil {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    doc.locate("format_version"),
+			Severity: SeverityError,
+			Source:   diagnosticSource,
+			Message:  err.Error(),
+		})
+	}
+
+	return diagnostics
+}
+
+// parseConfig runs the same parsing and validation as the run command. Documents being edited are often incomplete,
+// so a panic on an unexpected structure is reported as an error instead of bringing down the server.
+func parseConfig(content []byte) (config models.BitriseDataModel, warnings []string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid config: %v", r)
+		}
+	}()
+
+	return bitrise.ConfigModelFromYAMLBytesWithValidation(content, bitrise.ValidationTypeFull)
+}
+
+func (s *Server) mergeConfig(doc *document, content []byte) (string, error) {
+	pth, err := uriToPath(doc.uri)
+	if err != nil {
+		return "", err
+	}
+
+	reader, err := s.newConfigReader()
+	if err != nil {
+		return "", err
+	}
+
+	logger := log.NewLogger(log.GetGlobalLoggerOpts())
+	merger := configmerge.NewMerger(overlayConfigReader{mainPath: pth, mainContent: content, reader: reader}, logger)
+	merged, _, err := merger.MergeConfig(pth)
+	return merged, err
+}
+
+func validateFormatVersion(formatVersion string) error {
+	supportedVersion, err := ver.NewVersion(models.FormatVersion)
+	if err != nil {
+		return err
+	}
+
+	configVersion, err := ver.NewVersion(formatVersion)
+	if err != nil {
+		return fmt.Errorf("invalid format_version (%s): %s", formatVersion, err)
+	}
+
+	if configVersion.GreaterThan(supportedVersion) {
+		return fmt.Errorf("the config has a higher format version (%s) than the bitrise CLI supported format version (%s)", formatVersion, models.FormatVersion)
+	}
+
+	return nil
+}
+
+// locateMessage guesses the position an error or warning message refers to.
+// YAML syntax errors carry a line number, validation errors usually mention the ID of the invalid item in parentheses.
+func (d *document) locateMessage(message string, hasLineNumbers bool) Range {
+	if hasLineNumbers {
+		if match := yamlErrorLinePattern.FindStringSubmatch(message); match != nil {
+			if line, err := strconv.Atoi(match[1]); err == nil && line > 0 && line <= len(d.lines) {
+				return d.lineRange(line - 1)
+			}
+		}
+	}
+
+	for _, match := range validationErrorIDPattern.FindAllStringSubmatch(message, -1) {
+		id := firstNonEmpty(match[1:]...)
+		if r, ok := d.find(id); ok {
+			return r
+		}
+	}
+
+	return d.lineRange(0)
+}
+
+// locate returns the range of the first mapping key with the given name, or the first line of the document.
+func (d *document) locate(key string) Range {
+	for _, entry := range d.outline() {
+		if entry.key == key {
+			return d.keyRange(entry.line, entry.keyCol, entry.key)
+		}
+	}
+	return d.lineRange(0)
+}
+
+// find returns the range of the first mapping key or list item referring to the given ID.
+func (d *document) find(id string) (Range, bool) {
+	for _, entry := range d.outline() {
+		if entry.key == id || entry.key == models.StepListItemStepBundleKeyPrefix+id {
+			return d.keyRange(entry.line, entry.keyCol, entry.key), true
+		}
+	}
+
+	for idx, line := range d.lines {
+		info := parseLine(line)
+		if info.blank || info.value != id {
+			continue
+		}
+		col := strings.LastIndex(line, id)
+		return d.keyRange(idx, col, id), true
+	}
+
+	return Range{}, false
+}
+
+func (d *document) lineRange(line int) Range {
+	text := d.line(line)
+	indent := len(text) - len(strings.TrimLeft(text, " "))
+	return Range{
+		Start: Position{Line: line, Character: byteToUTF16Offset(text, indent)},
+		End:   Position{Line: line, Character: byteToUTF16Offset(text, len(text))},
+	}
+}
@@ -0,0 +1,347 @@
+package lsp
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// document is an open text document. Its outline is built by a line based scanner instead of a YAML parser,
+// because the document is edited continuously and is often not valid YAML at the time completion is requested.
+type document struct {
+	uri   string
+	text  string
+	lines []string
+}
+
+func newDocument(uri, text string) *document {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	return &document{
+		uri:   uri,
+		text:  text,
+		lines: lines,
+	}
+}
+
+func (d *document) line(idx int) string {
+	if idx < 0 || idx >= len(d.lines) {
+		return ""
+	}
+	return d.lines[idx]
+}
+
+// lineInfo describes a single line of a block style YAML document.
+type lineInfo struct {
+	blank      bool
+	isListItem bool
+	// contentCol is the byte column of the first dash for list items and the column of the content otherwise.
+	contentCol int
+	key        string
+	keyCol     int
+	// valueCol is the byte column right after the key separator colon, -1 if the line has no key.
+	valueCol int
+	value    string
+}
+
+func parseLine(line string) lineInfo {
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	content := line[indent:]
+	if content == "" || strings.HasPrefix(content, "#") {
+		return lineInfo{blank: true, contentCol: indent, valueCol: -1}
+	}
+
+	info := lineInfo{contentCol: indent, keyCol: indent, valueCol: -1}
+	for content == "-" || strings.HasPrefix(content, "- ") {
+		info.isListItem = true
+		trimmed := strings.TrimLeft(content[1:], " ")
+		info.keyCol += len(content) - len(trimmed)
+		content = trimmed
+	}
+
+	key, sepIdx := splitKey(content)
+	if sepIdx < 0 {
+		info.value = strings.TrimSpace(content)
+		return info
+	}
+
+	info.key = key
+	info.valueCol = info.keyCol + sepIdx + 1
+	info.value = strings.TrimSpace(stripComment(content[sepIdx+1:]))
+	return info
+}
+
+// splitKey returns the key of a `key: value` mapping entry and the index of the separator colon.
+func splitKey(content string) (string, int) {
+	start := 0
+	if content != "" && (content[0] == '"' || content[0] == '\'') {
+		end := strings.IndexByte(content[1:], content[0])
+		if end < 0 {
+			return "", -1
+		}
+		start = end + 2
+	}
+
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case '#':
+			if i > 0 && (content[i-1] == ' ' || content[i-1] == '\t') {
+				return "", -1
+			}
+		case '[', '{':
+			if i == 0 {
+				return "", -1
+			}
+		case ':':
+			if i+1 == len(content) || content[i+1] == ' ' || content[i+1] == '\t' {
+				key := strings.TrimSpace(content[:i])
+				key = strings.Trim(key, `"'`)
+				return key, i
+			}
+		}
+	}
+
+	return "", -1
+}
+
+func stripComment(value string) string {
+	if strings.HasPrefix(strings.TrimSpace(value), "#") {
+		return ""
+	}
+	if idx := strings.Index(value, " #"); idx >= 0 {
+		return value[:idx]
+	}
+	return value
+}
+
+func isBlockScalarIndicator(value string) bool {
+	return strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">")
+}
+
+type scope struct {
+	key string
+	col int
+	// itemCol is the dash column of the list item this mapping key belongs to, -1 if it is not part of a list item.
+	itemCol int
+}
+
+// outlineScanner walks the document line by line and tracks the chain of mapping keys enclosing each line.
+type outlineScanner struct {
+	stack []scope
+	// blockCol is the column of the key owning the block scalar being scanned, -1 outside of block scalars.
+	blockCol int
+}
+
+func newOutlineScanner() *outlineScanner {
+	return &outlineScanner{blockCol: -1}
+}
+
+// inBlockScalar reports whether the given line is part of the block scalar opened by a previous line.
+func (s *outlineScanner) inBlockScalar(line string) bool {
+	if s.blockCol < 0 {
+		return false
+	}
+	if strings.TrimSpace(line) == "" {
+		return true
+	}
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	if indent > s.blockCol {
+		return true
+	}
+	s.blockCol = -1
+	return false
+}
+
+// parents returns the keys enclosing a line with the given shape and pops the scopes the line closes.
+func (s *outlineScanner) parents(info lineInfo) ([]string, int) {
+	inheritedItemCol := -1
+	for len(s.stack) > 0 {
+		top := s.stack[len(s.stack)-1]
+		if info.isListItem {
+			if top.col < info.contentCol || (top.col == info.contentCol && top.itemCol != info.contentCol) {
+				break
+			}
+		} else if top.col < info.contentCol {
+			break
+		}
+
+		if !info.isListItem && top.col == info.contentCol && top.itemCol >= 0 {
+			inheritedItemCol = top.itemCol
+		}
+		s.stack = s.stack[:len(s.stack)-1]
+	}
+
+	parents := make([]string, 0, len(s.stack))
+	for _, sc := range s.stack {
+		parents = append(parents, sc.key)
+	}
+	return parents, inheritedItemCol
+}
+
+func (s *outlineScanner) feed(line string) ([]string, lineInfo, bool) {
+	if s.inBlockScalar(line) {
+		return nil, lineInfo{blank: true}, false
+	}
+
+	info := parseLine(line)
+	if info.blank {
+		return nil, info, false
+	}
+
+	parents, inheritedItemCol := s.parents(info)
+	if info.key != "" {
+		itemCol := inheritedItemCol
+		if info.isListItem {
+			itemCol = info.contentCol
+		}
+		s.stack = append(s.stack, scope{key: info.key, col: info.keyCol, itemCol: itemCol})
+
+		if isBlockScalarIndicator(info.value) {
+			s.blockCol = info.keyCol
+		}
+	}
+
+	return parents, info, true
+}
+
+// outlineEntry is a mapping key of the document together with its enclosing keys.
+type outlineEntry struct {
+	parents []string
+	key     string
+	line    int
+	keyCol  int
+}
+
+func (d *document) outline() []outlineEntry {
+	var entries []outlineEntry
+	scanner := newOutlineScanner()
+	for idx, line := range d.lines {
+		parents, info, ok := scanner.feed(line)
+		if !ok || info.key == "" {
+			continue
+		}
+		entries = append(entries, outlineEntry{parents: parents, key: info.key, line: idx, keyCol: info.keyCol})
+	}
+	return entries
+}
+
+// cursorContext describes the YAML structure around a cursor position.
+type cursorContext struct {
+	parents    []string
+	isListItem bool
+	// key is the mapping key of the cursor line, if any.
+	key string
+	// inValue is true if the cursor is after the key separator of the cursor line.
+	inValue bool
+	// word is the token under the cursor.
+	word      string
+	wordRange Range
+	// prefix is the part of the token before the cursor.
+	prefix string
+}
+
+func (c cursorContext) parentsMatch(pattern ...string) bool {
+	if len(c.parents) != len(pattern) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != c.parents[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *document) contextAt(pos Position) (cursorContext, bool) {
+	if pos.Line < 0 || pos.Line >= len(d.lines) {
+		return cursorContext{}, false
+	}
+
+	scanner := newOutlineScanner()
+	for idx := 0; idx < pos.Line; idx++ {
+		scanner.feed(d.lines[idx])
+	}
+
+	line := d.lines[pos.Line]
+	if scanner.inBlockScalar(line) {
+		return cursorContext{}, false
+	}
+
+	cursor := utf16ToByteOffset(line, pos.Character)
+	info := parseLine(line)
+	if info.blank {
+		// Typing on an empty line: its indentation (up to the cursor) decides the enclosing keys.
+		info.contentCol = min(cursor, len(line)-len(strings.TrimLeft(line, " ")))
+	}
+
+	parents, _ := scanner.parents(info)
+	ctx := cursorContext{
+		parents:    parents,
+		isListItem: info.isListItem,
+		key:        info.key,
+		inValue:    info.valueCol >= 0 && cursor >= info.valueCol,
+	}
+
+	start, end := wordBounds(line, cursor)
+	ctx.word = strings.TrimSuffix(line[start:end], ":")
+	ctx.prefix = line[start:max(start, min(cursor, end))]
+	ctx.wordRange = Range{
+		Start: Position{Line: pos.Line, Character: byteToUTF16Offset(line, start)},
+		End:   Position{Line: pos.Line, Character: byteToUTF16Offset(line, start+len(ctx.word))},
+	}
+
+	return ctx, true
+}
+
+func isWordSeparator(c byte) bool {
+	return strings.IndexByte(" \t,[]{}\"'#", c) >= 0
+}
+
+func wordBounds(line string, cursor int) (int, int) {
+	cursor = min(max(cursor, 0), len(line))
+
+	start := cursor
+	for start > 0 && !isWordSeparator(line[start-1]) {
+		start--
+	}
+	end := cursor
+	for end < len(line) && !isWordSeparator(line[end]) {
+		end++
+	}
+	return start, end
+}
+
+// utf16ToByteOffset converts an LSP character offset (UTF-16 code units) to a byte offset in the line.
+func utf16ToByteOffset(line string, character int) int {
+	units := 0
+	for idx, r := range line {
+		if units >= character {
+			return idx
+		}
+		units += utf16.RuneLen(r)
+	}
+	return len(line)
+}
+
+// byteToUTF16Offset converts a byte offset in the line to an LSP character offset (UTF-16 code units).
+func byteToUTF16Offset(line string, offset int) int {
+	offset = min(max(offset, 0), len(line))
+	units := 0
+	for idx := 0; idx < offset; {
+		r, size := utf8.DecodeRuneInString(line[idx:])
+		units += utf16.RuneLen(r)
+		idx += size
+	}
+	return units
+}
+
+func (d *document) keyRange(line, keyCol int, key string) Range {
+	text := d.line(line)
+	return Range{
+		Start: Position{Line: line, Character: byteToUTF16Offset(text, keyCol)},
+		End:   Position{Line: line, Character: byteToUTF16Offset(text, keyCol+len(key))},
+	}
+}
@@ -0,0 +1,187 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `format_version: "13"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+
+containers:
+  golang:
+    type: execution
+    image: golang:1.22
+  postgres:
+    type: service
+    image: postgres:16
+
+step_bundles:
+  setup:
+    title: Setup
+    inputs:
+    - verbose: "false"
+    steps:
+    - script@1:
+        inputs:
+        - content: |
+            echo "key: value"
+            echo done
+
+pipelines:
+  ci:
+    workflows:
+      test: {}
+      deploy:
+        depends_on:
+        - test
+
+workflows:
+  _prepare:
+    summary: Prepares the build
+  test:
+    before_run:
+    - _prepare
+    steps:
+    - bundle::setup:
+        inputs:
+        - verbose: "true"
+    - script@1:
+        execution_container: golang
+        service_containers:
+        - postgres
+        inputs:
+        - content: echo test
+  deploy:
+    steps:
+    - deploy-to-bitrise-io@2: {}
+`
+
+func Test_parseLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want lineInfo
+	}{
+		{line: "", want: lineInfo{blank: true, valueCol: -1}},
+		{line: "    # comment", want: lineInfo{blank: true, contentCol: 4, valueCol: -1}},
+		{line: "workflows:", want: lineInfo{key: "workflows", valueCol: 10}},
+		{line: "  test: {}", want: lineInfo{contentCol: 2, key: "test", keyCol: 2, valueCol: 7, value: "{}"}},
+		{line: "    - bundle::setup:", want: lineInfo{isListItem: true, contentCol: 4, key: "bundle::setup", keyCol: 6, valueCol: 20}},
+		{line: "    - _prepare", want: lineInfo{isListItem: true, contentCol: 4, keyCol: 6, valueCol: -1, value: "_prepare"}},
+		{line: "  - git::https://github.com/bitrise-io/steps-script.git@main:", want: lineInfo{isListItem: true, contentCol: 2, key: "git::https://github.com/bitrise-io/steps-script.git@main", keyCol: 4, valueCol: 61}},
+		{line: `  "quoted: key": value # comment`, want: lineInfo{contentCol: 2, key: "quoted: key", keyCol: 2, valueCol: 16, value: "value"}},
+		{line: "  before_run: [a, b]", want: lineInfo{contentCol: 2, key: "before_run", keyCol: 2, valueCol: 13, value: "[a, b]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			require.Equal(t, tt.want, parseLine(tt.line))
+		})
+	}
+}
+
+func Test_document_outline(t *testing.T) {
+	doc := newDocument("file:///bitrise.yml", testConfig)
+
+	keysByPath := map[string]bool{}
+	for _, entry := range doc.outline() {
+		pth := ""
+		for _, parent := range entry.parents {
+			pth += parent + "."
+		}
+		keysByPath[pth+entry.key] = true
+	}
+
+	require.True(t, keysByPath["workflows.test.steps.bundle::setup.inputs.verbose"])
+	require.True(t, keysByPath["workflows.test.steps.script@1.service_containers"])
+	require.True(t, keysByPath["step_bundles.setup.steps.script@1.inputs.content"])
+	require.True(t, keysByPath["pipelines.ci.workflows.deploy.depends_on"])
+	require.True(t, keysByPath["workflows.deploy.steps.deploy-to-bitrise-io@2"])
+	// Block scalar content is not part of the outline
+	require.False(t, keysByPath["step_bundles.setup.steps.script@1.inputs.content.echo \"key"])
+}
+
+func Test_document_contextAt(t *testing.T) {
+	doc := newDocument("file:///bitrise.yml", testConfig+"  new:\n    before_run:\n    - \n")
+
+	tests := []struct {
+		name        string
+		pos         Position
+		wantParents []string
+		wantWord    string
+		wantRef     reference
+	}{
+		{
+			name:        "before_run list item",
+			pos:         Position{Line: 36, Character: 8},
+			wantParents: []string{"workflows", "test", "before_run"},
+			wantWord:    "_prepare",
+			wantRef:     reference{kind: referenceWorkflow},
+		},
+		{
+			name:        "step bundle reference",
+			pos:         Position{Line: 38, Character: 10},
+			wantParents: []string{"workflows", "test", "steps"},
+			wantWord:    "bundle::setup",
+			wantRef:     reference{kind: referenceStepListItem},
+		},
+		{
+			name:        "step bundle input",
+			pos:         Position{Line: 40, Character: 11},
+			wantParents: []string{"workflows", "test", "steps", "bundle::setup", "inputs"},
+			wantWord:    "verbose",
+			wantRef:     reference{kind: referenceStepInput, scope: "bundle::setup"},
+		},
+		{
+			name:        "execution container",
+			pos:         Position{Line: 42, Character: 30},
+			wantParents: []string{"workflows", "test", "steps", "script@1"},
+			wantWord:    "golang",
+			wantRef:     reference{kind: referenceExecutionContainer},
+		},
+		{
+			name:        "service container",
+			pos:         Position{Line: 44, Character: 12},
+			wantParents: []string{"workflows", "test", "steps", "script@1", "service_containers"},
+			wantWord:    "postgres",
+			wantRef:     reference{kind: referenceServiceContainer},
+		},
+		{
+			name:        "depends_on",
+			pos:         Position{Line: 29, Character: 11},
+			wantParents: []string{"pipelines", "ci", "workflows", "deploy", "depends_on"},
+			wantWord:    "test",
+			wantRef:     reference{kind: referencePipelineWorkflow, scope: "ci"},
+		},
+		{
+			name:        "empty list item",
+			pos:         Position{Line: 52, Character: 6},
+			wantParents: []string{"workflows", "new", "before_run"},
+			wantRef:     reference{kind: referenceWorkflow},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, ok := doc.contextAt(tt.pos)
+			require.True(t, ok)
+			require.Equal(t, tt.wantParents, ctx.parents)
+			require.Equal(t, tt.wantWord, ctx.word)
+			require.Equal(t, tt.wantRef, referenceAt(ctx))
+		})
+	}
+}
+
+func Test_document_contextAt_blockScalar(t *testing.T) {
+	doc := newDocument("file:///bitrise.yml", testConfig)
+
+	_, ok := doc.contextAt(Position{Line: 20, Character: 14})
+	require.False(t, ok)
+}
+
+func Test_utf16Offsets(t *testing.T) {
+	line := "  title: 🚀 deploy"
+
+	require.Equal(t, 11, byteToUTF16Offset(line, 13))
+	require.Equal(t, 13, utf16ToByteOffset(line, 11))
+	require.Equal(t, len(line), utf16ToByteOffset(line, 100))
+}
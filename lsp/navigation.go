@@ -0,0 +1,177 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (s *Server) definition(doc *document, pos Position) []Location {
+	ctx, ok := doc.contextAt(pos)
+	if !ok || ctx.word == "" {
+		return nil
+	}
+
+	syms := newSymbols(doc)
+	ref := referenceAt(ctx)
+
+	var entry outlineEntry
+	found := false
+	switch ref.kind {
+	case referenceWorkflow:
+		entry, found = syms.lookup(workflowsKey, ctx.word)
+	case referencePipelineWorkflow:
+		entry, found = syms.pipelineWorkflows[ref.scope][ctx.word]
+	case referencePipeline:
+		entry, found = syms.lookup(pipelinesKey, ctx.word)
+	case referenceStage:
+		entry, found = syms.lookup(stagesKey, ctx.word)
+	case referenceStepListItem:
+		if bundleID, ok := stepBundleID(ctx.word); ok {
+			entry, found = syms.lookup(stepBundlesKey, bundleID)
+		}
+	case referenceExecutionContainer, referenceServiceContainer, referenceWithContainer:
+		entry, found = syms.lookup(containersKey, ctx.word)
+	case referenceWithService:
+		entry, found = syms.lookup(servicesKey, ctx.word)
+	}
+
+	if !found {
+		return nil
+	}
+
+	return []Location{{
+		URI:   doc.uri,
+		Range: doc.keyRange(entry.line, entry.keyCol, entry.key),
+	}}
+}
+
+func (s *Server) hover(doc *document, pos Position) *Hover {
+	ctx, ok := doc.contextAt(pos)
+	if !ok || ctx.word == "" {
+		return nil
+	}
+
+	syms := newSymbols(doc)
+	ref := referenceAt(ctx)
+
+	var content string
+	switch ref.kind {
+	case referenceWorkflow, referencePipelineWorkflow:
+		content = s.workflowHover(syms, ctx.word)
+	case referenceStepListItem:
+		if bundleID, ok := stepBundleID(ctx.word); ok {
+			content = stepBundleHover(syms, bundleID)
+		} else {
+			content = s.stepHover(syms, ctx.word)
+		}
+	case referenceExecutionContainer, referenceServiceContainer, referenceWithContainer:
+		content = containerHover(syms, containersKey, ctx.word)
+	case referenceWithService:
+		content = containerHover(syms, servicesKey, ctx.word)
+	case referenceStepInput:
+		content = s.inputHover(syms, ref.scope, ctx.word)
+	}
+
+	if content == "" {
+		return nil
+	}
+
+	return &Hover{
+		Contents: markupContent{Kind: "markdown", Value: content},
+		Range:    &ctx.wordRange,
+	}
+}
+
+func markdownDoc(title, summary, description string, details ...string) string {
+	var parts []string
+	if title != "" {
+		parts = append(parts, "**"+title+"**")
+	}
+	if summary != "" {
+		parts = append(parts, summary)
+	}
+	parts = append(parts, details...)
+	if description != "" && description != summary {
+		parts = append(parts, description)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (s *Server) workflowHover(syms symbols, id string) string {
+	if syms.config == nil {
+		return ""
+	}
+	workflow, ok := syms.config.Workflows[id]
+	if !ok {
+		return ""
+	}
+	return markdownDoc(firstNonEmpty(workflow.Title, id), workflow.Summary, workflow.Description)
+}
+
+func stepBundleHover(syms symbols, id string) string {
+	if syms.config == nil {
+		return ""
+	}
+	bundle, ok := syms.config.StepBundles[id]
+	if !ok {
+		return ""
+	}
+	return markdownDoc(firstNonEmpty(bundle.Title, id), bundle.Summary, bundle.Description, fmt.Sprintf("Steps: %d", len(bundle.Steps)))
+}
+
+func containerHover(syms symbols, section, id string) string {
+	if syms.config == nil {
+		return ""
+	}
+
+	containers := syms.config.Containers
+	if section == servicesKey {
+		containers = syms.config.Services
+	}
+	container, ok := containers[id]
+	if !ok {
+		return ""
+	}
+
+	details := []string{fmt.Sprintf("Image: `%s`", container.Image)}
+	if container.Build != nil {
+		details = []string{fmt.Sprintf("Build: `%s`", container.Build.GetDockerfile())}
+	}
+	if container.Type != "" {
+		details = append(details, fmt.Sprintf("Type: %s", container.Type))
+	}
+	if len(container.Ports) > 0 {
+		details = append(details, fmt.Sprintf("Ports: %s", strings.Join(container.Ports, ", ")))
+	}
+	return markdownDoc(id, "", "", details...)
+}
+
+func (s *Server) stepHover(syms symbols, stepRef string) string {
+	step, err := s.stepInfo.StepInfo(stepRef, defaultStepLibSourceOf(syms))
+	if err != nil {
+		s.logf("Failed to get step info (%s): %s", stepRef, err)
+		return ""
+	}
+
+	var details []string
+	if step.SourceCodeURL != nil {
+		details = append(details, fmt.Sprintf("Source: %s", *step.SourceCodeURL))
+	}
+	return markdownDoc(firstNonEmpty(derefString(step.Title), stepRef), derefString(step.Summary), derefString(step.Description), details...)
+}
+
+func (s *Server) inputHover(syms symbols, stepListItemKey, inputKey string) string {
+	for _, input := range s.stepListItemInputs(syms, stepListItemKey) {
+		key, value, err := input.GetKeyValuePair()
+		if err != nil || key != inputKey {
+			continue
+		}
+		opts, err := input.GetOptions()
+		if err != nil {
+			return ""
+		}
+		return markdownDoc(firstNonEmpty(derefString(opts.Title), key), "", "", inputDocumentation(value, opts))
+	}
+
+	return ""
+}
@@ -0,0 +1,99 @@
+package lsp
+
+import (
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+)
+
+type referenceKind int
+
+const (
+	referenceNone referenceKind = iota
+	referenceWorkflow
+	// referencePipelineWorkflow is a workflow of a graph pipeline (depends_on), the reference's scope is the pipeline ID.
+	referencePipelineWorkflow
+	referencePipeline
+	referenceStage
+	// referenceStepListItem is a step list item key: either a step or a step bundle (bundle::<id>) reference.
+	referenceStepListItem
+	referenceExecutionContainer
+	referenceServiceContainer
+	// referenceWithContainer and referenceWithService are the legacy with group container references.
+	referenceWithContainer
+	referenceWithService
+	// referenceStepInput is an input key of a step or step bundle, the reference's scope is the step list item key.
+	referenceStepInput
+)
+
+type reference struct {
+	kind  referenceKind
+	scope string
+}
+
+func isStepListParents(ctx cursorContext) bool {
+	return ctx.parentsMatch(workflowsKey, "*", "steps") ||
+		ctx.parentsMatch(stepBundlesKey, "*", "steps") ||
+		ctx.parentsMatch(workflowsKey, "*", "steps", models.StepListItemWithKey, "steps")
+}
+
+// referenceAt tells what kind of config item the token under the cursor refers to, based on where it is in the document.
+func referenceAt(ctx cursorContext) reference {
+	n := len(ctx.parents)
+	last := ""
+	if n > 0 {
+		last = ctx.parents[n-1]
+	}
+	// A scalar list item or a flow sequence value of the given key.
+	listItemOrValueOf := func(key string, parents ...string) bool {
+		if ctx.inValue {
+			return ctx.key == key && ctx.parentsMatch(parents...)
+		}
+		return ctx.isListItem && ctx.key == "" && ctx.parentsMatch(append(parents, key)...)
+	}
+	valueOf := func(key string, parents ...string) bool {
+		return ctx.inValue && ctx.key == key && ctx.parentsMatch(parents...)
+	}
+
+	switch {
+	case listItemOrValueOf("before_run", workflowsKey, "*"), listItemOrValueOf("after_run", workflowsKey, "*"):
+		return reference{kind: referenceWorkflow}
+	case listItemOrValueOf("depends_on", pipelinesKey, "*", workflowsKey, "*"):
+		return reference{kind: referencePipelineWorkflow, scope: ctx.parents[1]}
+	case valueOf("uses", pipelinesKey, "*", workflowsKey, "*"):
+		return reference{kind: referenceWorkflow}
+	case !ctx.inValue && !ctx.isListItem && ctx.parentsMatch(pipelinesKey, "*", workflowsKey):
+		return reference{kind: referenceWorkflow}
+	case !ctx.inValue && ctx.isListItem && ctx.parentsMatch(pipelinesKey, "*", stagesKey):
+		return reference{kind: referenceStage}
+	case !ctx.inValue && ctx.isListItem && ctx.parentsMatch(stagesKey, "*", workflowsKey):
+		return reference{kind: referenceWorkflow}
+	case valueOf("workflow", "trigger_map"):
+		return reference{kind: referenceWorkflow}
+	case valueOf("pipeline", "trigger_map"):
+		return reference{kind: referencePipeline}
+	case !ctx.inValue && ctx.isListItem && isStepListParents(ctx):
+		return reference{kind: referenceStepListItem}
+	case valueOf("container", workflowsKey, "*", "steps", models.StepListItemWithKey):
+		return reference{kind: referenceWithContainer}
+	case listItemOrValueOf(servicesKey, workflowsKey, "*", "steps", models.StepListItemWithKey):
+		return reference{kind: referenceWithService}
+	case ctx.inValue && ctx.key == "execution_container",
+		!ctx.inValue && last == "execution_container":
+		return reference{kind: referenceExecutionContainer}
+	case ctx.inValue && ctx.key == "service_containers",
+		!ctx.inValue && ctx.isListItem && last == "service_containers":
+		return reference{kind: referenceServiceContainer}
+	case !ctx.inValue && ctx.isListItem && n >= 3 && last == "inputs" && ctx.parents[n-3] == "steps":
+		return reference{kind: referenceStepInput, scope: ctx.parents[n-2]}
+	}
+
+	return reference{kind: referenceNone}
+}
+
+func stepBundleID(stepListItemKey string) (string, bool) {
+	if !strings.HasPrefix(stepListItemKey, models.StepListItemStepBundleKeyPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(stepListItemKey, models.StepListItemStepBundleKeyPrefix), true
+}
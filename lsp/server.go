@@ -0,0 +1,237 @@
+// Package lsp implements a Language Server Protocol server for Bitrise config files (bitrise.yml).
+//
+// The server speaks JSON-RPC over stdio and provides diagnostics (using the same parsing, merging and validation
+// as the bitrise run command), completion of workflow, step bundle, container and step input references,
+// go-to-definition of references and hover documentation.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/configmerge"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/version"
+)
+
+const serverName = "bitrise-lsp"
+
+var errExitWithoutShutdown = errors.New("exit notification received before shutdown request")
+
+type Server struct {
+	transport       *transport
+	logger          log.Logger
+	stepInfo        StepInfoProvider
+	newConfigReader func() (configmerge.ConfigReader, error)
+
+	documents   map[string]*document
+	initialized bool
+	shutdown    bool
+}
+
+func NewServer(in io.Reader, out io.Writer, logger log.Logger, stepInfo StepInfoProvider) *Server {
+	return &Server{
+		transport: newTransport(in, out),
+		logger:    logger,
+		stepInfo:  stepInfo,
+		newConfigReader: func() (configmerge.ConfigReader, error) {
+			return configmerge.NewConfigReader(logger)
+		},
+		documents: map[string]*document{},
+	}
+}
+
+// Serve processes messages until the client sends the exit notification or closes the input stream.
+func (s *Server) Serve() error {
+	for {
+		content, err := s.transport.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("read message: %w", err)
+		}
+
+		var req request
+		if err := json.Unmarshal(content, &req); err != nil {
+			if err := s.replyError(nil, codeParseError, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return errExitWithoutShutdown
+			}
+			return nil
+		}
+
+		if err := s.handle(req); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(req request) error {
+	if !s.initialized && req.Method != "initialize" {
+		if req.isNotification() {
+			return nil
+		}
+		return s.replyError(req.ID, codeServerNotInitialized, "server not initialized")
+	}
+
+	switch req.Method {
+	case "initialize":
+		s.initialized = true
+		return s.reply(req.ID, initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync: textDocumentSyncOptions{
+					OpenClose: true,
+					Change:    textDocumentSyncKindFull,
+					Save:      saveOptions{IncludeText: true},
+				},
+				CompletionProvider: completionOptions{TriggerCharacters: []string{":", " ", "-"}},
+				DefinitionProvider: true,
+				HoverProvider:      true,
+			},
+			ServerInfo: serverInfo{Name: serverName, Version: version.VERSION},
+		})
+	case "initialized":
+		return nil
+	case "shutdown":
+		s.shutdown = true
+		return s.reply(req.ID, nil)
+	case "textDocument/didOpen":
+		var params didOpenTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.invalidParams(req, err)
+		}
+		return s.updateDocument(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params didChangeTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.invalidParams(req, err)
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil
+		}
+		// Only full document sync is advertised, so the last change holds the whole document.
+		return s.updateDocument(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+	case "textDocument/didSave":
+		var params didSaveTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.invalidParams(req, err)
+		}
+		if params.Text != nil {
+			return s.updateDocument(params.TextDocument.URI, *params.Text)
+		}
+		return nil
+	case "textDocument/didClose":
+		var params didCloseTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.invalidParams(req, err)
+		}
+		delete(s.documents, params.TextDocument.URI)
+		return s.publishDiagnostics(params.TextDocument.URI, []Diagnostic{})
+	case "textDocument/completion":
+		return s.handlePositionRequest(req, func(doc *document, pos Position) any {
+			return completionList{Items: nonNil(s.complete(doc, pos))}
+		})
+	case "textDocument/definition":
+		return s.handlePositionRequest(req, func(doc *document, pos Position) any {
+			return nonNil(s.definition(doc, pos))
+		})
+	case "textDocument/hover":
+		return s.handlePositionRequest(req, func(doc *document, pos Position) any {
+			if hover := s.hover(doc, pos); hover != nil {
+				return hover
+			}
+			return nil
+		})
+	}
+
+	if req.isNotification() {
+		// Unknown notifications (for example $/cancelRequest) can be ignored
+		return nil
+	}
+	return s.replyError(req.ID, codeMethodNotFound, fmt.Sprintf("method not supported: %s", req.Method))
+}
+
+func (s *Server) handlePositionRequest(req request, handler func(doc *document, pos Position) any) error {
+	var params textDocumentPositionParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return s.invalidParams(req, err)
+	}
+
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return s.reply(req.ID, nil)
+	}
+
+	return s.reply(req.ID, handler(doc, params.Position))
+}
+
+func (s *Server) updateDocument(uri, text string) error {
+	doc := newDocument(uri, text)
+	s.documents[uri] = doc
+	return s.publishDiagnostics(uri, s.diagnose(doc))
+}
+
+func (s *Server) publishDiagnostics(uri string, diagnostics []Diagnostic) error {
+	return s.transport.write(notification{
+		JSONRPC: jsonRPCVersion,
+		Method:  "textDocument/publishDiagnostics",
+		Params:  publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics},
+	})
+}
+
+func (s *Server) reply(id *json.RawMessage, result any) error {
+	return s.transport.write(response{JSONRPC: jsonRPCVersion, ID: id, Result: result})
+}
+
+func (s *Server) replyError(id *json.RawMessage, code int, message string) error {
+	return s.transport.write(response{JSONRPC: jsonRPCVersion, ID: id, Error: &responseError{Code: code, Message: message}})
+}
+
+func (s *Server) invalidParams(req request, err error) error {
+	if req.isNotification() {
+		s.logf("Invalid %s notification: %s", req.Method, err)
+		return nil
+	}
+	return s.replyError(req.ID, codeInvalidParams, err.Error())
+}
+
+func (s *Server) logf(format string, args ...any) {
+	s.logger.Debugf("[%s] %s", serverName, fmt.Sprintf(format, args...))
+}
+
+// nonNil makes sure empty results are encoded as an empty JSON array instead of null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported document URI scheme: %s", u.Scheme)
+	}
+
+	pth := u.Path
+	if runtime.GOOS == "windows" {
+		pth = strings.TrimPrefix(pth, "/")
+	}
+	return filepath.FromSlash(pth), nil
+}
@@ -0,0 +1,343 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/log"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+)
+
+const testURI = "file:///project/bitrise.yml"
+
+type fakeStepInfoProvider struct {
+	steps map[string]stepmanModels.StepModel
+}
+
+func (p fakeStepInfoProvider) StepInfo(stepRef, _ string) (*stepmanModels.StepModel, error) {
+	step, ok := p.steps[stepRef]
+	if !ok {
+		return nil, fmt.Errorf("step not found: %s", stepRef)
+	}
+	return &step, nil
+}
+
+func newFakeStepInfoProvider() fakeStepInfoProvider {
+	title := "Script"
+	summary := "Runs a script"
+	inputTitle := "Script content"
+	isRequired := true
+	return fakeStepInfoProvider{steps: map[string]stepmanModels.StepModel{
+		"script@1": {
+			Title:   &title,
+			Summary: &summary,
+			Inputs: []envmanModels.EnvironmentItemModel{
+				{"content": "", "opts": envmanModels.EnvironmentItemOptionsModel{Title: &inputTitle, IsRequired: &isRequired}},
+				{"is_debug": "no"},
+			},
+		},
+	}}
+}
+
+type testClient struct {
+	input  bytes.Buffer
+	nextID int
+}
+
+func (c *testClient) send(method string, params any) {
+	c.nextID++
+	c.write(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+}
+
+func (c *testClient) notify(method string, params any) {
+	c.write(map[string]any{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (c *testClient) write(message map[string]any) {
+	content, err := json.Marshal(message)
+	if err != nil {
+		panic(err)
+	}
+	c.input.WriteString(fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(content), content))
+}
+
+type testMessage struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+}
+
+func runServer(t *testing.T, client *testClient) ([]testMessage, error) {
+	var output bytes.Buffer
+	server := NewServer(&client.input, &output, log.NewLogger(log.GetGlobalLoggerOpts()), newFakeStepInfoProvider())
+	serveErr := server.Serve()
+
+	var messages []testMessage
+	tr := newTransport(&output, nil)
+	for {
+		content, err := tr.read()
+		if err != nil {
+			break
+		}
+		var message testMessage
+		require.NoError(t, json.Unmarshal(content, &message))
+		messages = append(messages, message)
+	}
+	return messages, serveErr
+}
+
+func responseTo(t *testing.T, messages []testMessage, id int, result any) {
+	for _, message := range messages {
+		if message.ID != nil && *message.ID == id {
+			require.Nil(t, message.Error)
+			require.NoError(t, json.Unmarshal(message.Result, result))
+			return
+		}
+	}
+	t.Fatalf("no response to request %d", id)
+}
+
+func diagnosticsOf(t *testing.T, messages []testMessage) [][]Diagnostic {
+	var diagnostics [][]Diagnostic
+	for _, message := range messages {
+		if message.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var params publishDiagnosticsParams
+		require.NoError(t, json.Unmarshal(message.Params, &params))
+		diagnostics = append(diagnostics, params.Diagnostics)
+	}
+	return diagnostics
+}
+
+func positionParams(line, character int) map[string]any {
+	return map[string]any{
+		"textDocument": map[string]any{"uri": testURI},
+		"position":     map[string]any{"line": line, "character": character},
+	}
+}
+
+func openDocument(client *testClient, text string) {
+	client.send("initialize", map[string]any{})
+	client.notify("initialized", map[string]any{})
+	client.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": testURI, "languageId": "yaml", "version": 1, "text": text},
+	})
+}
+
+func shutdown(client *testClient) {
+	client.send("shutdown", nil)
+	client.notify("exit", nil)
+}
+
+func TestServer_lifecycle(t *testing.T) {
+	client := &testClient{}
+	client.send("textDocument/hover", positionParams(0, 0))
+	client.send("initialize", map[string]any{})
+	client.send("workspace/symbol", map[string]any{})
+	shutdown(client)
+
+	messages, err := runServer(t, client)
+	require.NoError(t, err)
+	require.Len(t, messages, 4)
+
+	require.Equal(t, codeServerNotInitialized, messages[0].Error.Code)
+
+	var result initializeResult
+	responseTo(t, messages, 2, &result)
+	require.Equal(t, serverName, result.ServerInfo.Name)
+	require.True(t, result.Capabilities.HoverProvider)
+
+	require.Equal(t, codeMethodNotFound, messages[2].Error.Code)
+}
+
+func TestServer_exitWithoutShutdown(t *testing.T) {
+	client := &testClient{}
+	client.send("initialize", map[string]any{})
+	client.notify("exit", nil)
+
+	_, err := runServer(t, client)
+	require.ErrorIs(t, err, errExitWithoutShutdown)
+}
+
+func TestServer_diagnostics(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		wantSeverity DiagnosticSeverity
+		wantLine     int
+		wantMessage  string
+	}{
+		{
+			name: "missing workflow reference",
+			text: `format_version: "13"
+workflows:
+  primary:
+    before_run:
+    - missing
+`,
+			wantSeverity: SeverityError,
+			wantLine:     4,
+			wantMessage:  "missing",
+		},
+		{
+			name: "yaml syntax error",
+			text: `format_version: "13"
+workflows:
+  primary:
+    steps: [
+`,
+			wantSeverity: SeverityError,
+			wantMessage:  "yaml",
+		},
+		{
+			name: "unsupported format version",
+			text: `format_version: "1000"
+workflows:
+  primary: {}
+`,
+			wantSeverity: SeverityError,
+			wantLine:     0,
+			wantMessage:  "higher format version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &testClient{}
+			openDocument(client, tt.text)
+			shutdown(client)
+
+			messages, err := runServer(t, client)
+			require.NoError(t, err)
+
+			diagnostics := diagnosticsOf(t, messages)
+			require.Len(t, diagnostics, 1)
+			require.NotEmpty(t, diagnostics[0])
+
+			diagnostic := diagnostics[0][len(diagnostics[0])-1]
+			require.Equal(t, tt.wantSeverity, diagnostic.Severity)
+			require.Contains(t, diagnostic.Message, tt.wantMessage)
+			if tt.wantLine > 0 {
+				require.Equal(t, tt.wantLine, diagnostic.Range.Start.Line)
+			}
+		})
+	}
+}
+
+func TestServer_diagnosticsCleared(t *testing.T) {
+	client := &testClient{}
+	openDocument(client, testConfig)
+	client.notify("textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": testURI, "version": 2},
+		"contentChanges": []map[string]any{{"text": strings.Replace(testConfig, "- _prepare", "- _missing", 1)}},
+	})
+	client.notify("textDocument/didClose", map[string]any{"textDocument": map[string]any{"uri": testURI}})
+	shutdown(client)
+
+	messages, err := runServer(t, client)
+	require.NoError(t, err)
+
+	diagnostics := diagnosticsOf(t, messages)
+	require.Len(t, diagnostics, 3)
+	require.Empty(t, diagnostics[0])
+	require.Len(t, diagnostics[1], 1)
+	require.Empty(t, diagnostics[2])
+}
+
+func TestServer_completion(t *testing.T) {
+	text := testConfig + `  new:
+    before_run:
+    -
+    steps:
+    - script@1:
+        execution_container:
+        inputs:
+        -
+`
+	client := &testClient{}
+	openDocument(client, text)
+	client.send("textDocument/completion", positionParams(52, 6))
+	client.send("textDocument/completion", positionParams(55, 29))
+	client.send("textDocument/completion", positionParams(57, 10))
+	client.send("textDocument/completion", positionParams(29, 10))
+	shutdown(client)
+
+	messages, err := runServer(t, client)
+	require.NoError(t, err)
+
+	labels := func(id int) []string {
+		var list completionList
+		responseTo(t, messages, id, &list)
+		var labels []string
+		for _, item := range list.Items {
+			labels = append(labels, item.Label)
+		}
+		return labels
+	}
+
+	require.Equal(t, []string{"_prepare", "deploy", "new", "test"}, labels(2))
+	require.Equal(t, []string{"golang"}, labels(3))
+	require.Equal(t, []string{"content", "is_debug"}, labels(4))
+	require.Equal(t, []string{"test"}, labels(5))
+}
+
+func TestServer_definition(t *testing.T) {
+	client := &testClient{}
+	openDocument(client, testConfig)
+	client.send("textDocument/definition", positionParams(36, 8))
+	client.send("textDocument/definition", positionParams(38, 10))
+	client.send("textDocument/definition", positionParams(44, 12))
+	client.send("textDocument/definition", positionParams(29, 11))
+	client.send("textDocument/definition", positionParams(0, 2))
+	shutdown(client)
+
+	messages, err := runServer(t, client)
+	require.NoError(t, err)
+
+	tests := []struct {
+		id   int
+		want []Location
+	}{
+		{id: 2, want: []Location{{URI: testURI, Range: Range{Start: Position{Line: 32, Character: 2}, End: Position{Line: 32, Character: 10}}}}},
+		{id: 3, want: []Location{{URI: testURI, Range: Range{Start: Position{Line: 12, Character: 2}, End: Position{Line: 12, Character: 7}}}}},
+		{id: 4, want: []Location{{URI: testURI, Range: Range{Start: Position{Line: 7, Character: 2}, End: Position{Line: 7, Character: 10}}}}},
+		{id: 5, want: []Location{{URI: testURI, Range: Range{Start: Position{Line: 26, Character: 6}, End: Position{Line: 26, Character: 10}}}}},
+		{id: 6, want: []Location{}},
+	}
+	for _, tt := range tests {
+		var locations []Location
+		responseTo(t, messages, tt.id, &locations)
+		require.Equal(t, tt.want, locations, "request %d", tt.id)
+	}
+}
+
+func TestServer_hover(t *testing.T) {
+	client := &testClient{}
+	openDocument(client, testConfig)
+	client.send("textDocument/hover", positionParams(36, 8))
+	client.send("textDocument/hover", positionParams(41, 8))
+	client.send("textDocument/hover", positionParams(46, 11))
+	client.send("textDocument/hover", positionParams(42, 30))
+	shutdown(client)
+
+	messages, err := runServer(t, client)
+	require.NoError(t, err)
+
+	contents := func(id int) string {
+		var hover Hover
+		responseTo(t, messages, id, &hover)
+		return hover.Contents.Value
+	}
+
+	require.Equal(t, "**_prepare**\n\nPrepares the build", contents(2))
+	require.Equal(t, "**Script**\n\nRuns a script", contents(3))
+	require.Equal(t, "**Script content**\n\nRequired.", contents(4))
+	require.Equal(t, "**golang**\n\nImage: `golang:1.22`\n\nType: execution", contents(5))
+}
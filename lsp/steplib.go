@@ -0,0 +1,83 @@
+package lsp
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	stepmanModels "github.com/bitrise-io/stepman/models"
+	"github.com/bitrise-io/stepman/stepid"
+	"github.com/bitrise-io/stepman/stepman"
+)
+
+const defaultStepLibSource = "https://github.com/bitrise-io/bitrise-steplib.git"
+
+// StepInfoProvider returns the definition of a step referenced in a config.
+type StepInfoProvider interface {
+	StepInfo(stepRef, defaultSource string) (*stepmanModels.StepModel, error)
+}
+
+// localStepLibProvider reads step definitions from the local steplib cache maintained by stepman,
+// it never touches the network so it is safe to be called on every keystroke.
+type localStepLibProvider struct {
+	mu          sync.Mutex
+	collections map[string]stepmanModels.StepCollectionModel
+}
+
+func NewLocalStepLibProvider() StepInfoProvider {
+	return &localStepLibProvider{collections: map[string]stepmanModels.StepCollectionModel{}}
+}
+
+func (p *localStepLibProvider) StepInfo(stepRef, defaultSource string) (*stepmanModels.StepModel, error) {
+	if defaultSource == "" {
+		defaultSource = defaultStepLibSource
+	}
+
+	id, err := stepid.CreateCanonicalIDFromString(stepRef, defaultSource)
+	if err != nil {
+		return nil, err
+	}
+
+	switch id.SteplibSource {
+	case "path":
+		step, err := stepman.ParseStepDefinition(filepath.Join(id.IDorURI, "step.yml"), false)
+		if err != nil {
+			return nil, err
+		}
+		return &step, nil
+	case "git", "_":
+		return nil, fmt.Errorf("step (%s) is not a steplib step", stepRef)
+	}
+
+	collection, err := p.collection(id.SteplibSource)
+	if err != nil {
+		return nil, err
+	}
+
+	step, stepFound, versionFound := collection.GetStep(id.IDorURI, id.Version)
+	if !stepFound {
+		return nil, fmt.Errorf("step (%s) not found in the local steplib cache", id.IDorURI)
+	}
+	if !versionFound {
+		return nil, fmt.Errorf("step (%s) version (%s) not found in the local steplib cache", id.IDorURI, id.Version)
+	}
+
+	return &step, nil
+}
+
+func (p *localStepLibProvider) collection(source string) (stepmanModels.StepCollectionModel, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if collection, ok := p.collections[source]; ok {
+		return collection, nil
+	}
+
+	collection, err := stepman.ReadStepSpec(source)
+	if err != nil {
+		return stepmanModels.StepCollectionModel{}, fmt.Errorf("read steplib (%s) from the local cache: %w", source, err)
+	}
+	p.collections[source] = collection
+
+	return collection, nil
+}
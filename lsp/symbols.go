@@ -0,0 +1,95 @@
+package lsp
+
+import (
+	"sort"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	workflowsKey   = "workflows"
+	stepBundlesKey = "step_bundles"
+	containersKey  = "containers"
+	servicesKey    = "services"
+	pipelinesKey   = "pipelines"
+	stagesKey      = "stages"
+)
+
+// symbols indexes the definitions of a document that can be referenced from other parts of the config.
+type symbols struct {
+	definitions map[string]map[string]outlineEntry
+	// pipelineWorkflows maps pipeline IDs to the workflows listed in the pipeline's graph.
+	pipelineWorkflows map[string]map[string]outlineEntry
+
+	// config is the document decoded without validation, nil if the document is not valid YAML.
+	config *models.BitriseDataModel
+}
+
+func newSymbols(doc *document) symbols {
+	s := symbols{
+		definitions:       map[string]map[string]outlineEntry{},
+		pipelineWorkflows: map[string]map[string]outlineEntry{},
+	}
+
+	for _, entry := range doc.outline() {
+		if len(entry.parents) == 1 {
+			section := entry.parents[0]
+			switch section {
+			case workflowsKey, stepBundlesKey, containersKey, servicesKey, pipelinesKey, stagesKey:
+				if s.definitions[section] == nil {
+					s.definitions[section] = map[string]outlineEntry{}
+				}
+				s.definitions[section][entry.key] = entry
+			}
+		} else if len(entry.parents) == 3 && entry.parents[0] == pipelinesKey && entry.parents[2] == workflowsKey {
+			pipelineID := entry.parents[1]
+			if s.pipelineWorkflows[pipelineID] == nil {
+				s.pipelineWorkflows[pipelineID] = map[string]outlineEntry{}
+			}
+			s.pipelineWorkflows[pipelineID][entry.key] = entry
+		}
+	}
+
+	var config models.BitriseDataModel
+	if err := yaml.Unmarshal([]byte(doc.text), &config); err == nil {
+		s.config = &config
+	}
+
+	return s
+}
+
+func (s symbols) lookup(section, id string) (outlineEntry, bool) {
+	entry, ok := s.definitions[section][id]
+	return entry, ok
+}
+
+func (s symbols) ids(section string) []string {
+	return sortedKeys(s.definitions[section])
+}
+
+// containerIDs returns the IDs of the containers with the given type.
+// Untyped containers are only used by the legacy with groups, so they are returned when no type is given.
+func (s symbols) containerIDs(containerType models.ContainerType) []string {
+	if s.config == nil {
+		return s.ids(containersKey)
+	}
+
+	var ids []string
+	for id, container := range s.config.Containers {
+		if container.Type == containerType {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,68 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// transport reads and writes base protocol messages: a header part with a mandatory Content-Length field,
+// followed by the JSON-RPC content.
+type transport struct {
+	reader *bufio.Reader
+
+	writeLock sync.Mutex
+	writer    io.Writer
+}
+
+func newTransport(r io.Reader, w io.Writer) *transport {
+	return &transport{
+		reader: bufio.NewReader(r),
+		writer: w,
+	}
+}
+
+func (t *transport) read() ([]byte, error) {
+	headers, err := textproto.NewReader(t.reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	lengthHeader := headers.Get("Content-Length")
+	if lengthHeader == "" {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(lengthHeader))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length header: %s", lengthHeader)
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(t.reader, content); err != nil {
+		return nil, fmt.Errorf("read message content: %w", err)
+	}
+
+	return content, nil
+}
+
+func (t *transport) write(message any) error {
+	content, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	t.writeLock.Lock()
+	defer t.writeLock.Unlock()
+
+	if _, err := fmt.Fprintf(t.writer, "Content-Length: %d\r\n\r\n", len(content)); err != nil {
+		return err
+	}
+	_, err = t.writer.Write(content)
+	return err
+}
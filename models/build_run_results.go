@@ -16,6 +16,32 @@ type BuildRunResultsModel struct {
 	FailedSteps          []StepRunResultsModel `json:"failed_steps" yaml:"failed_steps"`
 	FailedSkippableSteps []StepRunResultsModel `json:"failed_skippable_steps" yaml:"failed_skippable_steps"`
 	SkippedSteps         []StepRunResultsModel `json:"skipped_steps" yaml:"skipped_steps"`
+
+	ContainerResults []ContainerRunResultModel `json:"container_results,omitempty" yaml:"container_results,omitempty"`
+	Annotations      []AnnotationModel         `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// AnnotationModel is a message attached to the build by a plugin.
+type AnnotationModel struct {
+	Plugin  string `json:"plugin" yaml:"plugin"`
+	Level   string `json:"level" yaml:"level"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// ContainerRunResultModel is the state of an execution or service container when it was removed.
+type ContainerRunResultModel struct {
+	ID        string        `json:"id" yaml:"id"`
+	Type      ContainerType `json:"type" yaml:"type"`
+	Image     string        `json:"image" yaml:"image"`
+	Running   bool          `json:"running" yaml:"running"`
+	ExitCode  int           `json:"exit_code" yaml:"exit_code"`
+	OOMKilled bool          `json:"oom_killed" yaml:"oom_killed"`
+	LogPath   string        `json:"log_path,omitempty" yaml:"log_path,omitempty"`
+}
+
+// IsFailed returns true if the container exited with a non-zero exit code or was killed because it ran out of memory.
+func (result ContainerRunResultModel) IsFailed() bool {
+	return result.OOMKilled || (!result.Running && result.ExitCode != 0)
 }
 
 func NewBuildRunResultsModel(workflowID string, start time.Time, projectType string) BuildRunResultsModel {
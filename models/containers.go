@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"path/filepath"
+	"time"
 
 	envmanModels "github.com/bitrise-io/envman/v2/models"
 	stepmanModels "github.com/bitrise-io/stepman/models"
@@ -16,12 +18,135 @@ const (
 
 // Container model defines a Docker container configuration.
 type Container struct {
-	Type        ContainerType                       `json:"type,omitempty" yaml:"type,omitempty"`
-	Image       string                              `json:"image,omitempty" yaml:"image,omitempty"`
+	Type  ContainerType `json:"type,omitempty" yaml:"type,omitempty"`
+	Image string        `json:"image,omitempty" yaml:"image,omitempty"`
+	// Build builds the image of an execution container from a Dockerfile, as an alternative to Image.
+	Build       *ContainerBuild                     `json:"build,omitempty" yaml:"build,omitempty"`
 	Credentials DockerCredentials                   `json:"credentials,omitempty" yaml:"credentials,omitempty"`
 	Ports       []string                            `json:"ports,omitempty" yaml:"ports,omitempty"`
 	Envs        []envmanModels.EnvironmentItemModel `json:"envs,omitempty" yaml:"envs,omitempty"`
-	Options     string                              `json:"options,omitempty" yaml:"options,omitempty"`
+	// Volumes are mounted into the container, in the source:target[:mode] or target (anonymous volume) format.
+	Volumes []string `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	// Tmpfs mounts are in the target[:options] format.
+	Tmpfs []string `json:"tmpfs,omitempty" yaml:"tmpfs,omitempty"`
+	// CPUs limits the number of CPUs the container can use, for example 1.5.
+	CPUs float64 `json:"cpus,omitempty" yaml:"cpus,omitempty"`
+	// Memory limits the memory the container can use, for example 512m or 2g.
+	Memory string `json:"memory,omitempty" yaml:"memory,omitempty"`
+	// Platform is the os/arch[/variant] of the image, defaults to DefaultContainerPlatform.
+	Platform string `json:"platform,omitempty" yaml:"platform,omitempty"`
+	// Entrypoint overrides the entrypoint of the image, the container's command is passed to it as arguments.
+	Entrypoint string `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
+	// Workdir overrides the working directory inside the container.
+	Workdir string `json:"workdir,omitempty" yaml:"workdir,omitempty"`
+	// User overrides the user the container runs as, in the user[:group] format.
+	User string `json:"user,omitempty" yaml:"user,omitempty"`
+	// Readiness is checked after a service container has started, the steps using the service only run once it is ready.
+	Readiness *ReadinessProbe `json:"readiness,omitempty" yaml:"readiness,omitempty"`
+	// Options are passed to the container create command as is, prefer the typed fields above.
+	Options string `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// ContainerBuild describes how to build a container image from a Dockerfile.
+// The paths are relative to the working directory of the build, usually the root of the repository.
+type ContainerBuild struct {
+	// Context is the build context directory, defaults to the working directory.
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
+	// Dockerfile is the path of the Dockerfile within the context, defaults to Dockerfile.
+	Dockerfile string `json:"dockerfile,omitempty" yaml:"dockerfile,omitempty"`
+	// Args are passed as build arguments, values can reference env vars ($VAR).
+	Args map[string]string `json:"args,omitempty" yaml:"args,omitempty"`
+	// Target is the stage of a multi-stage Dockerfile to build.
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+// Defaults of the container build settings.
+const (
+	DefaultContainerBuildContext    = "."
+	DefaultContainerBuildDockerfile = "Dockerfile"
+)
+
+// GetContext returns the build context directory or its default.
+func (build ContainerBuild) GetContext() string {
+	if build.Context != "" {
+		return build.Context
+	}
+	return DefaultContainerBuildContext
+}
+
+// GetDockerfile returns the path of the Dockerfile relative to the working directory.
+func (build ContainerBuild) GetDockerfile() string {
+	dockerfile := build.Dockerfile
+	if dockerfile == "" {
+		dockerfile = DefaultContainerBuildDockerfile
+	}
+	return filepath.Join(build.GetContext(), dockerfile)
+}
+
+// ReadinessProbe checks whether a service container is ready to accept requests, exactly one of TCP, HTTP and Command should be set.
+// TCP and HTTP probes are run from the host running the CLI, so the probed port should be published with ports.
+type ReadinessProbe struct {
+	// TCP is a host:port address, the probe succeeds once a connection can be opened.
+	TCP string `json:"tcp,omitempty" yaml:"tcp,omitempty"`
+	// HTTP is a URL, the probe succeeds once a GET request returns a 2xx status code.
+	HTTP string `json:"http,omitempty" yaml:"http,omitempty"`
+	// Command is run with sh -c inside the container, the probe succeeds once it exits with 0.
+	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+	// Interval is the number of seconds to wait between the attempts.
+	Interval int `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// Timeout is the number of seconds an attempt can take.
+	Timeout int `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Retries is the number of failed attempts after the service is considered not ready.
+	Retries int `json:"retries,omitempty" yaml:"retries,omitempty"`
+}
+
+// Defaults of the readiness probe settings.
+const (
+	DefaultReadinessInterval = 2
+	DefaultReadinessTimeout  = 5
+	DefaultReadinessRetries  = 15
+)
+
+// GetInterval returns the interval between the attempts or its default.
+func (probe ReadinessProbe) GetInterval() time.Duration {
+	return secondsOrDefault(probe.Interval, DefaultReadinessInterval)
+}
+
+// GetTimeout returns the timeout of an attempt or its default.
+func (probe ReadinessProbe) GetTimeout() time.Duration {
+	return secondsOrDefault(probe.Timeout, DefaultReadinessTimeout)
+}
+
+// GetRetries returns the number of attempts or its default.
+func (probe ReadinessProbe) GetRetries() int {
+	if probe.Retries > 0 {
+		return probe.Retries
+	}
+	return DefaultReadinessRetries
+}
+
+func secondsOrDefault(seconds, defaultSeconds int) time.Duration {
+	if seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return time.Duration(defaultSeconds) * time.Second
+}
+
+// DefaultContainerPlatform is the platform of the containers without an explicit platform.
+const DefaultContainerPlatform = "linux/amd64"
+
+// Valid container memory and platform values should match these patterns.
+const (
+	ContainerMemoryPattern   = `^[0-9]+(\.[0-9]+)?[bkmgBKMG]?$`
+	ContainerPlatformPattern = `^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`
+)
+
+// GetPlatform returns the platform of the container or DefaultContainerPlatform if it is not set.
+func (container Container) GetPlatform() string {
+	if container.Platform != "" {
+		return container.Platform
+	}
+	return DefaultContainerPlatform
 }
 
 type DockerCredentials struct {
@@ -33,6 +33,7 @@ type StepBundleModel struct {
 	Environments       []envmanModels.EnvironmentItemModel `json:"envs,omitempty" yaml:"envs,omitempty"`
 	ExecutionContainer stepmanModels.ContainerReference    `json:"execution_container,omitempty" yaml:"execution_container,omitempty"`
 	ServiceContainers  []stepmanModels.ContainerReference  `json:"service_containers,omitempty" yaml:"service_containers,omitempty"`
+	Tools              ToolsModel                          `json:"tools,omitempty" yaml:"tools,omitempty"`
 	Steps              []StepListItemStepOrBundleModel     `json:"steps,omitempty" yaml:"steps,omitempty"`
 }
 
@@ -48,6 +49,7 @@ type StepBundleListItemModel struct {
 	Environments       []envmanModels.EnvironmentItemModel `json:"envs,omitempty" yaml:"envs,omitempty"`
 	ExecutionContainer stepmanModels.ContainerReference    `json:"execution_container,omitempty" yaml:"execution_container,omitempty"`
 	ServiceContainers  []stepmanModels.ContainerReference  `json:"service_containers,omitempty" yaml:"service_containers,omitempty"`
+	Tools              ToolsModel                          `json:"tools,omitempty" yaml:"tools,omitempty"`
 }
 
 type StepListStepBundleItemModel map[string]StepBundleListItemModel
@@ -63,6 +65,13 @@ type StepListWithItemModel map[string]WithModel
 // StepListStepItemModel is a map representing a step list item of a With group, the value is a Step.
 type StepListStepItemModel map[string]stepmanModels.StepModel
 
+// StepWithToolsModel is a Step declaring the tools it needs. The tools are installed before the Step
+// and activated only in the Step's environment. A Step without tools is stored as a plain Step in its step list item.
+type StepWithToolsModel struct {
+	stepmanModels.StepModel `yaml:",inline"`
+	Tools                   ToolsModel `json:"tools,omitempty" yaml:"tools,omitempty"`
+}
+
 // StepListItemModel is a map representing a step list item of a workflow, the value is either a Step, a With Group or Step Bundle.
 type StepListItemModel map[string]interface{}
 
@@ -195,6 +204,8 @@ type BitriseDataModel struct {
 	StepBundles map[string]StepBundleModel `json:"step_bundles,omitempty" yaml:"step_bundles,omitempty"`
 	Tools       ToolsModel                 `json:"tools,omitempty" yaml:"tools,omitempty"`
 	ToolConfig  *ToolConfigModel           `json:"tool_config,omitempty" yaml:"tool_config,omitempty"`
+	// Plugins required by the builds by plugin name
+	Plugins map[string]PluginModel `json:"plugins,omitempty" yaml:"plugins,omitempty"`
 	// Docker container definitions
 	Services map[string]Container `json:"services,omitempty" yaml:"services,omitempty"`
 	// The new containerization syntax uses the "containers" property to define both execution and service containers.
@@ -208,6 +219,61 @@ type BuildRunStartModel struct {
 	StartTime   time.Time `json:"start_time" yaml:"start_time"`
 }
 
+// WorkflowRunStartModel is the payload of the WorkflowWillStart plugin event.
+type WorkflowRunStartModel struct {
+	EventName     string    `json:"event_name" yaml:"event_name"`
+	ExecutionID   string    `json:"execution_id" yaml:"execution_id"`
+	WorkflowID    string    `json:"workflow_id" yaml:"workflow_id"`
+	WorkflowTitle string    `json:"workflow_title" yaml:"workflow_title"`
+	StartTime     time.Time `json:"start_time" yaml:"start_time"`
+}
+
+// WorkflowRunFinishModel is the payload of the WorkflowDidFinish plugin event.
+type WorkflowRunFinishModel struct {
+	EventName     string        `json:"event_name" yaml:"event_name"`
+	ExecutionID   string        `json:"execution_id" yaml:"execution_id"`
+	WorkflowID    string        `json:"workflow_id" yaml:"workflow_id"`
+	WorkflowTitle string        `json:"workflow_title" yaml:"workflow_title"`
+	StartTime     time.Time     `json:"start_time" yaml:"start_time"`
+	RunTime       time.Duration `json:"run_time" yaml:"run_time"`
+	IsFailed      bool          `json:"is_failed" yaml:"is_failed"`
+}
+
+// StepRunStartModel is the payload of the StepWillStart plugin event.
+type StepRunStartModel struct {
+	EventName   string    `json:"event_name" yaml:"event_name"`
+	ExecutionID string    `json:"execution_id" yaml:"execution_id"`
+	WorkflowID  string    `json:"workflow_id" yaml:"workflow_id"`
+	StepID      string    `json:"step_id" yaml:"step_id"`
+	Idx         int       `json:"idx" yaml:"idx"`
+	StartTime   time.Time `json:"start_time" yaml:"start_time"`
+}
+
+// StepRunFinishModel is the payload of the StepDidFinish plugin event.
+type StepRunFinishModel struct {
+	EventName   string              `json:"event_name" yaml:"event_name"`
+	ExecutionID string              `json:"execution_id" yaml:"execution_id"`
+	WorkflowID  string              `json:"workflow_id" yaml:"workflow_id"`
+	StepID      string              `json:"step_id" yaml:"step_id"`
+	Result      StepRunResultsModel `json:"result" yaml:"result"`
+}
+
+// ToolSetupFinishModel is the payload of the ToolSetupDidFinish plugin event.
+type ToolSetupFinishModel struct {
+	EventName  string        `json:"event_name" yaml:"event_name"`
+	WorkflowID string        `json:"workflow_id" yaml:"workflow_id"`
+	RunTime    time.Duration `json:"run_time" yaml:"run_time"`
+	ErrorStr   string        `json:"error_str,omitempty" yaml:"error_str,omitempty"`
+}
+
+// BuildAbortModel is the payload of the BuildWillAbort plugin event.
+type BuildAbortModel struct {
+	EventName  string    `json:"event_name" yaml:"event_name"`
+	WorkflowID string    `json:"workflow_id" yaml:"workflow_id"`
+	Signal     string    `json:"signal" yaml:"signal"`
+	AbortTime  time.Time `json:"abort_time" yaml:"abort_time"`
+}
+
 type StepError struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
@@ -221,6 +287,8 @@ func (s StepRunResultsModel) StatusReasonAndErrors() (string, []StepError) {
 		return s.statusReason(), nil
 	case StepRunStatusCodeSkippedWithRunIf:
 		return s.statusReason(), nil
+	case StepRunStatusCodeSkippedByPlugin:
+		return s.statusReason(), nil
 	case StepRunStatusCodeFailedSkippable:
 		return s.statusReason(), s.error()
 	case StepRunStatusCodeFailed:
@@ -231,6 +299,8 @@ func (s StepRunResultsModel) StatusReasonAndErrors() (string, []StepError) {
 		return "", s.error()
 	case StepRunStatusAbortedWithNoOutputTimeout:
 		return "", s.error()
+	case StepRunStatusCodeServiceNotReady:
+		return "", s.error()
 	default:
 		return "", nil
 	}
@@ -242,7 +312,8 @@ func (s StepRunResultsModel) statusReason() string {
 		StepRunStatusCodeFailed,
 		StepRunStatusCodePreparationFailed,
 		StepRunStatusAbortedWithCustomTimeout,
-		StepRunStatusAbortedWithNoOutputTimeout:
+		StepRunStatusAbortedWithNoOutputTimeout,
+		StepRunStatusCodeServiceNotReady:
 		return ""
 	case StepRunStatusCodeFailedSkippable:
 		return `This Step failed, but it was marked as "is_skippable", so the build continued.`
@@ -251,6 +322,8 @@ func (s StepRunResultsModel) statusReason() string {
 	case StepRunStatusCodeSkippedWithRunIf:
 		return fmt.Sprintf(`This Step was skipped, because its "run_if" expression evaluated to false.
 The "run_if" expression was: %s`, *s.StepInfo.Step.RunIf)
+	case StepRunStatusCodeSkippedByPlugin:
+		return fmt.Sprintf("This Step was skipped by a plugin: %s", s.ErrorStr)
 	}
 
 	return ""
@@ -262,11 +335,13 @@ func (s StepRunResultsModel) error() []StepError {
 	switch s.Status {
 	case StepRunStatusCodeSuccess,
 		StepRunStatusCodeSkipped,
-		StepRunStatusCodeSkippedWithRunIf:
+		StepRunStatusCodeSkippedWithRunIf,
+		StepRunStatusCodeSkippedByPlugin:
 		return nil
 	case StepRunStatusCodeFailedSkippable,
 		StepRunStatusCodeFailed,
-		StepRunStatusCodePreparationFailed:
+		StepRunStatusCodePreparationFailed,
+		StepRunStatusCodeServiceNotReady:
 		message = s.ErrorStr
 	case StepRunStatusAbortedWithCustomTimeout:
 		message = fmt.Sprintf("This Step timed out after %s.", formatStatusReasonTimeInterval(s.Timeout))
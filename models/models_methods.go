@@ -4,6 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"sort"
@@ -156,7 +160,7 @@ func (bundle *StepBundleModel) Normalize() error {
 				return err
 			}
 
-			stepListItem[key] = *step
+			stepListItem[key] = updateStepListItemStep(stepListItem[key], *step)
 			bundle.Steps[idx] = stepListItem
 		} else if t == StepListItemTypeBundle {
 			b, err := stepListItem.GetBundle()
@@ -292,7 +296,7 @@ func (workflow *WorkflowModel) Normalize() error {
 				return err
 			}
 
-			stepListItem[key] = *step
+			stepListItem[key] = updateStepListItemStep(stepListItem[key], *step)
 			workflow.Steps[idx] = stepListItem
 		} else if t == StepListItemTypeBundle {
 			bundle, err := stepListItem.GetBundle()
@@ -497,6 +501,140 @@ func (container *Container) Validate() error {
 			return err
 		}
 	}
+
+	for _, volume := range container.Volumes {
+		if err := validateContainerVolume(volume); err != nil {
+			return err
+		}
+	}
+
+	for _, tmpfs := range container.Tmpfs {
+		target, _, _ := strings.Cut(tmpfs, ":")
+		if !path.IsAbs(target) {
+			return fmt.Errorf("tmpfs (%s) target should be an absolute path", tmpfs)
+		}
+	}
+
+	if container.CPUs < 0 {
+		return fmt.Errorf("cpus (%g) should be a positive number", container.CPUs)
+	}
+
+	if container.Memory != "" && !regexp.MustCompile(ContainerMemoryPattern).MatchString(container.Memory) {
+		return fmt.Errorf("memory (%s) is invalid, should be a number with an optional b, k, m or g unit, for example 512m", container.Memory)
+	}
+
+	if container.Platform != "" && !regexp.MustCompile(ContainerPlatformPattern).MatchString(container.Platform) {
+		return fmt.Errorf("platform (%s) is invalid, should be in the os/arch[/variant] format, for example %s", container.Platform, DefaultContainerPlatform)
+	}
+
+	if container.Entrypoint != "" && strings.TrimSpace(container.Entrypoint) == "" {
+		return fmt.Errorf("entrypoint should not be blank")
+	}
+
+	if container.Workdir != "" && !path.IsAbs(container.Workdir) {
+		return fmt.Errorf("workdir (%s) should be an absolute path", container.Workdir)
+	}
+
+	if strings.ContainsAny(container.User, " \t") {
+		return fmt.Errorf("user (%s) should not contain whitespace", container.User)
+	}
+
+	if container.Build != nil {
+		if container.Type == ContainerTypeService {
+			return fmt.Errorf("build is only supported for execution containers")
+		}
+		if err := container.Build.Validate(); err != nil {
+			return fmt.Errorf("build: %w", err)
+		}
+	}
+
+	if container.Readiness != nil {
+		if container.Type == ContainerTypeExecution {
+			return fmt.Errorf("readiness is only supported for service containers")
+		}
+		if err := container.Readiness.Validate(); err != nil {
+			return fmt.Errorf("readiness: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (build *ContainerBuild) Validate() error {
+	for _, pth := range []string{build.Context, build.Dockerfile} {
+		if filepath.IsAbs(pth) {
+			return fmt.Errorf("path (%s) should be relative to the repository", pth)
+		}
+	}
+
+	for key := range build.Args {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("args should not have an empty key")
+		}
+	}
+
+	return nil
+}
+
+func (probe *ReadinessProbe) Validate() error {
+	probes := 0
+	for _, value := range []string{probe.TCP, probe.HTTP, probe.Command} {
+		if value != "" {
+			probes++
+		}
+	}
+	if probes != 1 {
+		return fmt.Errorf("exactly one of tcp, http and command should be defined")
+	}
+
+	if probe.TCP != "" {
+		if _, _, err := net.SplitHostPort(probe.TCP); err != nil {
+			return fmt.Errorf("tcp (%s) should be a host:port address: %w", probe.TCP, err)
+		}
+	}
+
+	if probe.HTTP != "" {
+		u, err := url.Parse(probe.HTTP)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("http (%s) should be an http or https URL", probe.HTTP)
+		}
+	}
+
+	if probe.Interval < 0 || probe.Timeout < 0 || probe.Retries < 0 {
+		return fmt.Errorf("interval, timeout and retries should not be negative")
+	}
+
+	return nil
+}
+
+var containerVolumeModes = []string{"ro", "rw", "z", "Z"}
+
+// validateContainerVolume validates a volume in the source:target[:mode] or target format.
+func validateContainerVolume(volume string) error {
+	parts := strings.Split(volume, ":")
+	if len(parts) > 3 {
+		return fmt.Errorf("volume (%s) is invalid, should be in the source:target[:mode] format", volume)
+	}
+
+	target := parts[0]
+	if len(parts) > 1 {
+		if parts[0] == "" {
+			return fmt.Errorf("volume (%s) has no source defined", volume)
+		}
+		target = parts[1]
+	}
+	if !path.IsAbs(target) {
+		return fmt.Errorf("volume (%s) target should be an absolute path", volume)
+	}
+
+	if len(parts) == 3 {
+		for _, mode := range strings.Split(parts[2], ",") {
+			if !slices.Contains(containerVolumeModes, mode) {
+				return fmt.Errorf("volume (%s) has invalid mode (%s), should be one of: %s", volume, mode, strings.Join(containerVolumeModes, ", "))
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -553,27 +691,37 @@ func (workflow *WorkflowModel) Validate() error {
 	return validateStatusReportName(workflow.StatusReportName)
 }
 
-const statusReportNameRegex = `^[a-zA-Z0-9,./():\-_ <>[\]|]*$`
+// Valid status_report_name values should match StatusReportNamePattern and be at most MaxStatusReportNameLength long.
+const (
+	StatusReportNamePattern   = `^[a-zA-Z0-9,./():\-_ <>[\]|]*$`
+	MaxStatusReportNameLength = 100
+)
 
 func validateStatusReportName(statusReportName string) error {
-	if len(statusReportName) > 100 {
-		return fmt.Errorf("status_report_name (%s) is too long, max length is 100 characters", statusReportName)
+	if len(statusReportName) > MaxStatusReportNameLength {
+		return fmt.Errorf("status_report_name (%s) is too long, max length is %d characters", statusReportName, MaxStatusReportNameLength)
 	}
 
-	re := regexp.MustCompile(statusReportNameRegex)
+	re := regexp.MustCompile(StatusReportNamePattern)
 	if !re.MatchString(statusReportName) {
-		return fmt.Errorf("status_report_name (%s) contains invalid characters, should match the '%s' regex", statusReportName, statusReportNameRegex)
+		return fmt.Errorf("status_report_name (%s) contains invalid characters, should match the '%s' regex", statusReportName, StatusReportNamePattern)
 	}
 	return nil
 }
 
+// The range of valid pipeline, workflow and trigger item priorities.
+const (
+	MinPriority = -100
+	MaxPriority = 100
+)
+
 func validatePriority(priority *int) error {
 	if priority == nil {
 		return nil
 	}
 
-	if *priority > 100 || *priority < -100 {
-		return fmt.Errorf("priority (%d) should be between -100 and 100", *priority)
+	if *priority > MaxPriority || *priority < MinPriority {
+		return fmt.Errorf("priority (%d) should be between %d and %d", *priority, MinPriority, MaxPriority)
 	}
 	return nil
 }
@@ -692,6 +840,12 @@ func (config *BitriseDataModel) internalValidation(full bool) ([]string, error)
 	}
 	// ---
 
+	// plugins
+	if err := validatePlugins(config.Plugins); err != nil {
+		return warnings, err
+	}
+	// ---
+
 	return warnings, nil
 }
 
@@ -797,9 +951,13 @@ func validateContainers(config BitriseDataModel) error {
 		if containerID == "" {
 			return fmt.Errorf("container (image: %s) has empty ID defined", containerDef.Image)
 		}
-		if strings.TrimSpace(containerDef.Image) == "" {
+		hasImage := strings.TrimSpace(containerDef.Image) != ""
+		if !hasImage && containerDef.Build == nil {
 			return fmt.Errorf("container (%s) has no image defined", containerID)
 		}
+		if hasImage && containerDef.Build != nil {
+			return fmt.Errorf("container (%s) has both image and build defined", containerID)
+		}
 		if requiresType && containerDef.Type == "" {
 			return fmt.Errorf("container (%s) has no type defined (must be %q or %q)", containerID, ContainerTypeExecution, ContainerTypeService)
 		}
@@ -815,6 +973,9 @@ func validateContainers(config BitriseDataModel) error {
 		if strings.TrimSpace(serviceDef.Image) == "" {
 			return fmt.Errorf("service (%s) has no image defined", serviceID)
 		}
+		if serviceDef.Build != nil {
+			return fmt.Errorf("service (%s) has build defined, building images is only supported for execution containers", serviceID)
+		}
 		if err := serviceDef.Validate(); err != nil {
 			return fmt.Errorf("container (%s) has config issue: %w", serviceID, err)
 		}
@@ -1199,7 +1360,7 @@ func validateWorkflows(config *BitriseDataModel) ([]string, error) {
 				}
 
 				// TODO: Why is this assignment needed?
-				stepListItem[stepID] = *step
+				stepListItem[stepID] = updateStepListItemStep(stepListItem[stepID], *step)
 			} else if t == StepListItemTypeWith {
 				with, err := stepListItem.GetWith()
 				if err != nil {
@@ -1295,7 +1456,9 @@ func (config *BitriseDataModel) FillMissingDefaults() error {
 // ----------------------------
 // --- RemoveRedundantFields
 
-func removeEnvironmentRedundantFields(env *envmanModels.EnvironmentItemModel) error {
+// RemoveEnvironmentRedundantFields removes the options of the env which are set to their default value,
+// and the options map itself if no option is left.
+func RemoveEnvironmentRedundantFields(env *envmanModels.EnvironmentItemModel) error {
 	options, err := env.GetOptions()
 	if err != nil {
 		return err
@@ -1397,7 +1560,7 @@ func (workflow *WorkflowModel) removeRedundantFields() error {
 	// example: isExpand = true by default for normal envs,
 	// but script step content input env isExpand = false by default
 	for _, env := range workflow.Environments {
-		if err := removeEnvironmentRedundantFields(&env); err != nil {
+		if err := RemoveEnvironmentRedundantFields(&env); err != nil {
 			return err
 		}
 	}
@@ -1406,7 +1569,7 @@ func (workflow *WorkflowModel) removeRedundantFields() error {
 
 func (app *AppModel) removeRedundantFields() error {
 	for _, env := range app.Environments {
-		if err := removeEnvironmentRedundantFields(&env); err != nil {
+		if err := RemoveEnvironmentRedundantFields(&env); err != nil {
 			return err
 		}
 	}
@@ -1680,14 +1843,14 @@ func (stepListItem *StepListItemStepOrBundleModel) UnmarshalJSON(b []byte) error
 			(*stepListItem)[k] = v
 		}
 	} else {
-		var stepItem StepListStepItemModel
+		var stepItem map[string]StepWithToolsModel
 		if err := json.Unmarshal(b, &stepItem); err != nil {
 			return err
 		}
 
 		*stepListItem = map[string]interface{}{}
 		for k, v := range stepItem {
-			(*stepListItem)[k] = v
+			(*stepListItem)[k] = v.stepListItemValue()
 		}
 	}
 
@@ -1717,14 +1880,14 @@ func (stepListItem *StepListItemStepOrBundleModel) UnmarshalYAML(unmarshal func(
 			(*stepListItem)[k] = v
 		}
 	} else {
-		var stepItem StepListStepItemModel
+		var stepItem map[string]StepWithToolsModel
 		if err := unmarshal(&stepItem); err != nil {
 			return err
 		}
 
 		*stepListItem = map[string]interface{}{}
 		for k, v := range stepItem {
-			(*stepListItem)[k] = v
+			(*stepListItem)[k] = v.stepListItemValue()
 		}
 	}
 
@@ -1780,9 +1943,11 @@ func (stepListItem *StepListItemStepOrBundleModel) GetStep() (*stepmanModels.Ste
 	}
 
 	for _, value := range *stepListItem {
-		s, ok := value.(stepmanModels.StepModel)
-		if ok {
+		switch s := value.(type) {
+		case stepmanModels.StepModel:
 			return &s, nil
+		case StepWithToolsModel:
+			return &s.StepModel, nil
 		}
 		break
 	}
@@ -1823,14 +1988,14 @@ func (stepListItem *StepListItemModel) UnmarshalJSON(b []byte) error {
 			(*stepListItem)[k] = v
 		}
 	} else {
-		var stepItem StepListStepItemModel
+		var stepItem map[string]StepWithToolsModel
 		if err := json.Unmarshal(b, &stepItem); err != nil {
 			return err
 		}
 
 		*stepListItem = map[string]interface{}{}
 		for k, v := range stepItem {
-			(*stepListItem)[k] = v
+			(*stepListItem)[k] = v.stepListItemValue()
 		}
 	}
 
@@ -1870,14 +2035,14 @@ func (stepListItem *StepListItemModel) UnmarshalYAML(unmarshal func(interface{})
 			(*stepListItem)[k] = v
 		}
 	} else {
-		var stepItem StepListStepItemModel
+		var stepItem map[string]StepWithToolsModel
 		if err := unmarshal(&stepItem); err != nil {
 			return err
 		}
 
 		*stepListItem = map[string]interface{}{}
 		for k, v := range stepItem {
-			(*stepListItem)[k] = v
+			(*stepListItem)[k] = v.stepListItemValue()
 		}
 	}
 
@@ -1985,9 +2150,11 @@ func (stepListItem *StepListItemModel) GetStep() (*stepmanModels.StepModel, erro
 	}
 
 	for _, value := range *stepListItem {
-		s, ok := value.(stepmanModels.StepModel)
-		if ok {
+		switch s := value.(type) {
+		case stepmanModels.StepModel:
 			return &s, nil
+		case StepWithToolsModel:
+			return &s.StepModel, nil
 		}
 		break
 	}
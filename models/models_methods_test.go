@@ -483,7 +483,7 @@ func TestValidateConfig(t *testing.T) {
 
 		bitriseData.App.StatusReportName += "*"
 		_, err = bitriseData.Validate()
-		require.EqualError(t, err, "status_report_name ("+bitriseData.App.StatusReportName+") contains invalid characters, should match the '"+statusReportNameRegex+"' regex")
+		require.EqualError(t, err, "status_report_name ("+bitriseData.App.StatusReportName+") contains invalid characters, should match the '"+StatusReportNamePattern+"' regex")
 	}
 
 	t.Log("Invalid bitriseData - pipeline ID empty")
@@ -755,7 +755,7 @@ func TestValidateConfig(t *testing.T) {
 		pipeline.StatusReportName += "*"
 		bitriseData.Pipelines["pipeline1"] = pipeline
 		_, err = bitriseData.Validate()
-		require.EqualError(t, err, "pipeline (pipeline1) has invalid status_report_name: status_report_name ("+pipeline.StatusReportName+") contains invalid characters, should match the '"+statusReportNameRegex+"' regex")
+		require.EqualError(t, err, "pipeline (pipeline1) has invalid status_report_name: status_report_name ("+pipeline.StatusReportName+") contains invalid characters, should match the '"+StatusReportNamePattern+"' regex")
 	}
 }
 
@@ -1023,6 +1023,37 @@ services:
     image: " "`),
 			wantErr: "service (postgres) has no image defined",
 		},
+		{
+			name: "Valid bitrise.yml: container built from a Dockerfile",
+			config: createConfig(t, `
+format_version: '11'
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+containers:
+  builder:
+    type: execution
+    build:
+      context: ci
+      args:
+        GO_VERSION: "1.25"
+workflows:
+  test:
+    steps:
+    - script:
+        execution_container: builder`),
+		},
+		{
+			name: "Invalid bitrise.yml: container with both image and build",
+			config: createConfig(t, `
+format_version: '11'
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+containers:
+  builder:
+    type: execution
+    image: golang:1.25
+    build:
+      context: ci`),
+			wantErr: "container (builder) has both image and build defined",
+		},
 		{
 			name: "Invalid bitrise.yml: missing container id",
 			config: createConfig(t, `
@@ -2384,7 +2415,7 @@ func TestRemoveEnvironmentRedundantFields(t *testing.T) {
 				Meta: map[string]interface{}{},
 			},
 		}
-		require.NoError(t, removeEnvironmentRedundantFields(&env))
+		require.NoError(t, RemoveEnvironmentRedundantFields(&env))
 
 		options, err := env.GetOptions()
 		require.NoError(t, err)
@@ -2424,7 +2455,7 @@ func TestRemoveEnvironmentRedundantFields(t *testing.T) {
 				Meta: map[string]interface{}{"is_expose": true},
 			},
 		}
-		require.NoError(t, removeEnvironmentRedundantFields(&env))
+		require.NoError(t, RemoveEnvironmentRedundantFields(&env))
 
 		options, err := env.GetOptions()
 		require.NoError(t, err)
@@ -2449,7 +2480,7 @@ func TestRemoveEnvironmentRedundantFields(t *testing.T) {
 		env := envmanModels.EnvironmentItemModel{
 			"TEST_KEY": "test_value",
 		}
-		require.NoError(t, removeEnvironmentRedundantFields(&env))
+		require.NoError(t, RemoveEnvironmentRedundantFields(&env))
 
 		_, ok := env[envmanModels.OptionsKey]
 		require.Equal(t, false, ok)
@@ -2465,7 +2496,7 @@ func TestRemoveEnvironmentRedundantFields(t *testing.T) {
 				},
 			},
 		}
-		require.NoError(t, removeEnvironmentRedundantFields(&env))
+		require.NoError(t, RemoveEnvironmentRedundantFields(&env))
 
 		options, err := env.GetOptions()
 		require.NoError(t, err)
@@ -2610,3 +2641,139 @@ func createConfig(t *testing.T, yamlContent string) BitriseDataModel {
 	require.NoError(t, yaml.Unmarshal([]byte(yamlContent), &config))
 	return config
 }
+
+func TestContainer_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		container Container
+		wantErr   string
+	}{
+		{
+			name: "valid typed fields",
+			container: Container{
+				Image:      "ubuntu:24.04",
+				Volumes:    []string{"/cache", "cache:/root/.cache", "/tmp/data:/data:ro", "./src:/src:rw,z"},
+				Tmpfs:      []string{"/run", "/tmp:size=64m"},
+				CPUs:       1.5,
+				Memory:     "2g",
+				Platform:   "linux/arm64/v8",
+				Entrypoint: "/bin/sh",
+				Workdir:    "/bitrise/src",
+				User:       "1000:1000",
+			},
+		},
+		{
+			name:      "relative volume target",
+			container: Container{Volumes: []string{"cache:root/.cache"}},
+			wantErr:   "volume (cache:root/.cache) target should be an absolute path",
+		},
+		{
+			name:      "volume without source",
+			container: Container{Volumes: []string{":/data"}},
+			wantErr:   "volume (:/data) has no source defined",
+		},
+		{
+			name:      "invalid volume mode",
+			container: Container{Volumes: []string{"cache:/data:rx"}},
+			wantErr:   "volume (cache:/data:rx) has invalid mode (rx), should be one of: ro, rw, z, Z",
+		},
+		{
+			name:      "too many volume parts",
+			container: Container{Volumes: []string{"a:/b:ro:rw"}},
+			wantErr:   "volume (a:/b:ro:rw) is invalid, should be in the source:target[:mode] format",
+		},
+		{
+			name:      "relative tmpfs target",
+			container: Container{Tmpfs: []string{"tmp:size=64m"}},
+			wantErr:   "tmpfs (tmp:size=64m) target should be an absolute path",
+		},
+		{
+			name:      "negative cpus",
+			container: Container{CPUs: -1},
+			wantErr:   "cpus (-1) should be a positive number",
+		},
+		{
+			name:      "invalid memory",
+			container: Container{Memory: "2 GB"},
+			wantErr:   "memory (2 GB) is invalid, should be a number with an optional b, k, m or g unit, for example 512m",
+		},
+		{
+			name:      "invalid platform",
+			container: Container{Platform: "amd64"},
+			wantErr:   "platform (amd64) is invalid, should be in the os/arch[/variant] format, for example linux/amd64",
+		},
+		{
+			name:      "relative workdir",
+			container: Container{Workdir: "src"},
+			wantErr:   "workdir (src) should be an absolute path",
+		},
+		{
+			name:      "valid build",
+			container: Container{Type: ContainerTypeExecution, Build: &ContainerBuild{Context: "ci", Dockerfile: "builder.Dockerfile", Args: map[string]string{"GO_VERSION": "1.25"}, Target: "builder"}},
+		},
+		{
+			name:      "build on service container",
+			container: Container{Type: ContainerTypeService, Build: &ContainerBuild{}},
+			wantErr:   "build is only supported for execution containers",
+		},
+		{
+			name:      "build with absolute context",
+			container: Container{Build: &ContainerBuild{Context: "/tmp/ci"}},
+			wantErr:   "build: path (/tmp/ci) should be relative to the repository",
+		},
+		{
+			name:      "build with empty arg key",
+			container: Container{Build: &ContainerBuild{Args: map[string]string{" ": "value"}}},
+			wantErr:   "build: args should not have an empty key",
+		},
+		{
+			name:      "valid readiness probe",
+			container: Container{Type: ContainerTypeService, Readiness: &ReadinessProbe{TCP: "localhost:5432", Interval: 1, Timeout: 2, Retries: 10}},
+		},
+		{
+			name:      "readiness on execution container",
+			container: Container{Type: ContainerTypeExecution, Readiness: &ReadinessProbe{Command: "true"}},
+			wantErr:   "readiness is only supported for service containers",
+		},
+		{
+			name:      "readiness without probe",
+			container: Container{Readiness: &ReadinessProbe{Retries: 3}},
+			wantErr:   "readiness: exactly one of tcp, http and command should be defined",
+		},
+		{
+			name:      "readiness with multiple probes",
+			container: Container{Readiness: &ReadinessProbe{TCP: "localhost:6379", Command: "redis-cli ping"}},
+			wantErr:   "readiness: exactly one of tcp, http and command should be defined",
+		},
+		{
+			name:      "readiness tcp without port",
+			container: Container{Readiness: &ReadinessProbe{TCP: "localhost"}},
+			wantErr:   "readiness: tcp (localhost) should be a host:port address: address localhost: missing port in address",
+		},
+		{
+			name:      "readiness http without scheme",
+			container: Container{Readiness: &ReadinessProbe{HTTP: "localhost:8080/health"}},
+			wantErr:   "readiness: http (localhost:8080/health) should be an http or https URL",
+		},
+		{
+			name:      "readiness negative retries",
+			container: Container{Readiness: &ReadinessProbe{Command: "true", Retries: -1}},
+			wantErr:   "readiness: interval, timeout and retries should not be negative",
+		},
+		{
+			name:      "user with whitespace",
+			container: Container{User: "build user"},
+			wantErr:   "user (build user) should not contain whitespace",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.container.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+
+	ver "github.com/hashicorp/go-version"
+)
+
+// PluginModel is a plugin the builds of the config rely on, installed by `bitrise run` if missing.
+type PluginModel struct {
+	// Source is the git URL or local path of the plugin.
+	Source string `json:"source" yaml:"source"`
+	// Version is a version constraint, e.g. `1.2.0`, `~> 1.2` or `>= 1.0, < 2.0`. The latest version is used if empty.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// Events the plugin has to subscribe to.
+	Events []string `json:"events,omitempty" yaml:"events,omitempty"`
+}
+
+// VersionConstraints returns the parsed version constraint, nil if no version is defined.
+func (plugin PluginModel) VersionConstraints() (ver.Constraints, error) {
+	if plugin.Version == "" {
+		return nil, nil
+	}
+	return ver.NewConstraint(plugin.Version)
+}
+
+func validatePlugins(plugins map[string]PluginModel) error {
+	for name, plugin := range plugins {
+		if name == "" {
+			return fmt.Errorf("plugin has empty name")
+		}
+		if plugin.Source == "" {
+			return fmt.Errorf("plugin (%s) has no source", name)
+		}
+		if _, err := plugin.VersionConstraints(); err != nil {
+			return fmt.Errorf("plugin (%s) has invalid version (%s): %w", name, plugin.Version, err)
+		}
+		for _, event := range plugin.Events {
+			if event == "" {
+				return fmt.Errorf("plugin (%s) has an empty event", name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePlugins(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins map[string]PluginModel
+		wantErr string
+	}{
+		{
+			name: "valid plugins",
+			plugins: map[string]PluginModel{
+				"step":     {Source: "https://github.com/bitrise-io/bitrise-plugins-step.git", Version: "~> 0.10"},
+				"reporter": {Source: "./tools/reporter", Events: []string{"StepDidFinish"}},
+			},
+		},
+		{
+			name:    "missing source",
+			plugins: map[string]PluginModel{"step": {Version: "1.0.0"}},
+			wantErr: "plugin (step) has no source",
+		},
+		{
+			name:    "invalid version",
+			plugins: map[string]PluginModel{"step": {Source: "https://github.com/bitrise-io/bitrise-plugins-step.git", Version: "latest"}},
+			wantErr: "plugin (step) has invalid version (latest): malformed constraint: latest",
+		},
+		{
+			name:    "empty event",
+			plugins: map[string]PluginModel{"reporter": {Source: "./tools/reporter", Events: []string{""}}},
+			wantErr: "plugin (reporter) has an empty event",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePlugins(tt.plugins)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -16,8 +16,10 @@ const (
 	StepRunStatusCodeSkipped                StepRunStatus = 3
 	StepRunStatusCodeSkippedWithRunIf       StepRunStatus = 4
 	StepRunStatusCodePreparationFailed      StepRunStatus = 5
-	StepRunStatusAbortedWithCustomTimeout   StepRunStatus = 7 // step times out due to a custom timeout
-	StepRunStatusAbortedWithNoOutputTimeout StepRunStatus = 8 // step times out due to no output received (hang)
+	StepRunStatusAbortedWithCustomTimeout   StepRunStatus = 7  // step times out due to a custom timeout
+	StepRunStatusAbortedWithNoOutputTimeout StepRunStatus = 8  // step times out due to no output received (hang)
+	StepRunStatusCodeServiceNotReady        StepRunStatus = 9  // a service container required by the step did not become ready
+	StepRunStatusCodeSkippedByPlugin        StepRunStatus = 10 // a plugin vetoed the step before it started
 )
 
 type StepRunResultsModel struct {
@@ -52,6 +54,10 @@ func NewStepRunStatus(status string) StepRunStatus {
 		return StepRunStatusAbortedWithCustomTimeout
 	case "aborted_with_no_output":
 		return StepRunStatusAbortedWithNoOutputTimeout
+	case "service_not_ready":
+		return StepRunStatusCodeServiceNotReady
+	case "skipped_by_plugin":
+		return StepRunStatusCodeSkippedByPlugin
 	default:
 		return -1
 	}
@@ -75,6 +81,10 @@ func (s StepRunStatus) String() string {
 		return "aborted_with_custom_timeout"
 	case StepRunStatusAbortedWithNoOutputTimeout:
 		return "aborted_with_no_output"
+	case StepRunStatusCodeServiceNotReady:
+		return "service_not_ready"
+	case StepRunStatusCodeSkippedByPlugin:
+		return "skipped_by_plugin"
 	default:
 		return "unknown"
 	}
@@ -88,10 +98,12 @@ func (s StepRunStatus) Name() string {
 		StepRunStatusCodePreparationFailed,
 		StepRunStatusCodeFailedSkippable,
 		StepRunStatusAbortedWithCustomTimeout,
-		StepRunStatusAbortedWithNoOutputTimeout:
+		StepRunStatusAbortedWithNoOutputTimeout,
+		StepRunStatusCodeServiceNotReady:
 		return "Failed"
 	case StepRunStatusCodeSkipped,
-		StepRunStatusCodeSkippedWithRunIf:
+		StepRunStatusCodeSkippedWithRunIf,
+		StepRunStatusCodeSkippedByPlugin:
 		return "Skipped"
 	default:
 		return ""
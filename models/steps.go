@@ -12,6 +12,7 @@ type StepListItem struct {
 	step     *stepmanModels.StepModel
 	with     *WithModel
 	bundle   *StepBundleListItemModel
+	tools    ToolsModel
 }
 
 func NewStepListItemFromWorkflowStep(source StepListItemModel) (*StepListItem, error) {
@@ -23,6 +24,7 @@ func NewStepListItemFromWorkflowStep(source StepListItemModel) (*StepListItem, e
 	item := &StepListItem{
 		key:      k,
 		itemType: t,
+		tools:    source.GetTools(),
 	}
 
 	switch t {
@@ -72,6 +74,7 @@ func NewStepListItemFromBundleStep(source StepListItemStepOrBundleModel) (*StepL
 	item := &StepListItem{
 		key:      k,
 		itemType: t,
+		tools:    source.GetTools(),
 	}
 
 	switch t {
@@ -108,3 +111,8 @@ func (i *StepListItem) GetWithGroup() *WithModel {
 func (i *StepListItem) GetBundle() *StepBundleListItemModel {
 	return i.bundle
 }
+
+// GetTools returns the tools declared by the Step or by the Step Bundle override.
+func (i *StepListItem) GetTools() ToolsModel {
+	return i.tools
+}
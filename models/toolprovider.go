@@ -1,13 +1,16 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"slices"
 	"strings"
+
+	stepmanModels "github.com/bitrise-io/stepman/models"
 )
 
-var ToolProviders = []string{"asdf", "mise"}
+var ToolProviders = []string{"asdf", "mise", "native"}
 
 type ToolID string
 
@@ -25,6 +28,9 @@ type ToolConfigModel struct {
 	FastInstall *bool `json:"fast_install,omitempty" yaml:"fast_install,omitempty"`
 }
 
+// ToolVersionUnset removes a tool inherited from an outer level (config, workflow or step bundle).
+const ToolVersionUnset = "unset"
+
 const ToolSyntaxPatternLatest = `(.*):latest$`
 const ToolSyntaxPatternInstalled = `(.*):installed$`
 
@@ -55,25 +61,145 @@ func validateTools(config *BitriseDataModel) error {
 		return nil
 	}
 
-	for toolID, versionString := range config.Tools {
+	if err := validateToolVersions(config.Tools); err != nil {
+		return err
+	}
+
+	for workflowID, wf := range config.Workflows {
+		if err := validateToolVersions(wf.Tools); err != nil {
+			return err
+		}
+
+		for _, stepListItem := range wf.Steps {
+			if err := validateToolVersions(stepListItem.GetTools()); err != nil {
+				return fmt.Errorf("workflow (%s): %w", workflowID, err)
+			}
+		}
+	}
+
+	for bundleID, bundle := range config.StepBundles {
+		if err := validateToolVersions(bundle.Tools); err != nil {
+			return fmt.Errorf("step bundle (%s): %w", bundleID, err)
+		}
+
+		for _, stepListItem := range bundle.Steps {
+			if err := validateToolVersions(stepListItem.GetTools()); err != nil {
+				return fmt.Errorf("step bundle (%s): %w", bundleID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateToolVersions(tools ToolsModel) error {
+	for toolID, versionString := range tools {
 		err := validateVersionString(versionString)
 		if err != nil {
 			return fmt.Errorf("%s: invalid version syntax %s: %w", toolID, versionString, err)
 		}
 	}
+	return nil
+}
 
-	for _, wf := range config.Workflows {
-		for toolID, versionString := range wf.Tools {
-			err := validateVersionString(versionString)
-			if err != nil {
-				return fmt.Errorf("%s: invalid version syntax %s: %w", toolID, versionString, err)
-			}
-		}
+// GetTools returns the tools declared by the Step or Step Bundle of the step list item.
+func (stepListItem *StepListItemModel) GetTools() ToolsModel {
+	if stepListItem == nil {
+		return nil
+	}
+	for _, value := range *stepListItem {
+		return stepListItemTools(value)
+	}
+	return nil
+}
+
+// GetTools returns the tools declared by the Step or Step Bundle of the step list item.
+func (stepListItem *StepListItemStepOrBundleModel) GetTools() ToolsModel {
+	if stepListItem == nil {
+		return nil
+	}
+	for _, value := range *stepListItem {
+		return stepListItemTools(value)
+	}
+	return nil
+}
+
+func stepListItemTools(value any) ToolsModel {
+	switch item := value.(type) {
+	case StepWithToolsModel:
+		return item.Tools
+	case StepBundleListItemModel:
+		return item.Tools
+	default:
+		return nil
 	}
+}
 
+func (step StepWithToolsModel) stepListItemValue() any {
+	if len(step.Tools) == 0 {
+		return step.StepModel
+	}
+	return step
+}
+
+// UnmarshalJSON decodes a Step of a With group. These Steps can't declare tools, the tools key is decoded
+// only to reject it instead of silently ignoring it.
+func (stepListStepItem *StepListStepItemModel) UnmarshalJSON(b []byte) error {
+	var stepItem map[string]StepWithToolsModel
+	if err := json.Unmarshal(b, &stepItem); err != nil {
+		return err
+	}
+	return stepListStepItem.setSteps(stepItem)
+}
+
+func (stepListStepItem *StepListStepItemModel) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var stepItem map[string]StepWithToolsModel
+	if err := unmarshal(&stepItem); err != nil {
+		return err
+	}
+	return stepListStepItem.setSteps(stepItem)
+}
+
+func (stepListStepItem *StepListStepItemModel) setSteps(stepItem map[string]StepWithToolsModel) error {
+	*stepListStepItem = StepListStepItemModel{}
+	for stepID, step := range stepItem {
+		if len(step.Tools) > 0 {
+			return fmt.Errorf("step (%s) in a with group declares tools, which are not supported in with groups: declare them on the workflow instead", stepID)
+		}
+		(*stepListStepItem)[stepID] = step.StepModel
+	}
 	return nil
 }
 
+// updateStepListItemStep replaces the Step of a step list item value, keeping the tools declared by the Step.
+func updateStepListItemStep(value any, step stepmanModels.StepModel) any {
+	if withTools, ok := value.(StepWithToolsModel); ok {
+		withTools.StepModel = step
+		return withTools
+	}
+	return step
+}
+
+// MergeTools returns the tools of base overridden by the tools of override. The special "unset" version removes the tool.
+func MergeTools(base, override ToolsModel) ToolsModel {
+	if len(override) == 0 {
+		return base
+	}
+
+	merged := ToolsModel{}
+	for toolID, version := range base {
+		merged[toolID] = version
+	}
+	for toolID, version := range override {
+		if version == ToolVersionUnset {
+			delete(merged, toolID)
+		} else {
+			merged[toolID] = version
+		}
+	}
+	return merged
+}
+
 // validateVersionString takes a string like `3.12:latest` or `3.12.0` and validates it against the expected syntax.
 func validateVersionString(versionString string) error {
 	versionString = strings.TrimSpace(versionString)
@@ -1,9 +1,12 @@
 package models
 
 import (
+	"encoding/json"
 	"testing"
 
+	stepmanModels "github.com/bitrise-io/stepman/models"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestValidateTools(t *testing.T) {
@@ -112,3 +115,87 @@ func TestValidateTools(t *testing.T) {
 		})
 	}
 }
+
+func TestStepTools(t *testing.T) {
+	configYML := `format_version: "13"
+workflows:
+  wf:
+    steps:
+    - script:
+        title: Node 18
+        tools:
+          nodejs: "18"
+    - script:
+        title: No tools
+    - bundle::lint:
+        tools:
+          golang: 1.23:latest
+step_bundles:
+  lint:
+    tools:
+      golang: "1.22"
+    steps:
+    - script:
+        tools:
+          golangci-lint: 1.61.0
+`
+	var config BitriseDataModel
+	require.NoError(t, yaml.Unmarshal([]byte(configYML), &config))
+	require.NoError(t, config.Normalize())
+	_, err := config.Validate()
+	require.NoError(t, err)
+
+	steps := config.Workflows["wf"].Steps
+	require.Equal(t, ToolsModel{"nodejs": "18"}, steps[0].GetTools())
+	step, err := steps[0].GetStep()
+	require.NoError(t, err)
+	require.Equal(t, "Node 18", *step.Title)
+
+	require.Nil(t, steps[1].GetTools())
+	_, isPlainStep := steps[1]["script"].(stepmanModels.StepModel)
+	require.True(t, isPlainStep)
+
+	require.Equal(t, ToolsModel{"golang": "1.23:latest"}, steps[2].GetTools())
+
+	bundleSteps := config.StepBundles["lint"].Steps
+	require.Equal(t, ToolsModel{"golangci-lint": "1.61.0"}, bundleSteps[0].GetTools())
+
+	configJSON, err := json.Marshal(config)
+	require.NoError(t, err)
+	require.Contains(t, string(configJSON), `"title":"Node 18","tools":{"nodejs":"18"}`)
+}
+
+func TestStepTools_withGroup(t *testing.T) {
+	configYML := `format_version: "13"
+workflows:
+  wf:
+    steps:
+    - with:
+        container: ubuntu
+        steps:
+        - script:
+            tools:
+              nodejs: "18"
+`
+	var config BitriseDataModel
+	err := yaml.Unmarshal([]byte(configYML), &config)
+	require.EqualError(t, err, "step (script) in a with group declares tools, which are not supported in with groups: declare them on the workflow instead")
+
+	var stepListItem StepListItemModel
+	err = json.Unmarshal([]byte(`{"with":{"steps":[{"script":{"tools":{"nodejs":"18"}}}]}}`), &stepListItem)
+	require.EqualError(t, err, "step (script) in a with group declares tools, which are not supported in with groups: declare them on the workflow instead")
+
+	require.NoError(t, json.Unmarshal([]byte(`{"with":{"steps":[{"script":{"title":"Test"}}]}}`), &stepListItem))
+	with, err := stepListItem.GetWith()
+	require.NoError(t, err)
+	require.Equal(t, "Test", *with.Steps[0]["script"].Title)
+}
+
+func TestMergeTools(t *testing.T) {
+	require.Nil(t, MergeTools(nil, nil))
+	require.Equal(t, ToolsModel{"nodejs": "20"}, MergeTools(ToolsModel{"nodejs": "20"}, nil))
+	require.Equal(t,
+		ToolsModel{"nodejs": "22", "ruby": "3.3"},
+		MergeTools(ToolsModel{"nodejs": "20", "python": "3.12"}, ToolsModel{"nodejs": "22", "python": ToolVersionUnset, "ruby": "3.3"}),
+	)
+}
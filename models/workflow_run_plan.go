@@ -80,6 +80,10 @@ type StepExecutionPlan struct {
 	ExecutionContainer *ContainerConfig  `json:"-"`
 	ServiceContainers  []ContainerConfig `json:"-"`
 
+	// Tools declared by the Step and its including Step Bundles, installed before the Step and activated only for the Step.
+	// The tools of the config and the workflow are not included, they are activated for the whole workflow.
+	Tools ToolsModel `json:"-"`
+
 	// With (container) group
 	WithGroupUUID string   `json:"with_group_uuid,omitempty"`
 	ContainerID   string   `json:"-"`
@@ -120,6 +124,7 @@ type BundleContext struct {
 	RunIfs             []StepBundleRunIf
 	ExecutionContainer *ContainerConfig  // resolved exec container from parent chain
 	ServiceContainers  []ContainerConfig // accumulated services from parent chain
+	Tools              ToolsModel        // merged tools of the parent chain
 }
 
 type WithGroupContext struct {
@@ -292,7 +297,9 @@ func (builder *WorkflowRunPlanBuilder) processStep(stepID string, stepListItem S
 		plan.StepBundleUUID = bundleContext.UUID
 		plan.StepBundleEnvs = bundleContext.Envs
 		plan.StepBundleRunIfs = bundleContext.RunIfs
+		plan.Tools = bundleContext.Tools
 	}
+	plan.Tools = MergeTools(plan.Tools, stepListItem.GetTools())
 	if withGroupContext != nil {
 		plan.WithGroupUUID = withGroupContext.UUID
 		plan.ContainerID = withGroupContext.ContainerID
@@ -421,6 +428,13 @@ func (builder *WorkflowRunPlanBuilder) processStepBundle(bundleID string, stepLi
 		}
 	}
 
+	// Collect Bundle tools: parent tools, overridden by the definition's, overridden by the usage-side tools.
+	var bundleTools ToolsModel
+	if bundleContext != nil {
+		bundleTools = bundleContext.Tools
+	}
+	bundleTools = MergeTools(MergeTools(bundleTools, bundleDefinition.Tools), bundleOverride.Tools)
+
 	// Process Bundle Steps
 	newBundleContext := BundleContext{
 		UUID:               bundleUUID,
@@ -428,6 +442,7 @@ func (builder *WorkflowRunPlanBuilder) processStepBundle(bundleID string, stepLi
 		RunIfs:             runIfs,
 		ExecutionContainer: bundleExecCfg,
 		ServiceContainers:  bundleSvcCfgs,
+		Tools:              bundleTools,
 	}
 	plans, err := builder.gatherBundleSteps(bundleDefinition, newBundleContext)
 	if err != nil {
@@ -1686,3 +1686,37 @@ func cliVersion() string {
 	}
 	return version.VERSION
 }
+
+func TestNewWorkflowRunPlan_StepTools(t *testing.T) {
+	workflows := map[string]WorkflowModel{
+		"wf": {
+			Tools: ToolsModel{"nodejs": "20"},
+			Steps: []StepListItemModel{
+				{"step1": StepWithToolsModel{Tools: ToolsModel{"nodejs": "18"}}},
+				{"bundle::my_bundle": StepBundleListItemModel{Tools: ToolsModel{"python": "unset", "ruby": "3.3"}}},
+				{"step4": stepmanModels.StepModel{}},
+			},
+		},
+	}
+	stepBundles := map[string]StepBundleModel{
+		"my_bundle": {
+			Tools: ToolsModel{"nodejs": "22", "python": "3.12"},
+			Steps: []StepListItemStepOrBundleModel{
+				{"step2": stepmanModels.StepModel{}},
+				{"step3": StepWithToolsModel{Tools: ToolsModel{"nodejs": "24:latest"}}},
+			},
+		},
+	}
+
+	got, err := NewWorkflowRunPlanBuilder(workflows, stepBundles, nil, nil, (&MockUUIDProvider{}).UUID).Build(WorkflowRunModes{}, "wf")
+	require.NoError(t, err)
+
+	want := []StepExecutionPlan{
+		{UUID: "uuid_1", StepID: "step1", Tools: ToolsModel{"nodejs": "18"}},
+		{UUID: "uuid_3", StepID: "step2", StepBundleUUID: "uuid_2", Tools: ToolsModel{"nodejs": "22", "ruby": "3.3"}},
+		{UUID: "uuid_4", StepID: "step3", StepBundleUUID: "uuid_2", Tools: ToolsModel{"nodejs": "24:latest", "ruby": "3.3"}},
+		{UUID: "uuid_5", StepID: "step4"},
+	}
+	require.Len(t, got.ExecutionPlan, 1)
+	require.Equal(t, want, got.ExecutionPlan[0].Steps)
+}
@@ -1,7 +1,7 @@
 package plugins
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 )
@@ -15,34 +15,35 @@ const (
 
 	// DidFinishRun ...
 	DidFinishRun TriggerEventName = "DidFinishRun"
-)
 
-// TriggerEvent ...
-func TriggerEvent(name TriggerEventName, payload interface{}) error {
-	// Create plugin input
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
+	// WorkflowWillStart is triggered before the steps of a workflow run.
+	WorkflowWillStart TriggerEventName = "WorkflowWillStart"
 
-	pluginConfig := PluginConfig{
-		PluginConfigTriggerEventKey: string(name),
-	}
+	// WorkflowDidFinish is triggered after the last step of a workflow finished.
+	WorkflowDidFinish TriggerEventName = "WorkflowDidFinish"
 
-	// Load plugins
-	plugins, err := LoadPlugins(string(name))
-	if err != nil {
-		return err
-	}
+	// StepWillStart is triggered before a step is activated.
+	StepWillStart TriggerEventName = "StepWillStart"
 
-	// Run plugins
-	for _, plugin := range plugins {
-		if err := RunPluginByEvent(plugin, pluginConfig, payloadBytes); err != nil {
-			return err
-		}
-	}
+	// StepDidFinish is triggered with the result of a step.
+	StepDidFinish TriggerEventName = "StepDidFinish"
+
+	// ToolSetupDidFinish is triggered after the declarative tool setup of a workflow.
+	ToolSetupDidFinish TriggerEventName = "ToolSetupDidFinish"
+
+	// BuildWillAbort is triggered when the build is interrupted by a signal.
+	BuildWillAbort TriggerEventName = "BuildWillAbort"
+)
 
-	return nil
+// TriggerEvent delivers a single event to the plugins registered for it,
+// persistent plugins are started for this event only.
+func TriggerEvent(name TriggerEventName, payload interface{}) error {
+	session := NewSession()
+	_, err := session.TriggerEvent(name, payload)
+	if closeErr := session.Close(); closeErr != nil {
+		err = errors.Join(err, closeErr)
+	}
+	return err
 }
 
 // LoadPlugins ...
@@ -72,5 +73,7 @@ func LoadPlugins(eventName string) ([]Plugin, error) {
 		plugins = append(plugins, plugin)
 	}
 
+	SortByName(plugins)
+
 	return plugins, nil
 }
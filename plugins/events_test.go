@@ -0,0 +1,47 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/configs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerEvent(t *testing.T) {
+	ForceInitPaths(t.TempDir())
+	isCIMode := configs.IsCIMode
+	configs.IsCIMode = true
+	t.Cleanup(func() { configs.IsCIMode = isCIMode })
+	t.Setenv(PluginEventTimeoutEnvKey, "1s")
+
+	installScriptPlugin(t, "reporter", []string{string(StepDidFinish)}, `cat > "$BITRISE_PLUGIN_INPUT_DATA_DIR/$BITRISE_PLUGIN_INPUT_TRIGGER.json"`)
+	installScriptPlugin(t, "slow", []string{string(StepDidFinish), string(BuildWillAbort)}, "exec sleep 10")
+
+	err := TriggerEvent(StepDidFinish, map[string]string{"event_name": string(StepDidFinish)})
+	require.EqualError(t, err, "plugin (slow): timed out after 1s")
+
+	payload, err := os.ReadFile(filepath.Join(GetPluginDataDir("reporter"), "StepDidFinish.json"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"event_name":"StepDidFinish"}`, string(payload))
+
+	plugins, err := LoadPlugins(string(WorkflowWillStart))
+	require.NoError(t, err)
+	require.Empty(t, plugins)
+}
+
+func installScriptPlugin(t *testing.T, name string, triggers []string, script string) {
+	srcDir := GetPluginSrcDir(name)
+	require.NoError(t, os.MkdirAll(srcDir, 0755))
+	require.NoError(t, os.MkdirAll(GetPluginDataDir(name), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, pluginScriptFileName), []byte("#!/bin/bash\n"+script+"\n"), 0755))
+
+	plugin := Plugin{Name: name, TriggerEvents: triggers}
+	definition := "name: " + name + "\ntriggers:\n"
+	for _, trigger := range triggers {
+		definition += "- " + trigger + "\n"
+	}
+	require.NoError(t, os.WriteFile(GetPluginDefinitionPath(name), []byte(definition), 0644))
+	require.NoError(t, CreateAndAddPluginRoute(plugin, "local", ""))
+}
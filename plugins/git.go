@@ -67,8 +67,22 @@ func gitRemoteTagList(cloneIntoDir string) ([]string, error) {
 	if err != nil {
 		return []string{}, err
 	}
+	return parseTagList(out), nil
+}
+
+// gitRemoteURLTagList lists the tags of the repository without cloning it.
+func gitRemoteURLTagList(repositoryURL string) ([]string, error) {
+	cmd := command.New("git", "ls-remote", "--tags", repositoryURL)
+	out, err := runForOutputAndHandle(cmd)
+	if err != nil {
+		return []string{}, err
+	}
+	return parseTagList(out), nil
+}
+
+func parseTagList(out string) []string {
 	if out == "" {
-		return []string{}, nil
+		return []string{}
 	}
 
 	var exp = regexp.MustCompile(`(^[a-z0-9]+)+.*refs/tags/([0-9.]+)`)
@@ -92,7 +106,7 @@ func gitRemoteTagList(cloneIntoDir string) ([]string, error) {
 		versions = append(versions, key)
 	}
 
-	return versions, nil
+	return versions
 }
 
 func gitInit(cloneIntoDir string) error {
@@ -119,6 +133,12 @@ func gitCheckout(cloneIntoDir, gitCheckoutParam string) error {
 	return runAndHandle(cmd)
 }
 
+func gitCommitHash(cloneIntoDir string) (string, error) {
+	cmd := command.New("git", "rev-parse", "HEAD")
+	cmd.SetDir(cloneIntoDir)
+	return runForOutputAndHandle(cmd)
+}
+
 func gitInitWithRemote(cloneIntoDir, repositoryURL string) error {
 	gitCheckPath := filepath.Join(cloneIntoDir, ".git")
 	if exist, err := pathutil.IsPathExists(gitCheckPath); err != nil {
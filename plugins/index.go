@@ -0,0 +1,269 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/go-utils/command"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// PluginIndexEnvKey sets the plugin index used by `bitrise plugin search` and `bitrise plugin install <name>`.
+	PluginIndexEnvKey = "BITRISE_PLUGIN_INDEX"
+
+	// PluginIndexFileName is the name of the index file in the root of a plugin index git repository or directory.
+	PluginIndexFileName = "index.yml"
+)
+
+var pluginNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// PluginIndex lists the plugins installable by name. It can be a local YAML or JSON file, a directory or git repository
+// with an index.yml in its root, or a YAML or JSON file served over HTTP(S).
+type PluginIndex struct {
+	FormatVersion string                   `yaml:"format_version" json:"format_version"`
+	Plugins       map[string]IndexedPlugin `yaml:"plugins" json:"plugins"`
+}
+
+// IndexedPlugin is a plugin listed in a plugin index.
+type IndexedPlugin struct {
+	Name         string          `yaml:"-" json:"name"`
+	Description  string          `yaml:"description,omitempty" json:"description,omitempty"`
+	Source       string          `yaml:"source" json:"source"`
+	Executable   ExecutableModel `yaml:"executable,omitempty" json:"-"`
+	Requirements []Requirement   `yaml:"requirements,omitempty" json:"requirements,omitempty"`
+}
+
+// IndexedPlugins ...
+type IndexedPlugins []IndexedPlugin
+
+// IsPluginName reports whether the install argument is a plugin name to look up in the plugin index,
+// rather than a source URL or a local path.
+func IsPluginName(arg string) bool {
+	if !pluginNameRegexp.MatchString(arg) {
+		return false
+	}
+	exists, err := pathutil.IsPathExists(arg)
+	return err == nil && !exists
+}
+
+// ReadPluginIndex reads the plugin index from the given location.
+func ReadPluginIndex(location string) (PluginIndex, error) {
+	content, err := readPluginIndexContent(location)
+	if err != nil {
+		return PluginIndex{}, fmt.Errorf("failed to read plugin index (%s): %w", location, err)
+	}
+
+	index, err := parsePluginIndex(content)
+	if err != nil {
+		return PluginIndex{}, fmt.Errorf("invalid plugin index (%s): %w", location, err)
+	}
+	return index, nil
+}
+
+func parsePluginIndex(content []byte) (PluginIndex, error) {
+	// JSON is valid YAML, both formats are parsed by the YAML parser
+	var index PluginIndex
+	if err := yaml.Unmarshal(content, &index); err != nil {
+		return PluginIndex{}, err
+	}
+
+	for name, plugin := range index.Plugins {
+		plugin.Name = name
+		index.Plugins[name] = plugin
+	}
+
+	if err := index.Validate(); err != nil {
+		return PluginIndex{}, err
+	}
+	return index, nil
+}
+
+func readPluginIndexContent(location string) ([]byte, error) {
+	if isGitURL(location) {
+		return readPluginIndexFromGit(location)
+	}
+
+	parsed, err := url.Parse(location)
+	if err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") {
+		return readPluginIndexFromURL(location)
+	}
+
+	pth := strings.TrimPrefix(location, "file://")
+	info, err := os.Stat(pth)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		pth = filepath.Join(pth, PluginIndexFileName)
+	}
+	return os.ReadFile(pth)
+}
+
+func isGitURL(location string) bool {
+	return strings.HasPrefix(location, "git@") || (strings.HasSuffix(location, ".git") && !isLocalURL(location))
+}
+
+func readPluginIndexFromGit(repositoryURL string) ([]byte, error) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("plugin-index")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			log.Warnf("Failed to remove path (%s)", tmpDir)
+		}
+	}()
+
+	cloneDir := filepath.Join(tmpDir, "index")
+	if err := runAndHandle(command.New("git", "clone", "--depth", "1", repositoryURL, cloneDir)); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(cloneDir, PluginIndexFileName))
+}
+
+func readPluginIndexFromURL(indexURL string) ([]byte, error) {
+	resp, err := http.Get(indexURL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Warnf("failed to close (%s) body", indexURL)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non success status code (%d)", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Validate ...
+func (index PluginIndex) Validate() error {
+	if index.FormatVersion != "" && index.FormatVersion != "1" {
+		return fmt.Errorf("unsupported format_version (%s)", index.FormatVersion)
+	}
+
+	for _, name := range index.names() {
+		plugin := index.Plugins[name]
+		if !pluginNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid plugin name (%s)", name)
+		}
+		if plugin.Source == "" {
+			return fmt.Errorf("plugin (%s): source is required", name)
+		}
+		for _, requirement := range plugin.Requirements {
+			if requirement.Tool == "" || requirement.MinVersion == "" {
+				return fmt.Errorf("plugin (%s): requirements need a tool and a min_version", name)
+			}
+		}
+	}
+	return nil
+}
+
+// Lookup ...
+func (index PluginIndex) Lookup(name string) (IndexedPlugin, bool) {
+	plugin, ok := index.Plugins[name]
+	return plugin, ok
+}
+
+// Search returns the plugins whose name or description contains the query, case insensitive, sorted by name.
+// An empty query matches every plugin.
+func (index PluginIndex) Search(query string) IndexedPlugins {
+	query = strings.ToLower(query)
+
+	results := IndexedPlugins{}
+	for _, name := range index.names() {
+		plugin := index.Plugins[name]
+		if strings.Contains(strings.ToLower(name), query) || strings.Contains(strings.ToLower(plugin.Description), query) {
+			results = append(results, plugin)
+		}
+	}
+	return results
+}
+
+func (index PluginIndex) names() []string {
+	names := make([]string, 0, len(index.Plugins))
+	for name := range index.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Platforms returns the platforms with a prebuilt executable, or nil for plugins run from their source.
+func (plugin IndexedPlugin) Platforms() []string {
+	var platforms []string
+	if plugin.Executable.OSX != "" {
+		platforms = append(platforms, "osx")
+	}
+	if plugin.Executable.OSXArm64 != "" {
+		platforms = append(platforms, "osx-arm64")
+	}
+	if plugin.Executable.Linux != "" {
+		platforms = append(platforms, "linux")
+	}
+	return platforms
+}
+
+// IsSupportedOnCurrentPlatform ...
+func (plugin IndexedPlugin) IsSupportedOnCurrentPlatform() bool {
+	return len(plugin.Platforms()) == 0 || plugin.Executable.forCurrentPlatform() != ""
+}
+
+// String ...
+func (plugins IndexedPlugins) String() string {
+	str := ""
+	for _, plugin := range plugins {
+		platforms := "any"
+		if len(plugin.Platforms()) > 0 {
+			platforms = strings.Join(plugin.Platforms(), ", ")
+		}
+
+		str += fmt.Sprintf("%s\n", plugin.Name)
+		if plugin.Description != "" {
+			str += fmt.Sprintf("  %s\n", plugin.Description)
+		}
+		str += fmt.Sprintf("  source: %s\n", plugin.Source)
+		str += fmt.Sprintf("  platforms: %s\n", platforms)
+		for _, requirement := range plugin.Requirements {
+			requirementStr := fmt.Sprintf("%s >= %s", requirement.Tool, requirement.MinVersion)
+			if requirement.MaxVersion != "" {
+				requirementStr += fmt.Sprintf(", <= %s", requirement.MaxVersion)
+			}
+			str += fmt.Sprintf("  requires: %s\n", requirementStr)
+		}
+		str += "\n"
+	}
+	return str
+}
+
+// JSON ...
+func (plugins IndexedPlugins) JSON() string {
+	type indexedPluginJSON struct {
+		IndexedPlugin
+		Platforms []string `json:"platforms,omitempty"`
+	}
+
+	items := make([]indexedPluginJSON, 0, len(plugins))
+	for _, plugin := range plugins {
+		items = append(items, indexedPluginJSON{IndexedPlugin: plugin, Platforms: plugin.Platforms()})
+	}
+
+	bytes, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Sprintf(`"Failed to marshal plugins (%#v), err: %s"`, plugins, err)
+	}
+	return string(bytes) + "\n"
+}
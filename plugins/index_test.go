@@ -0,0 +1,118 @@
+package plugins
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPluginIndex = `format_version: "1"
+plugins:
+  step:
+    description: Step development helpers
+    source: https://github.com/bitrise-io/bitrise-plugins-step.git
+    executable:
+      osx: https://example.com/step-Darwin-x86_64
+      osx-arm64: https://example.com/step-Darwin-arm64
+      linux: https://example.com/step-Linux-x86_64
+  init:
+    description: Generate a bitrise.yml for the project
+    source: https://github.com/bitrise-io/bitrise-plugins-init.git
+    requirements:
+    - tool: bitrise
+      min_version: 1.3.0
+`
+
+func TestReadPluginIndex(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PluginIndexFileName), []byte(testPluginIndex), 0644))
+
+	jsonIndexPth := filepath.Join(dir, "plugins.json")
+	require.NoError(t, os.WriteFile(jsonIndexPth, []byte(`{"plugins":{"step":{"source":"https://github.com/bitrise-io/bitrise-plugins-step.git"}}}`), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.yml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(testPluginIndex))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	for _, location := range []string{dir, "file://" + filepath.Join(dir, PluginIndexFileName), server.URL + "/index.yml"} {
+		index, err := ReadPluginIndex(location)
+		require.NoError(t, err, location)
+		require.Equal(t, []string{"init", "step"}, index.names())
+
+		plugin, ok := index.Lookup("step")
+		require.True(t, ok)
+		require.Equal(t, "step", plugin.Name)
+		require.Equal(t, []string{"osx", "osx-arm64", "linux"}, plugin.Platforms())
+	}
+
+	index, err := ReadPluginIndex(jsonIndexPth)
+	require.NoError(t, err)
+	require.Equal(t, "https://github.com/bitrise-io/bitrise-plugins-step.git", index.Plugins["step"].Source)
+
+	_, err = ReadPluginIndex(server.URL + "/missing.yml")
+	require.EqualError(t, err, "failed to read plugin index ("+server.URL+"/missing.yml): non success status code (404)")
+}
+
+func TestPluginIndex_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		index   string
+		wantErr string
+	}{
+		{name: "valid", index: testPluginIndex},
+		{name: "unsupported format version", index: `format_version: "2"`, wantErr: "unsupported format_version (2)"},
+		{name: "missing source", index: "plugins:\n  step: {}", wantErr: "plugin (step): source is required"},
+		{name: "invalid name", index: "plugins:\n  my/step:\n    source: ./step", wantErr: "invalid plugin name (my/step)"},
+		{name: "invalid requirement", index: "plugins:\n  step:\n    source: ./step\n    requirements:\n    - tool: bitrise", wantErr: "plugin (step): requirements need a tool and a min_version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parsePluginIndex([]byte(tt.index))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPluginIndex_Search(t *testing.T) {
+	index, err := parsePluginIndex([]byte(testPluginIndex))
+	require.NoError(t, err)
+
+	names := func(plugins IndexedPlugins) []string {
+		var names []string
+		for _, plugin := range plugins {
+			names = append(names, plugin.Name)
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"init", "step"}, names(index.Search("")))
+	require.Equal(t, []string{"step"}, names(index.Search("STEP")))
+	require.Equal(t, []string{"init"}, names(index.Search("bitrise.yml")))
+	require.Empty(t, index.Search("deploy"))
+}
+
+func TestIsPluginName(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "local-plugin"), 0755))
+
+	require.True(t, IsPluginName("step"))
+	require.True(t, IsPluginName("workflow-editor"))
+	require.False(t, IsPluginName("local-plugin"))
+	require.False(t, IsPluginName("./step"))
+	require.False(t, IsPluginName("https://github.com/bitrise-io/bitrise-plugins-step.git"))
+}
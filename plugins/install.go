@@ -114,35 +114,38 @@ func isSourceURIChanged(installed, new string) bool {
 	return true
 }
 
-func installLocalPlugin(pluginSourceURI, pluginLocalPth string) (Plugin, error) {
+// installLocalPlugin installs the plugin from the given directory and returns the SHA-256 checksum of its executable.
+// If expectedChecksum is set, the plugin is only installed if its executable matches it.
+func installLocalPlugin(pluginSourceURI, pluginLocalPth, expectedChecksum string) (Plugin, string, error) {
 	// Parse & validate plugin
 	tmpPluginYMLPath := filepath.Join(pluginLocalPth, pluginDefinitionFileName)
 
 	if err := validatePath(tmpPluginYMLPath); err != nil {
-		return Plugin{}, fmt.Errorf("bitrise-plugin.yml validation failed, error: %s", err)
+		return Plugin{}, "", fmt.Errorf("bitrise-plugin.yml validation failed, error: %s", err)
 	}
 
 	newPlugin, err := ParsePluginFromYML(tmpPluginYMLPath)
 	if err != nil {
-		return Plugin{}, fmt.Errorf("failed to parse bitrise-plugin.yml (%s), error: %s", tmpPluginYMLPath, err)
+		return Plugin{}, "", fmt.Errorf("failed to parse bitrise-plugin.yml (%s), error: %s", tmpPluginYMLPath, err)
 	}
 
-	if err := validatePlugin(newPlugin, pluginSourceURI, os.Args[0]); err != nil {
-		return Plugin{}, fmt.Errorf("plugin validation failed, error: %s", err)
+	// The bitrise-plugin.sh of script plugins is looked up next to the definition, pluginSourceURI can be a git URL
+	if err := validatePlugin(newPlugin, tmpPluginYMLPath, os.Args[0]); err != nil {
+		return Plugin{}, "", fmt.Errorf("plugin validation failed, error: %s", err)
 	}
 	// ---
 
 	// Check if plugin already installed
 	if route, found, err := ReadPluginRoute(newPlugin.Name); err != nil {
-		return Plugin{}, fmt.Errorf("failed to check if plugin already installed, error: %s", err)
+		return Plugin{}, "", fmt.Errorf("failed to check if plugin already installed, error: %s", err)
 	} else if found {
 		if isSourceURIChanged(route.Source, pluginSourceURI) {
-			return Plugin{}, fmt.Errorf("plugin already installed with name (%s) from different source (%s)", route.Name, route.Source)
+			return Plugin{}, "", fmt.Errorf("plugin already installed with name (%s) from different source (%s)", route.Name, route.Source)
 		}
 
 		installedPluginVersionPtr, err := GetPluginVersion(route.Name)
 		if err != nil {
-			return Plugin{}, fmt.Errorf("failed to check installed plugin (%s) version, error: %s", route.Name, err)
+			return Plugin{}, "", fmt.Errorf("failed to check installed plugin (%s) version, error: %s", route.Name, err)
 		}
 
 		if installedPluginVersionPtr != nil {
@@ -155,7 +158,7 @@ func installLocalPlugin(pluginSourceURI, pluginLocalPth string) (Plugin, error)
 
 	tmpPluginDir, err := pathutil.NormalizedOSTempDirPath("__plugin__")
 	if err != nil {
-		return Plugin{}, fmt.Errorf("failed to create tmp plugin dir, error: %s", err)
+		return Plugin{}, "", fmt.Errorf("failed to create tmp plugin dir, error: %s", err)
 	}
 	defer func() {
 		err = os.RemoveAll(tmpPluginDir)
@@ -165,11 +168,12 @@ func installLocalPlugin(pluginSourceURI, pluginLocalPth string) (Plugin, error)
 	}()
 
 	// Install plugin executable
+	executableChecksum := ""
 	executableURL := newPlugin.ExecutableURL()
 	if executableURL != "" {
 		tmpPluginBinDir := filepath.Join(tmpPluginDir, "bin")
 		if err := os.MkdirAll(tmpPluginBinDir, 0755); err != nil {
-			return Plugin{}, fmt.Errorf("failed to create tmp plugin bin dir, error: %s", err)
+			return Plugin{}, "", fmt.Errorf("failed to create tmp plugin bin dir, error: %s", err)
 		}
 
 		tmpPluginBinPth := filepath.Join(tmpPluginBinDir, newPlugin.Name)
@@ -179,7 +183,11 @@ func installLocalPlugin(pluginSourceURI, pluginLocalPth string) (Plugin, error)
 			err = downloadPluginBin(executableURL, tmpPluginBinPth)
 		})
 		if err != nil {
-			return Plugin{}, fmt.Errorf("failed to download plugin executable from (%s), error: %s", executableURL, err)
+			return Plugin{}, "", fmt.Errorf("failed to download plugin executable from (%s), error: %s", executableURL, err)
+		}
+
+		if executableChecksum, err = verifyExecutable(newPlugin, tmpPluginBinPth); err != nil {
+			return Plugin{}, "", fmt.Errorf("failed to verify plugin executable downloaded from (%s): %w", executableURL, err)
 		}
 	}
 	// ---
@@ -187,18 +195,29 @@ func installLocalPlugin(pluginSourceURI, pluginLocalPth string) (Plugin, error)
 	// Install plugin source
 	tmpPluginSrcDir := filepath.Join(tmpPluginDir, "src")
 	if err := os.MkdirAll(tmpPluginSrcDir, 0755); err != nil {
-		return Plugin{}, fmt.Errorf("failed to create tmp plugin src dir, error: %s", err)
+		return Plugin{}, "", fmt.Errorf("failed to create tmp plugin src dir, error: %s", err)
 	}
 
 	if err := command.CopyDir(pluginLocalPth, tmpPluginSrcDir, true); err != nil {
-		return Plugin{}, fmt.Errorf("failed to copy plugin from (%s) to (%s), error: %s", pluginLocalPth, tmpPluginSrcDir, err)
+		return Plugin{}, "", fmt.Errorf("failed to copy plugin from (%s) to (%s), error: %s", pluginLocalPth, tmpPluginSrcDir, err)
+	}
+
+	if executableURL == "" {
+		executableChecksum, err = fileSHA256(filepath.Join(tmpPluginSrcDir, pluginScriptFileName))
+		if err != nil {
+			return Plugin{}, "", fmt.Errorf("failed to calculate the checksum of %s: %w", pluginScriptFileName, err)
+		}
+	}
+
+	if expectedChecksum != "" && expectedChecksum != executableChecksum {
+		return Plugin{}, "", fmt.Errorf("plugin (%s) executable checksum mismatch: expected %s, got %s", newPlugin.Name, expectedChecksum, executableChecksum)
 	}
 	// ---
 
 	// Create plugin work dir
 	tmpPluginDataDir := filepath.Join(tmpPluginDir, "data")
 	if err := os.MkdirAll(tmpPluginDataDir, 0755); err != nil {
-		return Plugin{}, fmt.Errorf("failed to create tmp plugin data dir (%s), error: %s", tmpPluginDataDir, err)
+		return Plugin{}, "", fmt.Errorf("failed to create tmp plugin data dir (%s), error: %s", tmpPluginDataDir, err)
 	}
 	// ---
 
@@ -207,7 +226,7 @@ func installLocalPlugin(pluginSourceURI, pluginLocalPth string) (Plugin, error)
 		if err := cleanupPlugin(newPlugin.Name); err != nil {
 			log.Warnf("Failed to cleanup plugin (%s), error: %s", newPlugin.Name, err)
 		}
-		return Plugin{}, fmt.Errorf("failed to copy plugin, error: %s", err)
+		return Plugin{}, "", fmt.Errorf("failed to copy plugin, error: %s", err)
 	}
 
 	if executableURL != "" {
@@ -217,11 +236,11 @@ func installLocalPlugin(pluginSourceURI, pluginLocalPth string) (Plugin, error)
 			if err := cleanupPlugin(newPlugin.Name); err != nil {
 				log.Warnf("Failed to cleanup plugin (%s), error: %s", newPlugin.Name, err)
 			}
-			return Plugin{}, fmt.Errorf("failed to make plugin bin executable, error: %s", err)
+			return Plugin{}, "", fmt.Errorf("failed to make plugin bin executable, error: %s", err)
 		}
 	}
 
-	return newPlugin, nil
+	return newPlugin, executableChecksum, nil
 }
 
 func isLocalURL(urlStr string) bool {
@@ -241,7 +260,12 @@ func isLocalURL(urlStr string) bool {
 
 // InstallPlugin ...
 func InstallPlugin(pluginSourceURI, versionTag string) (Plugin, string, error) {
+	return installPlugin(pluginSourceURI, versionTag, nil)
+}
+
+func installPlugin(pluginSourceURI, versionTag string, locked *LockedPlugin) (Plugin, string, error) {
 	newVersion := ""
+	commitHash := ""
 	pluginDir := ""
 
 	if !isLocalURL(pluginSourceURI) {
@@ -266,6 +290,14 @@ func InstallPlugin(pluginSourceURI, versionTag string) (Plugin, string, error) {
 			return Plugin{}, "", fmt.Errorf("failed to download plugin, error: %s", err)
 		}
 
+		commitHash, err = gitCommitHash(pluginSrcTmpDir)
+		if err != nil {
+			return Plugin{}, "", fmt.Errorf("failed to get plugin commit hash, error: %s", err)
+		}
+		if locked != nil && locked.CommitHash != "" && locked.CommitHash != commitHash {
+			return Plugin{}, "", fmt.Errorf("version (%s) of the plugin points to commit (%s), but (%s) is locked", version, commitHash, locked.CommitHash)
+		}
+
 		pluginDir = pluginSrcTmpDir
 		newVersion = version
 	} else {
@@ -273,13 +305,24 @@ func InstallPlugin(pluginSourceURI, versionTag string) (Plugin, string, error) {
 		pluginDir = pluginSourceURI
 	}
 
-	newPlugin, err := installLocalPlugin(pluginSourceURI, pluginDir)
+	expectedChecksum := ""
+	if locked != nil {
+		expectedChecksum = locked.ExecutableSHA256
+	}
+
+	newPlugin, executableChecksum, err := installLocalPlugin(pluginSourceURI, pluginDir, expectedChecksum)
 	if err != nil {
 		return Plugin{}, "", err
 	}
 
 	// Register plugin
-	if err := CreateAndAddPluginRoute(newPlugin, pluginSourceURI, newVersion); err != nil {
+	route, err := NewPluginRoute(newPlugin, pluginSourceURI, newVersion)
+	if err == nil {
+		route.CommitHash = commitHash
+		route.ExecutableSHA256 = executableChecksum
+		err = AddPluginRoute(route)
+	}
+	if err != nil {
 		if err := cleanupPlugin(newPlugin.Name); err != nil {
 			log.Warnf("Failed to cleanup plugin (%s), error: %s", newPlugin.Name, err)
 		}
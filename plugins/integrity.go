@@ -0,0 +1,133 @@
+package plugins
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var sha256ChecksumRegexp = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+func isSHA256Checksum(checksum string) bool {
+	return sha256ChecksumRegexp.MatchString(checksum)
+}
+
+func fileSHA256(pth string) (string, error) {
+	file, err := os.Open(pth)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read (%s): %w", pth, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func parsePublicKey(publicKey string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public_key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public_key: ed25519 public key should be %d bytes long, got %d", ed25519.PublicKeySize, len(key))
+	}
+	return key, nil
+}
+
+// verifyExecutable checks the downloaded executable against the checksum and signature declared in the plugin definition,
+// and returns its SHA-256 checksum.
+func verifyExecutable(plugin Plugin, executablePth string) (string, error) {
+	checksum, err := fileSHA256(executablePth)
+	if err != nil {
+		return "", err
+	}
+
+	if expected := plugin.ExecutableChecksum(); expected != "" && expected != checksum {
+		return "", fmt.Errorf("checksum mismatch: expected %s, got %s", expected, checksum)
+	}
+
+	if signatureURL := plugin.ExecutableSignatureURL(); signatureURL != "" {
+		if err := verifySignature(executablePth, signatureURL, plugin.PublicKey); err != nil {
+			return "", err
+		}
+	}
+
+	return checksum, nil
+}
+
+func verifySignature(executablePth, signatureURL, publicKey string) error {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	signatureDir, err := os.MkdirTemp("", "plugin-signature")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(signatureDir)
+	}()
+
+	signaturePth := filepath.Join(signatureDir, "signature")
+	if err := downloadPluginBin(signatureURL, signaturePth); err != nil {
+		return fmt.Errorf("failed to download signature from (%s): %w", signatureURL, err)
+	}
+
+	encodedSignature, err := os.ReadFile(signaturePth)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encodedSignature)))
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	executable, err := os.ReadFile(executablePth)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(key, executable, signature) {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
+
+// verifyInstalledPlugin checks that the executable of the installed plugin did not change since it was installed.
+// It runs before the plugin is executed only, so that a modified plugin can still be listed, inspected, updated and deleted.
+func verifyInstalledPlugin(name string) error {
+	route, found, err := ReadPluginRoute(name)
+	if err != nil {
+		return err
+	}
+	if !found || route.ExecutableSHA256 == "" {
+		// Not registered or installed before the checksums were recorded
+		return nil
+	}
+
+	executablePth, _, err := GetPluginExecutablePath(route.Name)
+	if err != nil {
+		return err
+	}
+
+	checksum, err := fileSHA256(executablePth)
+	if err != nil {
+		return err
+	}
+	if checksum != route.ExecutableSHA256 {
+		return fmt.Errorf("integrity check of plugin (%s) failed: the checksum of (%s) is %s, expected %s, reinstall the plugin", route.Name, executablePth, checksum, route.ExecutableSHA256)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package plugins
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/configs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyExecutable(t *testing.T) {
+	dir := t.TempDir()
+	executablePth := filepath.Join(dir, "plugin")
+	require.NoError(t, os.WriteFile(executablePth, []byte("plugin binary"), 0755))
+	checksum := "0000000000000000000000000000000000000000000000000000000000000000"
+	actualChecksum, err := fileSHA256(executablePth)
+	require.NoError(t, err)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	encodedPublicKey := base64.StdEncoding.EncodeToString(publicKey)
+
+	signatures := map[string][]byte{
+		"/plugin.sig": ed25519.Sign(privateKey, []byte("plugin binary")),
+		"/other.sig":  ed25519.Sign(privateKey, []byte("other binary")),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString(signatures[r.URL.Path]) + "\n"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		plugin  Plugin
+		wantErr string
+	}{
+		{
+			name:   "no checksum or signature declared",
+			plugin: Plugin{},
+		},
+		{
+			name:   "matching checksum",
+			plugin: Plugin{Checksums: allPlatforms(actualChecksum)},
+		},
+		{
+			name:    "checksum mismatch",
+			plugin:  Plugin{Checksums: allPlatforms(checksum)},
+			wantErr: "checksum mismatch: expected " + checksum + ", got " + actualChecksum,
+		},
+		{
+			name:   "valid signature",
+			plugin: Plugin{Signatures: allPlatforms(server.URL + "/plugin.sig"), PublicKey: encodedPublicKey},
+		},
+		{
+			name:    "invalid signature",
+			plugin:  Plugin{Signatures: allPlatforms(server.URL + "/other.sig"), PublicKey: encodedPublicKey},
+			wantErr: "signature verification failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifyExecutable(tt.plugin, executablePth)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, actualChecksum, got)
+		})
+	}
+}
+
+func TestInstalledPlugin_integrity(t *testing.T) {
+	ForceInitPaths(t.TempDir())
+	isCIMode := configs.IsCIMode
+	configs.IsCIMode = true
+	t.Cleanup(func() { configs.IsCIMode = isCIMode })
+
+	srcDir := writeLocalPlugin(t, "reporter")
+	_, _, err := InstallPlugin(srcDir, "")
+	require.NoError(t, err)
+
+	route, found, err := ReadPluginRoute("reporter")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.True(t, isSHA256Checksum(route.ExecutableSHA256))
+
+	plugin, found, err := LoadPlugin("reporter")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NoError(t, RunPluginByCommand(plugin, nil))
+
+	require.NoError(t, os.WriteFile(filepath.Join(GetPluginSrcDir("reporter"), pluginScriptFileName), []byte("#!/bin/bash\necho tampered\n"), 0755))
+	require.ErrorContains(t, RunPluginByCommand(plugin, nil), "integrity check of plugin (reporter) failed")
+
+	// The modified plugin can still be inspected and deleted
+	_, found, err = LoadPlugin("reporter")
+	require.NoError(t, err)
+	require.True(t, found)
+	installed, err := InstalledPluginList()
+	require.NoError(t, err)
+	require.Len(t, installed, 1)
+	require.NoError(t, DeletePlugin("reporter"))
+}
+
+func TestTriggerEvent_skipsModifiedPlugin(t *testing.T) {
+	ForceInitPaths(t.TempDir())
+	isCIMode := configs.IsCIMode
+	configs.IsCIMode = true
+	t.Cleanup(func() { configs.IsCIMode = isCIMode })
+
+	installScriptPlugin(t, "reporter", []string{string(StepDidFinish)}, `cat > "$BITRISE_PLUGIN_INPUT_DATA_DIR/$BITRISE_PLUGIN_INPUT_TRIGGER.json"`)
+	installScriptPlugin(t, "modified", []string{string(StepDidFinish)}, "echo hello")
+	route, _, err := ReadPluginRoute("modified")
+	require.NoError(t, err)
+	route.ExecutableSHA256 = strings.Repeat("0", 64)
+	require.NoError(t, AddPluginRoute(route))
+
+	err = TriggerEvent(StepDidFinish, map[string]string{"event_name": string(StepDidFinish)})
+	require.ErrorContains(t, err, "plugin (modified): integrity check of plugin (modified) failed")
+	require.FileExists(t, filepath.Join(GetPluginDataDir("reporter"), "StepDidFinish.json"))
+}
+
+func TestLockfile(t *testing.T) {
+	ForceInitPaths(t.TempDir())
+
+	srcDir := writeLocalPlugin(t, "reporter")
+	_, _, err := InstallPlugin(srcDir, "")
+	require.NoError(t, err)
+
+	lockfile, err := NewLockfileFromInstalledPlugins()
+	require.NoError(t, err)
+	lockfilePth := filepath.Join(t.TempDir(), DefaultLockfileName)
+	require.NoError(t, lockfile.Write(lockfilePth))
+
+	lockfile, err = ReadLockfile(lockfilePth)
+	require.NoError(t, err)
+	require.Equal(t, []string{"reporter"}, lockfile.Names())
+
+	// Reinstall on a clean machine
+	ForceInitPaths(t.TempDir())
+	plugin, err := InstallLockedPlugin("reporter", lockfile.Plugins["reporter"])
+	require.NoError(t, err)
+	require.Equal(t, "reporter", plugin.Name)
+
+	// The plugin source changed since it was locked
+	ForceInitPaths(t.TempDir())
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, pluginScriptFileName), []byte("#!/bin/bash\necho changed\n"), 0755))
+	_, err = InstallLockedPlugin("reporter", lockfile.Plugins["reporter"])
+	require.ErrorContains(t, err, "plugin (reporter) executable checksum mismatch")
+}
+
+func writeLocalPlugin(t *testing.T, name string) string {
+	srcDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, pluginDefinitionFileName), []byte("name: "+name+"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, pluginScriptFileName), []byte("#!/bin/bash\necho hello\n"), 0755))
+	return srcDir
+}
+
+func allPlatforms(value string) ExecutableModel {
+	return ExecutableModel{OSX: value, OSXArm64: value, Linux: value}
+}
@@ -0,0 +1,124 @@
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultLockfileName is the default file name of the plugin lockfile.
+const DefaultLockfileName = "bitrise-plugins.lock"
+
+// LockedPlugin is an installed plugin pinned to a version, commit and executable checksum.
+type LockedPlugin struct {
+	Source           string `yaml:"source"`
+	Version          string `yaml:"version,omitempty"`
+	CommitHash       string `yaml:"commit_hash,omitempty"`
+	ExecutableSHA256 string `yaml:"executable_sha256,omitempty"`
+}
+
+// Lockfile lists the installed plugins by name, for reinstalling the same plugins on another machine.
+type Lockfile struct {
+	Plugins map[string]LockedPlugin `yaml:"plugins"`
+}
+
+// NewLockfileFromInstalledPlugins creates a lockfile of the currently installed plugins.
+func NewLockfileFromInstalledPlugins() (Lockfile, error) {
+	routing, err := readPluginRouting()
+	if err != nil {
+		return Lockfile{}, err
+	}
+
+	lockfile := Lockfile{Plugins: map[string]LockedPlugin{}}
+	for name, route := range routing.RouteMap {
+		lockfile.Plugins[name] = LockedPlugin{
+			Source:           route.Source,
+			Version:          route.Version,
+			CommitHash:       route.CommitHash,
+			ExecutableSHA256: route.ExecutableSHA256,
+		}
+	}
+	return lockfile, nil
+}
+
+// ReadLockfile ...
+func ReadLockfile(pth string) (Lockfile, error) {
+	bytes, err := os.ReadFile(pth)
+	if err != nil {
+		return Lockfile{}, err
+	}
+
+	var lockfile Lockfile
+	if err := yaml.Unmarshal(bytes, &lockfile); err != nil {
+		return Lockfile{}, fmt.Errorf("failed to parse plugin lockfile (%s): %w", pth, err)
+	}
+	if err := lockfile.Validate(); err != nil {
+		return Lockfile{}, fmt.Errorf("invalid plugin lockfile (%s): %w", pth, err)
+	}
+	return lockfile, nil
+}
+
+// Write ...
+func (lockfile Lockfile) Write(pth string) error {
+	bytes, err := yaml.Marshal(lockfile)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(pth, bytes, 0644)
+}
+
+// Validate ...
+func (lockfile Lockfile) Validate() error {
+	for name, plugin := range lockfile.Plugins {
+		if plugin.Source == "" {
+			return fmt.Errorf("plugin (%s): missing source", name)
+		}
+		if plugin.ExecutableSHA256 != "" && !isSHA256Checksum(plugin.ExecutableSHA256) {
+			return fmt.Errorf("plugin (%s): invalid executable_sha256 (%s)", name, plugin.ExecutableSHA256)
+		}
+	}
+	return nil
+}
+
+// Names returns the locked plugin names in alphabetical order.
+func (lockfile Lockfile) Names() []string {
+	var names []string
+	for name := range lockfile.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// InstallLockedPlugin installs the plugin with the locked version and verifies its commit and executable checksum.
+// An already installed plugin matching the lock is kept.
+func InstallLockedPlugin(name string, locked LockedPlugin) (Plugin, error) {
+	route, found, err := ReadPluginRoute(name)
+	if err != nil {
+		return Plugin{}, err
+	}
+	if found && isRouteMatchingLock(route, locked) {
+		plugin, found, err := LoadPlugin(name)
+		if err == nil && found {
+			return plugin, nil
+		}
+	}
+
+	plugin, _, err := installPlugin(locked.Source, locked.Version, &locked)
+	if err != nil {
+		return Plugin{}, err
+	}
+	if plugin.Name != name {
+		return Plugin{}, fmt.Errorf("plugin is locked with name (%s), but the installed plugin is called (%s)", name, plugin.Name)
+	}
+	return plugin, nil
+}
+
+func isRouteMatchingLock(route PluginRoute, locked LockedPlugin) bool {
+	return route.Source == locked.Source &&
+		route.Version == locked.Version &&
+		route.CommitHash == locked.CommitHash &&
+		route.ExecutableSHA256 == locked.ExecutableSHA256
+}
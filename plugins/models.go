@@ -19,6 +19,8 @@ type PluginRoute struct {
 	TriggerEvent           string   `yaml:"trigger"`
 	TriggerEvents          []string `yaml:"triggers"`
 	LatestAvailableVersion string   `yaml:"latest_available_version"`
+	// ExecutableSHA256 is the checksum of the installed executable (or bitrise-plugin.sh), verified before the plugin is run.
+	ExecutableSHA256 string `yaml:"executable_sha256,omitempty"`
 }
 
 // PluginRouting ...
@@ -42,12 +44,20 @@ type Requirement struct {
 
 // Plugin ...
 type Plugin struct {
-	Name          string          `yaml:"name,omitempty"`
-	Description   string          `yaml:"description,omitempty"`
-	Executable    ExecutableModel `yaml:"executable,omitempty"`
+	Name        string          `yaml:"name,omitempty"`
+	Description string          `yaml:"description,omitempty"`
+	Executable  ExecutableModel `yaml:"executable,omitempty"`
+	// Checksums are the hex encoded SHA-256 checksums of the executables by platform.
+	Checksums ExecutableModel `yaml:"checksums,omitempty"`
+	// Signatures are the URLs of the base64 encoded ed25519 signatures of the executables by platform,
+	// verified with PublicKey.
+	Signatures    ExecutableModel `yaml:"signatures,omitempty"`
+	PublicKey     string          `yaml:"public_key,omitempty"`
 	TriggerEvent  string          `yaml:"trigger,omitempty"`
 	TriggerEvents []string        `yaml:"triggers,omitempty"`
 	Requirements  []Requirement   `yaml:"requirements,omitempty"`
+	// Protocol is empty for plugins run for every event, or jsonrpc for plugins started once per build.
+	Protocol string `yaml:"protocol,omitempty"`
 }
 
 // PluginInfoModel ...
@@ -118,6 +118,24 @@ func validatePlugin(plugin Plugin, pluginDefinitionPth, binPath string) error {
 		return errors.New("both osx and linux executable should be defined, or non of them")
 	}
 
+	if plugin.Protocol != "" && plugin.Protocol != ProtocolJSONRPC {
+		return fmt.Errorf("unsupported protocol: %s, supported protocols: %s", plugin.Protocol, ProtocolJSONRPC)
+	}
+
+	for _, checksum := range []string{plugin.Checksums.OSX, plugin.Checksums.OSXArm64, plugin.Checksums.Linux} {
+		if checksum != "" && !isSHA256Checksum(checksum) {
+			return fmt.Errorf("invalid SHA-256 checksum: %s", checksum)
+		}
+	}
+	if plugin.Signatures != (ExecutableModel{}) && plugin.PublicKey == "" {
+		return errors.New("signatures are defined without a public_key")
+	}
+	if plugin.PublicKey != "" {
+		if _, err := parsePublicKey(plugin.PublicKey); err != nil {
+			return err
+		}
+	}
+
 	if !linuxRemoteExecutable && !osxRemoteExecutable {
 		pluginDir := filepath.Dir(pluginDefinitionPth)
 		pluginScriptPth := filepath.Join(pluginDir, pluginScriptFileName)
@@ -176,13 +194,27 @@ func (plugin Plugin) String() string {
 
 // ExecutableURL ...
 func (plugin Plugin) ExecutableURL() string {
+	return plugin.Executable.forCurrentPlatform()
+}
+
+// ExecutableChecksum returns the declared SHA-256 checksum of the executable for the current platform.
+func (plugin Plugin) ExecutableChecksum() string {
+	return plugin.Checksums.forCurrentPlatform()
+}
+
+// ExecutableSignatureURL returns the URL of the signature of the executable for the current platform.
+func (plugin Plugin) ExecutableSignatureURL() string {
+	return plugin.Signatures.forCurrentPlatform()
+}
+
+func (executable ExecutableModel) forCurrentPlatform() string {
 	systemOS, err := tools.UnameGOOS()
 	if err != nil {
 		return ""
 	}
 
 	if systemOS == "Linux" {
-		return plugin.Executable.Linux
+		return executable.Linux
 	}
 
 	if systemOS == "Darwin" {
@@ -192,11 +224,11 @@ func (plugin Plugin) ExecutableURL() string {
 		}
 
 		if systemArch == "x86_64" {
-			return plugin.Executable.OSX
+			return executable.OSX
 		}
 
 		if systemArch == "arm64" {
-			return plugin.Executable.OSXArm64
+			return executable.OSXArm64
 		}
 	}
 	return ""
@@ -277,6 +309,9 @@ func (route PluginRoute) Validate() error {
 			return fmt.Errorf("invalid route: invalid version (%s)", route.Version)
 		}
 	}
+	if route.ExecutableSHA256 != "" && !isSHA256Checksum(route.ExecutableSHA256) {
+		return fmt.Errorf("invalid route: invalid executable_sha256 (%s)", route.ExecutableSHA256)
+	}
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/bitrise-io/go-utils/pathutil"
 )
@@ -22,8 +23,18 @@ const (
 
 	// PluginOutputEnvKey ...
 	PluginOutputEnvKey = "BITRISE_PLUGIN_OUTPUT"
+
+	// PluginEventTimeoutEnvKey overrides the time limit of a plugin invocation triggered by an event, e.g. 30s.
+	PluginEventTimeoutEnvKey = "BITRISE_PLUGIN_EVENT_TIMEOUT"
 )
 
+// DefaultEventTimeout is the time limit of a plugin invocation triggered by an event.
+const DefaultEventTimeout = 5 * time.Minute
+
+// AbortEventTimeout is the time limit of a plugin invocation triggered by the BuildWillAbort event.
+// The build is being stopped and the containers still have to be removed before the process gets killed.
+const AbortEventTimeout = 10 * time.Second
+
 const bitrisePluginPrefix = ":"
 
 const (
@@ -31,8 +42,13 @@ const (
 	TriggerMode PluginMode = "trigger"
 	// CommandMode ...
 	CommandMode PluginMode = "command"
+	// PersistentMode is the mode of plugins started once per build, receiving the events over JSON-RPC.
+	PersistentMode PluginMode = "persistent"
 )
 
+// ProtocolJSONRPC is the protocol of the persistent plugins.
+const ProtocolJSONRPC = "jsonrpc"
+
 // PluginMode ...
 type PluginMode string
 
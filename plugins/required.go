@@ -0,0 +1,116 @@
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/version"
+	ver "github.com/hashicorp/go-version"
+)
+
+// EnsureRequiredPlugin installs a plugin declared in the plugins section of the config if it is not installed yet,
+// or its installed version does not satisfy the required version, then validates its requirements and events.
+// It returns the plugin and whether it was installed.
+func EnsureRequiredPlugin(name string, required models.PluginModel) (Plugin, bool, error) {
+	constraints, err := required.VersionConstraints()
+	if err != nil {
+		return Plugin{}, false, fmt.Errorf("invalid version (%s): %w", required.Version, err)
+	}
+
+	route, found, err := ReadPluginRoute(name)
+	if err != nil {
+		return Plugin{}, false, err
+	}
+	if found && isSourceURIChanged(route.Source, required.Source) {
+		return Plugin{}, false, fmt.Errorf("plugin already installed with name (%s) from different source (%s)", name, route.Source)
+	}
+
+	installed := false
+	if !found || !isInstalledVersionSatisfying(route, constraints) {
+		versionTag := ""
+		if constraints != nil && !isLocalURL(required.Source) {
+			versionTag, err = latestSatisfyingVersion(required.Source, constraints)
+			if err != nil {
+				return Plugin{}, false, err
+			}
+		}
+
+		plugin, _, err := InstallPlugin(required.Source, versionTag)
+		if err != nil {
+			return Plugin{}, false, err
+		}
+		if plugin.Name != name {
+			return Plugin{}, false, fmt.Errorf("plugin is required with name (%s), but the plugin at (%s) is called (%s)", name, required.Source, plugin.Name)
+		}
+		installed = true
+	}
+
+	plugin, found, err := LoadPlugin(name)
+	if err != nil {
+		return Plugin{}, false, err
+	}
+	if !found {
+		return Plugin{}, false, fmt.Errorf("plugin (%s) exist in routing, but not found", name)
+	}
+
+	if err := validateRequiredPlugin(plugin, required); err != nil {
+		return Plugin{}, false, err
+	}
+
+	return plugin, installed, nil
+}
+
+func isInstalledVersionSatisfying(route PluginRoute, constraints ver.Constraints) bool {
+	if constraints == nil || isLocalURL(route.Source) {
+		return true
+	}
+	if route.Version == "" {
+		return false
+	}
+
+	installedVersion, err := ver.NewVersion(route.Version)
+	if err != nil {
+		return false
+	}
+	return constraints.Check(installedVersion)
+}
+
+func latestSatisfyingVersion(source string, constraints ver.Constraints) (string, error) {
+	tagList, err := gitRemoteURLTagList(source)
+	if err != nil {
+		return "", fmt.Errorf("could not get version tag list, error: %s", err)
+	}
+
+	versions := filterVersionTags(tagList)
+	sort.Sort(sort.Reverse(ByVersion(versions)))
+
+	for _, v := range versions {
+		if constraints.Check(v) {
+			return v.Original(), nil
+		}
+	}
+	return "", fmt.Errorf("no version of the plugin (%s) satisfies the required version (%s)", source, constraints)
+}
+
+func validateRequiredPlugin(plugin Plugin, required models.PluginModel) error {
+	if len(plugin.Requirements) > 0 {
+		currentVersionMap, err := version.ToolVersionMap(os.Args[0])
+		if err != nil {
+			return fmt.Errorf("check Bitrise tool versions: %s", err)
+		}
+		if err := validateRequirements(plugin.Requirements, currentVersionMap); err != nil {
+			return fmt.Errorf("validate requirements: %s", err)
+		}
+	}
+
+	for _, event := range required.Events {
+		if plugin.TriggerEvent != event && !slices.Contains(plugin.TriggerEvents, event) {
+			return fmt.Errorf("plugin (%s) is required for the event (%s), but it does not subscribe to it", plugin.Name, event)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	ver "github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureRequiredPlugin(t *testing.T) {
+	ForceInitPaths(t.TempDir())
+
+	srcDir := writeLocalPlugin(t, "reporter")
+	definition := "name: reporter\ntriggers:\n- StepDidFinish\n"
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, pluginDefinitionFileName), []byte(definition), 0644))
+
+	plugin, installed, err := EnsureRequiredPlugin("reporter", models.PluginModel{Source: srcDir, Events: []string{"StepDidFinish"}})
+	require.NoError(t, err)
+	require.True(t, installed)
+	require.Equal(t, "reporter", plugin.Name)
+
+	_, installed, err = EnsureRequiredPlugin("reporter", models.PluginModel{Source: srcDir})
+	require.NoError(t, err)
+	require.False(t, installed)
+
+	_, _, err = EnsureRequiredPlugin("reporter", models.PluginModel{Source: srcDir, Events: []string{"WorkflowWillStart"}})
+	require.EqualError(t, err, "plugin (reporter) is required for the event (WorkflowWillStart), but it does not subscribe to it")
+
+	_, _, err = EnsureRequiredPlugin("reporter", models.PluginModel{Source: t.TempDir()})
+	require.ErrorContains(t, err, "plugin already installed with name (reporter) from different source")
+
+	_, _, err = EnsureRequiredPlugin("analytics", models.PluginModel{Source: srcDir})
+	require.EqualError(t, err, "plugin is required with name (analytics), but the plugin at ("+srcDir+") is called (reporter)")
+}
+
+func TestIsInstalledVersionSatisfying(t *testing.T) {
+	constraints, err := ver.NewConstraint("~> 1.2")
+	require.NoError(t, err)
+
+	source := "https://github.com/bitrise-io/bitrise-plugins-step.git"
+	require.True(t, isInstalledVersionSatisfying(PluginRoute{Source: source, Version: "1.0.0"}, nil))
+	require.True(t, isInstalledVersionSatisfying(PluginRoute{Source: source, Version: "1.4.1"}, constraints))
+	require.False(t, isInstalledVersionSatisfying(PluginRoute{Source: source, Version: "2.0.0"}, constraints))
+	require.False(t, isInstalledVersionSatisfying(PluginRoute{Source: source}, constraints))
+	require.True(t, isInstalledVersionSatisfying(PluginRoute{Source: "/path/to/plugin"}, constraints))
+}
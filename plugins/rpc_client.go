@@ -0,0 +1,222 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/log/logwriter"
+)
+
+const (
+	jsonRPCVersion = "2.0"
+
+	rpcMethodEvent    = "event"
+	rpcMethodShutdown = "shutdown"
+)
+
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int            `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e rpcError) Error() string {
+	return fmt.Sprintf("%s (code: %d)", e.Message, e.Code)
+}
+
+type eventParams struct {
+	Name    TriggerEventName `json:"name"`
+	Payload json.RawMessage  `json:"payload"`
+}
+
+// rpcClient talks to a plugin process started once per build: the requests are written to its stdin
+// and the responses are read from its stdout, one JSON-RPC 2.0 message per line.
+type rpcClient struct {
+	name string
+
+	mu        sync.Mutex
+	cmd       *exec.Cmd
+	cancel    context.CancelFunc
+	stdin     io.WriteCloser
+	logWriter io.WriteCloser
+	responses chan rpcResponse
+	exited    chan struct{}
+	closed    chan struct{}
+	nextID    int
+	broken    error
+}
+
+func startRPCClient(plugin Plugin) (*rpcClient, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	pluginConfig := PluginConfig{PluginConfigPluginModeKey: string(PersistentMode)}
+	cmd, err := newPluginCommand(ctx, plugin, []string{}, pluginConfig)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	execCmd := cmd.GetCmd()
+
+	stdin, err := execCmd.StdinPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	stdout, err := execCmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The plugin logs to its stderr, its stdout is reserved for the protocol
+	logWriter := logwriter.NewLogWriter(log.NewLogger(log.GetGlobalLoggerOpts()))
+	execCmd.Stderr = logWriter
+
+	if err := execCmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start plugin: %w", err)
+	}
+
+	client := &rpcClient{
+		name:      plugin.Name,
+		cmd:       execCmd,
+		cancel:    cancel,
+		stdin:     stdin,
+		logWriter: logWriter,
+		responses: make(chan rpcResponse),
+		exited:    make(chan struct{}),
+		closed:    make(chan struct{}),
+	}
+	go client.readResponses(stdout)
+
+	return client, nil
+}
+
+func (c *rpcClient) readResponses(stdout io.Reader) {
+	defer close(c.exited)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		var response rpcResponse
+		if err := json.Unmarshal(line, &response); err != nil || response.JSONRPC != jsonRPCVersion || response.ID == nil {
+			log.Debugf("Plugin (%s) wrote a non JSON-RPC response line to its stdout: %s", c.name, line)
+			continue
+		}
+		select {
+		case c.responses <- response:
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+// call sends the request and waits for its response, a plugin not answering in time is stopped.
+func (c *rpcClient) call(method string, params interface{}, timeout time.Duration) (json.RawMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.broken != nil {
+		return nil, c.broken
+	}
+
+	c.nextID++
+	request := rpcRequest{JSONRPC: jsonRPCVersion, ID: c.nextID, Method: method, Params: params}
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.stdin.Write(append(requestBytes, '\n')); err != nil {
+		c.broken = fmt.Errorf("failed to send request: %w", err)
+		return nil, c.broken
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case response := <-c.responses:
+			if *response.ID != request.ID {
+				log.Debugf("Plugin (%s) responded to an unknown request (%d)", c.name, *response.ID)
+				continue
+			}
+			if response.Error != nil {
+				return nil, response.Error
+			}
+			return response.Result, nil
+		case <-c.exited:
+			c.broken = errors.New("plugin exited")
+			return nil, c.broken
+		case <-timer.C:
+			c.broken = fmt.Errorf("timed out after %s", timeout)
+			c.cancel()
+			return nil, c.broken
+		}
+	}
+}
+
+func (c *rpcClient) event(name TriggerEventName, payload []byte, timeout time.Duration) (EventResponse, error) {
+	result, err := c.call(rpcMethodEvent, eventParams{Name: name, Payload: payload}, timeout)
+	if err != nil {
+		return EventResponse{}, err
+	}
+
+	var response EventResponse
+	if len(result) > 0 && string(result) != "null" {
+		if err := json.Unmarshal(result, &response); err != nil {
+			return EventResponse{}, fmt.Errorf("invalid event response: %w", err)
+		}
+	}
+	return response, nil
+}
+
+// shutdown asks the plugin to exit, and stops it if it does not exit in time.
+func (c *rpcClient) shutdown(timeout time.Duration) error {
+	_, callErr := c.call(rpcMethodShutdown, nil, timeout)
+	close(c.closed)
+
+	if err := c.stdin.Close(); err != nil {
+		log.Debugf("Failed to close the stdin of plugin (%s): %s", c.name, err)
+	}
+
+	select {
+	case <-c.exited:
+	case <-time.After(timeout):
+		c.cancel()
+	}
+
+	waitErr := c.cmd.Wait()
+	c.cancel()
+	if err := c.logWriter.Close(); err != nil {
+		log.Warnf("Failed to close command output writer: %s", err)
+	}
+
+	if callErr != nil {
+		return callErr
+	}
+	return waitErr
+}
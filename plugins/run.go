@@ -2,8 +2,13 @@ package plugins
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/bitrise-io/bitrise/v2/configs"
 	"github.com/bitrise-io/bitrise/v2/log"
@@ -13,6 +18,8 @@ import (
 	"github.com/bitrise-io/go-utils/command"
 )
 
+const pluginWaitDelay = 5 * time.Second
+
 //=======================================
 // Util
 //=======================================
@@ -60,7 +67,12 @@ func strip(str string) string {
 func RunPluginByEvent(plugin Plugin, pluginConfig PluginConfig, input []byte) error {
 	pluginConfig[PluginConfigPluginModeKey] = string(TriggerMode)
 
-	return runPlugin(plugin, []string{}, pluginConfig, input, false)
+	timeout, err := eventTimeout(TriggerEventName(pluginConfig[PluginConfigTriggerEventKey]))
+	if err != nil {
+		return err
+	}
+
+	return runPlugin(plugin, []string{}, pluginConfig, input, false, timeout)
 }
 
 // RunPluginByCommand ...
@@ -69,7 +81,35 @@ func RunPluginByCommand(plugin Plugin, args []string) error {
 		PluginConfigPluginModeKey: string(CommandMode),
 	}
 
-	return runPlugin(plugin, args, pluginConfig, nil, true)
+	return runPlugin(plugin, args, pluginConfig, nil, true, 0)
+}
+
+// eventTimeout returns the time limit of a plugin invocation triggered by the event.
+func eventTimeout(name TriggerEventName) (time.Duration, error) {
+	timeout, err := configuredEventTimeout()
+	if err != nil {
+		return 0, err
+	}
+	if name == BuildWillAbort {
+		return min(timeout, AbortEventTimeout), nil
+	}
+	return timeout, nil
+}
+
+func configuredEventTimeout() (time.Duration, error) {
+	value := os.Getenv(PluginEventTimeoutEnvKey)
+	if value == "" {
+		return DefaultEventTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s (%s): %w", PluginEventTimeoutEnvKey, value, err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid %s (%s): should be positive", PluginEventTimeoutEnvKey, value)
+	}
+	return timeout, nil
 }
 
 // PrintPluginUpdateInfos ...
@@ -81,7 +121,56 @@ func PrintPluginUpdateInfos(newVersion string, plugin Plugin) {
 	log.Donef("$ bitrise plugin update %s", plugin.Name)
 }
 
-func runPlugin(plugin Plugin, args []string, envKeyValues PluginConfig, input []byte, fromCommand bool) error {
+func runPlugin(plugin Plugin, args []string, envKeyValues PluginConfig, input []byte, fromCommand bool, timeout time.Duration) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd, err := newPluginCommand(ctx, plugin, args, envKeyValues)
+	if err != nil {
+		return err
+	}
+
+	if len(input) > 0 {
+		cmd.SetStdin(bytes.NewReader(input))
+	} else {
+		cmd.SetStdin(os.Stdin)
+	}
+
+	if fromCommand {
+		cmd.SetStdout(os.Stdout)
+		cmd.SetStderr(os.Stderr)
+	} else {
+		logger := log.NewLogger(log.GetGlobalLoggerOpts())
+		logWriter := logwriter.NewLogWriter(logger)
+
+		cmd.SetStdout(logWriter)
+		cmd.SetStderr(logWriter)
+
+		defer func() {
+			if err := logWriter.Close(); err != nil {
+				log.Warnf("Failed to close command output writer: %s", err)
+			}
+		}()
+	}
+
+	cmdErr := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+
+	return cmdErr
+}
+
+// newPluginCommand creates the command running the plugin executable with the common plugin inputs.
+func newPluginCommand(ctx context.Context, plugin Plugin, args []string, envKeyValues PluginConfig) (*command.Model, error) {
+	if err := verifyInstalledPlugin(plugin.Name); err != nil {
+		return nil, err
+	}
+
 	if !configs.IsCIMode && configs.CheckIsPluginUpdateCheckRequired(plugin.Name) {
 		// Check for new version
 		log.Infof("Checking for plugin (%s) new version...", plugin.Name)
@@ -94,7 +183,7 @@ func runPlugin(plugin Plugin, args []string, envKeyValues PluginConfig, input []
 		}
 
 		if err := configs.SavePluginUpdateCheck(plugin.Name); err != nil {
-			return err
+			return nil, err
 		}
 
 		log.Print()
@@ -108,22 +197,18 @@ func runPlugin(plugin Plugin, args []string, envKeyValues PluginConfig, input []
 	// Run plugin executable
 	pluginExecutable, isBin, err := GetPluginExecutablePath(plugin.Name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var cmd *command.Model
-
+	var execCmd *exec.Cmd
 	if isBin {
-		cmd = command.New(pluginExecutable, args...)
-	} else {
-		cmd = command.New("bash", append([]string{pluginExecutable}, args...)...)
-	}
-
-	if len(input) > 0 {
-		cmd.SetStdin(bytes.NewReader(input))
+		execCmd = exec.CommandContext(ctx, pluginExecutable, args...)
 	} else {
-		cmd.SetStdin(os.Stdin)
+		execCmd = exec.CommandContext(ctx, "bash", append([]string{pluginExecutable}, args...)...)
 	}
+	// Processes started by the plugin could keep the output pipes open after it was killed
+	execCmd.WaitDelay = pluginWaitDelay
+	cmd := command.NewWithCmd(execCmd)
 
 	var envs []string
 	for key, value := range envKeyValues {
@@ -135,24 +220,5 @@ func runPlugin(plugin Plugin, args []string, envKeyValues PluginConfig, input []
 	// $ENV_1 will be printed (and not value).
 	cmd.AppendEnvs(envs...)
 
-	if fromCommand {
-		cmd.SetStdout(os.Stdout)
-		cmd.SetStderr(os.Stderr)
-	} else {
-		logger := log.NewLogger(log.GetGlobalLoggerOpts())
-		logWriter := logwriter.NewLogWriter(logger)
-
-		cmd.SetStdout(logWriter)
-		cmd.SetStderr(logWriter)
-
-		defer func() {
-			if err := logWriter.Close(); err != nil {
-				log.Warnf("Failed to close command output writer: %s", err)
-			}
-		}()
-	}
-
-	cmdErr := cmd.Run()
-
-	return cmdErr
+	return cmd, nil
 }
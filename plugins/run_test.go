@@ -2,6 +2,7 @@ package plugins
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -22,3 +23,33 @@ func TestStrip(t *testing.T) {
 	str = ""
 	require.Equal(t, "", strip(str))
 }
+
+func TestEventTimeout(t *testing.T) {
+	t.Setenv(PluginEventTimeoutEnvKey, "")
+	timeout, err := eventTimeout(StepWillStart)
+	require.NoError(t, err)
+	require.Equal(t, DefaultEventTimeout, timeout)
+
+	t.Setenv(PluginEventTimeoutEnvKey, "30s")
+	timeout, err = eventTimeout(StepWillStart)
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, timeout)
+
+	t.Setenv(PluginEventTimeoutEnvKey, "30")
+	_, err = eventTimeout(StepWillStart)
+	require.EqualError(t, err, `invalid BITRISE_PLUGIN_EVENT_TIMEOUT (30): time: missing unit in duration "30"`)
+
+	t.Setenv(PluginEventTimeoutEnvKey, "-1s")
+	_, err = eventTimeout(StepWillStart)
+	require.EqualError(t, err, "invalid BITRISE_PLUGIN_EVENT_TIMEOUT (-1s): should be positive")
+
+	t.Setenv(PluginEventTimeoutEnvKey, "")
+	timeout, err = eventTimeout(BuildWillAbort)
+	require.NoError(t, err)
+	require.Equal(t, AbortEventTimeout, timeout)
+
+	t.Setenv(PluginEventTimeoutEnvKey, "3s")
+	timeout, err = eventTimeout(BuildWillAbort)
+	require.NoError(t, err)
+	require.Equal(t, 3*time.Second, timeout)
+}
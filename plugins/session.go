@@ -0,0 +1,159 @@
+package plugins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+)
+
+// EventResponse is the reply of a persistent plugin to an event.
+type EventResponse struct {
+	// Veto skips the step, only considered for the StepWillStart event.
+	Veto       bool   `json:"veto,omitempty"`
+	VetoReason string `json:"veto_reason,omitempty"`
+	// Envs are exposed to the rest of the build.
+	Envs        map[string]string        `json:"envs,omitempty"`
+	Annotations []models.AnnotationModel `json:"annotations,omitempty"`
+}
+
+func (response *EventResponse) merge(pluginName string, other EventResponse) {
+	if other.Veto {
+		response.Veto = true
+		reason := other.VetoReason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		reasons := []string{}
+		if response.VetoReason != "" {
+			reasons = append(reasons, response.VetoReason)
+		}
+		response.VetoReason = strings.Join(append(reasons, fmt.Sprintf("%s: %s", pluginName, reason)), "; ")
+	}
+
+	if len(other.Envs) > 0 {
+		if response.Envs == nil {
+			response.Envs = map[string]string{}
+		}
+		maps.Copy(response.Envs, other.Envs)
+	}
+
+	for _, annotation := range other.Annotations {
+		annotation.Plugin = pluginName
+		response.Annotations = append(response.Annotations, annotation)
+	}
+}
+
+// Session delivers the events of a build to the plugins. Plugins with the jsonrpc protocol are started
+// at their first event and kept running until the session is closed, the others are run for every event.
+type Session struct {
+	mu          sync.Mutex
+	clients     map[string]*rpcClient
+	annotations []models.AnnotationModel
+}
+
+// NewSession ...
+func NewSession() *Session {
+	return &Session{clients: map[string]*rpcClient{}}
+}
+
+// TriggerEvent delivers the event to the plugins registered for it and returns their merged replies.
+func (s *Session) TriggerEvent(name TriggerEventName, payload interface{}) (EventResponse, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return EventResponse{}, err
+	}
+
+	plugins, err := LoadPlugins(string(name))
+	if err != nil {
+		return EventResponse{}, err
+	}
+
+	timeout, err := eventTimeout(name)
+	if err != nil {
+		return EventResponse{}, err
+	}
+
+	// A failing plugin does not prevent the others from receiving the event
+	var response EventResponse
+	var errs []error
+	for _, plugin := range plugins {
+		if plugin.Protocol != ProtocolJSONRPC {
+			pluginConfig := PluginConfig{
+				PluginConfigTriggerEventKey: string(name),
+			}
+			if err := RunPluginByEvent(plugin, pluginConfig, payloadBytes); err != nil {
+				errs = append(errs, fmt.Errorf("plugin (%s): %w", plugin.Name, err))
+			}
+			continue
+		}
+
+		client, err := s.client(plugin)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("plugin (%s): %w", plugin.Name, err))
+			continue
+		}
+
+		pluginResponse, err := client.event(name, payloadBytes, timeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("plugin (%s): %w", plugin.Name, err))
+			continue
+		}
+		response.merge(plugin.Name, pluginResponse)
+	}
+
+	s.mu.Lock()
+	s.annotations = append(s.annotations, response.Annotations...)
+	s.mu.Unlock()
+
+	return response, errors.Join(errs...)
+}
+
+// Annotations returns the annotations attached by the plugins so far.
+func (s *Session) Annotations() []models.AnnotationModel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.AnnotationModel{}, s.annotations...)
+}
+
+// Close shuts down the persistent plugins.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	clients := s.clients
+	s.clients = map[string]*rpcClient{}
+	s.mu.Unlock()
+
+	timeout, err := configuredEventTimeout()
+	if err != nil {
+		timeout = DefaultEventTimeout
+	}
+
+	var errs []error
+	for name, client := range clients {
+		if err := client.shutdown(timeout); err != nil {
+			errs = append(errs, fmt.Errorf("plugin (%s): %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Session) client(plugin Plugin) (*rpcClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if client, ok := s.clients[plugin.Name]; ok {
+		return client, nil
+	}
+
+	client, err := startRPCClient(plugin)
+	if err != nil {
+		return nil, err
+	}
+	s.clients[plugin.Name] = client
+	return client, nil
+}
@@ -0,0 +1,75 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/configs"
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/stretchr/testify/require"
+)
+
+const rpcPluginScript = `echo started >> "$BITRISE_PLUGIN_INPUT_DATA_DIR/starts"
+while IFS= read -r line; do
+  id=$(echo "$line" | sed -E 's/.*"id":([0-9]+).*/\1/')
+  case "$line" in
+    *'"method":"shutdown"'*)
+      echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"result\":{}}"
+      exit 0
+      ;;
+    *StepWillStart*)
+      echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"result\":{\"veto\":true,\"veto_reason\":\"not today\",\"envs\":{\"GATE\":\"closed\"},\"annotations\":[{\"level\":\"warning\",\"message\":\"step vetoed\"}]}}"
+      ;;
+    *)
+      echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"result\":{}}"
+      ;;
+  esac
+done`
+
+func TestSession(t *testing.T) {
+	ForceInitPaths(t.TempDir())
+	isCIMode := configs.IsCIMode
+	configs.IsCIMode = true
+	t.Cleanup(func() { configs.IsCIMode = isCIMode })
+	t.Setenv(PluginEventTimeoutEnvKey, "5s")
+
+	installScriptPlugin(t, "gate", []string{string(StepWillStart), string(StepDidFinish)}, rpcPluginScript)
+	definition, err := os.OpenFile(GetPluginDefinitionPath("gate"), os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = definition.WriteString("protocol: jsonrpc\n")
+	require.NoError(t, err)
+	require.NoError(t, definition.Close())
+
+	session := NewSession()
+
+	response, err := session.TriggerEvent(StepWillStart, map[string]string{"event_name": string(StepWillStart)})
+	require.NoError(t, err)
+	require.Equal(t, EventResponse{
+		Veto:        true,
+		VetoReason:  "gate: not today",
+		Envs:        map[string]string{"GATE": "closed"},
+		Annotations: []models.AnnotationModel{{Plugin: "gate", Level: "warning", Message: "step vetoed"}},
+	}, response)
+
+	response, err = session.TriggerEvent(StepDidFinish, map[string]string{"event_name": string(StepDidFinish)})
+	require.NoError(t, err)
+	require.Equal(t, EventResponse{}, response)
+
+	require.NoError(t, session.Close())
+	require.Equal(t, []models.AnnotationModel{{Plugin: "gate", Level: "warning", Message: "step vetoed"}}, session.Annotations())
+
+	starts, err := os.ReadFile(filepath.Join(GetPluginDataDir("gate"), "starts"))
+	require.NoError(t, err)
+	require.Equal(t, "started\n", string(starts))
+}
+
+func TestEventResponse_merge(t *testing.T) {
+	var response EventResponse
+	response.merge("first", EventResponse{Veto: true, Envs: map[string]string{"A": "1", "B": "1"}})
+	response.merge("second", EventResponse{Veto: true, VetoReason: "busy", Envs: map[string]string{"B": "2"}})
+
+	require.True(t, response.Veto)
+	require.Equal(t, "first: no reason given; second: busy", response.VetoReason)
+	require.Equal(t, map[string]string{"A": "1", "B": "2"}, response.Envs)
+}
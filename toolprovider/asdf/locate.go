@@ -0,0 +1,49 @@
+package asdf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// DataDirFromEnv returns the asdf data dir: $ASDF_DATA_DIR if set, ~/.asdf otherwise.
+// See https://asdf-vm.com/manage/configuration.html#asdf-data-dir
+func DataDirFromEnv(envs map[string]string) string {
+	if dataDir := envs["ASDF_DATA_DIR"]; dataDir != "" {
+		return dataDir
+	}
+	if dataDir := os.Getenv("ASDF_DATA_DIR"); dataDir != "" {
+		return dataDir
+	}
+	return filepath.Join(os.Getenv("HOME"), ".asdf")
+}
+
+// DataDir ...
+func (a *AsdfToolProvider) DataDir() string {
+	return DataDirFromEnv(a.ExecEnv.EnvVars)
+}
+
+// InstallPaths returns the install dir of the tool version and the plugin of the tool.
+func (a *AsdfToolProvider) InstallPaths(result provider.ToolInstallResult) ([]string, error) {
+	installPath := filepath.Join("installs", string(result.ToolName), result.ConcreteVersion)
+	if _, err := os.Stat(filepath.Join(a.DataDir(), installPath)); err != nil {
+		return nil, fmt.Errorf("%s %s install dir: %w", result.ToolName, result.ConcreteVersion, err)
+	}
+
+	paths := []string{installPath}
+	pluginPath := filepath.Join("plugins", string(result.ToolName))
+	if _, err := os.Stat(filepath.Join(a.DataDir(), pluginPath)); err == nil {
+		paths = append(paths, pluginPath)
+	}
+	return paths, nil
+}
+
+// UninstallTool ...
+func (a *AsdfToolProvider) UninstallTool(toolName provider.ToolID, version string) error {
+	if _, err := a.ExecEnv.RunAsdf("uninstall", string(toolName), version); err != nil {
+		return fmt.Errorf("asdf uninstall %s %s: %w", toolName, version, err)
+	}
+	return nil
+}
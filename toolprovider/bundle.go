@@ -0,0 +1,437 @@
+package toolprovider
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise/v2/analytics"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/asdf"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/mise"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/native"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// DefaultToolsBundleFileName is the archive written by `bitrise tools bundle export` by default.
+const DefaultToolsBundleFileName = "bitrise-tools-bundle.tar.gz"
+
+const (
+	toolsBundleFormatVersion = "1"
+	toolsBundleManifestName  = "manifest.yml"
+	// Archive dir of the installed tools, relative to the data dir of the provider
+	toolsBundleDataDir = "data"
+	// Archive dir of the provider itself (the mise binary), so that it does not need to be downloaded either
+	toolsBundleProviderDir = "provider"
+)
+
+// ToolsBundleManifest describes the tool installs packaged into a tools bundle archive.
+type ToolsBundleManifest struct {
+	FormatVersion   string `yaml:"format_version"`
+	Provider        string `yaml:"provider"`
+	ProviderVersion string `yaml:"provider_version,omitempty"`
+	// Platform is the GOOS-GOARCH pair the tools were installed on, the installs are not portable across platforms.
+	Platform string `yaml:"platform"`
+	// Tools are the requested versions and the concrete versions they resolved to, in the tools lockfile format.
+	Tools []LockedTool `yaml:"tools"`
+	// Paths are the bundled paths relative to the data dir of the provider.
+	Paths []string `yaml:"paths"`
+}
+
+// ExportToolsBundle installs the tools of the bitrise.yml tools section, including the tools declared by steps and step bundles,
+// and packages the installs into a tools bundle archive. The tools of every workflow are bundled if no workflow is given.
+// The bundle is imported with ImportToolsBundle on machines without network access.
+func ExportToolsBundle(config models.BitriseDataModel, tracker analytics.Tracker, workflowID string, lockfile *ToolsLockfile, providerOverride *string, archivePath string) (ToolsBundleManifest, error) {
+	toolRequests, err := getDeclarativeSetupToolRequests(config, workflowID)
+	if err != nil {
+		return ToolsBundleManifest{}, err
+	}
+	if len(toolRequests) == 0 {
+		return ToolsBundleManifest{}, errors.New("no tools are declared in the config")
+	}
+
+	providerID := declarativeSetupProvider(config, providerOverride)
+	requested := slices.Clone(toolRequests)
+	if lockfile != nil {
+		toolRequests = lockfile.apply(toolRequests, providerID, false)
+	}
+
+	// Fast install (Nix) installs live in the Nix store, only the regular installs can be bundled.
+	// extraEnvs=nil: this runs as a CLI subcommand from a user's shell, so secrets are already in the process env.
+	toolProvider, err := CreateProvider(providerID, false, false, nil)
+	if err != nil {
+		return ToolsBundleManifest{}, err
+	}
+	locator, ok := toolProvider.(provider.InstallLocator)
+	if !ok {
+		return ToolsBundleManifest{}, fmt.Errorf("tool provider %s does not support tools bundles", providerID)
+	}
+
+	_, results, err := installToolsWithProvider(toolRequests, providerID, toolProvider, tracker, false, time.Now())
+	if err != nil {
+		return ToolsBundleManifest{}, err
+	}
+
+	manifest := ToolsBundleManifest{
+		FormatVersion: toolsBundleFormatVersion,
+		Provider:      providerID,
+		Platform:      currentPlatform(),
+	}
+	var providerDir string
+	if providerID == "mise" {
+		manifest.ProviderVersion = mise.GetMiseVersion()
+		providerDir, _ = mise.Dirs(manifest.ProviderVersion)
+	}
+
+	lockedTools := ToolsLockfile{}
+	for i, result := range results {
+		lockedTools.add(LockedTool{
+			Tool:      string(requested[i].ToolName),
+			Requested: requestedVersionSpec(requested[i]),
+			Version:   result.ConcreteVersion,
+			Provider:  providerID,
+			PluginURL: pluginURLValue(requested[i].PluginURL),
+		})
+
+		paths, err := locator.InstallPaths(result)
+		if err != nil {
+			return ToolsBundleManifest{}, fmt.Errorf("locate %s %s: %w", result.ToolName, result.ConcreteVersion, err)
+		}
+		for _, pth := range paths {
+			if !slices.Contains(manifest.Paths, pth) {
+				manifest.Paths = append(manifest.Paths, pth)
+			}
+		}
+	}
+	manifest.Tools = lockedTools.Tools
+	slices.Sort(manifest.Paths)
+
+	if err := writeToolsBundle(archivePath, manifest, locator.DataDir(), providerDir); err != nil {
+		return ToolsBundleManifest{}, fmt.Errorf("write tools bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+// ImportToolsBundle unpacks a tools bundle into the data dir of its provider. The bundled tool versions are found
+// as already installed afterwards, so installing the versions of the returned manifest's tools needs no network access.
+func ImportToolsBundle(archivePath string) (ToolsBundleManifest, error) {
+	return extractToolsBundle(archivePath, toolsBundleTargetDirs)
+}
+
+// LockBundledTools pins the tool versions of an imported bundle in the lockfile at pth, keeping its other entries,
+// so that the declarative setup installs the bundled versions instead of resolving the requested versions remotely.
+func LockBundledTools(manifest ToolsBundleManifest, pth string) error {
+	lockfile, err := ReadToolsLockfile(pth)
+	if err != nil {
+		return err
+	}
+	if lockfile == nil {
+		lockfile = &ToolsLockfile{FormatVersion: toolsLockfileFormatVersion}
+	}
+	for _, tool := range manifest.Tools {
+		lockfile.add(tool)
+	}
+	return lockfile.Write(pth)
+}
+
+func toolsBundleTargetDirs(manifest ToolsBundleManifest) (dataDir, providerDir string, err error) {
+	switch manifest.Provider {
+	case "mise":
+		if manifest.ProviderVersion != mise.GetMiseVersion() {
+			log.Warnf("The tools bundle was created with mise %s, this Bitrise CLI uses mise %s: the bundled mise binary will not be used", manifest.ProviderVersion, mise.GetMiseVersion())
+		}
+		providerDir, _ = mise.Dirs(manifest.ProviderVersion)
+		_, dataDir = mise.Dirs(mise.GetMiseVersion())
+		return dataDir, providerDir, nil
+	case "asdf":
+		return asdf.DataDirFromEnv(nil), "", nil
+	case "native":
+		return native.DataDir(), "", nil
+	default:
+		return "", "", fmt.Errorf("unsupported tool provider: %s", manifest.Provider)
+	}
+}
+
+func currentPlatform() string {
+	return runtime.GOOS + "-" + runtime.GOARCH
+}
+
+func writeToolsBundle(archivePath string, manifest ToolsBundleManifest, dataDir, providerDir string) (err error) {
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	manifestContent, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name:    toolsBundleManifestName,
+		Mode:    0644,
+		Size:    int64(len(manifestContent)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	if _, err := tarWriter.Write(manifestContent); err != nil {
+		return err
+	}
+
+	for _, pth := range manifest.Paths {
+		if err := addToTar(tarWriter, filepath.Join(dataDir, pth), path.Join(toolsBundleDataDir, filepath.ToSlash(pth))); err != nil {
+			return err
+		}
+	}
+	if providerDir != "" {
+		if err := addToTar(tarWriter, providerDir, toolsBundleProviderDir); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// addToTar adds the file or dir tree at src to the archive as name. Symlinks are archived as symlinks, not followed.
+func addToTar(tarWriter *tar.Writer, src, name string) error {
+	return filepath.Walk(src, func(pth string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, pth)
+		if err != nil {
+			return err
+		}
+
+		var linkTarget string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if linkTarget, err = os.Readlink(pth); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, linkTarget)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(name, filepath.ToSlash(rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(pth)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				log.Warnf("Failed to close %s: %s", pth, err)
+			}
+		}()
+		_, err = io.Copy(tarWriter, f)
+		return err
+	})
+}
+
+func extractToolsBundle(archivePath string, targetDirs func(ToolsBundleManifest) (string, string, error)) (ToolsBundleManifest, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return ToolsBundleManifest{}, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Warnf("Failed to close %s: %s", archivePath, err)
+		}
+	}()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return ToolsBundleManifest{}, fmt.Errorf("read tools bundle %s: %w", archivePath, err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+
+	manifest, err := readToolsBundleManifest(tarReader)
+	if err != nil {
+		return ToolsBundleManifest{}, fmt.Errorf("read tools bundle %s: %w", archivePath, err)
+	}
+
+	dataDir, providerDir, err := targetDirs(manifest)
+	if err != nil {
+		return ToolsBundleManifest{}, err
+	}
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ToolsBundleManifest{}, fmt.Errorf("read tools bundle %s: %w", archivePath, err)
+		}
+
+		topDir, rel, ok := strings.Cut(strings.TrimSuffix(header.Name, "/"), "/")
+		var root, target string
+		switch {
+		case ok && topDir == toolsBundleDataDir:
+			root = dataDir
+			target, err = secureJoin(dataDir, rel)
+		case ok && topDir == toolsBundleProviderDir && providerDir != "":
+			root = providerDir
+			target, err = secureJoin(providerDir, rel)
+		case topDir == toolsBundleProviderDir && providerDir != "":
+			root, target = providerDir, providerDir
+		default:
+			err = fmt.Errorf("unexpected entry: %s", header.Name)
+		}
+		if err != nil {
+			return ToolsBundleManifest{}, fmt.Errorf("invalid tools bundle %s: %w", archivePath, err)
+		}
+
+		if err := extractTarEntry(tarReader, header, root, target); err != nil {
+			return ToolsBundleManifest{}, fmt.Errorf("extract %s: %w", header.Name, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+func readToolsBundleManifest(tarReader *tar.Reader) (ToolsBundleManifest, error) {
+	header, err := tarReader.Next()
+	if err != nil {
+		return ToolsBundleManifest{}, err
+	}
+	if header.Name != toolsBundleManifestName {
+		return ToolsBundleManifest{}, fmt.Errorf("%s is not the first entry of the archive", toolsBundleManifestName)
+	}
+
+	content, err := io.ReadAll(tarReader)
+	if err != nil {
+		return ToolsBundleManifest{}, err
+	}
+	var manifest ToolsBundleManifest
+	if err := yaml.Unmarshal(content, &manifest); err != nil {
+		return ToolsBundleManifest{}, fmt.Errorf("parse %s: %w", toolsBundleManifestName, err)
+	}
+
+	if manifest.FormatVersion != toolsBundleFormatVersion {
+		return ToolsBundleManifest{}, fmt.Errorf("unsupported tools bundle format version: %s", manifest.FormatVersion)
+	}
+	if manifest.Platform != currentPlatform() {
+		return ToolsBundleManifest{}, fmt.Errorf("the tools bundle was created on %s, it can not be used on %s", manifest.Platform, currentPlatform())
+	}
+	return manifest, nil
+}
+
+// secureJoin joins the archive entry path to the root, rejecting paths escaping the root.
+func secureJoin(root, rel string) (string, error) {
+	target := filepath.Join(root, filepath.FromSlash(rel))
+	if !isWithinDir(root, target) {
+		return "", fmt.Errorf("path escapes the target dir: %s", rel)
+	}
+	return target, nil
+}
+
+func isWithinDir(dir, pth string) bool {
+	dir = filepath.Clean(dir)
+	return pth == dir || strings.HasPrefix(pth, dir+string(os.PathSeparator))
+}
+
+// checkParentDirs rejects targets with a symlink parent dir below the root: entries would be written through the symlink,
+// possibly outside the root. The bundle is written without following symlinks, so a valid bundle never has such entries.
+func checkParentDirs(root, target string) error {
+	if target == filepath.Clean(root) {
+		return nil
+	}
+	rel, err := filepath.Rel(root, filepath.Dir(target))
+	if err != nil || rel == "." {
+		return err
+	}
+
+	pth := root
+	for _, dir := range strings.Split(rel, string(os.PathSeparator)) {
+		pth = filepath.Join(pth, dir)
+		info, err := os.Lstat(pth)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("parent dir is a symlink: %s", pth)
+		}
+	}
+	return nil
+}
+
+func extractTarEntry(tarReader *tar.Reader, header *tar.Header, root, target string) error {
+	if err := checkParentDirs(root, target); err != nil {
+		return err
+	}
+	mode := os.FileMode(header.Mode).Perm()
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, mode|0700)
+	case tar.TypeSymlink:
+		if filepath.IsAbs(header.Linkname) || !isWithinDir(root, filepath.Join(filepath.Dir(target), header.Linkname)) {
+			return fmt.Errorf("symlink points outside of the target dir: %s", header.Linkname)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return os.Symlink(header.Linkname, target)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		// The existing file might be read-only or a symlink, it is replaced instead of being written through
+		if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tarReader); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	default:
+		return fmt.Errorf("unsupported entry type: %c", header.Typeflag)
+	}
+}
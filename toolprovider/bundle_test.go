@@ -0,0 +1,201 @@
+package toolprovider
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolsBundle_WriteAndExtract(t *testing.T) {
+	srcDataDir := t.TempDir()
+	srcProviderDir := t.TempDir()
+	installDir := filepath.Join(srcDataDir, "installs", "nodejs", "20.18.1")
+	require.NoError(t, os.MkdirAll(filepath.Join(installDir, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(installDir, "bin", "node"), []byte("node"), 0755))
+	require.NoError(t, os.Symlink("node", filepath.Join(installDir, "bin", "nodejs")))
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDataDir, "installs", "ruby", "3.3.6"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(srcProviderDir, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcProviderDir, "bin", "mise"), []byte("mise"), 0755))
+
+	manifest := ToolsBundleManifest{
+		FormatVersion:   toolsBundleFormatVersion,
+		Provider:        "mise",
+		ProviderVersion: "v2025.1.0",
+		Platform:        currentPlatform(),
+		Tools:           []LockedTool{{Tool: "nodejs", Requested: "20:latest", Version: "20.18.1", Provider: "mise"}},
+		Paths:           []string{filepath.Join("installs", "nodejs", "20.18.1")},
+	}
+	archivePath := filepath.Join(t.TempDir(), DefaultToolsBundleFileName)
+	require.NoError(t, writeToolsBundle(archivePath, manifest, srcDataDir, srcProviderDir))
+
+	dstDataDir := t.TempDir()
+	dstProviderDir := filepath.Join(t.TempDir(), "mise")
+	// An outdated install is overwritten
+	require.NoError(t, os.MkdirAll(filepath.Join(dstDataDir, "installs", "nodejs", "20.18.1", "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dstDataDir, "installs", "nodejs", "20.18.1", "bin", "node"), []byte("old"), 0555))
+
+	imported, err := extractToolsBundle(archivePath, func(ToolsBundleManifest) (string, string, error) {
+		return dstDataDir, dstProviderDir, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, manifest, imported)
+
+	content, err := os.ReadFile(filepath.Join(dstDataDir, "installs", "nodejs", "20.18.1", "bin", "node"))
+	require.NoError(t, err)
+	require.Equal(t, "node", string(content))
+	info, err := os.Stat(filepath.Join(dstDataDir, "installs", "nodejs", "20.18.1", "bin", "node"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	linkTarget, err := os.Readlink(filepath.Join(dstDataDir, "installs", "nodejs", "20.18.1", "bin", "nodejs"))
+	require.NoError(t, err)
+	require.Equal(t, "node", linkTarget)
+
+	require.NoDirExists(t, filepath.Join(dstDataDir, "installs", "ruby"))
+	require.FileExists(t, filepath.Join(dstProviderDir, "bin", "mise"))
+}
+
+func TestToolsBundle_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest ToolsBundleManifest
+		entries  []*tar.Header
+		wantErr  string
+	}{
+		{
+			name:     "unsupported format version",
+			manifest: ToolsBundleManifest{FormatVersion: "2", Provider: "mise", Platform: currentPlatform()},
+			wantErr:  "unsupported tools bundle format version: 2",
+		},
+		{
+			name:     "other platform",
+			manifest: ToolsBundleManifest{FormatVersion: toolsBundleFormatVersion, Provider: "mise", Platform: "plan9-386"},
+			wantErr:  "the tools bundle was created on plan9-386, it can not be used on " + currentPlatform(),
+		},
+		{
+			name:     "path escaping the data dir",
+			manifest: ToolsBundleManifest{FormatVersion: toolsBundleFormatVersion, Provider: "mise", Platform: currentPlatform()},
+			entries:  []*tar.Header{fileEntry("data/../../evil")},
+			wantErr:  "path escapes the target dir: ../../evil",
+		},
+		{
+			name:     "unexpected entry",
+			manifest: ToolsBundleManifest{FormatVersion: toolsBundleFormatVersion, Provider: "mise", Platform: currentPlatform()},
+			entries:  []*tar.Header{fileEntry("evil")},
+			wantErr:  "unexpected entry: evil",
+		},
+		{
+			name:     "absolute symlink",
+			manifest: ToolsBundleManifest{FormatVersion: toolsBundleFormatVersion, Provider: "mise", Platform: currentPlatform()},
+			entries:  []*tar.Header{symlinkEntry("data/x", "/etc"), fileEntry("data/x/evil")},
+			wantErr:  "symlink points outside of the target dir: /etc",
+		},
+		{
+			name:     "symlink escaping the data dir",
+			manifest: ToolsBundleManifest{FormatVersion: toolsBundleFormatVersion, Provider: "mise", Platform: currentPlatform()},
+			entries:  []*tar.Header{symlinkEntry("data/installs/x", "../../.."), fileEntry("data/installs/x/evil")},
+			wantErr:  "symlink points outside of the target dir: ../../..",
+		},
+		{
+			name:     "write through a symlink",
+			manifest: ToolsBundleManifest{FormatVersion: toolsBundleFormatVersion, Provider: "mise", Platform: currentPlatform()},
+			entries:  []*tar.Header{symlinkEntry("data/x", "installs"), fileEntry("data/x/evil")},
+			wantErr:  "parent dir is a symlink",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archivePath := filepath.Join(t.TempDir(), DefaultToolsBundleFileName)
+			require.NoError(t, writeToolsBundle(archivePath, tt.manifest, t.TempDir(), ""))
+			appendTarEntries(t, archivePath, tt.entries...)
+
+			dataDir := t.TempDir()
+			_, err := extractToolsBundle(archivePath, func(ToolsBundleManifest) (string, string, error) {
+				return dataDir, "", nil
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+			require.NoFileExists(t, filepath.Join(filepath.Dir(dataDir), "evil"))
+		})
+	}
+}
+
+func TestToolsBundle_ExistingSymlink(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), DefaultToolsBundleFileName)
+	manifest := ToolsBundleManifest{FormatVersion: toolsBundleFormatVersion, Provider: "mise", Platform: currentPlatform()}
+	require.NoError(t, writeToolsBundle(archivePath, manifest, t.TempDir(), ""))
+	appendTarEntries(t, archivePath, fileEntry("data/installs/evil"))
+
+	outsideDir := t.TempDir()
+	dataDir := t.TempDir()
+	require.NoError(t, os.Symlink(outsideDir, filepath.Join(dataDir, "installs")))
+
+	_, err := extractToolsBundle(archivePath, func(ToolsBundleManifest) (string, string, error) {
+		return dataDir, "", nil
+	})
+	require.ErrorContains(t, err, "parent dir is a symlink")
+	require.NoFileExists(t, filepath.Join(outsideDir, "evil"))
+}
+
+func TestLockBundledTools(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), DefaultToolsLockfileName)
+	existing := ToolsLockfile{FormatVersion: toolsLockfileFormatVersion}
+	existing.add(LockedTool{Tool: "nodejs", Requested: "20:latest", Version: "20.18.0", Provider: "mise"})
+	existing.add(LockedTool{Tool: "ruby", Requested: "3.3:latest", Version: "3.3.5", Provider: "mise"})
+	require.NoError(t, existing.Write(pth))
+
+	manifest := ToolsBundleManifest{Tools: []LockedTool{{Tool: "nodejs", Requested: "20:latest", Version: "20.18.1", Provider: "mise"}}}
+	require.NoError(t, LockBundledTools(manifest, pth))
+
+	lockfile, err := ReadToolsLockfile(pth)
+	require.NoError(t, err)
+	require.Equal(t, []LockedTool{
+		{Tool: "nodejs", Requested: "20:latest", Version: "20.18.1", Provider: "mise"},
+		{Tool: "ruby", Requested: "3.3:latest", Version: "3.3.5", Provider: "mise"},
+	}, lockfile.Tools)
+}
+
+// appendTarEntries rewrites the archive with the extra entries after the existing ones.
+func appendTarEntries(t *testing.T, archivePath string, entries ...*tar.Header) {
+	src, err := os.Open(archivePath)
+	require.NoError(t, err)
+	gzipReader, err := gzip.NewReader(src)
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+
+	dstPath := archivePath + ".tmp"
+	dst, err := os.Create(dstPath)
+	require.NoError(t, err)
+	gzipWriter := gzip.NewWriter(dst)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			break
+		}
+		require.NoError(t, tarWriter.WriteHeader(header))
+		_, err = io.Copy(tarWriter, tarReader)
+		require.NoError(t, err)
+	}
+	for _, entry := range entries {
+		require.NoError(t, tarWriter.WriteHeader(entry))
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, dst.Close())
+	require.NoError(t, src.Close())
+	require.NoError(t, os.Rename(dstPath, archivePath))
+}
+
+func fileEntry(name string) *tar.Header {
+	return &tar.Header{Name: name, Mode: 0644, Typeflag: tar.TypeReg}
+}
+
+func symlinkEntry(name, linkname string) *tar.Header {
+	return &tar.Header{Name: name, Linkname: linkname, Mode: 0777, Typeflag: tar.TypeSymlink}
+}
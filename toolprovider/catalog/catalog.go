@@ -0,0 +1,295 @@
+// Package catalog is the catalog of the supported tools: their canonical names, aliases and per-tool settings.
+// The built-in catalog can be extended and overridden by a catalog file, see OverridePath.
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise/v2/configs"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// OverridePathEnvKey is the env var pointing to the catalog override file, e.g. one shared by an organization.
+const OverridePathEnvKey = "BITRISE_TOOLS_CATALOG"
+
+const formatVersion = "1"
+
+// Default resolutions of the plain versions (without the :latest or :installed suffix) of a tool.
+const (
+	ResolutionStrict    = "strict"
+	ResolutionLatest    = "latest"
+	ResolutionInstalled = "installed"
+)
+
+// Tool is a catalog entry.
+type Tool struct {
+	// Name is the canonical name of the tool, e.g. golang.
+	Name    string   `json:"name" yaml:"-"`
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	// DefaultResolution is how plain versions are resolved: strict (default), latest (as if suffixed with :latest)
+	// or installed (as if suffixed with :installed).
+	DefaultResolution string `json:"default_resolution,omitempty" yaml:"default_resolution,omitempty"`
+	// Providers are the tool providers able to install the tool, empty means any provider.
+	Providers []string `json:"providers,omitempty" yaml:"providers,omitempty"`
+	// ExtraPlugins maps tool providers to the plugin URL installing the tool, unless tool_config.extra_plugins sets one.
+	ExtraPlugins map[string]string `json:"extra_plugins,omitempty" yaml:"extra_plugins,omitempty"`
+}
+
+// catalogFile is the format of the catalog override file. Its tools are merged into the built-in catalog:
+// new tools are added, the set fields of existing tools override the built-in values.
+type catalogFile struct {
+	FormatVersion string                   `yaml:"format_version"`
+	Tools         map[provider.ToolID]Tool `yaml:"tools"`
+}
+
+var (
+	miseOnly          = []string{"mise"}
+	asdfAndMise       = []string{"asdf", "mise"}
+	asdfMiseAndNative = []string{"asdf", "mise", "native"}
+)
+
+// builtinTools is the source-of-truth list of supported tools, by canonical name (e.g. "golang" not "go").
+// It is predominantly composed of mise core tools, but can include others, such as flutter.
+// Providers list the asdf plugins vetted by Bitrise and the tools of the native provider.
+var builtinTools = map[provider.ToolID]Tool{
+	"bun":     {Providers: miseOnly},
+	"deno":    {Providers: miseOnly},
+	"elixir":  {Providers: miseOnly},
+	"erlang":  {Providers: miseOnly},
+	"flutter": {Providers: asdfAndMise},
+	"golang":  {Aliases: []string{"go"}, Providers: asdfMiseAndNative},
+	"java":    {Providers: []string{"mise", "native"}},
+	"nodejs":  {Aliases: []string{"node"}, Providers: asdfMiseAndNative},
+	"python":  {Providers: asdfAndMise},
+	"ruby":    {Providers: asdfAndMise},
+	"rust":    {Providers: miseOnly},
+	"swift":   {Providers: miseOnly},
+	"zig":     {Providers: miseOnly},
+}
+
+// Catalog is a validated set of tools.
+type Catalog struct {
+	tools map[provider.ToolID]Tool
+	// aliases maps the aliases to the canonical names
+	aliases map[provider.ToolID]provider.ToolID
+}
+
+var (
+	loadOnce   sync.Once
+	current    *Catalog
+	currentErr error
+)
+
+// Get returns the built-in catalog merged with the override file, loaded once per process.
+// If the override file is invalid, the built-in catalog is returned along with the error.
+func Get() (*Catalog, error) {
+	loadOnce.Do(func() {
+		current, currentErr = Load(OverridePath())
+		if currentErr != nil {
+			current = Builtin()
+		}
+	})
+	return current, currentErr
+}
+
+// Default returns the catalog of Get, ignoring the errors of the override file.
+// Use it where the error can not be reported, the override file is validated when the tools of a config are resolved
+// and by `bitrise tools catalog`.
+func Default() *Catalog {
+	c, _ := Get()
+	return c
+}
+
+// OverridePath returns the catalog override file: $BITRISE_TOOLS_CATALOG if set, ~/.bitrise/tools-catalog.yml otherwise.
+func OverridePath() string {
+	if pth := os.Getenv(OverridePathEnvKey); pth != "" {
+		return pth
+	}
+	return filepath.Join(configs.GetBitriseHomeDirPath(), "tools-catalog.yml")
+}
+
+// Builtin returns the built-in catalog.
+func Builtin() *Catalog {
+	c, err := newCatalog(builtinTools)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in tools catalog: %s", err))
+	}
+	return c
+}
+
+// Load merges the override file at pth into the built-in catalog. A missing override file is not an error.
+func Load(pth string) (*Catalog, error) {
+	content, err := os.ReadFile(pth)
+	if errors.Is(err, os.ErrNotExist) {
+		return Builtin(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read tools catalog %s: %w", pth, err)
+	}
+
+	c, err := parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("tools catalog %s: %w", pth, err)
+	}
+	return c, nil
+}
+
+func parse(content []byte) (*Catalog, error) {
+	var file catalogFile
+	if err := yaml.UnmarshalStrict(content, &file); err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	if file.FormatVersion != "" && file.FormatVersion != formatVersion {
+		return nil, fmt.Errorf("unsupported format version: %s", file.FormatVersion)
+	}
+
+	tools := map[provider.ToolID]Tool{}
+	for id, tool := range builtinTools {
+		tools[id] = tool
+	}
+	for id, override := range file.Tools {
+		tools[id] = mergeTool(tools[id], override)
+	}
+	return newCatalog(tools)
+}
+
+func mergeTool(base, override Tool) Tool {
+	merged := base
+	if override.Aliases != nil {
+		merged.Aliases = override.Aliases
+	}
+	if override.DefaultResolution != "" {
+		merged.DefaultResolution = override.DefaultResolution
+	}
+	if override.Providers != nil {
+		merged.Providers = override.Providers
+	}
+	if len(override.ExtraPlugins) > 0 {
+		merged.ExtraPlugins = map[string]string{}
+		for providerID, url := range base.ExtraPlugins {
+			merged.ExtraPlugins[providerID] = url
+		}
+		for providerID, url := range override.ExtraPlugins {
+			merged.ExtraPlugins[providerID] = url
+		}
+	}
+	return merged
+}
+
+func newCatalog(tools map[provider.ToolID]Tool) (*Catalog, error) {
+	c := &Catalog{
+		tools:   map[provider.ToolID]Tool{},
+		aliases: map[provider.ToolID]provider.ToolID{},
+	}
+
+	ids := make([]provider.ToolID, 0, len(tools))
+	for id := range tools {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		tool := tools[id]
+		tool.Name = string(id)
+		switch tool.DefaultResolution {
+		case "", ResolutionStrict, ResolutionLatest, ResolutionInstalled:
+		default:
+			return nil, fmt.Errorf("%s: invalid default_resolution: %s, should be one of: %v", id, tool.DefaultResolution, []string{ResolutionStrict, ResolutionLatest, ResolutionInstalled})
+		}
+		for providerID, url := range tool.ExtraPlugins {
+			if url == "" {
+				return nil, fmt.Errorf("%s: %s plugin URL is empty", id, providerID)
+			}
+		}
+		for _, aliasName := range tool.Aliases {
+			aliasID := provider.ToolID(aliasName)
+			if _, ok := tools[aliasID]; ok {
+				return nil, fmt.Errorf("%s: alias %s is the name of another tool", id, aliasName)
+			}
+			if other, ok := c.aliases[aliasID]; ok {
+				return nil, fmt.Errorf("%s: alias %s is already an alias of %s", id, aliasName, other)
+			}
+			c.aliases[aliasID] = id
+		}
+		c.tools[id] = tool
+	}
+
+	return c, nil
+}
+
+// Tools returns the tools sorted by name.
+func (c *Catalog) Tools() []Tool {
+	tools := make([]Tool, 0, len(c.tools))
+	for _, tool := range c.tools {
+		tools = append(tools, tool)
+	}
+	slices.SortFunc(tools, func(a, b Tool) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return tools
+}
+
+// CanonicalID resolves an alias to its canonical name. Canonical names and unknown tools are returned unchanged.
+func (c *Catalog) CanonicalID(id provider.ToolID) provider.ToolID {
+	if canonicalID, ok := c.aliases[id]; ok {
+		return canonicalID
+	}
+	return id
+}
+
+// Lookup returns the catalog entry of the tool, by canonical name or alias.
+func (c *Catalog) Lookup(id provider.ToolID) (Tool, bool) {
+	tool, ok := c.tools[c.CanonicalID(id)]
+	return tool, ok
+}
+
+// AliasesFor returns the aliases of the canonical tool ID, sorted. Returns nil if the tool has no aliases.
+func (c *Catalog) AliasesFor(canonical provider.ToolID) []provider.ToolID {
+	tool, ok := c.tools[canonical]
+	if !ok || len(tool.Aliases) == 0 {
+		return nil
+	}
+	aliases := make([]provider.ToolID, 0, len(tool.Aliases))
+	for _, aliasName := range tool.Aliases {
+		aliases = append(aliases, provider.ToolID(aliasName))
+	}
+	slices.Sort(aliases)
+	return aliases
+}
+
+// DefaultResolution returns the resolution strategy of the tool's plain versions.
+func (c *Catalog) DefaultResolution(id provider.ToolID) provider.ResolutionStrategy {
+	tool, _ := c.Lookup(id)
+	switch tool.DefaultResolution {
+	case ResolutionLatest:
+		return provider.ResolutionStrategyLatestReleased
+	case ResolutionInstalled:
+		return provider.ResolutionStrategyLatestInstalled
+	default:
+		return provider.ResolutionStrategyStrict
+	}
+}
+
+// SupportsProvider reports whether the provider is able to install the tool. Tools missing from the catalog
+// and tools without a providers list are not restricted.
+func (c *Catalog) SupportsProvider(id provider.ToolID, providerID string) bool {
+	tool, ok := c.Lookup(id)
+	if !ok || len(tool.Providers) == 0 {
+		return true
+	}
+	return slices.Contains(tool.Providers, providerID)
+}
+
+// PluginURL returns the plugin URL of the tool for the provider, empty if the catalog sets none.
+func (c *Catalog) PluginURL(id provider.ToolID, providerID string) string {
+	tool, _ := c.Lookup(id)
+	return tool.ExtraPlugins[providerID]
+}
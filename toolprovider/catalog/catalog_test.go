@@ -0,0 +1,127 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+func TestBuiltin(t *testing.T) {
+	c := Builtin()
+
+	require.Equal(t, provider.ToolID("golang"), c.CanonicalID("go"))
+	require.Equal(t, provider.ToolID("nodejs"), c.CanonicalID("node"))
+	require.Equal(t, provider.ToolID("custom-tool"), c.CanonicalID("custom-tool"))
+
+	tool, ok := c.Lookup("go")
+	require.True(t, ok)
+	require.Equal(t, "golang", tool.Name)
+	_, ok = c.Lookup("custom-tool")
+	require.False(t, ok)
+
+	require.True(t, c.SupportsProvider("java", "native"))
+	require.False(t, c.SupportsProvider("ruby", "native"))
+	// Tools missing from the catalog are not restricted
+	require.True(t, c.SupportsProvider("custom-tool", "asdf"))
+
+	require.Equal(t, provider.ResolutionStrategyStrict, c.DefaultResolution("golang"))
+	require.Empty(t, c.PluginURL("golang", "asdf"))
+}
+
+func TestLoad(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "tools-catalog.yml")
+	require.NoError(t, os.WriteFile(pth, []byte(`format_version: "1"
+tools:
+  golang:
+    aliases: [go, gol]
+  ruby:
+    default_resolution: installed
+  tuist:
+    providers: [asdf, mise]
+    default_resolution: latest
+    extra_plugins:
+      asdf: https://github.com/tuist/asdf-tuist.git
+`), 0644))
+
+	c, err := Load(pth)
+	require.NoError(t, err)
+
+	require.Equal(t, provider.ToolID("golang"), c.CanonicalID("gol"))
+	require.Equal(t, []provider.ToolID{"go", "gol"}, c.AliasesFor("golang"))
+
+	// Fields not set by the override file keep their built-in values
+	ruby, ok := c.Lookup("ruby")
+	require.True(t, ok)
+	require.Equal(t, Tool{Name: "ruby", DefaultResolution: ResolutionInstalled, Providers: []string{"asdf", "mise"}}, ruby)
+	require.Equal(t, provider.ResolutionStrategyLatestInstalled, c.DefaultResolution("ruby"))
+
+	require.Equal(t, provider.ResolutionStrategyLatestReleased, c.DefaultResolution("tuist"))
+	require.False(t, c.SupportsProvider("tuist", "native"))
+	require.Equal(t, "https://github.com/tuist/asdf-tuist.git", c.PluginURL("tuist", "asdf"))
+	require.Empty(t, c.PluginURL("tuist", "mise"))
+
+	var names []string
+	for _, tool := range c.Tools() {
+		names = append(names, tool.Name)
+	}
+	require.Contains(t, names, "tuist")
+	require.IsIncreasing(t, names)
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	c, err := Load(filepath.Join(t.TempDir(), "tools-catalog.yml"))
+	require.NoError(t, err)
+	require.Equal(t, Builtin(), c)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "alias of another tool",
+			content: "tools:\n  ruby:\n    aliases: [go]\n",
+			wantErr: "ruby: alias go is already an alias of golang",
+		},
+		{
+			name:    "alias is a tool name",
+			content: "tools:\n  golang:\n    aliases: [nodejs]\n",
+			wantErr: "golang: alias nodejs is the name of another tool",
+		},
+		{
+			name:    "invalid default resolution",
+			content: "tools:\n  ruby:\n    default_resolution: newest\n",
+			wantErr: "ruby: invalid default_resolution: newest, should be one of: [strict latest installed]",
+		},
+		{
+			name:    "empty plugin URL",
+			content: "tools:\n  tuist:\n    extra_plugins:\n      asdf: \"\"\n",
+			wantErr: "tuist: asdf plugin URL is empty",
+		},
+		{
+			name:    "unknown field",
+			content: "tools:\n  ruby:\n    alias: [rb]\n",
+			wantErr: "field alias not found",
+		},
+		{
+			name:    "unsupported format version",
+			content: "format_version: \"2\"\n",
+			wantErr: "unsupported format version: 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pth := filepath.Join(t.TempDir(), "tools-catalog.yml")
+			require.NoError(t, os.WriteFile(pth, []byte(tt.content), 0644))
+
+			_, err := Load(pth)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package toolprovider
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bitrise-io/bitrise/v2/analytics"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/alias"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// ToolSetupParallelismEnvKey limits the number of tools installed at the same time, 1 disables concurrent installation.
+const ToolSetupParallelismEnvKey = "BITRISE_TOOLSETUP_PARALLELISM"
+
+const defaultToolSetupParallelism = 4
+
+// toolSetupParallelism returns the number of tools to install at the same time, 1 if the provider
+// doesn't support concurrent installation or there is a single tool to install.
+func toolSetupParallelism(toolProvider provider.ToolProvider, toolRequests []provider.ToolRequest, silent bool) int {
+	installer, ok := toolProvider.(provider.ConcurrentInstaller)
+	if !ok || !installer.SupportsConcurrentInstall() {
+		return 1
+	}
+
+	parallelism := defaultToolSetupParallelism
+	if value := os.Getenv(ToolSetupParallelismEnvKey); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			if !silent {
+				log.Warnf("Invalid %s value: %s, using the default (%d)", ToolSetupParallelismEnvKey, value, defaultToolSetupParallelism)
+			}
+		} else {
+			parallelism = parsed
+		}
+	}
+
+	return min(parallelism, len(groupToolRequests(toolRequests)))
+}
+
+// groupToolRequests groups the indexes of the requests by tool. The versions of a tool share the tool's plugin,
+// so they are installed one after another.
+func groupToolRequests(toolRequests []provider.ToolRequest) [][]int {
+	var groups [][]int
+	groupIndexes := map[provider.ToolID]int{}
+	for i, toolRequest := range toolRequests {
+		toolID := alias.GetCanonicalToolID(toolRequest.ToolName)
+		groupIdx, ok := groupIndexes[toolID]
+		if !ok {
+			groupIdx = len(groups)
+			groupIndexes[toolID] = groupIdx
+			groups = append(groups, nil)
+		}
+		groups[groupIdx] = append(groups[groupIdx], i)
+	}
+	return groups
+}
+
+type toolInstallOutcome struct {
+	setup    toolSetupResult
+	duration time.Duration
+	err      error
+	done     bool
+}
+
+// installToolsConcurrently installs up to parallelism tools at the same time. The setup results keep the order of
+// the requests, so the activations and their PATH precedence are the same as with serial installation.
+// No new install is started after a failure.
+func installToolsConcurrently(
+	toolRequests []provider.ToolRequest,
+	providerID string,
+	toolProvider provider.ToolProvider,
+	tracker analytics.Tracker,
+	silent bool,
+	parallelism int,
+) ([]toolSetupResult, error) {
+	if !silent {
+		log.Debugf("[TOOLPROVIDER] Installing up to %d tools concurrently", parallelism)
+	}
+
+	outcomes := make([]toolInstallOutcome, len(toolRequests))
+
+	var (
+		printMu sync.Mutex
+		failMu  sync.Mutex
+		failed  bool
+		wg      sync.WaitGroup
+	)
+	hasFailed := func() bool {
+		failMu.Lock()
+		defer failMu.Unlock()
+		return failed
+	}
+
+	slots := make(chan struct{}, parallelism)
+	for _, group := range groupToolRequests(toolRequests) {
+		wg.Add(1)
+		go func(group []int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			for _, i := range group {
+				if hasFailed() {
+					return
+				}
+
+				toolStartTime := time.Now()
+				toolRequest := toolRequests[i]
+				toolRequest.ToolName = alias.GetCanonicalToolID(toolRequest.ToolName)
+
+				if !silent {
+					printMu.Lock()
+					printInstallStart(toolRequest)
+					printMu.Unlock()
+				}
+
+				result, err := toolProvider.InstallTool(toolRequest)
+				outcomes[i] = toolInstallOutcome{
+					setup:    toolSetupResult{request: toolRequest, result: result, startTime: toolStartTime},
+					duration: time.Since(toolStartTime),
+					err:      err,
+					done:     true,
+				}
+				if err != nil {
+					failMu.Lock()
+					failed = true
+					failMu.Unlock()
+					return
+				}
+
+				if !silent {
+					printMu.Lock()
+					printConcurrentInstallResult(toolRequest, result, outcomes[i].duration)
+					printMu.Unlock()
+				}
+			}
+		}(group)
+	}
+	wg.Wait()
+
+	var toolSetups []toolSetupResult
+	var firstErr error
+	for _, outcome := range outcomes {
+		if !outcome.done {
+			continue
+		}
+		if outcome.err != nil {
+			tracker.SendToolSetupEvent(providerID, outcome.setup.request, outcome.setup.result, false, outcome.duration)
+			err := toolInstallFailure(outcome.setup.request, outcome.err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		toolSetups = append(toolSetups, outcome.setup)
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if !silent {
+		log.Printf("")
+	}
+
+	return toolSetups, nil
+}
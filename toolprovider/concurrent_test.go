@@ -0,0 +1,124 @@
+package toolprovider
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+	"github.com/stretchr/testify/require"
+)
+
+type concurrentFakeToolProvider struct {
+	mu          sync.Mutex
+	inFlight    map[provider.ToolID]int
+	maxInFlight int
+	maxPerTool  int
+	failingTool provider.ToolID
+}
+
+func newConcurrentFakeToolProvider() *concurrentFakeToolProvider {
+	return &concurrentFakeToolProvider{inFlight: map[provider.ToolID]int{}}
+}
+
+func (f *concurrentFakeToolProvider) ID() string { return "fake" }
+
+func (f *concurrentFakeToolProvider) Bootstrap() error { return nil }
+
+func (f *concurrentFakeToolProvider) SupportsConcurrentInstall() bool { return true }
+
+func (f *concurrentFakeToolProvider) InstallTool(request provider.ToolRequest) (provider.ToolInstallResult, error) {
+	f.mu.Lock()
+	f.inFlight[request.ToolName]++
+	total := 0
+	for _, count := range f.inFlight {
+		total += count
+	}
+	f.maxInFlight = max(f.maxInFlight, total)
+	f.maxPerTool = max(f.maxPerTool, f.inFlight[request.ToolName])
+	f.mu.Unlock()
+
+	time.Sleep(50 * time.Millisecond)
+
+	f.mu.Lock()
+	f.inFlight[request.ToolName]--
+	f.mu.Unlock()
+
+	if request.ToolName == f.failingTool {
+		return provider.ToolInstallResult{}, errors.New("download failed")
+	}
+	return provider.ToolInstallResult{ToolName: request.ToolName, ConcreteVersion: request.UnparsedVersion}, nil
+}
+
+func (f *concurrentFakeToolProvider) ActivateEnv(result provider.ToolInstallResult) (provider.EnvironmentActivation, error) {
+	return provider.EnvironmentActivation{ContributedPaths: []string{"/tools/" + string(result.ToolName) + "/" + result.ConcreteVersion}}, nil
+}
+
+func (f *concurrentFakeToolProvider) ListReleasedVersions(provider.ToolID) ([]string, error) {
+	return nil, nil
+}
+
+var concurrentTestRequests = []provider.ToolRequest{
+	{ToolName: "nodejs", UnparsedVersion: "20.18.1"},
+	{ToolName: "ruby", UnparsedVersion: "3.3.6"},
+	{ToolName: "node", UnparsedVersion: "22.11.0"},
+	{ToolName: "python", UnparsedVersion: "3.12.7"},
+	{ToolName: "java", UnparsedVersion: "21.0.5"},
+}
+
+func TestInstallResolvedTools_concurrently(t *testing.T) {
+	t.Setenv(ToolSetupParallelismEnvKey, "3")
+	tracker := &capturingTracker{}
+	toolProvider := newConcurrentFakeToolProvider()
+
+	activations, results, err := installResolvedTools(concurrentTestRequests, "mise", toolProvider, tracker, true, time.Now())
+	require.NoError(t, err)
+
+	var paths []string
+	for _, activation := range activations {
+		paths = append(paths, activation.ContributedPaths...)
+	}
+	require.Equal(t, []string{
+		"/tools/nodejs/20.18.1",
+		"/tools/ruby/3.3.6",
+		"/tools/nodejs/22.11.0",
+		"/tools/python/3.12.7",
+		"/tools/java/21.0.5",
+	}, paths)
+	require.Len(t, results, 5)
+	require.Len(t, tracker.toolSetupCalls, 5)
+
+	require.Equal(t, 3, toolProvider.maxInFlight)
+	require.Equal(t, 1, toolProvider.maxPerTool)
+}
+
+func TestInstallResolvedTools_concurrentFailure(t *testing.T) {
+	tracker := &capturingTracker{}
+	toolProvider := newConcurrentFakeToolProvider()
+	toolProvider.failingTool = "ruby"
+
+	_, _, err := installResolvedTools(concurrentTestRequests, "mise", toolProvider, tracker, true, time.Now())
+	require.EqualError(t, err, "install ruby 3.3.6: download failed")
+
+	var failed []provider.ToolID
+	for _, call := range tracker.toolSetupCalls {
+		require.False(t, call.isSuccessful)
+		failed = append(failed, call.request.ToolName)
+	}
+	require.Equal(t, []provider.ToolID{"ruby"}, failed)
+}
+
+func TestToolSetupParallelism(t *testing.T) {
+	concurrent := newConcurrentFakeToolProvider()
+
+	require.Equal(t, 1, toolSetupParallelism(fakeToolProvider{}, concurrentTestRequests, true))
+	require.Equal(t, 4, toolSetupParallelism(concurrent, concurrentTestRequests, true))
+	require.Equal(t, 1, toolSetupParallelism(concurrent, concurrentTestRequests[:1], true))
+
+	t.Setenv(ToolSetupParallelismEnvKey, "1")
+	require.Equal(t, 1, toolSetupParallelism(concurrent, concurrentTestRequests, true))
+
+	t.Setenv(ToolSetupParallelismEnvKey, "invalid")
+	require.Equal(t, 4, toolSetupParallelism(concurrent, concurrentTestRequests, true))
+}
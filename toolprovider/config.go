@@ -2,9 +2,12 @@ package toolprovider
 
 import (
 	"fmt"
+	"maps"
 	"runtime"
+	"slices"
 
 	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/catalog"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
 )
 
@@ -20,7 +23,8 @@ func getToolRequests(config models.BitriseDataModel, workflowID string) ([]provi
 		workflowTools = workflow.Tools
 	}
 
-	mergedTools := globalTools
+	// Cloned, the workflow overrides must not leak into the global tools of the config
+	mergedTools := maps.Clone(globalTools)
 	if mergedTools == nil {
 		mergedTools = workflowTools
 	}
@@ -29,16 +33,35 @@ func getToolRequests(config models.BitriseDataModel, workflowID string) ([]provi
 	}
 
 	for toolID, toolVersion := range workflowTools {
-		if toolVersion == "unset" {
+		if toolVersion == models.ToolVersionUnset {
 			delete(mergedTools, toolID)
 		} else {
 			mergedTools[toolID] = toolVersion
 		}
 	}
 
+	return toolRequestsFromTools(config, mergedTools)
+}
+
+// toolRequestsFromTools converts the tools to requests, using the extra plugins of the config's tool_config.
+// The tools catalog is loaded here, so an invalid catalog override file fails the configs declaring tools only.
+func toolRequestsFromTools(config models.BitriseDataModel, tools models.ToolsModel) ([]provider.ToolRequest, error) {
+	toolCatalog, err := catalog.Get()
+	if err != nil {
+		return nil, err
+	}
+
 	var toolRequests []provider.ToolRequest
-	for toolID, toolVersion := range mergedTools {
-		v, strategy, err := ParseVersionString(toolVersion)
+	declaredAs := map[provider.ToolID]models.ToolID{}
+	for _, toolID := range slices.Sorted(maps.Keys(tools)) {
+		toolVersion := tools[toolID]
+		canonicalID := toolCatalog.CanonicalID(provider.ToolID(toolID))
+		if other, ok := declaredAs[canonicalID]; ok {
+			return nil, fmt.Errorf("%s and %s are the same tool (%s), declare it only once", other, toolID, canonicalID)
+		}
+		declaredAs[canonicalID] = toolID
+
+		v, strategy, err := ParseToolVersionString(provider.ToolID(toolID), toolVersion)
 		if err != nil {
 			return nil, fmt.Errorf("parse %s version: %w", toolID, err)
 		}
@@ -412,6 +412,23 @@ func TestGetToolRequests(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "workflow tool declared by the alias of a global tool",
+			config: models.BitriseDataModel{
+				Tools: models.ToolsModel{
+					"golang": "1.23",
+				},
+				Workflows: map[string]models.WorkflowModel{
+					"test": {
+						Tools: models.ToolsModel{
+							"go": "1.22",
+						},
+					},
+				},
+			},
+			workflowID: "test",
+			wantErr:    true,
+		},
 	}
 
 	for _, tt := range tests {
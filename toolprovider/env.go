@@ -11,8 +11,10 @@ import (
 )
 
 func ConvertToEnvMap(activations []provider.EnvironmentActivation) map[string]string {
-	pathValue := os.Getenv("PATH")
+	return convertToEnvMap(activations, os.Getenv("PATH"))
+}
 
+func convertToEnvMap(activations []provider.EnvironmentActivation, pathValue string) map[string]string {
 	envMap := make(map[string]string)
 	for _, activation := range activations {
 		for k, v := range activation.ContributedEnvVars {
@@ -20,15 +22,7 @@ func ConvertToEnvMap(activations []provider.EnvironmentActivation) map[string]st
 		}
 	}
 
-	var newPathEntries []string
-	for _, act := range activations {
-		for _, p := range act.ContributedPaths {
-			if p != "" {
-				newPathEntries = append(newPathEntries, p)
-			}
-		}
-	}
-
+	newPathEntries := ContributedPaths(activations)
 	if len(newPathEntries) > 0 {
 		newPath := prependPaths(pathValue, newPathEntries)
 		if newPath != "" {
@@ -39,8 +33,25 @@ func ConvertToEnvMap(activations []provider.EnvironmentActivation) map[string]st
 	return envMap
 }
 
+// ContributedPaths returns the PATH entries of the activations in precedence order, without the current PATH.
+func ContributedPaths(activations []provider.EnvironmentActivation) []string {
+	var paths []string
+	for _, act := range activations {
+		for _, p := range act.ContributedPaths {
+			if p != "" {
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths
+}
+
 func ConvertToEnvmanEnvs(activations []provider.EnvironmentActivation) []envmanModels.EnvironmentItemModel {
-	envMap := ConvertToEnvMap(activations)
+	return convertToEnvmanEnvs(activations, os.Getenv("PATH"))
+}
+
+func convertToEnvmanEnvs(activations []provider.EnvironmentActivation, pathValue string) []envmanModels.EnvironmentItemModel {
+	envMap := convertToEnvMap(activations, pathValue)
 
 	envs := make([]envmanModels.EnvironmentItemModel, 0, len(envMap))
 	for k, v := range envMap {
@@ -9,6 +9,7 @@ import (
 	"github.com/bitrise-io/bitrise/v2/toolprovider/asdf"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/asdf/execenv"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/mise"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/native"
 )
 
 // InstalledTool represents an installed tool with its versions.
@@ -31,6 +32,8 @@ func ListInstalledTools(providerName string, activeOnly, silent bool) ([]Install
 		return listAsdfTools(activeOnly)
 	case "mise":
 		return listMiseTools(activeOnly, silent)
+	case "native":
+		return listNativeTools(activeOnly, silent)
 	default:
 		return nil, fmt.Errorf("unsupported tool provider: %s", providerName)
 	}
@@ -212,6 +215,31 @@ func listMiseTools(activeOnly bool, silent bool) ([]InstalledTool, error) {
 	return tools, nil
 }
 
+// listNativeTools lists the tool versions installed by the native provider.
+// The native provider has no notion of active versions outside of a tool setup, so no tool is active.
+func listNativeTools(activeOnly bool, silent bool) ([]InstalledTool, error) {
+	tools := []InstalledTool{}
+	if activeOnly {
+		return tools, nil
+	}
+
+	nativeProvider, err := native.NewToolProvider(native.DataDir(), native.DefaultReleaseIndexURLs, silent)
+	if err != nil {
+		return nil, fmt.Errorf("create native provider: %w", err)
+	}
+
+	for _, toolName := range nativeProvider.SupportedTools() {
+		versions, err := nativeProvider.InstalledVersions(toolName)
+		if err != nil {
+			return nil, fmt.Errorf("list installed %s versions: %w", toolName, err)
+		}
+		if len(versions) > 0 {
+			tools = append(tools, InstalledTool{Name: string(toolName), InstalledVersions: versions})
+		}
+	}
+	return tools, nil
+}
+
 func parseLines(output string) []string {
 	var lines []string
 	for _, line := range strings.Split(output, "\n") {
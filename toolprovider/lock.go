@@ -0,0 +1,254 @@
+package toolprovider
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise/v2/analytics"
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// DefaultToolsLockfileName is the tools lockfile read by `bitrise run` and `bitrise tools setup` from the working directory.
+const DefaultToolsLockfileName = "bitrise-tools.lock"
+
+const toolsLockfileFormatVersion = "1"
+
+// ToolsLockfile pins the versions the tools of the bitrise.yml tools section were resolved to,
+// so that every build installs the same versions until the lockfile is updated with `bitrise tools lock`.
+type ToolsLockfile struct {
+	FormatVersion string       `yaml:"format_version"`
+	Tools         []LockedTool `yaml:"tools"`
+}
+
+// LockedTool is the concrete version a requested tool version resolved to with the given provider.
+type LockedTool struct {
+	Tool string `yaml:"tool"`
+	// Requested is the version as declared in the bitrise.yml, e.g. 22:latest.
+	Requested string `yaml:"requested"`
+	Version   string `yaml:"version"`
+	Provider  string `yaml:"provider"`
+	PluginURL string `yaml:"plugin_url,omitempty"`
+}
+
+// ReadToolsLockfile reads the lockfile, a missing lockfile is returned as nil.
+func ReadToolsLockfile(pth string) (*ToolsLockfile, error) {
+	content, err := os.ReadFile(pth)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read tools lockfile: %w", err)
+	}
+
+	var lockfile ToolsLockfile
+	if err := yaml.Unmarshal(content, &lockfile); err != nil {
+		return nil, fmt.Errorf("parse tools lockfile %s: %w", pth, err)
+	}
+	if lockfile.FormatVersion != toolsLockfileFormatVersion {
+		return nil, fmt.Errorf("unsupported tools lockfile format version: %s", lockfile.FormatVersion)
+	}
+	for _, tool := range lockfile.Tools {
+		if tool.Tool == "" || tool.Version == "" || tool.Provider == "" {
+			return nil, fmt.Errorf("invalid tools lockfile %s: tool, version and provider are required", pth)
+		}
+	}
+
+	return &lockfile, nil
+}
+
+// Write ...
+func (l ToolsLockfile) Write(pth string) error {
+	content, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	content = append([]byte("# Generated by `bitrise tools lock`, do not edit.\n"), content...)
+	return os.WriteFile(pth, content, 0644)
+}
+
+func (l ToolsLockfile) lookup(request provider.ToolRequest, providerID string) (LockedTool, bool) {
+	for _, tool := range l.Tools {
+		if tool.Tool == string(request.ToolName) &&
+			tool.Requested == requestedVersionSpec(request) &&
+			tool.Provider == providerID &&
+			tool.PluginURL == pluginURLValue(request.PluginURL) {
+			return tool, true
+		}
+	}
+	return LockedTool{}, false
+}
+
+func (l *ToolsLockfile) add(tool LockedTool) {
+	l.Tools = slices.DeleteFunc(l.Tools, func(existing LockedTool) bool {
+		return existing.Tool == tool.Tool && existing.Requested == tool.Requested && existing.Provider == tool.Provider && existing.PluginURL == tool.PluginURL
+	})
+	l.Tools = append(l.Tools, tool)
+	slices.SortFunc(l.Tools, func(a, b LockedTool) int {
+		return strings.Compare(a.Tool+" "+a.Requested, b.Tool+" "+b.Requested)
+	})
+}
+
+// Merge returns the lockfile extended with the tools of other, its entries replace the ones pinning the same requests.
+func (l ToolsLockfile) Merge(other ToolsLockfile) ToolsLockfile {
+	merged := ToolsLockfile{FormatVersion: toolsLockfileFormatVersion, Tools: slices.Clone(l.Tools)}
+	for _, tool := range other.Tools {
+		merged.add(tool)
+	}
+	return merged
+}
+
+// apply replaces the requests with the locked concrete versions. Requests missing from the lockfile are resolved as usual.
+func (l ToolsLockfile) apply(toolRequests []provider.ToolRequest, providerID string, silent bool) []provider.ToolRequest {
+	locked := make([]provider.ToolRequest, 0, len(toolRequests))
+	for _, request := range toolRequests {
+		tool, ok := l.lookup(request, providerID)
+		if !ok {
+			if !silent {
+				log.Warnf("%s %s is not in the tools lockfile, run `bitrise tools lock` to pin its version", request.ToolName, requestedVersionSpec(request))
+			}
+			locked = append(locked, request)
+			continue
+		}
+
+		if !silent {
+			log.Debugf("[TOOLPROVIDER] Using locked version %s for %s %s", tool.Version, request.ToolName, tool.Requested)
+		}
+		locked = append(locked, provider.ToolRequest{
+			ToolName:           request.ToolName,
+			UnparsedVersion:    tool.Version,
+			ResolutionStrategy: provider.ResolutionStrategyStrict,
+			PluginURL:          request.PluginURL,
+		})
+	}
+	return locked
+}
+
+// LockDeclarativeSetup installs the tools of the bitrise.yml tools section, including the tools declared by steps and step bundles,
+// and returns the lockfile pinning the versions they resolved to. The tools of every workflow are locked if no workflow is given.
+func LockDeclarativeSetup(config models.BitriseDataModel, tracker analytics.Tracker, workflowID string, silent bool, providerOverride *string, fastInstallOverride *bool) (ToolsLockfile, error) {
+	toolRequests, err := getDeclarativeSetupToolRequests(config, workflowID)
+	if err != nil {
+		return ToolsLockfile{}, err
+	}
+
+	lockfile := ToolsLockfile{FormatVersion: toolsLockfileFormatVersion, Tools: []LockedTool{}}
+	if len(toolRequests) == 0 {
+		return lockfile, nil
+	}
+
+	providerID := declarativeSetupProvider(config, providerOverride)
+	useFastInstall := declarativeSetupFastInstall(config, fastInstallOverride)
+
+	// installTools resolves the constraints in place, the original requests are the keys of the lockfile
+	requested := slices.Clone(toolRequests)
+	// extraEnvs=nil: this runs as a CLI subcommand from a user's shell, so secrets are already in the process env.
+	_, results, err := installTools(toolRequests, providerID, useFastInstall, tracker, silent, nil)
+	if err != nil {
+		return ToolsLockfile{}, err
+	}
+
+	for i, result := range results {
+		lockfile.add(LockedTool{
+			Tool:      string(requested[i].ToolName),
+			Requested: requestedVersionSpec(requested[i]),
+			Version:   result.ConcreteVersion,
+			Provider:  providerID,
+			PluginURL: pluginURLValue(requested[i].PluginURL),
+		})
+	}
+
+	return lockfile, nil
+}
+
+// getDeclarativeSetupToolRequests returns the distinct tool requests of the workflow, including the tools declared by its steps.
+// The tools of every workflow are returned if no workflow is given.
+func getDeclarativeSetupToolRequests(config models.BitriseDataModel, workflowID string) ([]provider.ToolRequest, error) {
+	workflowIDs := []string{workflowID}
+	if workflowID == "" {
+		workflowIDs = append(workflowIDs, sortedWorkflowIDs(config)...)
+	}
+
+	var toolRequests []provider.ToolRequest
+	for _, id := range workflowIDs {
+		requests, err := getToolRequests(config, id)
+		if err != nil {
+			return nil, fmt.Errorf("tools: %w", err)
+		}
+		if id != "" {
+			stepRequests, err := getStepToolRequests(config, id)
+			if err != nil {
+				return nil, fmt.Errorf("tools: %w", err)
+			}
+			requests = append(requests, stepRequests...)
+		}
+		for _, request := range requests {
+			if !slices.ContainsFunc(toolRequests, func(r provider.ToolRequest) bool { return isSameToolRequest(r, request) }) {
+				toolRequests = append(toolRequests, request)
+			}
+		}
+	}
+
+	return toolRequests, nil
+}
+
+// getStepToolRequests returns the requests of the tools declared by the steps and step bundles the workflow runs.
+func getStepToolRequests(config models.BitriseDataModel, workflowID string) ([]provider.ToolRequest, error) {
+	plan, err := models.NewWorkflowRunPlanBuilder(
+		config.Workflows, config.StepBundles, config.Containers, config.Services,
+		func() string { return "" },
+	).Build(models.WorkflowRunModes{}, workflowID)
+	if err != nil {
+		return nil, fmt.Errorf("workflow %s: %w", workflowID, err)
+	}
+
+	var toolRequests []provider.ToolRequest
+	for _, workflowPlan := range plan.ExecutionPlan {
+		for _, stepPlan := range workflowPlan.Steps {
+			requests, err := toolRequestsFromTools(config, stepPlan.Tools)
+			if err != nil {
+				return nil, fmt.Errorf("step %s: %w", stepPlan.StepID, err)
+			}
+			toolRequests = append(toolRequests, requests...)
+		}
+	}
+	return toolRequests, nil
+}
+
+func sortedWorkflowIDs(config models.BitriseDataModel) []string {
+	ids := make([]string, 0, len(config.Workflows))
+	for id := range config.Workflows {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func isSameToolRequest(a, b provider.ToolRequest) bool {
+	return a.ToolName == b.ToolName && requestedVersionSpec(a) == requestedVersionSpec(b) && pluginURLValue(a.PluginURL) == pluginURLValue(b.PluginURL)
+}
+
+// requestedVersionSpec is the inverse of ParseVersionString.
+func requestedVersionSpec(request provider.ToolRequest) string {
+	switch request.ResolutionStrategy {
+	case provider.ResolutionStrategyLatestReleased:
+		return request.UnparsedVersion + ":latest"
+	case provider.ResolutionStrategyLatestInstalled:
+		return request.UnparsedVersion + ":installed"
+	default:
+		return request.UnparsedVersion
+	}
+}
+
+func pluginURLValue(pluginURL *string) string {
+	if pluginURL == nil {
+		return ""
+	}
+	return *pluginURL
+}
@@ -0,0 +1,120 @@
+package toolprovider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolsLockfile_WriteAndRead(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), DefaultToolsLockfileName)
+
+	lockfile, err := ReadToolsLockfile(pth)
+	require.NoError(t, err)
+	require.Nil(t, lockfile)
+
+	expected := ToolsLockfile{FormatVersion: toolsLockfileFormatVersion}
+	expected.add(LockedTool{Tool: "ruby", Requested: "3.3:latest", Version: "3.3.6", Provider: "mise"})
+	expected.add(LockedTool{Tool: "nodejs", Requested: "^20.1", Version: "20.18.0", Provider: "mise"})
+	expected.add(LockedTool{Tool: "nodejs", Requested: "^20.1", Version: "20.18.1", Provider: "mise"})
+	require.NoError(t, expected.Write(pth))
+
+	lockfile, err = ReadToolsLockfile(pth)
+	require.NoError(t, err)
+	require.Equal(t, []LockedTool{
+		{Tool: "nodejs", Requested: "^20.1", Version: "20.18.1", Provider: "mise"},
+		{Tool: "ruby", Requested: "3.3:latest", Version: "3.3.6", Provider: "mise"},
+	}, lockfile.Tools)
+
+	require.NoError(t, os.WriteFile(pth, []byte("format_version: \"2\"\n"), 0644))
+	_, err = ReadToolsLockfile(pth)
+	require.EqualError(t, err, "unsupported tools lockfile format version: 2")
+
+	require.NoError(t, os.WriteFile(pth, []byte("format_version: \"1\"\ntools:\n- tool: ruby\n  provider: mise\n"), 0644))
+	_, err = ReadToolsLockfile(pth)
+	require.EqualError(t, err, "invalid tools lockfile "+pth+": tool, version and provider are required")
+}
+
+func TestToolsLockfile_apply(t *testing.T) {
+	pluginURL := "https://github.com/example/custom-tool-plugin"
+	lockfile := ToolsLockfile{
+		FormatVersion: toolsLockfileFormatVersion,
+		Tools: []LockedTool{
+			{Tool: "nodejs", Requested: "20:latest", Version: "20.18.1", Provider: "mise"},
+			{Tool: "ruby", Requested: "3.3:installed", Version: "3.3.6", Provider: "asdf"},
+			{Tool: "custom-tool", Requested: ":latest", Version: "1.2.0", Provider: "mise", PluginURL: pluginURL},
+		},
+	}
+
+	requests := []provider.ToolRequest{
+		{ToolName: "nodejs", UnparsedVersion: "20", ResolutionStrategy: provider.ResolutionStrategyLatestReleased},
+		// Locked with another provider
+		{ToolName: "ruby", UnparsedVersion: "3.3", ResolutionStrategy: provider.ResolutionStrategyLatestInstalled},
+		{ToolName: "custom-tool", UnparsedVersion: "", ResolutionStrategy: provider.ResolutionStrategyLatestReleased, PluginURL: &pluginURL},
+		// Requested version changed since locking
+		{ToolName: "nodejs", UnparsedVersion: "^22", ResolutionStrategy: provider.ResolutionStrategyConstraint},
+	}
+
+	require.Equal(t, []provider.ToolRequest{
+		{ToolName: "nodejs", UnparsedVersion: "20.18.1", ResolutionStrategy: provider.ResolutionStrategyStrict},
+		requests[1],
+		{ToolName: "custom-tool", UnparsedVersion: "1.2.0", ResolutionStrategy: provider.ResolutionStrategyStrict, PluginURL: &pluginURL},
+		requests[3],
+	}, lockfile.apply(requests, "mise", true))
+}
+
+func TestToolsLockfile_Merge(t *testing.T) {
+	existing := ToolsLockfile{
+		FormatVersion: toolsLockfileFormatVersion,
+		Tools: []LockedTool{
+			{Tool: "nodejs", Requested: "20:latest", Version: "20.18.0", Provider: "mise"},
+			{Tool: "ruby", Requested: "3.3:latest", Version: "3.3.5", Provider: "mise"},
+		},
+	}
+	workflowLockfile := ToolsLockfile{
+		FormatVersion: toolsLockfileFormatVersion,
+		Tools: []LockedTool{
+			{Tool: "golang", Requested: "1.23:latest", Version: "1.23.4", Provider: "mise"},
+			{Tool: "nodejs", Requested: "20:latest", Version: "20.18.1", Provider: "mise"},
+		},
+	}
+
+	require.Equal(t, []LockedTool{
+		{Tool: "golang", Requested: "1.23:latest", Version: "1.23.4", Provider: "mise"},
+		{Tool: "nodejs", Requested: "20:latest", Version: "20.18.1", Provider: "mise"},
+		{Tool: "ruby", Requested: "3.3:latest", Version: "3.3.5", Provider: "mise"},
+	}, existing.Merge(workflowLockfile).Tools)
+	require.Len(t, existing.Tools, 2)
+}
+
+func TestRequestedVersionSpec(t *testing.T) {
+	for _, spec := range []string{"20.10.0", "22:latest", "3.2:installed", ":latest", "latest"} {
+		version, strategy, err := ParseVersionString(spec)
+		require.NoError(t, err)
+		require.Equal(t, spec, requestedVersionSpec(provider.ToolRequest{UnparsedVersion: version, ResolutionStrategy: strategy}))
+	}
+
+	require.Equal(t, "^20.1", requestedVersionSpec(provider.ToolRequest{UnparsedVersion: "^20.1", ResolutionStrategy: provider.ResolutionStrategyConstraint}))
+}
+
+func TestGetToolRequests_doesNotModifyGlobalTools(t *testing.T) {
+	config := models.BitriseDataModel{
+		Tools: models.ToolsModel{"nodejs": "20", "ruby": "3.3"},
+		Workflows: map[string]models.WorkflowModel{
+			"a": {Tools: models.ToolsModel{"nodejs": "22", "ruby": "unset"}},
+			"b": {},
+		},
+	}
+
+	_, err := getToolRequests(config, "a")
+	require.NoError(t, err)
+	requests, err := getToolRequests(config, "b")
+	require.NoError(t, err)
+
+	require.Equal(t, models.ToolsModel{"nodejs": "20", "ruby": "3.3"}, config.Tools)
+	require.Len(t, requests, 2)
+}
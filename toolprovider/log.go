@@ -70,6 +70,16 @@ func printInstallResult(toolRequest provider.ToolRequest, result provider.ToolIn
 	log.Printf("")
 }
 
+// printConcurrentInstallResult prints the result with the tool name, as the results of concurrent installs are interleaved.
+func printConcurrentInstallResult(toolRequest provider.ToolRequest, result provider.ToolInstallResult, duration time.Duration) {
+	status := "installed"
+	if result.IsAlreadyInstalled {
+		status = "already installed"
+	}
+
+	log.Printf("%s %s %s %s (took %s)", colorstring.Green("✓"), colorstring.Magenta(toolRequest.ToolName), colorstring.Cyan(result.ConcreteVersion), status, duration.Round(time.Millisecond))
+}
+
 func printInstallError(err provider.ToolInstallError) {
 	optionalLines := ""
 	if err.Cause != "" {
@@ -0,0 +1,72 @@
+package mise
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/mise/execenv"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// DataDir ...
+func (m *MiseToolProvider) DataDir() string {
+	return m.dataDir
+}
+
+// InstallPaths returns the install dir of the tool version, the metadata files next to it (e.g. .mise.backend)
+// and the plugin of the tool if it is installed from a plugin.
+func (m *MiseToolProvider) InstallPaths(result provider.ToolInstallResult) ([]string, error) {
+	installPath, err := installPath(m.ExecEnv, result.ToolName, result.ConcreteVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	relInstallPath, err := filepath.Rel(m.dataDir, installPath)
+	if err != nil || strings.HasPrefix(relInstallPath, "..") {
+		return nil, fmt.Errorf("%s %s is installed outside of the mise data dir (%s), fast install (Nix) installs can not be bundled", result.ToolName, result.ConcreteVersion, installPath)
+	}
+	paths := []string{relInstallPath}
+
+	toolDir := filepath.Dir(installPath)
+	entries, err := os.ReadDir(toolDir)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", toolDir, err)
+	}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			paths = append(paths, filepath.Join(filepath.Dir(relInstallPath), entry.Name()))
+		}
+	}
+
+	pluginPath := filepath.Join("plugins", string(result.ToolName))
+	if _, err := os.Stat(filepath.Join(m.dataDir, pluginPath)); err == nil {
+		paths = append(paths, pluginPath)
+	}
+
+	return paths, nil
+}
+
+func installPath(execEnv execenv.ExecEnv, toolName provider.ToolID, concreteVersion string) (string, error) {
+	versionString := miseVersionString(toolName, concreteVersion)
+	output, err := execEnv.RunMiseWithTimeout(execenv.DefaultTimeout, "where", versionString)
+	if err != nil {
+		return "", fmt.Errorf("mise where %s: %w", versionString, err)
+	}
+
+	pth := extractLastLine(StripMiseLogLines(output))
+	if pth == "" {
+		return "", fmt.Errorf("mise where %s: empty output", versionString)
+	}
+	return pth, nil
+}
+
+// UninstallTool ...
+func (m *MiseToolProvider) UninstallTool(toolName provider.ToolID, version string) error {
+	versionString := miseVersionString(toolName, version)
+	if _, err := m.ExecEnv.RunMiseWithTimeout(execenv.DefaultTimeout, "uninstall", versionString); err != nil {
+		return fmt.Errorf("mise uninstall %s: %w", versionString, err)
+	}
+	return nil
+}
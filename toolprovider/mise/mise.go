@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/bitrise-io/bitrise/v2/configs"
 	"github.com/bitrise-io/bitrise/v2/log"
@@ -46,6 +47,8 @@ type MiseToolProvider struct {
 	ExecEnv        execenv.ExecEnv
 	UseFastInstall bool
 	Silent         bool
+
+	dataDir string
 }
 
 func NewToolProvider(installDir string, dataDir string, useFastInstall, silent bool, extraEnvs map[string]string) (*MiseToolProvider, error) {
@@ -91,6 +94,7 @@ func NewToolProvider(installDir string, dataDir string, useFastInstall, silent b
 		ExecEnv:        execenv.NewMiseExecEnv(installDir, miseEnvs),
 		UseFastInstall: useFastInstall,
 		Silent:         silent,
+		dataDir:        dataDir,
 	}, nil
 }
 
@@ -115,17 +119,33 @@ func (m *MiseToolProvider) Bootstrap() error {
 	return nil
 }
 
+// SupportsConcurrentInstall reports that different tools can be installed at the same time,
+// mise locks the install dir of each tool version itself.
+func (m *MiseToolProvider) SupportsConcurrentInstall() bool {
+	return true
+}
+
 func (m *MiseToolProvider) InstallTool(tool provider.ToolRequest) (provider.ToolInstallResult, error) {
-	useNix := canBeInstalledWithNix(tool, m.ExecEnv, m.UseFastInstall, nixpkgs.ShouldUseBackend, m.Silent)
-	if !useNix {
-		err := m.InstallPlugin(tool)
-		if err != nil {
-			return provider.ToolInstallResult{}, fmt.Errorf("install tool plugin %s: %w", tool.ToolName, err)
-		}
-	} // else: nixpkgs plugin is already installed in canBeInstalledWithNix()
+	useNix, err := m.preparePlugin(tool)
+	if err != nil {
+		return provider.ToolInstallResult{}, err
+	}
 
 	installRequest := installRequest(tool, useNix)
 
+	// Short-circuit for exact version match among installed versions. Resolving the version queries the released versions,
+	// which is slow and fails without network access (e.g. when the tools are imported from a tools bundle).
+	if installRequest.ResolutionStrategy == provider.ResolutionStrategyStrict {
+		isInstalled, err := m.isAlreadyInstalled(installRequest.ToolName, installRequest.UnparsedVersion)
+		if err == nil && isInstalled {
+			return provider.ToolInstallResult{
+				ToolName:           installRequest.ToolName,
+				IsAlreadyInstalled: true,
+				ConcreteVersion:    installRequest.UnparsedVersion,
+			}, nil
+		}
+	}
+
 	normalizedRequest, err := normalizeRequest(m.ExecEnv, installRequest, m.Silent)
 	if err != nil {
 		return provider.ToolInstallResult{}, err
@@ -195,6 +215,24 @@ func (m *MiseToolProvider) InstallTool(tool provider.ToolRequest) (provider.Tool
 	}, nil
 }
 
+// pluginSetupMu serializes the changes of the shared mise settings and plugins when tools are installed concurrently.
+var pluginSetupMu sync.Mutex
+
+func (m *MiseToolProvider) preparePlugin(tool provider.ToolRequest) (bool, error) {
+	pluginSetupMu.Lock()
+	defer pluginSetupMu.Unlock()
+
+	useNix := canBeInstalledWithNix(tool, m.ExecEnv, m.UseFastInstall, nixpkgs.ShouldUseBackend, m.Silent)
+	if !useNix {
+		err := m.InstallPlugin(tool)
+		if err != nil {
+			return false, fmt.Errorf("install tool plugin %s: %w", tool.ToolName, err)
+		}
+	} // else: nixpkgs plugin is already installed in canBeInstalledWithNix()
+
+	return useNix, nil
+}
+
 func (m *MiseToolProvider) ActivateEnv(result provider.ToolInstallResult) (provider.EnvironmentActivation, error) {
 	envs, err := m.envVarsForTool(result)
 	if err != nil {
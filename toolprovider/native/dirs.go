@@ -0,0 +1,16 @@
+package native
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DataDir returns the directory the native provider installs the tools into.
+// The tool versions are installed to <data dir>/installs/<tool>/<version>, mirroring the mise and asdf layout.
+func DataDir() string {
+	dataHomeDir := os.Getenv("XDG_DATA_HOME")
+	if dataHomeDir == "" {
+		dataHomeDir = filepath.Join(os.Getenv("HOME"), ".local", "share")
+	}
+	return filepath.Join(dataHomeDir, "bitrise", "toolprovider", "native")
+}
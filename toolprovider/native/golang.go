@@ -0,0 +1,106 @@
+package native
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// goSource is the Go release index: https://go.dev/dl/?mode=json&include=all
+type goSource struct {
+	baseURL  string
+	client   *retryablehttp.Client
+	platform platform
+
+	mu       sync.Mutex
+	releases []goRelease
+}
+
+type goRelease struct {
+	// Version is prefixed with go, e.g. go1.22.5
+	Version string   `json:"version"`
+	Files   []goFile `json:"files"`
+}
+
+type goFile struct {
+	Filename string `json:"filename"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	SHA256   string `json:"sha256"`
+	// Kind is archive, installer or source
+	Kind string `json:"kind"`
+}
+
+func (s *goSource) versions() ([]string, error) {
+	releases, err := s.index()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, release := range releases {
+		if _, ok := s.archiveFile(release); ok {
+			versions = append(versions, strings.TrimPrefix(release.Version, "go"))
+		}
+	}
+	return versions, nil
+}
+
+func (s *goSource) artifact(version string) (artifact, error) {
+	releases, err := s.index()
+	if err != nil {
+		return artifact{}, err
+	}
+
+	for _, release := range releases {
+		if release.Version != "go"+version {
+			continue
+		}
+		file, ok := s.archiveFile(release)
+		if !ok {
+			break
+		}
+		return artifact{
+			url:    strings.TrimSuffix(s.baseURL, "/") + "/" + file.Filename,
+			sha256: file.SHA256,
+		}, nil
+	}
+	return artifact{}, errNotFound
+}
+
+func (s *goSource) activation(installDir string) provider.EnvironmentActivation {
+	return provider.EnvironmentActivation{
+		ContributedEnvVars: map[string]string{"GOROOT": installDir},
+		ContributedPaths:   []string{filepath.Join(installDir, "bin")},
+	}
+}
+
+func (s *goSource) archiveFile(release goRelease) (goFile, bool) {
+	for _, file := range release.Files {
+		if file.Kind == "archive" && file.OS == s.platform.os && file.Arch == s.platform.arch && strings.HasSuffix(file.Filename, ".tar.gz") {
+			return file, true
+		}
+	}
+	return goFile{}, false
+}
+
+func (s *goSource) index() ([]goRelease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.releases != nil {
+		return s.releases, nil
+	}
+
+	var releases []goRelease
+	url := fmt.Sprintf("%s/?mode=json&include=all", strings.TrimSuffix(s.baseURL, "/"))
+	if err := getJSON(s.client, url, &releases); err != nil {
+		return nil, err
+	}
+	s.releases = releases
+	return releases, nil
+}
@@ -0,0 +1,222 @@
+package native
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// releaseSource is the release index of a tool.
+type releaseSource interface {
+	// versions lists the released versions having an archive for the platform, including pre-releases.
+	versions() ([]string, error)
+	// artifact looks up the release archive of the version for the platform.
+	artifact(version string) (artifact, error)
+	// activation returns the envs activating the version installed into installDir.
+	activation(installDir string) provider.EnvironmentActivation
+}
+
+// artifact is a .tar.gz release archive. Its contents are expected to be nested in a single top-level dir, which is stripped.
+type artifact struct {
+	url    string
+	sha256 string
+}
+
+var errNotFound = errors.New("not found")
+
+func getJSON(client *retryablehttp.Client, url string, v any) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", url, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("get %s: %w", url, errNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("get %s: received status code %d", url, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("parse %s: %w", url, err)
+	}
+	return nil
+}
+
+// installArchive downloads and verifies the archive, then extracts it to installDir.
+// The archive is extracted next to installDir first, so that an interrupted install never leaves a partial install behind.
+func installArchive(client *retryablehttp.Client, archive artifact, installDir string) error {
+	archivePath, err := downloadAndVerify(client, archive)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(archivePath)
+	}()
+
+	toolDir := filepath.Dir(installDir)
+	if err := os.MkdirAll(toolDir, 0755); err != nil {
+		return fmt.Errorf("create %s: %w", toolDir, err)
+	}
+	tmpDir, err := os.MkdirTemp(toolDir, "."+filepath.Base(installDir)+"-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	if err := extractArchive(archivePath, tmpDir); err != nil {
+		return fmt.Errorf("extract %s: %w", archive.url, err)
+	}
+
+	if err := os.Rename(tmpDir, installDir); err != nil {
+		if _, statErr := os.Stat(installDir); statErr == nil {
+			// Installed by a concurrent tool setup in the meantime
+			return nil
+		}
+		return fmt.Errorf("move %s to %s: %w", tmpDir, installDir, err)
+	}
+	return nil
+}
+
+// downloadAndVerify downloads the archive to a temp file and verifies its checksum.
+func downloadAndVerify(client *retryablehttp.Client, archive artifact) (string, error) {
+	resp, err := client.Get(archive.url)
+	if err != nil {
+		return "", fmt.Errorf("download %s: %w", archive.url, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download %s: received status code %d", archive.url, resp.StatusCode)
+	}
+
+	tempFile, err := os.CreateTemp("", "bitrise-tool-*.tar.gz")
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	tempPath := tempFile.Name()
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tempFile, hash), resp.Body)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return "", fmt.Errorf("download %s: %w", archive.url, err)
+	}
+
+	calculatedChecksum := fmt.Sprintf("%x", hash.Sum(nil))
+	if !strings.EqualFold(calculatedChecksum, archive.sha256) {
+		_ = os.Remove(tempPath)
+		return "", fmt.Errorf("checksum validation of %s failed: expected %s, got %s", archive.url, archive.sha256, calculatedChecksum)
+	}
+	return tempPath, nil
+}
+
+// extractArchive extracts the .tar.gz archive to targetDir, stripping the top-level dir of the entries.
+func extractArchive(archivePath, targetDir string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("create gzip reader: %w", err)
+	}
+	defer func() {
+		_ = gzipReader.Close()
+	}()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read tar entry: %w", err)
+		}
+
+		name := stripTopLevelDir(header.Name)
+		if name == "" {
+			continue
+		}
+		targetPath := filepath.Join(targetDir, name)
+		if !isWithinDir(targetDir, targetPath) {
+			return fmt.Errorf("tar entry %s points outside of the target dir", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(targetPath, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := extractFile(tarReader, targetPath, header.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			linkTarget := filepath.Join(filepath.Dir(targetPath), header.Linkname)
+			if filepath.IsAbs(header.Linkname) || !isWithinDir(targetDir, linkTarget) {
+				return fmt.Errorf("symlink %s points outside of the target dir", header.Name)
+			}
+			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, targetPath); err != nil {
+				return err
+			}
+		default:
+			// Hard links, devices and the like are not part of the release archives
+			continue
+		}
+	}
+}
+
+func extractFile(r io.Reader, targetPath string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func stripTopLevelDir(name string) string {
+	name = strings.TrimPrefix(filepath.ToSlash(name), "./")
+	_, rest, _ := strings.Cut(name, "/")
+	return strings.TrimSuffix(rest, "/")
+}
+
+func isWithinDir(dir, pth string) bool {
+	rel, err := filepath.Rel(dir, pth)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
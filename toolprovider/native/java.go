@@ -0,0 +1,157 @@
+package native
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// javaPageLimit caps the number of result pages queried from the Adoptium API.
+const javaPageLimit = 20
+
+// javaSource is the Eclipse Temurin release index of the Adoptium API: https://api.adoptium.net/q/swagger-ui
+// Versions are in the <major>.<minor>.<security> format, e.g. 21.0.5 and 8.0.432.
+type javaSource struct {
+	baseURL  string
+	client   *retryablehttp.Client
+	platform platform
+}
+
+type javaVersionData struct {
+	Major    int `json:"major"`
+	Minor    int `json:"minor"`
+	Security int `json:"security"`
+}
+
+func (v javaVersionData) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Security)
+}
+
+type javaRelease struct {
+	Binaries []struct {
+		Package struct {
+			Link     string `json:"link"`
+			Checksum string `json:"checksum"`
+		} `json:"package"`
+	} `json:"binaries"`
+	VersionData javaVersionData `json:"version_data"`
+}
+
+func (s *javaSource) versions() ([]string, error) {
+	query, err := s.query()
+	if err != nil {
+		return nil, err
+	}
+	query.Set("release_type", "ga")
+	query.Set("page_size", "50")
+
+	var versions []string
+	seen := map[string]bool{}
+	for page := 0; page < javaPageLimit; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var response struct {
+			Versions []javaVersionData `json:"versions"`
+		}
+		err := getJSON(s.client, s.apiURL("/v3/info/release_versions", query), &response)
+		if errors.Is(err, errNotFound) {
+			// Paging past the last page
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(response.Versions) == 0 {
+			break
+		}
+
+		// The same version is listed once for every build
+		for _, v := range response.Versions {
+			if !seen[v.String()] {
+				seen[v.String()] = true
+				versions = append(versions, v.String())
+			}
+		}
+	}
+	return versions, nil
+}
+
+func (s *javaSource) artifact(version string) (artifact, error) {
+	major, _, _ := strings.Cut(version, ".")
+	if _, err := strconv.Atoi(major); err != nil {
+		return artifact{}, fmt.Errorf("invalid Java version: %s", version)
+	}
+
+	query, err := s.query()
+	if err != nil {
+		return artifact{}, err
+	}
+	query.Set("page_size", "20")
+
+	for page := 0; page < javaPageLimit; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var releases []javaRelease
+		err := getJSON(s.client, s.apiURL("/v3/assets/feature_releases/"+major+"/ga", query), &releases)
+		if errors.Is(err, errNotFound) {
+			break
+		}
+		if err != nil {
+			return artifact{}, err
+		}
+		if len(releases) == 0 {
+			break
+		}
+
+		// Releases are sorted from the latest build, the first match is the latest build of the version
+		for _, release := range releases {
+			if release.VersionData.String() != version || len(release.Binaries) == 0 {
+				continue
+			}
+			pkg := release.Binaries[0].Package
+			return artifact{url: pkg.Link, sha256: pkg.Checksum}, nil
+		}
+	}
+	return artifact{}, errNotFound
+}
+
+func (s *javaSource) activation(installDir string) provider.EnvironmentActivation {
+	// The macOS archives contain a bundle, the JDK is in Contents/Home
+	javaHome := installDir
+	if info, err := os.Stat(filepath.Join(installDir, "Contents", "Home")); err == nil && info.IsDir() {
+		javaHome = filepath.Join(installDir, "Contents", "Home")
+	}
+	return provider.EnvironmentActivation{
+		ContributedEnvVars: map[string]string{"JAVA_HOME": javaHome},
+		ContributedPaths:   []string{filepath.Join(javaHome, "bin")},
+	}
+}
+
+func (s *javaSource) query() (url.Values, error) {
+	arch := map[string]string{"amd64": "x64", "arm64": "aarch64"}[s.platform.arch]
+	if arch == "" {
+		return nil, fmt.Errorf("unsupported architecture: %s", s.platform.arch)
+	}
+	osName := map[string]string{"linux": "linux", "darwin": "mac"}[s.platform.os]
+	if osName == "" {
+		return nil, fmt.Errorf("unsupported OS: %s", s.platform.os)
+	}
+
+	return url.Values{
+		"architecture": {arch},
+		"os":           {osName},
+		"image_type":   {"jdk"},
+		"vendor":       {"eclipse"},
+		"sort_order":   {"DESC"},
+	}, nil
+}
+
+func (s *javaSource) apiURL(pth string, query url.Values) string {
+	return strings.TrimSuffix(s.baseURL, "/") + pth + "?" + query.Encode()
+}
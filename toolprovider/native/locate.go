@@ -0,0 +1,32 @@
+package native
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// DataDir ...
+func (p *NativeToolProvider) DataDir() string {
+	return p.dataDir
+}
+
+// InstallPaths returns the install dir of the tool version, the native provider has no plugins.
+func (p *NativeToolProvider) InstallPaths(result provider.ToolInstallResult) ([]string, error) {
+	installPath := filepath.Join("installs", string(result.ToolName), result.ConcreteVersion)
+	if _, err := os.Stat(filepath.Join(p.dataDir, installPath)); err != nil {
+		return nil, fmt.Errorf("%s %s install dir: %w", result.ToolName, result.ConcreteVersion, err)
+	}
+	return []string{installPath}, nil
+}
+
+// UninstallTool ...
+func (p *NativeToolProvider) UninstallTool(toolName provider.ToolID, version string) error {
+	installDir := p.installDir(toolName, version)
+	if _, err := os.Stat(installDir); err != nil {
+		return fmt.Errorf("%s %s install dir: %w", toolName, version, err)
+	}
+	return os.RemoveAll(installDir)
+}
@@ -0,0 +1,277 @@
+package native
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// ReleaseIndexURLs are the base URLs of the official release indexes the tools are installed from.
+// They can point to a mirror (or to a local HTTP server in tests) serving the same index format and archives.
+type ReleaseIndexURLs struct {
+	// Go is the base URL of the Go downloads: <Go>/?mode=json&include=all and <Go>/<archive>
+	Go string
+	// NodeJS is the base URL of the Node.js distributions: <NodeJS>/index.json and <NodeJS>/v<version>/SHASUMS256.txt
+	NodeJS string
+	// Java is the base URL of the Eclipse Adoptium (Temurin) API v3.
+	Java string
+}
+
+// DefaultReleaseIndexURLs are the official release indexes.
+var DefaultReleaseIndexURLs = ReleaseIndexURLs{
+	Go:     "https://go.dev/dl",
+	NodeJS: "https://nodejs.org/dist",
+	Java:   "https://api.adoptium.net",
+}
+
+// NativeToolProvider installs the core runtimes (Go, Node.js, Java) directly from their official release archives,
+// without a third-party version manager. The archives are verified against the checksums published in the release indexes.
+type NativeToolProvider struct {
+	Silent bool
+
+	dataDir string
+	client  *retryablehttp.Client
+	sources map[provider.ToolID]releaseSource
+}
+
+func NewToolProvider(dataDir string, indexURLs ReleaseIndexURLs, silent bool) (*NativeToolProvider, error) {
+	if dataDir == "" {
+		return nil, errors.New("data directory must be provided")
+	}
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("create data dir at %s: %w", dataDir, err)
+	}
+
+	client := newHTTPClient()
+	platform := currentPlatform()
+	return &NativeToolProvider{
+		Silent:  silent,
+		dataDir: dataDir,
+		client:  client,
+		sources: map[provider.ToolID]releaseSource{
+			"golang": &goSource{baseURL: indexURLs.Go, client: client, platform: platform},
+			"nodejs": &nodeSource{baseURL: indexURLs.NodeJS, client: client, platform: platform},
+			"java":   &javaSource{baseURL: indexURLs.Java, client: client, platform: platform},
+		},
+	}, nil
+}
+
+func (p *NativeToolProvider) ID() string {
+	return "native"
+}
+
+// Bootstrap is a no-op, the native provider has no external dependencies.
+func (p *NativeToolProvider) Bootstrap() error {
+	return nil
+}
+
+// SupportsConcurrentInstall reports that different tools can be installed at the same time,
+// each tool version is extracted to a temporary dir and moved into place at once.
+func (p *NativeToolProvider) SupportsConcurrentInstall() bool {
+	return true
+}
+
+// SupportedTools returns the tools the native provider is able to install.
+func (p *NativeToolProvider) SupportedTools() []provider.ToolID {
+	var tools []provider.ToolID
+	for toolID := range p.sources {
+		tools = append(tools, toolID)
+	}
+	slices.Sort(tools)
+	return tools
+}
+
+func (p *NativeToolProvider) InstallTool(tool provider.ToolRequest) (provider.ToolInstallResult, error) {
+	source, err := p.source(tool)
+	if err != nil {
+		return provider.ToolInstallResult{}, err
+	}
+
+	installedVersions, err := p.InstalledVersions(tool.ToolName)
+	if err != nil {
+		return provider.ToolInstallResult{}, fmt.Errorf("list installed versions: %w", err)
+	}
+
+	// Short-circuit for exact version match among installed versions, fetching the release index is slow and needs network access.
+	v := strings.TrimSpace(tool.UnparsedVersion)
+	if tool.ResolutionStrategy == provider.ResolutionStrategyStrict && slices.Contains(installedVersions, v) {
+		return provider.ToolInstallResult{
+			ToolName:           tool.ToolName,
+			IsAlreadyInstalled: true,
+			ConcreteVersion:    v,
+		}, nil
+	}
+
+	concreteVersion, err := p.resolveVersion(tool, source, installedVersions)
+	if err != nil {
+		return provider.ToolInstallResult{}, err
+	}
+	if !p.Silent {
+		log.Debugf("[TOOLPROVIDER] Resolved %s@%s to concrete version: %s", tool.ToolName, tool.UnparsedVersion, concreteVersion)
+	}
+
+	if slices.Contains(installedVersions, concreteVersion) {
+		return provider.ToolInstallResult{
+			ToolName:           tool.ToolName,
+			IsAlreadyInstalled: true,
+			ConcreteVersion:    concreteVersion,
+		}, nil
+	}
+
+	archive, err := source.artifact(concreteVersion)
+	if err != nil {
+		return provider.ToolInstallResult{}, provider.ToolInstallError{
+			ToolName:         tool.ToolName,
+			RequestedVersion: tool.UnparsedVersion,
+			Cause:            fmt.Sprintf("find %s %s release archive for %s: %s", tool.ToolName, concreteVersion, currentPlatform(), err),
+		}
+	}
+	if !p.Silent {
+		log.Printf("Downloading %s", archive.url)
+	}
+	if err := installArchive(p.client, archive, p.installDir(tool.ToolName, concreteVersion)); err != nil {
+		return provider.ToolInstallResult{}, provider.ToolInstallError{
+			ToolName:         tool.ToolName,
+			RequestedVersion: tool.UnparsedVersion,
+			Cause:            err.Error(),
+		}
+	}
+
+	return provider.ToolInstallResult{
+		ToolName:           tool.ToolName,
+		IsAlreadyInstalled: false,
+		ConcreteVersion:    concreteVersion,
+	}, nil
+}
+
+func (p *NativeToolProvider) ActivateEnv(result provider.ToolInstallResult) (provider.EnvironmentActivation, error) {
+	source, ok := p.sources[result.ToolName]
+	if !ok {
+		return provider.EnvironmentActivation{}, fmt.Errorf("%s is not supported by the native tool provider", result.ToolName)
+	}
+
+	installDir := p.installDir(result.ToolName, result.ConcreteVersion)
+	if _, err := os.Stat(installDir); err != nil {
+		return provider.EnvironmentActivation{}, fmt.Errorf("%s %s is not installed: %w", result.ToolName, result.ConcreteVersion, err)
+	}
+	return source.activation(installDir), nil
+}
+
+// ResolveLatestVersion resolves a tool to its latest version without installing it.
+func (p *NativeToolProvider) ResolveLatestVersion(tool provider.ToolRequest) (string, error) {
+	source, err := p.source(tool)
+	if err != nil {
+		return "", err
+	}
+
+	installedVersions, err := p.InstalledVersions(tool.ToolName)
+	if err != nil {
+		return "", fmt.Errorf("list installed versions: %w", err)
+	}
+	return p.resolveVersion(tool, source, installedVersions)
+}
+
+func (p *NativeToolProvider) ListReleasedVersions(toolName provider.ToolID) ([]string, error) {
+	source, err := p.source(provider.ToolRequest{ToolName: toolName})
+	if err != nil {
+		return nil, err
+	}
+	return source.versions()
+}
+
+func (p *NativeToolProvider) source(tool provider.ToolRequest) (releaseSource, error) {
+	source, ok := p.sources[tool.ToolName]
+	if !ok {
+		return nil, provider.ToolInstallError{
+			ToolName:         tool.ToolName,
+			RequestedVersion: tool.UnparsedVersion,
+			Cause:            fmt.Sprintf("%s is not supported by the native tool provider", tool.ToolName),
+			Recommendation:   fmt.Sprintf("Use the mise or asdf tool provider, the native tool provider supports: %v", p.SupportedTools()),
+		}
+	}
+	return source, nil
+}
+
+func (p *NativeToolProvider) resolveVersion(tool provider.ToolRequest, source releaseSource, installedVersions []string) (string, error) {
+	request := normalizeRequest(tool)
+
+	if request.ResolutionStrategy == provider.ResolutionStrategyLatestInstalled {
+		if version, err := resolveVersion(request.UnparsedVersion, request.ResolutionStrategy, installedVersions); err == nil {
+			return version, nil
+		}
+		if !p.Silent {
+			log.Infof("No installed versions found, fallback to latest released")
+		}
+		request.ResolutionStrategy = provider.ResolutionStrategyLatestReleased
+	}
+
+	releasedVersions, err := source.versions()
+	if err != nil {
+		return "", fmt.Errorf("list released versions of %s: %w", tool.ToolName, err)
+	}
+
+	version, err := resolveVersion(request.UnparsedVersion, request.ResolutionStrategy, releasedVersions)
+	if err != nil {
+		return "", provider.ToolInstallError{
+			ToolName:         tool.ToolName,
+			RequestedVersion: tool.UnparsedVersion,
+			Cause:            fmt.Sprintf("no match for requested version %s", tool.UnparsedVersion),
+		}
+	}
+	return version, nil
+}
+
+func (p *NativeToolProvider) installDir(toolName provider.ToolID, version string) string {
+	return filepath.Join(p.dataDir, "installs", string(toolName), version)
+}
+
+// InstalledVersions lists the installed versions of the tool.
+func (p *NativeToolProvider) InstalledVersions(toolName provider.ToolID) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(p.dataDir, "installs", string(toolName)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		// Dot dirs are installs in progress
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			versions = append(versions, entry.Name())
+		}
+	}
+	return versions, nil
+}
+
+// platform is the OS and CPU architecture the release archives are selected for, in Go's naming (e.g. darwin, arm64).
+type platform struct {
+	os   string
+	arch string
+}
+
+func currentPlatform() platform {
+	return platform{os: runtime.GOOS, arch: runtime.GOARCH}
+}
+
+func (p platform) String() string {
+	return p.os + "-" + p.arch
+}
+
+func newHTTPClient() *retryablehttp.Client {
+	logger := log.NewLogger(log.GetGlobalLoggerOpts())
+	client := retryablehttp.NewClient()
+	client.Logger = &log.HTTPLogAdaptor{Logger: logger}
+	client.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	return client
+}
@@ -0,0 +1,289 @@
+package native
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+type archiveEntry struct {
+	name     string
+	content  string
+	linkname string
+}
+
+func createArchive(t *testing.T, entries []archiveEntry) []byte {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0755, Typeflag: tar.TypeReg, Size: int64(len(entry.content))}
+		if entry.linkname != "" {
+			header = &tar.Header{Name: entry.name, Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: entry.linkname}
+		}
+		require.NoError(t, tarWriter.WriteHeader(header))
+		_, err := tarWriter.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return buf.Bytes()
+}
+
+func sha256Hex(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// newReleaseServer serves the Go, Node.js and Adoptium release indexes of the current platform, with one installable version each.
+func newReleaseServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	goArchive := createArchive(t, []archiveEntry{{name: "go/bin/go", content: "#!/bin/sh\necho go1.23.4"}})
+	nodeArchive := createArchive(t, []archiveEntry{
+		{name: "node-v22.11.0/bin/node", content: "#!/bin/sh\necho v22.11.0"},
+		{name: "node-v22.11.0/lib/node_modules/npm/bin/npm-cli.js", content: "npm"},
+		{name: "node-v22.11.0/bin/npm", linkname: "../lib/node_modules/npm/bin/npm-cli.js"},
+	})
+	javaArchive := createArchive(t, []archiveEntry{{name: "jdk-21.0.5+11/bin/java", content: "#!/bin/sh\necho 21.0.5"}})
+
+	nodePlatform := map[string]string{"darwin": "osx", "linux": "linux"}[runtime.GOOS]
+	nodeArch := map[string]string{"amd64": "x64", "arm64": "arm64"}[runtime.GOARCH]
+	nodeIndexFile := nodePlatform + "-" + nodeArch
+	if runtime.GOOS == "darwin" {
+		nodeIndexFile += "-tar"
+	}
+	nodeArchiveName := fmt.Sprintf("node-v22.11.0-%s-%s.tar.gz", runtime.GOOS, nodeArch)
+
+	var requests atomic.Int32
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, v any) {
+		require.NoError(t, json.NewEncoder(w).Encode(v))
+	}
+	goFiles := func(version string) []map[string]string {
+		return []map[string]string{
+			{"filename": version + ".src.tar.gz", "os": "", "arch": "", "sha256": "00", "kind": "source"},
+			{"filename": fmt.Sprintf("%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH), "os": runtime.GOOS, "arch": runtime.GOARCH, "sha256": sha256Hex(goArchive), "kind": "archive"},
+		}
+	}
+	mux.HandleFunc("/go/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/go/" {
+			require.Equal(t, "json", r.URL.Query().Get("mode"))
+			corrupt := goFiles("go1.22.5")
+			corrupt[1]["sha256"] = sha256Hex([]byte("other"))
+			writeJSON(w, []map[string]any{
+				{"version": "go1.24rc1", "files": goFiles("go1.24rc1")},
+				{"version": "go1.23.4", "files": goFiles("go1.23.4")},
+				{"version": "go1.22.5", "files": corrupt},
+			})
+			return
+		}
+		_, _ = w.Write(goArchive)
+	})
+	mux.HandleFunc("/node/index.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []map[string]any{
+			{"version": "v23.0.0", "files": []string{"win-x64-zip"}},
+			{"version": "v22.11.0", "files": []string{"win-x64-zip", nodeIndexFile}},
+			{"version": "v20.18.1", "files": []string{nodeIndexFile}},
+		})
+	})
+	mux.HandleFunc("/node/v22.11.0/SHASUMS256.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s  node-v22.11.0.tar.gz\n%s  %s\n", sha256Hex([]byte("src")), sha256Hex(nodeArchive), nodeArchiveName)
+	})
+	mux.HandleFunc("/node/v22.11.0/"+nodeArchiveName, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(nodeArchive)
+	})
+	mux.HandleFunc("/java/v3/info/release_versions", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") != "0" {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, map[string]any{"versions": []map[string]int{
+			{"major": 21, "minor": 0, "security": 5, "build": 11},
+			{"major": 21, "minor": 0, "security": 5, "build": 10},
+			{"major": 17, "minor": 0, "security": 13, "build": 11},
+		}})
+	})
+	var serverURL string
+	mux.HandleFunc("/java/v3/assets/feature_releases/21/ga", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") != "0" {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, []map[string]any{{
+			"binaries": []map[string]any{{"package": map[string]string{
+				"link":     serverURL + "/java/OpenJDK21U-jdk.tar.gz",
+				"checksum": sha256Hex(javaArchive),
+			}}},
+			"version_data": map[string]int{"major": 21, "minor": 0, "security": 5},
+		}})
+	})
+	mux.HandleFunc("/java/OpenJDK21U-jdk.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(javaArchive)
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		mux.ServeHTTP(w, r)
+	}))
+	serverURL = server.URL
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newTestProvider(t *testing.T, serverURL string) *NativeToolProvider {
+	p, err := NewToolProvider(t.TempDir(), ReleaseIndexURLs{
+		Go:     serverURL + "/go",
+		NodeJS: serverURL + "/node",
+		Java:   serverURL + "/java",
+	}, true)
+	require.NoError(t, err)
+	return p
+}
+
+func TestInstallTool_Go(t *testing.T) {
+	server, requests := newReleaseServer(t)
+	p := newTestProvider(t, server.URL)
+
+	versions, err := p.ListReleasedVersions("golang")
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.24rc1", "1.23.4", "1.22.5"}, versions)
+
+	result, err := p.InstallTool(provider.ToolRequest{ToolName: "golang", UnparsedVersion: "1", ResolutionStrategy: provider.ResolutionStrategyLatestReleased})
+	require.NoError(t, err)
+	require.Equal(t, provider.ToolInstallResult{ToolName: "golang", ConcreteVersion: "1.23.4"}, result)
+
+	installDir := filepath.Join(p.DataDir(), "installs", "golang", "1.23.4")
+	content, err := os.ReadFile(filepath.Join(installDir, "bin", "go"))
+	require.NoError(t, err)
+	require.Equal(t, "#!/bin/sh\necho go1.23.4", string(content))
+
+	activation, err := p.ActivateEnv(result)
+	require.NoError(t, err)
+	require.Equal(t, provider.EnvironmentActivation{
+		ContributedEnvVars: map[string]string{"GOROOT": installDir},
+		ContributedPaths:   []string{filepath.Join(installDir, "bin")},
+	}, activation)
+
+	paths, err := p.InstallPaths(result)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join("installs", "golang", "1.23.4")}, paths)
+
+	// Exact versions already installed need no network access
+	requestCount := requests.Load()
+	result, err = p.InstallTool(provider.ToolRequest{ToolName: "golang", UnparsedVersion: "1.23.4", ResolutionStrategy: provider.ResolutionStrategyStrict})
+	require.NoError(t, err)
+	require.Equal(t, provider.ToolInstallResult{ToolName: "golang", IsAlreadyInstalled: true, ConcreteVersion: "1.23.4"}, result)
+	require.Equal(t, requestCount, requests.Load())
+
+	result, err = p.InstallTool(provider.ToolRequest{ToolName: "golang", UnparsedVersion: "installed", ResolutionStrategy: provider.ResolutionStrategyStrict})
+	require.NoError(t, err)
+	require.Equal(t, provider.ToolInstallResult{ToolName: "golang", IsAlreadyInstalled: true, ConcreteVersion: "1.23.4"}, result)
+
+	require.NoError(t, p.UninstallTool("golang", "1.23.4"))
+	installed, err := p.InstalledVersions("golang")
+	require.NoError(t, err)
+	require.Empty(t, installed)
+}
+
+func TestInstallTool_ChecksumMismatch(t *testing.T) {
+	server, _ := newReleaseServer(t)
+	p := newTestProvider(t, server.URL)
+
+	_, err := p.InstallTool(provider.ToolRequest{ToolName: "golang", UnparsedVersion: "1.22", ResolutionStrategy: provider.ResolutionStrategyLatestReleased})
+	require.ErrorContains(t, err, "checksum validation of "+server.URL+"/go/go1.22.5.")
+
+	// No partial install is left behind
+	require.NoDirExists(t, filepath.Join(p.DataDir(), "installs", "golang"))
+}
+
+func TestInstallTool_NodeJS(t *testing.T) {
+	server, _ := newReleaseServer(t)
+	p := newTestProvider(t, server.URL)
+
+	versions, err := p.ListReleasedVersions("nodejs")
+	require.NoError(t, err)
+	require.Equal(t, []string{"22.11.0", "20.18.1"}, versions)
+
+	result, err := p.InstallTool(provider.ToolRequest{ToolName: "nodejs", UnparsedVersion: "^22", ResolutionStrategy: provider.ResolutionStrategyConstraint})
+	require.NoError(t, err)
+	require.Equal(t, provider.ToolInstallResult{ToolName: "nodejs", ConcreteVersion: "22.11.0"}, result)
+
+	installDir := filepath.Join(p.DataDir(), "installs", "nodejs", "22.11.0")
+	link, err := os.Readlink(filepath.Join(installDir, "bin", "npm"))
+	require.NoError(t, err)
+	require.Equal(t, "../lib/node_modules/npm/bin/npm-cli.js", link)
+
+	activation, err := p.ActivateEnv(result)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(installDir, "bin")}, activation.ContributedPaths)
+}
+
+func TestInstallTool_Java(t *testing.T) {
+	server, _ := newReleaseServer(t)
+	p := newTestProvider(t, server.URL)
+
+	versions, err := p.ListReleasedVersions("java")
+	require.NoError(t, err)
+	require.Equal(t, []string{"21.0.5", "17.0.13"}, versions)
+
+	result, err := p.InstallTool(provider.ToolRequest{ToolName: "java", UnparsedVersion: "21", ResolutionStrategy: provider.ResolutionStrategyLatestInstalled})
+	require.NoError(t, err)
+	require.Equal(t, provider.ToolInstallResult{ToolName: "java", ConcreteVersion: "21.0.5"}, result)
+
+	installDir := filepath.Join(p.DataDir(), "installs", "java", "21.0.5")
+	activation, err := p.ActivateEnv(result)
+	require.NoError(t, err)
+	require.Equal(t, provider.EnvironmentActivation{
+		ContributedEnvVars: map[string]string{"JAVA_HOME": installDir},
+		ContributedPaths:   []string{filepath.Join(installDir, "bin")},
+	}, activation)
+
+	_, err = p.InstallTool(provider.ToolRequest{ToolName: "java", UnparsedVersion: "17.0.13", ResolutionStrategy: provider.ResolutionStrategyStrict})
+	require.ErrorContains(t, err, "find java 17.0.13 release archive")
+}
+
+func TestInstallTool_Errors(t *testing.T) {
+	server, _ := newReleaseServer(t)
+	p := newTestProvider(t, server.URL)
+
+	_, err := p.InstallTool(provider.ToolRequest{ToolName: "ruby", UnparsedVersion: "3.3", ResolutionStrategy: provider.ResolutionStrategyLatestReleased})
+	var toolErr provider.ToolInstallError
+	require.ErrorAs(t, err, &toolErr)
+	require.Equal(t, "ruby is not supported by the native tool provider", toolErr.Cause)
+
+	_, err = p.InstallTool(provider.ToolRequest{ToolName: "nodejs", UnparsedVersion: "18", ResolutionStrategy: provider.ResolutionStrategyLatestReleased})
+	require.ErrorAs(t, err, &toolErr)
+	require.Equal(t, "no match for requested version 18", toolErr.Cause)
+}
+
+func TestExtractArchive_RejectsEntriesOutsideTargetDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+	}{
+		{name: "path traversal", entries: []archiveEntry{{name: "go/../../evil", content: "evil"}}},
+		{name: "absolute symlink", entries: []archiveEntry{{name: "go/bin/go", linkname: "/usr/bin/go"}}},
+		{name: "escaping symlink", entries: []archiveEntry{{name: "go/bin/go", linkname: "../../../evil"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archivePath := filepath.Join(t.TempDir(), "archive.tar.gz")
+			require.NoError(t, os.WriteFile(archivePath, createArchive(t, tt.entries), 0644))
+
+			err := extractArchive(archivePath, t.TempDir())
+			require.ErrorContains(t, err, "outside of the target dir")
+		})
+	}
+}
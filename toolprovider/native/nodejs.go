@@ -0,0 +1,136 @@
+package native
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// nodeSource is the Node.js distribution index: https://nodejs.org/dist/index.json
+// The archive checksums are published per version in SHASUMS256.txt.
+type nodeSource struct {
+	baseURL  string
+	client   *retryablehttp.Client
+	platform platform
+
+	mu       sync.Mutex
+	releases []nodeRelease
+}
+
+type nodeRelease struct {
+	// Version is prefixed with v, e.g. v22.11.0
+	Version string `json:"version"`
+	// Files are the available distributions, e.g. linux-x64, osx-arm64-tar
+	Files []string `json:"files"`
+}
+
+func (s *nodeSource) versions() ([]string, error) {
+	releases, err := s.index()
+	if err != nil {
+		return nil, err
+	}
+
+	indexFile, _, err := s.platformNames()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, release := range releases {
+		if slices.Contains(release.Files, indexFile) {
+			versions = append(versions, strings.TrimPrefix(release.Version, "v"))
+		}
+	}
+	return versions, nil
+}
+
+func (s *nodeSource) artifact(version string) (artifact, error) {
+	_, archivePlatform, err := s.platformNames()
+	if err != nil {
+		return artifact{}, err
+	}
+
+	versionURL := fmt.Sprintf("%s/v%s", strings.TrimSuffix(s.baseURL, "/"), version)
+	filename := fmt.Sprintf("node-v%s-%s.tar.gz", version, archivePlatform)
+	checksum, err := s.checksum(versionURL+"/SHASUMS256.txt", filename)
+	if err != nil {
+		return artifact{}, err
+	}
+	return artifact{
+		url:    versionURL + "/" + filename,
+		sha256: checksum,
+	}, nil
+}
+
+func (s *nodeSource) activation(installDir string) provider.EnvironmentActivation {
+	return provider.EnvironmentActivation{
+		ContributedEnvVars: map[string]string{},
+		ContributedPaths:   []string{filepath.Join(installDir, "bin")},
+	}
+}
+
+// platformNames returns the platform's name in the index files (e.g. osx-arm64-tar) and in the archive names (e.g. darwin-arm64).
+func (s *nodeSource) platformNames() (indexFile, archivePlatform string, err error) {
+	arch := map[string]string{"amd64": "x64", "arm64": "arm64"}[s.platform.arch]
+	if arch == "" {
+		return "", "", fmt.Errorf("unsupported architecture: %s", s.platform.arch)
+	}
+
+	switch s.platform.os {
+	case "linux":
+		return "linux-" + arch, "linux-" + arch, nil
+	case "darwin":
+		return "osx-" + arch + "-tar", "darwin-" + arch, nil
+	default:
+		return "", "", fmt.Errorf("unsupported OS: %s", s.platform.os)
+	}
+}
+
+func (s *nodeSource) checksum(url, filename string) (string, error) {
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("get %s: %w", url, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("get %s: received status code %d", url, resp.StatusCode)
+	}
+
+	// Lines are in the "<sha256>  <filename>" format
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == filename {
+			return fields[0], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("read %s: %w", url, err)
+	}
+	return "", fmt.Errorf("%s has no checksum for %s", url, filename)
+}
+
+func (s *nodeSource) index() ([]nodeRelease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.releases != nil {
+		return s.releases, nil
+	}
+
+	var releases []nodeRelease
+	if err := getJSON(s.client, strings.TrimSuffix(s.baseURL, "/")+"/index.json", &releases); err != nil {
+		return nil, err
+	}
+	s.releases = releases
+	return releases, nil
+}
@@ -0,0 +1,57 @@
+package native
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/versionresolver"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/versionsort"
+)
+
+var errNoMatchingVersion = errors.New("no matching version")
+
+// normalizeRequest handles the "installed" and "latest" special keywords.
+func normalizeRequest(request provider.ToolRequest) provider.ToolRequest {
+	request.UnparsedVersion = strings.TrimSpace(request.UnparsedVersion)
+	switch request.UnparsedVersion {
+	case "installed":
+		request.UnparsedVersion = ""
+		request.ResolutionStrategy = provider.ResolutionStrategyLatestInstalled
+	case "latest":
+		request.UnparsedVersion = ""
+		request.ResolutionStrategy = provider.ResolutionStrategyLatestReleased
+	}
+	return request
+}
+
+// resolveVersion picks the version to install from the given versions. An exact match wins, otherwise the latest
+// stable version starting with the requested version is picked (e.g. 22 resolves to 22.11.0, an empty version to the latest).
+func resolveVersion(version string, strategy provider.ResolutionStrategy, versions []string) (string, error) {
+	if strategy == provider.ResolutionStrategyConstraint {
+		return versionresolver.ResolveConstraint(version, versions)
+	}
+
+	prefix := strings.TrimRight(version, ".")
+	for _, v := range versions {
+		if v == prefix {
+			return v, nil
+		}
+	}
+	for _, v := range versionsort.SortSemverDescending(versions) {
+		if !isStableVersion(v) {
+			continue
+		}
+		if prefix == "" || strings.HasPrefix(v, prefix+".") {
+			return v, nil
+		}
+	}
+	return "", errNoMatchingVersion
+}
+
+func isStableVersion(version string) bool {
+	v, err := semver.NewVersion(version)
+	return err == nil && v.Prerelease() == ""
+}
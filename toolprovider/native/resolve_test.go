@@ -0,0 +1,41 @@
+package native
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+func TestResolveVersion(t *testing.T) {
+	versions := []string{"1.24rc1", "1.23.4", "1.23.10", "1.22.5", "1.22", "1.2.0"}
+
+	tests := []struct {
+		name     string
+		version  string
+		strategy provider.ResolutionStrategy
+		want     string
+		wantErr  bool
+	}{
+		{name: "exact match", version: "1.22", strategy: provider.ResolutionStrategyStrict, want: "1.22"},
+		{name: "exact pre-release match", version: "1.24rc1", strategy: provider.ResolutionStrategyStrict, want: "1.24rc1"},
+		{name: "prefix", version: "1.23", strategy: provider.ResolutionStrategyLatestReleased, want: "1.23.10"},
+		{name: "prefix is not a substring match", version: "1.2", strategy: provider.ResolutionStrategyLatestReleased, want: "1.2.0"},
+		{name: "trailing dot", version: "1.", strategy: provider.ResolutionStrategyLatestReleased, want: "1.23.10"},
+		{name: "latest skips pre-releases", version: "", strategy: provider.ResolutionStrategyLatestReleased, want: "1.23.10"},
+		{name: "constraint", version: "~1.22.0", strategy: provider.ResolutionStrategyConstraint, want: "1.22.5"},
+		{name: "no match", version: "2", strategy: provider.ResolutionStrategyLatestReleased, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveVersion(tt.version, tt.strategy, versions)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package toolprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/alias"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/versionfile"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/versionresolver"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/versionsort"
+)
+
+const eolAPIURL = "https://endoflife.date/api/%s.json"
+
+// eolProducts maps the canonical tool IDs to their endoflife.date product names.
+// Tools missing from this map have no known end-of-life dates.
+var eolProducts = map[provider.ToolID]string{
+	"golang": "go",
+	"nodejs": "nodejs",
+	"python": "python",
+	"ruby":   "ruby",
+	"elixir": "elixir",
+}
+
+// OutdatedTool reports the releases newer than the version a declared tool resolves to.
+type OutdatedTool struct {
+	Tool string `json:"tool"`
+	// Source is the bitrise.yml or version file declaring the tool.
+	Source string `json:"source"`
+	// Requested is the version as declared, e.g. 22:latest.
+	Requested string `json:"requested"`
+	// Pinned is the version the request resolves to: the locked version if the tools lockfile pins it.
+	Pinned string `json:"pinned"`
+	// LatestPatch, LatestMinor and LatestMajor are the newest stable releases within the pinned major.minor,
+	// within the pinned major and overall. The patch and minor releases are empty if the pinned version is not semver.
+	LatestPatch string `json:"latest_patch,omitempty"`
+	LatestMinor string `json:"latest_minor,omitempty"`
+	LatestMajor string `json:"latest_major,omitempty"`
+	Outdated    bool   `json:"outdated"`
+	// EOL is nil if the end-of-life status of the pinned version is unknown.
+	EOL     *bool  `json:"eol,omitempty"`
+	EOLDate string `json:"eol_date,omitempty"`
+}
+
+type declaredTool struct {
+	source  string
+	request provider.ToolRequest
+	// locked is the version pinned by the tools lockfile, if any
+	locked string
+}
+
+// eolCycle is a release cycle (e.g. 22 for Node.js, 3.12 for Python) of the endoflife.date API.
+type eolCycle struct {
+	Cycle string `json:"cycle"`
+	// EOL is the end-of-life date (YYYY-MM-DD) or a bool if the date is unknown.
+	EOL any `json:"eol"`
+}
+
+type eolCyclesFetcher func(product string) ([]eolCycle, error)
+
+// ReportOutdatedTools reports the newer releases and the end-of-life status of the tools declared in the bitrise.yml (if given)
+// and in the version files. The versions pinned by the lockfile are reported for the bitrise.yml tools if the lockfile is given.
+func ReportOutdatedTools(config *models.BitriseDataModel, configPath, workflowID string, versionFilePaths []string, lockfile *ToolsLockfile, providerOverride *string, silent bool) ([]OutdatedTool, error) {
+	providerID := declarativeSetupProvider(models.BitriseDataModel{}, providerOverride)
+
+	var declared []declaredTool
+	if config != nil {
+		providerID = declarativeSetupProvider(*config, providerOverride)
+		requests, err := getDeclarativeSetupToolRequests(*config, workflowID)
+		if err != nil {
+			return nil, err
+		}
+		for _, request := range requests {
+			tool := declaredTool{source: configPath, request: request}
+			if lockfile != nil {
+				if locked, ok := lockfile.lookup(request, providerID); ok {
+					tool.locked = locked.Version
+				}
+			}
+			declared = append(declared, tool)
+		}
+	}
+
+	for _, pth := range versionFilePaths {
+		tools, err := versionfile.Parse(pth)
+		if err != nil {
+			return nil, fmt.Errorf("parse version file %s: %w", pth, err)
+		}
+		for _, tool := range tools {
+			request, err := toolRequestFromVersionFile(tool)
+			if err != nil {
+				return nil, err
+			}
+			declared = append(declared, declaredTool{source: pth, request: request})
+		}
+	}
+
+	if len(declared) == 0 {
+		return []OutdatedTool{}, nil
+	}
+
+	toolProvider, err := CreateProvider(providerID, false, silent, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return reportOutdatedTools(declared, toolProvider, fetchEOLCycles, time.Now(), silent)
+}
+
+func reportOutdatedTools(declared []declaredTool, toolProvider provider.ToolProvider, fetchEOL eolCyclesFetcher, now time.Time, silent bool) ([]OutdatedTool, error) {
+	releasedVersions := map[provider.ToolID][]string{}
+	eolCycles := map[string][]eolCycle{}
+
+	report := make([]OutdatedTool, 0, len(declared))
+	for _, tool := range declared {
+		toolID := alias.GetCanonicalToolID(tool.request.ToolName)
+
+		versions, ok := releasedVersions[toolID]
+		if !ok {
+			var err error
+			versions, err = toolProvider.ListReleasedVersions(toolID)
+			if err != nil {
+				return nil, fmt.Errorf("list released versions of %s: %w", toolID, err)
+			}
+			releasedVersions[toolID] = versions
+		}
+
+		pinned := tool.locked
+		if pinned == "" {
+			var err error
+			pinned, err = resolvePinnedVersion(tool.request, versions)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", toolID, requestedVersionSpec(tool.request), err)
+			}
+		}
+
+		outdated := OutdatedTool{
+			Tool:      string(toolID),
+			Source:    tool.source,
+			Requested: requestedVersionSpec(tool.request),
+			Pinned:    pinned,
+		}
+		outdated.LatestPatch, outdated.LatestMinor, outdated.LatestMajor = latestReleases(pinned, versions)
+		outdated.Outdated = isOlderVersion(pinned, outdated.LatestMajor)
+
+		if product, ok := eolProducts[toolID]; ok {
+			cycles, ok := eolCycles[product]
+			if !ok {
+				var err error
+				cycles, err = fetchEOL(product)
+				if err != nil && !silent {
+					log.Warnf("Failed to get the end-of-life dates of %s: %s", toolID, err)
+				}
+				eolCycles[product] = cycles
+			}
+			outdated.EOL, outdated.EOLDate = eolStatus(cycles, pinned, now)
+		}
+
+		report = append(report, outdated)
+	}
+
+	slices.SortStableFunc(report, func(a, b OutdatedTool) int {
+		return strings.Compare(a.Tool, b.Tool)
+	})
+	return report, nil
+}
+
+// resolvePinnedVersion resolves the request against the released versions, the way the tool providers would install it.
+// Installed versions are not taken into account, :installed requests are resolved to the latest released version.
+func resolvePinnedVersion(request provider.ToolRequest, versions []string) (string, error) {
+	switch request.ResolutionStrategy {
+	case provider.ResolutionStrategyStrict:
+		return request.UnparsedVersion, nil
+	case provider.ResolutionStrategyConstraint:
+		return versionresolver.ResolveConstraint(request.UnparsedVersion, versions)
+	default:
+		prefix := strings.TrimRight(request.UnparsedVersion, ".")
+		for _, v := range versionsort.SortSemverDescending(versions) {
+			if !isStableVersion(v) {
+				continue
+			}
+			if prefix == "" || v == prefix || strings.HasPrefix(v, prefix+".") {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("no released version matches %s", request.UnparsedVersion)
+	}
+}
+
+// latestReleases returns the newest stable releases within the pinned major.minor, within the pinned major and overall.
+func latestReleases(pinned string, versions []string) (patch, minor, major string) {
+	pinnedVersion, pinnedErr := semver.NewVersion(pinned)
+
+	var patchVersion, minorVersion, majorVersion *semver.Version
+	for _, raw := range versions {
+		v, err := semver.NewVersion(raw)
+		if err != nil || !isStableRelease(v) {
+			continue
+		}
+
+		if majorVersion == nil || v.GreaterThan(majorVersion) {
+			majorVersion, major = v, raw
+		}
+		if pinnedErr != nil || v.Major() != pinnedVersion.Major() {
+			continue
+		}
+		if minorVersion == nil || v.GreaterThan(minorVersion) {
+			minorVersion, minor = v, raw
+		}
+		if v.Minor() != pinnedVersion.Minor() {
+			continue
+		}
+		if patchVersion == nil || v.GreaterThan(patchVersion) {
+			patchVersion, patch = v, raw
+		}
+	}
+	return patch, minor, major
+}
+
+func isOlderVersion(version, latest string) bool {
+	if latest == "" {
+		return false
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return version != latest
+	}
+	latestVersion, err := semver.NewVersion(latest)
+	if err != nil {
+		return false
+	}
+	return v.LessThan(latestVersion)
+}
+
+func isStableVersion(version string) bool {
+	v, err := semver.NewVersion(version)
+	return err == nil && isStableRelease(v)
+}
+
+// isStableRelease reports whether the version is not a pre-release. Flutter versions are suffixed with their release channel.
+func isStableRelease(v *semver.Version) bool {
+	return v.Prerelease() == "" || v.Prerelease() == "stable"
+}
+
+// eolStatus looks up the release cycle of the version, the most specific cycle wins (e.g. 3.12 over 3).
+func eolStatus(cycles []eolCycle, version string, now time.Time) (*bool, string) {
+	var match *eolCycle
+	for i, cycle := range cycles {
+		if version != cycle.Cycle && !strings.HasPrefix(version, cycle.Cycle+".") {
+			continue
+		}
+		if match == nil || len(cycle.Cycle) > len(match.Cycle) {
+			match = &cycles[i]
+		}
+	}
+	if match == nil {
+		return nil, ""
+	}
+
+	switch eol := match.EOL.(type) {
+	case bool:
+		return &eol, ""
+	case string:
+		date, err := time.Parse(time.DateOnly, eol)
+		if err != nil {
+			return nil, ""
+		}
+		isEOL := !now.Before(date)
+		return &isEOL, eol
+	default:
+		return nil, ""
+	}
+}
+
+func fetchEOLCycles(product string) ([]eolCycle, error) {
+	logger := log.NewLogger(log.GetGlobalLoggerOpts())
+	client := retryablehttp.NewClient()
+	client.Logger = &log.HTTPLogAdaptor{Logger: logger}
+	client.ErrorHandler = retryablehttp.PassthroughErrorHandler
+
+	url := fmt.Sprintf(eolAPIURL, product)
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", url, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get %s: received status code %d", url, resp.StatusCode)
+	}
+
+	var cycles []eolCycle
+	if err := json.NewDecoder(resp.Body).Decode(&cycles); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", url, err)
+	}
+	return cycles, nil
+}
@@ -0,0 +1,84 @@
+package toolprovider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportOutdatedTools(t *testing.T) {
+	fp := fakeVersionProvider{
+		versions: map[provider.ToolID][]string{
+			"nodejs":  {"18.19.0", "18.20.4", "20.10.0", "20.18.1", "22.11.0", "23.0.0-rc.1"},
+			"golang":  {"1.22.0", "1.22.5", "1.23.4"},
+			"flutter": {"3.24.5-stable", "3.27.1-stable"},
+		},
+	}
+	eolCycles := map[string][]eolCycle{
+		"nodejs": {{Cycle: "18", EOL: "2025-04-30"}, {Cycle: "20", EOL: "2026-04-30"}, {Cycle: "22", EOL: "2027-04-30"}},
+		"go":     {{Cycle: "1.22", EOL: true}, {Cycle: "1.23", EOL: false}},
+	}
+	fetchEOL := func(product string) ([]eolCycle, error) {
+		return eolCycles[product], nil
+	}
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	declared := []declaredTool{
+		{source: "bitrise.yml", request: provider.ToolRequest{ToolName: "node", UnparsedVersion: "18", ResolutionStrategy: provider.ResolutionStrategyLatestReleased}},
+		{source: "bitrise.yml", request: provider.ToolRequest{ToolName: "golang", UnparsedVersion: "1.22", ResolutionStrategy: provider.ResolutionStrategyLatestReleased}, locked: "1.22.0"},
+		{source: "package.json", request: provider.ToolRequest{ToolName: "nodejs", UnparsedVersion: "^20", ResolutionStrategy: provider.ResolutionStrategyConstraint}},
+		{source: ".fvmrc", request: provider.ToolRequest{ToolName: "flutter", UnparsedVersion: "3.24.5-stable", ResolutionStrategy: provider.ResolutionStrategyStrict}},
+	}
+
+	report, err := reportOutdatedTools(declared, fp, fetchEOL, now, true)
+	require.NoError(t, err)
+
+	eol, notEOL := true, false
+	require.Equal(t, []OutdatedTool{
+		{Tool: "flutter", Source: ".fvmrc", Requested: "3.24.5-stable", Pinned: "3.24.5-stable", LatestPatch: "3.24.5-stable", LatestMinor: "3.27.1-stable", LatestMajor: "3.27.1-stable", Outdated: true},
+		{Tool: "golang", Source: "bitrise.yml", Requested: "1.22:latest", Pinned: "1.22.0", LatestPatch: "1.22.5", LatestMinor: "1.23.4", LatestMajor: "1.23.4", Outdated: true, EOL: &eol},
+		{Tool: "nodejs", Source: "bitrise.yml", Requested: "18:latest", Pinned: "18.20.4", LatestPatch: "18.20.4", LatestMinor: "18.20.4", LatestMajor: "22.11.0", Outdated: true, EOL: &eol, EOLDate: "2025-04-30"},
+		{Tool: "nodejs", Source: "package.json", Requested: "^20", Pinned: "20.18.1", LatestPatch: "20.18.1", LatestMinor: "20.18.1", LatestMajor: "22.11.0", Outdated: true, EOL: &notEOL, EOLDate: "2026-04-30"},
+	}, report)
+}
+
+func TestReportOutdatedTools_Errors(t *testing.T) {
+	declared := []declaredTool{
+		{source: "bitrise.yml", request: provider.ToolRequest{ToolName: "nodejs", UnparsedVersion: "22", ResolutionStrategy: provider.ResolutionStrategyLatestReleased}},
+	}
+	fetchEOL := func(string) ([]eolCycle, error) { return nil, errors.New("network error") }
+
+	_, err := reportOutdatedTools(declared, fakeVersionProvider{err: errors.New("network error")}, fetchEOL, time.Now(), true)
+	require.EqualError(t, err, "list released versions of nodejs: network error")
+
+	_, err = reportOutdatedTools(declared, fakeVersionProvider{versions: map[provider.ToolID][]string{"nodejs": {"20.1.0"}}}, fetchEOL, time.Now(), true)
+	require.EqualError(t, err, "nodejs 22:latest: no released version matches 22")
+
+	// The end-of-life status is unknown if the dates can not be fetched
+	report, err := reportOutdatedTools(declared, fakeVersionProvider{versions: map[provider.ToolID][]string{"nodejs": {"22.11.0"}}}, fetchEOL, time.Now(), true)
+	require.NoError(t, err)
+	require.Equal(t, []OutdatedTool{{Tool: "nodejs", Source: "bitrise.yml", Requested: "22:latest", Pinned: "22.11.0", LatestPatch: "22.11.0", LatestMinor: "22.11.0", LatestMajor: "22.11.0"}}, report)
+}
+
+func TestEOLStatus(t *testing.T) {
+	cycles := []eolCycle{{Cycle: "3", EOL: false}, {Cycle: "3.8", EOL: "2024-10-07"}, {Cycle: "3.12", EOL: "2028-10-31"}}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	eol, date := eolStatus(cycles, "3.8.20", now)
+	require.True(t, *eol)
+	require.Equal(t, "2024-10-07", date)
+
+	eol, date = eolStatus(cycles, "3.12.1", now)
+	require.False(t, *eol)
+	require.Equal(t, "2028-10-31", date)
+
+	eol, date = eolStatus(cycles, "3.10.4", now)
+	require.False(t, *eol)
+	require.Empty(t, date)
+
+	eol, _ = eolStatus(cycles, "2.7.18", now)
+	require.Nil(t, eol)
+}
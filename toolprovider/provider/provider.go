@@ -14,6 +14,27 @@ type ToolProvider interface {
 	ListReleasedVersions(toolName ToolID) ([]string, error)
 }
 
+// ConcurrentInstaller is implemented by the providers able to install different tools at the same time.
+// Requests of the same tool are always installed one after another.
+type ConcurrentInstaller interface {
+	SupportsConcurrentInstall() bool
+}
+
+// InstallLocator is implemented by the providers able to locate the files of an installed tool version,
+// so that the installs can be packaged into a tools bundle and unpacked on a machine without network access.
+type InstallLocator interface {
+	// DataDir is the directory the provider installs the tools (and their plugins) into.
+	DataDir() string
+	// InstallPaths returns the paths, relative to DataDir, making up the installed tool version, including its plugin.
+	InstallPaths(result ToolInstallResult) ([]string, error)
+}
+
+// Uninstaller is implemented by the providers able to remove an installed tool version.
+type Uninstaller interface {
+	// UninstallTool removes the tool version, the tool name is the name of the tool's install dir.
+	UninstallTool(toolName ToolID, version string) error
+}
+
 type ToolID string
 
 type ToolRequest struct {
@@ -6,6 +6,7 @@ import (
 	"github.com/bitrise-io/bitrise/v2/toolprovider/asdf"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/asdf/execenv"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/mise"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/native"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
 )
 
@@ -32,6 +33,11 @@ func CreateProvider(providerID string, useFastInstall bool, silent bool, extraEn
 		if err != nil {
 			return nil, fmt.Errorf("create mise tool provider: %w", err)
 		}
+	case "native":
+		tp, err = native.NewToolProvider(native.DataDir(), native.DefaultReleaseIndexURLs, silent)
+		if err != nil {
+			return nil, fmt.Errorf("create native tool provider: %w", err)
+		}
 	default:
 		return nil, fmt.Errorf("unsupported tool provider: %s", providerID)
 	}
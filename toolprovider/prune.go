@@ -0,0 +1,222 @@
+package toolprovider
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/alias"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/asdf"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/mise"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/native"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// ToolInstall is a tool version installed in the data dir of a tool provider.
+type ToolInstall struct {
+	Tool    string `json:"tool"`
+	Version string `json:"version"`
+	// Path is the install dir, relative to the data dir of the provider.
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"size_bytes"`
+	// LastUsed is the last time the tool setup activated the version, the install time if it was never activated since tracking.
+	LastUsed time.Time `json:"last_used"`
+	// Locked reports whether one of the given lockfiles pins the version.
+	Locked bool `json:"locked"`
+	// Prune reports whether the version is selected for removal.
+	Prune bool `json:"prune"`
+}
+
+// PruneOptions selects the tool versions to remove. Versions pinned by a lockfile are always kept.
+type PruneOptions struct {
+	// UnusedFor selects the versions not activated for this long, zero disables this criteria.
+	UnusedFor time.Duration
+	// Lockfiles select the versions pinned by none of them if UnusedFor is zero.
+	Lockfiles []ToolsLockfile
+	DryRun    bool
+}
+
+func (o PruneOptions) selects() bool {
+	return o.UnusedFor > 0 || len(o.Lockfiles) > 0
+}
+
+// PruneToolInstalls lists the tool versions installed by the provider with their size and last use, and removes
+// the versions selected by the options unless it is a dry run. Nothing is removed if the options select nothing.
+func PruneToolInstalls(providerID string, opts PruneOptions, silent bool) ([]ToolInstall, error) {
+	dataDir, err := providerDataDir(providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	installs, err := listToolInstalls(dataDir, opts.Lockfiles, providerID)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.selects() {
+		return installs, nil
+	}
+
+	selectPrunableInstalls(installs, opts, time.Now())
+	if opts.DryRun || !slices.ContainsFunc(installs, func(install ToolInstall) bool { return install.Prune }) {
+		return installs, nil
+	}
+
+	toolProvider, err := CreateProvider(providerID, false, silent, nil)
+	if err != nil {
+		return nil, err
+	}
+	uninstall := func(install ToolInstall) error {
+		return os.RemoveAll(filepath.Join(dataDir, install.Path))
+	}
+	if uninstaller, ok := toolProvider.(provider.Uninstaller); ok {
+		uninstall = func(install ToolInstall) error {
+			return uninstaller.UninstallTool(provider.ToolID(install.Tool), install.Version)
+		}
+	}
+
+	return installs, removeToolInstalls(dataDir, installs, uninstall, silent)
+}
+
+func providerDataDir(providerID string) (string, error) {
+	switch providerID {
+	case "mise":
+		_, dataDir := mise.Dirs(mise.GetMiseVersion())
+		return dataDir, nil
+	case "asdf":
+		return asdf.DataDirFromEnv(nil), nil
+	case "native":
+		return native.DataDir(), nil
+	default:
+		return "", fmt.Errorf("unsupported tool provider: %s", providerID)
+	}
+}
+
+// listToolInstalls lists the <data dir>/installs/<tool>/<version> dirs. Version aliases (e.g. mise's latest symlink) are skipped.
+func listToolInstalls(dataDir string, lockfiles []ToolsLockfile, providerID string) ([]ToolInstall, error) {
+	usage, err := readToolsUsage(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	toolDirs, err := os.ReadDir(filepath.Join(dataDir, "installs"))
+	if errors.Is(err, os.ErrNotExist) {
+		return []ToolInstall{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	installs := []ToolInstall{}
+	for _, toolDir := range toolDirs {
+		if !toolDir.IsDir() || strings.HasPrefix(toolDir.Name(), ".") {
+			continue
+		}
+
+		versionDirs, err := os.ReadDir(filepath.Join(dataDir, "installs", toolDir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, versionDir := range versionDirs {
+			if !versionDir.IsDir() || strings.HasPrefix(versionDir.Name(), ".") {
+				continue
+			}
+
+			install := ToolInstall{
+				Tool:    toolDir.Name(),
+				Version: versionDir.Name(),
+				Path:    filepath.Join("installs", toolDir.Name(), versionDir.Name()),
+			}
+			install.SizeBytes, err = dirSize(filepath.Join(dataDir, install.Path))
+			if err != nil {
+				return nil, err
+			}
+			if lastUsed, ok := usage.LastUsed[install.Path]; ok {
+				install.LastUsed = lastUsed
+			} else if info, err := versionDir.Info(); err == nil {
+				install.LastUsed = info.ModTime()
+			}
+			install.Locked = isLockedInstall(install, lockfiles, providerID)
+
+			installs = append(installs, install)
+		}
+	}
+
+	return installs, nil
+}
+
+func isLockedInstall(install ToolInstall, lockfiles []ToolsLockfile, providerID string) bool {
+	toolID := alias.GetCanonicalToolID(provider.ToolID(install.Tool))
+	for _, lockfile := range lockfiles {
+		for _, tool := range lockfile.Tools {
+			if tool.Provider == providerID && tool.Version == install.Version && alias.GetCanonicalToolID(provider.ToolID(tool.Tool)) == toolID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func selectPrunableInstalls(installs []ToolInstall, opts PruneOptions, now time.Time) {
+	for i, install := range installs {
+		if install.Locked {
+			continue
+		}
+		if opts.UnusedFor > 0 {
+			installs[i].Prune = now.Sub(install.LastUsed) >= opts.UnusedFor
+			continue
+		}
+		installs[i].Prune = len(opts.Lockfiles) > 0
+	}
+}
+
+func removeToolInstalls(dataDir string, installs []ToolInstall, uninstall func(ToolInstall) error, silent bool) error {
+	usage, err := readToolsUsage(dataDir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, install := range installs {
+		if !install.Prune {
+			continue
+		}
+		if err := uninstall(install); err != nil {
+			errs = append(errs, fmt.Errorf("remove %s %s: %w", install.Tool, install.Version, err))
+			continue
+		}
+		delete(usage.LastUsed, install.Path)
+		if !silent {
+			log.Printf("Removed %s %s", install.Tool, install.Version)
+		}
+	}
+
+	if err := usage.write(dataDir); err != nil {
+		errs = append(errs, fmt.Errorf("update %s: %w", toolsUsageFileName, err))
+	}
+	return errors.Join(errs...)
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
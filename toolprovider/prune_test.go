@@ -0,0 +1,92 @@
+package toolprovider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeInstallLocator struct {
+	fakeToolProvider
+	dataDir string
+}
+
+func (f fakeInstallLocator) DataDir() string { return f.dataDir }
+
+func (f fakeInstallLocator) InstallPaths(result provider.ToolInstallResult) ([]string, error) {
+	return []string{filepath.Join("installs", string(result.ToolName), result.ConcreteVersion)}, nil
+}
+
+func createToolInstall(t *testing.T, dataDir, tool, version string, size int) {
+	installDir := filepath.Join(dataDir, "installs", tool, version)
+	require.NoError(t, os.MkdirAll(filepath.Join(installDir, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(installDir, "bin", tool), make([]byte, size), 0755))
+}
+
+func TestPruneToolInstalls(t *testing.T) {
+	dataDir := t.TempDir()
+	createToolInstall(t, dataDir, "node", "18.20.4", 100)
+	createToolInstall(t, dataDir, "node", "20.18.1", 200)
+	createToolInstall(t, dataDir, "ruby", "3.3.6", 300)
+	// Version aliases and metadata files are not installs
+	require.NoError(t, os.Symlink("20.18.1", filepath.Join(dataDir, "installs", "node", "latest")))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "installs", "node", ".mise.backend"), []byte("core:node"), 0644))
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	recordToolUsage(fakeInstallLocator{dataDir: dataDir}, []provider.ToolInstallResult{
+		{ToolName: "node", ConcreteVersion: "18.20.4"},
+	}, now.Add(-60*24*time.Hour), true)
+	recordToolUsage(fakeInstallLocator{dataDir: dataDir}, []provider.ToolInstallResult{
+		{ToolName: "node", ConcreteVersion: "20.18.1"},
+		{ToolName: "ruby", ConcreteVersion: "3.3.6"},
+	}, now.Add(-24*time.Hour), true)
+
+	lockfiles := []ToolsLockfile{{Tools: []LockedTool{{Tool: "nodejs", Requested: "18:latest", Version: "18.20.4", Provider: "mise"}}}}
+	installs, err := listToolInstalls(dataDir, lockfiles, "mise")
+	require.NoError(t, err)
+	require.Equal(t, []ToolInstall{
+		{Tool: "node", Version: "18.20.4", Path: filepath.Join("installs", "node", "18.20.4"), SizeBytes: 100, LastUsed: now.Add(-60 * 24 * time.Hour), Locked: true},
+		{Tool: "node", Version: "20.18.1", Path: filepath.Join("installs", "node", "20.18.1"), SizeBytes: 200, LastUsed: now.Add(-24 * time.Hour)},
+		{Tool: "ruby", Version: "3.3.6", Path: filepath.Join("installs", "ruby", "3.3.6"), SizeBytes: 300, LastUsed: now.Add(-24 * time.Hour)},
+	}, installs)
+
+	t.Run("unused versions", func(t *testing.T) {
+		installs, err := listToolInstalls(dataDir, nil, "mise")
+		require.NoError(t, err)
+		selectPrunableInstalls(installs, PruneOptions{UnusedFor: 30 * 24 * time.Hour}, now)
+		require.Equal(t, []bool{true, false, false}, []bool{installs[0].Prune, installs[1].Prune, installs[2].Prune})
+	})
+
+	t.Run("unlocked versions", func(t *testing.T) {
+		installs, err := listToolInstalls(dataDir, lockfiles, "mise")
+		require.NoError(t, err)
+		selectPrunableInstalls(installs, PruneOptions{Lockfiles: lockfiles}, now)
+		require.Equal(t, []bool{false, true, true}, []bool{installs[0].Prune, installs[1].Prune, installs[2].Prune})
+
+		var uninstalled []string
+		require.NoError(t, removeToolInstalls(dataDir, installs, func(install ToolInstall) error {
+			uninstalled = append(uninstalled, install.Tool+"@"+install.Version)
+			return os.RemoveAll(filepath.Join(dataDir, install.Path))
+		}, true))
+		require.Equal(t, []string{"node@20.18.1", "ruby@3.3.6"}, uninstalled)
+
+		usage, err := readToolsUsage(dataDir)
+		require.NoError(t, err)
+		require.Equal(t, map[string]time.Time{filepath.Join("installs", "node", "18.20.4"): now.Add(-60 * 24 * time.Hour)}, usage.LastUsed)
+
+		installs, err = listToolInstalls(dataDir, nil, "mise")
+		require.NoError(t, err)
+		require.Len(t, installs, 1)
+		require.Equal(t, "18.20.4", installs[0].Version)
+	})
+}
+
+func TestListToolInstalls_NoInstalls(t *testing.T) {
+	installs, err := listToolInstalls(t.TempDir(), nil, "mise")
+	require.NoError(t, err)
+	require.Empty(t, installs)
+}
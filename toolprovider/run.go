@@ -3,6 +3,9 @@ package toolprovider
 import (
 	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/bitrise-io/colorstring"
@@ -13,9 +16,12 @@ import (
 	"github.com/bitrise-io/bitrise/v2/toolprovider/alias"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/asdf"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/asdf/execenv"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/catalog"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/mise"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/native"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/versionresolver"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
 )
 
 type toolSetupResult struct {
@@ -44,7 +50,9 @@ func findGitHubTokenEnv(envs map[string]string) (name, value string, found bool)
 	return "", "", false
 }
 
-func RunDeclarativeSetup(config models.BitriseDataModel, tracker analytics.Tracker, isCI bool, workflowID string, silent bool, providerOverride *string, fastInstallOverride *bool, envs map[string]string) ([]provider.EnvironmentActivation, error) {
+// RunDeclarativeSetup installs the tools of the bitrise.yml tools section. The versions pinned in the lockfile
+// are installed instead of resolving the requested versions again, a nil lockfile resolves every version.
+func RunDeclarativeSetup(config models.BitriseDataModel, tracker analytics.Tracker, isCI bool, workflowID string, silent bool, providerOverride *string, fastInstallOverride *bool, envs map[string]string, lockfile *ToolsLockfile) ([]provider.EnvironmentActivation, error) {
 	toolRequests, err := getToolRequests(config, workflowID)
 	if err != nil {
 		return nil, fmt.Errorf("tools: %w", err)
@@ -54,13 +62,63 @@ func RunDeclarativeSetup(config models.BitriseDataModel, tracker analytics.Track
 		return nil, nil
 	}
 
-	var provider string
+	providerID := declarativeSetupProvider(config, providerOverride)
+	useFastInstall := declarativeSetupFastInstall(config, fastInstallOverride)
+	extraEnvs := declarativeSetupExtraEnvs(envs, silent)
+
+	if lockfile != nil {
+		toolRequests = lockfile.apply(toolRequests, providerID, silent)
+	}
+
+	activations, _, err := installTools(toolRequests, providerID, useFastInstall, tracker, silent, extraEnvs)
+	return activations, err
+}
+
+// RunStepToolSetup installs the tools declared by a Step and its including Step Bundles. The returned envs activate
+// the tools on top of envs, so they are meant to be used only in the Step's environment.
+func RunStepToolSetup(config models.BitriseDataModel, tools models.ToolsModel, tracker analytics.Tracker, envs map[string]string, lockfile *ToolsLockfile) ([]envmanModels.EnvironmentItemModel, error) {
+	toolRequests, err := toolRequestsFromTools(config, tools)
+	if err != nil {
+		return nil, fmt.Errorf("tools: %w", err)
+	}
+
+	if len(toolRequests) == 0 {
+		return nil, nil
+	}
+	// Map iteration order is random, keep the install logs stable
+	slices.SortFunc(toolRequests, func(a, b provider.ToolRequest) int {
+		return strings.Compare(string(a.ToolName), string(b.ToolName))
+	})
+
+	providerID := declarativeSetupProvider(config, nil)
+	useFastInstall := declarativeSetupFastInstall(config, nil)
+	extraEnvs := declarativeSetupExtraEnvs(envs, false)
+
+	if lockfile != nil {
+		toolRequests = lockfile.apply(toolRequests, providerID, false)
+	}
+
+	activations, _, err := installTools(toolRequests, providerID, useFastInstall, tracker, false, extraEnvs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The Step's PATH already contains the tools of the workflow, the Step's tools are prepended to it
+	pathValue, ok := envs["PATH"]
+	if !ok {
+		pathValue = os.Getenv("PATH")
+	}
+	return convertToEnvmanEnvs(activations, pathValue), nil
+}
+
+func declarativeSetupProvider(config models.BitriseDataModel, providerOverride *string) string {
 	if providerOverride != nil {
-		provider = *providerOverride
-	} else {
-		provider = selectProvider(config)
+		return *providerOverride
 	}
+	return selectProvider(config)
+}
 
+func declarativeSetupFastInstall(config models.BitriseDataModel, fastInstallOverride *bool) bool {
 	useFastInstall := DefaultFastInstall()
 	if config.ToolConfig != nil && config.ToolConfig.FastInstall != nil {
 		useFastInstall = *config.ToolConfig.FastInstall
@@ -68,21 +126,25 @@ func RunDeclarativeSetup(config models.BitriseDataModel, tracker analytics.Track
 	if fastInstallOverride != nil {
 		useFastInstall = *fastInstallOverride
 	}
+	return useFastInstall
+}
 
-	var extraEnvs map[string]string
-	if tokenName, tokenValue, ok := findGitHubTokenEnv(envs); ok {
-		if !silent {
-			log.Printf("Using %s for GitHub API authentication during tool setup", tokenName)
-		}
-		// Mise recognizes [a variety of env vars](// See https://mise.jdx.dev/dev-tools/github-tokens.html), but let's use
-		// a generic one because this layer is provider-agnostic.
-		extraEnvs = map[string]string{"GITHUB_TOKEN": tokenValue}
+func declarativeSetupExtraEnvs(envs map[string]string, silent bool) map[string]string {
+	tokenName, tokenValue, ok := findGitHubTokenEnv(envs)
+	if !ok {
+		return nil
 	}
 
-	return installTools(toolRequests, provider, useFastInstall, tracker, silent, extraEnvs)
+	if !silent {
+		log.Printf("Using %s for GitHub API authentication during tool setup", tokenName)
+	}
+	// Mise recognizes [a variety of env vars](// See https://mise.jdx.dev/dev-tools/github-tokens.html), but let's use
+	// a generic one because this layer is provider-agnostic.
+	return map[string]string{"GITHUB_TOKEN": tokenValue}
 }
 
-func installTools(toolRequests []provider.ToolRequest, providerID string, useFastInstall bool, tracker analytics.Tracker, silent bool, extraEnvs map[string]string) ([]provider.EnvironmentActivation, error) {
+// installTools returns the activations and the install results in the order of the requests.
+func installTools(toolRequests []provider.ToolRequest, providerID string, useFastInstall bool, tracker analytics.Tracker, silent bool, extraEnvs map[string]string) ([]provider.EnvironmentActivation, []provider.ToolInstallResult, error) {
 	startTime := time.Now()
 
 	if !silent {
@@ -90,9 +152,22 @@ func installTools(toolRequests []provider.ToolRequest, providerID string, useFas
 	}
 	toolProvider, err := CreateProvider(providerID, useFastInstall, silent, extraEnvs)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	return installToolsWithProvider(toolRequests, providerID, toolProvider, tracker, silent, startTime)
+}
+
+func installToolsWithProvider(
+	toolRequests []provider.ToolRequest,
+	providerID string,
+	toolProvider provider.ToolProvider,
+	tracker analytics.Tracker,
+	silent bool,
+	startTime time.Time,
+) ([]provider.EnvironmentActivation, []provider.ToolInstallResult, error) {
+	applyCatalog(toolRequests, providerID, silent)
+
 	for i, req := range toolRequests {
 		if req.ResolutionStrategy != provider.ResolutionStrategyConstraint {
 			continue
@@ -101,12 +176,12 @@ func installTools(toolRequests []provider.ToolRequest, providerID string, useFas
 		canonicalToolID := alias.GetCanonicalToolID(req.ToolName)
 		versions, err := toolProvider.ListReleasedVersions(canonicalToolID)
 		if err != nil {
-			return nil, fmt.Errorf("list versions for %s: %w", canonicalToolID, err)
+			return nil, nil, fmt.Errorf("list versions for %s: %w", canonicalToolID, err)
 		}
 
 		resolved, err := versionresolver.ResolveConstraint(req.UnparsedVersion, versions)
 		if err != nil {
-			return nil, fmt.Errorf("resolve constraint %q for %s: %w", req.UnparsedVersion, canonicalToolID, err)
+			return nil, nil, fmt.Errorf("resolve constraint %q for %s: %w", req.UnparsedVersion, canonicalToolID, err)
 		}
 
 		if !silent {
@@ -128,6 +203,25 @@ func installTools(toolRequests []provider.ToolRequest, providerID string, useFas
 	return installResolvedTools(toolRequests, providerID, toolProvider, tracker, silent, startTime)
 }
 
+// applyCatalog sets the plugin URL of the tools catalog for the requests without one from tool_config.extra_plugins,
+// and warns about the tools the catalog does not list for the provider. The provider decides whether it can install them.
+func applyCatalog(toolRequests []provider.ToolRequest, providerID string, silent bool) {
+	toolCatalog := catalog.Default()
+	for i, req := range toolRequests {
+		if req.PluginURL != nil {
+			continue
+		}
+		if url := toolCatalog.PluginURL(req.ToolName, providerID); url != "" {
+			toolRequests[i].PluginURL = &url
+			continue
+		}
+		if !silent && !toolCatalog.SupportsProvider(req.ToolName, providerID) {
+			tool, _ := toolCatalog.Lookup(req.ToolName)
+			log.Warnf("%s is not supported by the %s tool provider according to the tools catalog, supported providers: %s", req.ToolName, providerID, strings.Join(tool.Providers, ", "))
+		}
+	}
+}
+
 func installResolvedTools(
 	toolRequests []provider.ToolRequest,
 	providerID string,
@@ -135,7 +229,48 @@ func installResolvedTools(
 	tracker analytics.Tracker,
 	silent bool,
 	startTime time.Time,
-) ([]provider.EnvironmentActivation, error) {
+) ([]provider.EnvironmentActivation, []provider.ToolInstallResult, error) {
+	var toolSetups []toolSetupResult
+	var err error
+	if parallelism := toolSetupParallelism(toolProvider, toolRequests, silent); parallelism > 1 {
+		toolSetups, err = installToolsConcurrently(toolRequests, providerID, toolProvider, tracker, silent, parallelism)
+	} else {
+		toolSetups, err = installToolsSerially(toolRequests, providerID, toolProvider, tracker, silent)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var activations []provider.EnvironmentActivation
+	var results []provider.ToolInstallResult
+	for _, setup := range toolSetups {
+		activation, err := toolProvider.ActivateEnv(setup.result)
+		if err != nil {
+			tracker.SendToolSetupEvent(providerID, setup.request, setup.result, false, time.Since(setup.startTime))
+			return nil, nil, fmt.Errorf("activate %s: %w", setup.result.ToolName, err)
+		}
+		activations = append(activations, activation)
+		results = append(results, setup.result)
+		tracker.SendToolSetupEvent(providerID, setup.request, setup.result, true, time.Since(setup.startTime))
+	}
+	recordToolUsage(toolProvider, results, time.Now(), silent)
+
+	if !silent {
+		duration := time.Since(startTime).Round(time.Millisecond)
+		log.Printf("%s (took %s)", colorstring.Green("✓ Tool setup complete"), duration)
+		log.Printf("")
+	}
+
+	return activations, results, nil
+}
+
+func installToolsSerially(
+	toolRequests []provider.ToolRequest,
+	providerID string,
+	toolProvider provider.ToolProvider,
+	tracker analytics.Tracker,
+	silent bool,
+) ([]toolSetupResult, error) {
 	var toolSetups []toolSetupResult
 	for _, toolRequest := range toolRequests {
 		toolStartTime := time.Now()
@@ -149,14 +284,7 @@ func installResolvedTools(
 		result, err := toolProvider.InstallTool(toolRequest)
 		if err != nil {
 			tracker.SendToolSetupEvent(providerID, toolRequest, result, false, time.Since(toolStartTime))
-
-			var toolErr provider.ToolInstallError
-			if errors.As(err, &toolErr) {
-				printInstallError(toolErr)
-				return nil, fmt.Errorf("see error details above")
-			}
-
-			return nil, fmt.Errorf("install %s %s: %w", toolRequest.ToolName, toolRequest.UnparsedVersion, err)
+			return nil, toolInstallFailure(toolRequest, err)
 		}
 
 		toolSetups = append(toolSetups, toolSetupResult{
@@ -171,34 +299,29 @@ func installResolvedTools(
 		}
 	}
 
-	var activations []provider.EnvironmentActivation
-	for _, setup := range toolSetups {
-		activation, err := toolProvider.ActivateEnv(setup.result)
-		if err != nil {
-			tracker.SendToolSetupEvent(providerID, setup.request, setup.result, false, time.Since(setup.startTime))
-			return nil, fmt.Errorf("activate %s: %w", setup.result.ToolName, err)
-		}
-		activations = append(activations, activation)
-		tracker.SendToolSetupEvent(providerID, setup.request, setup.result, true, time.Since(setup.startTime))
-	}
+	return toolSetups, nil
+}
 
-	if !silent {
-		duration := time.Since(startTime).Round(time.Millisecond)
-		log.Printf("%s (took %s)", colorstring.Green("✓ Tool setup complete"), duration)
-		log.Printf("")
+// toolInstallFailure prints the details of a ToolInstallError and returns the error of the tool setup.
+func toolInstallFailure(toolRequest provider.ToolRequest, err error) error {
+	var toolErr provider.ToolInstallError
+	if errors.As(err, &toolErr) {
+		printInstallError(toolErr)
+		return fmt.Errorf("see error details above")
 	}
 
-	return activations, nil
+	return fmt.Errorf("install %s %s: %w", toolRequest.ToolName, toolRequest.UnparsedVersion, err)
 }
 
 // InstallSingleTool installs a single tool with the specified version using the given provider.
 func InstallSingleTool(toolRequest provider.ToolRequest, providerID string, useFastInstall bool, tracker analytics.Tracker, silent bool) ([]provider.EnvironmentActivation, error) {
 	// extraEnvs=nil: this runs as a CLI subcommand from a user's shell, so secrets are already in the process env.
-	return installTools([]provider.ToolRequest{toolRequest}, providerID, useFastInstall, tracker, silent, nil)
+	activations, _, err := installTools([]provider.ToolRequest{toolRequest}, providerID, useFastInstall, tracker, silent, nil)
+	return activations, err
 }
 
 // GetLatestVersion queries the latest version of a tool without installing it (installed or released).
-// Supports both mise and asdf providers.
+// Supports the mise, asdf and native providers.
 func GetLatestVersion(toolRequest provider.ToolRequest, providerID string, useFastInstall bool, silent bool) (string, error) {
 	canonicalToolID := alias.GetCanonicalToolID(toolRequest.ToolName)
 	toolRequest.ToolName = canonicalToolID
@@ -228,6 +351,12 @@ func GetLatestVersion(toolRequest provider.ToolRequest, providerID string, useFa
 		}
 
 		return miseProvider.ResolveLatestVersion(toolRequest)
+	case "native":
+		nativeProvider, err := native.NewToolProvider(native.DataDir(), native.DefaultReleaseIndexURLs, silent)
+		if err != nil {
+			return "", fmt.Errorf("create native tool provider: %w", err)
+		}
+		return nativeProvider.ResolveLatestVersion(toolRequest)
 	default:
 		return "", fmt.Errorf("unsupported tool provider: %s", providerID)
 	}
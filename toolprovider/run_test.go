@@ -77,7 +77,7 @@ func TestInstallResolvedToolsReportsToolInstallError(t *testing.T) {
 		UnparsedVersion: "999.0.0",
 	}
 
-	_, err := installResolvedTools([]provider.ToolRequest{request}, "mise", fakeToolProvider{
+	_, _, err := installResolvedTools([]provider.ToolRequest{request}, "mise", fakeToolProvider{
 		installErr: provider.ToolInstallError{
 			ToolName:         request.ToolName,
 			RequestedVersion: request.UnparsedVersion,
@@ -110,7 +110,7 @@ func TestInstallResolvedToolsReportsActivationFailure(t *testing.T) {
 		IsAlreadyInstalled: true,
 	}
 
-	_, err := installResolvedTools([]provider.ToolRequest{request}, "asdf", fakeToolProvider{
+	_, _, err := installResolvedTools([]provider.ToolRequest{request}, "asdf", fakeToolProvider{
 		installResult: result,
 		activationErr: fmt.Errorf("activation failed"),
 	}, tracker, true, time.Now())
@@ -139,13 +139,14 @@ func TestInstallResolvedToolsReportsSuccessAfterActivation(t *testing.T) {
 		ContributedEnvVars: map[string]string{"GEM_HOME": "/tmp/gems"},
 	}
 
-	activations, err := installResolvedTools([]provider.ToolRequest{request}, "mise", fakeToolProvider{
+	activations, results, err := installResolvedTools([]provider.ToolRequest{request}, "mise", fakeToolProvider{
 		installResult: result,
 		activation:    activation,
 	}, tracker, true, time.Now())
 
 	require.NoError(t, err)
 	require.Equal(t, []provider.EnvironmentActivation{activation}, activations)
+	require.Equal(t, []provider.ToolInstallResult{result}, results)
 	require.Len(t, tracker.toolSetupCalls, 1)
 	require.Equal(t, trackingCall{
 		provider:     "mise",
@@ -155,6 +156,15 @@ func TestInstallResolvedToolsReportsSuccessAfterActivation(t *testing.T) {
 	}, tracker.toolSetupCalls[0])
 }
 
+func TestApplyCatalog_unsupportedProviderWarning(t *testing.T) {
+	buf := setupTestLogger()
+	applyCatalog([]provider.ToolRequest{{ToolName: "python", UnparsedVersion: "3.12"}}, "native", true)
+	require.Empty(t, buf.String(), "silent mode output (e.g. --format json) must not contain logs")
+
+	applyCatalog([]provider.ToolRequest{{ToolName: "python", UnparsedVersion: "3.12"}}, "native", false)
+	require.Contains(t, buf.String(), "python is not supported by the native tool provider according to the tools catalog")
+}
+
 func TestFindGitHubTokenEnv(t *testing.T) {
 	tests := []struct {
 		name          string
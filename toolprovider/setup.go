@@ -34,7 +34,8 @@ func RunVersionFileSetup(versionFilePaths []string, tracker analytics.Tracker, s
 	}
 
 	// extraEnvs=nil: this runs as a CLI subcommand from a user's shell, so secrets are already in the process env.
-	return installTools(toolRequests, provider, useFastInstall, tracker, silent, nil)
+	activations, _, err := installTools(toolRequests, provider, useFastInstall, tracker, silent, nil)
+	return activations, err
 }
 
 func makeToolRequests(versionFilePaths []string, silent bool) ([]provider.ToolRequest, error) {
@@ -63,7 +64,7 @@ func makeToolRequests(versionFilePaths []string, silent bool) ([]provider.ToolRe
 	}
 
 	// Parse all version files.
-	var allTools []versionfile.ToolVersion
+	var parsedFiles []versionfile.FileToolVersions
 	for _, versionFile := range versionFilePaths {
 		absPath, err := filepath.Abs(versionFile)
 		if err != nil {
@@ -78,7 +79,14 @@ func makeToolRequests(versionFilePaths []string, silent bool) ([]provider.ToolRe
 			return nil, fmt.Errorf("parse version file %s: %w", absPath, err)
 		}
 
-		allTools = append(allTools, tools...)
+		parsedFiles = append(parsedFiles, versionfile.FileToolVersions{Path: absPath, Tools: tools})
+	}
+
+	allTools, overridden := versionfile.MergeToolVersions(parsedFiles)
+	if !silent {
+		for _, tool := range overridden {
+			log.Warnf("Ignoring %s %s from %s, %s takes precedence", tool.ToolName, tool.Version, tool.Path, tool.OverriddenBy)
+		}
 	}
 
 	if !silent && len(allTools) == 0 {
@@ -89,28 +97,35 @@ func makeToolRequests(versionFilePaths []string, silent bool) ([]provider.ToolRe
 	// Convert to tool requests.
 	toolRequests := make([]provider.ToolRequest, 0, len(allTools))
 	for _, tool := range allTools {
-		if tool.IsConstraint {
-			// Semver constraint, resolved to a concrete version before installation.
-			toolRequests = append(toolRequests, provider.ToolRequest{
-				ToolName:           tool.ToolName,
-				UnparsedVersion:    tool.Version,
-				ResolutionStrategy: provider.ResolutionStrategyConstraint,
-			})
-			continue
-		}
-
-		v, strategy, err := ParseVersionString(tool.Version)
+		request, err := toolRequestFromVersionFile(tool)
 		if err != nil {
-			return nil, fmt.Errorf("parse %s version %s: %w", tool.ToolName, tool.Version, err)
+			return nil, err
 		}
+		toolRequests = append(toolRequests, request)
+	}
 
-		toolRequests = append(toolRequests, provider.ToolRequest{
+	return toolRequests, nil
+}
+
+func toolRequestFromVersionFile(tool versionfile.ToolVersion) (provider.ToolRequest, error) {
+	if tool.IsConstraint {
+		// Semver constraint, resolved to a concrete version before installation.
+		return provider.ToolRequest{
 			ToolName:           tool.ToolName,
-			UnparsedVersion:    v,
-			ResolutionStrategy: strategy,
-			PluginURL:          nil,
-		})
+			UnparsedVersion:    tool.Version,
+			ResolutionStrategy: provider.ResolutionStrategyConstraint,
+		}, nil
 	}
 
-	return toolRequests, nil
+	v, strategy, err := ParseToolVersionString(tool.ToolName, tool.Version)
+	if err != nil {
+		return provider.ToolRequest{}, fmt.Errorf("parse %s version %s: %w", tool.ToolName, tool.Version, err)
+	}
+
+	return provider.ToolRequest{
+		ToolName:           tool.ToolName,
+		UnparsedVersion:    v,
+		ResolutionStrategy: strategy,
+		PluginURL:          nil,
+	}, nil
 }
@@ -235,10 +235,28 @@ func TestMakeToolRequestsDuplicateTools(t *testing.T) {
 
 		got, err := makeToolRequests([]string{toolVersionsPath, nodeVersionPath}, true)
 
-		// Both tools should be in the result (duplicates are preserved)
+		// .tool-versions takes precedence over the tool specific version file
 		assert.NoError(t, err)
-		assert.Equal(t, 2, len(got))
+		assert.Equal(t, 1, len(got))
 		assert.Equal(t, "18.0.0", got[0].UnparsedVersion)
-		assert.Equal(t, "16.0.0", got[1].UnparsedVersion)
+	})
+
+	t.Run("tool specific version file takes precedence over project manifest", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		packageJSONPath := filepath.Join(tmpDir, "package.json")
+		err := os.WriteFile(packageJSONPath, []byte(`{"engines":{"node":">=18"}}`), 0644)
+		require.NoError(t, err)
+
+		nvmrcPath := filepath.Join(tmpDir, ".nvmrc")
+		err = os.WriteFile(nvmrcPath, []byte("v20.11.0"), 0644)
+		require.NoError(t, err)
+
+		got, err := makeToolRequests([]string{packageJSONPath, nvmrcPath}, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []provider.ToolRequest{
+			{ToolName: "node", UnparsedVersion: "20.11.0", ResolutionStrategy: provider.ResolutionStrategyStrict},
+		}, got)
 	})
 }
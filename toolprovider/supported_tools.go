@@ -1,63 +1,71 @@
 package toolprovider
 
 import (
-	"slices"
-
-	"github.com/bitrise-io/bitrise/v2/toolprovider/alias"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/catalog"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
 )
 
-// ToolInfo describes a supported tool: its canonical name and any accepted
+// ToolInfo describes a supported tool: its canonical name, any accepted
 // aliases (e.g. "go" for "golang", "node" for "nodejs"), which the CLI treats
-// as equivalent to the canonical name.
+// as equivalent to the canonical name, and its settings from the tools catalog.
 type ToolInfo struct {
-	Name    string   `json:"name"`
-	Aliases []string `json:"aliases,omitempty"`
+	Name              string            `json:"name"`
+	Aliases           []string          `json:"aliases,omitempty"`
+	DefaultResolution string            `json:"default_resolution,omitempty"`
+	Providers         []string          `json:"providers,omitempty"`
+	ExtraPlugins      map[string]string `json:"extra_plugins,omitempty"`
 }
 
-// canonicalToolNames is the source-of-truth list of supported tools, by canonical
+// canonicalToolNames lists the supported tools of the tools catalog, by canonical
 // name (e.g. "golang" not "go", "nodejs" not "node").
-// It is predominantly composed of mise core tools, but can include others, such
-// as flutter.
 func canonicalToolNames() []string {
-	return []string{
-		"bun",
-		"deno",
-		"elixir",
-		"erlang",
-		"flutter",
-		"golang",
-		"java",
-		"nodejs",
-		"python",
-		"ruby",
-		"rust",
-		"swift",
-		"zig",
+	var names []string
+	for _, tool := range catalog.Default().Tools() {
+		names = append(names, tool.Name)
 	}
+	return names
 }
 
 // SupportedTools returns the catalog of tools the CLI advertises and accepts
 // for the "versions" and "list-tools" commands, each with its canonical name
 // and accepted aliases.
 func SupportedTools() []ToolInfo {
-	names := canonicalToolNames()
-	infos := make([]ToolInfo, 0, len(names))
-	for _, name := range names {
+	return supportedTools(catalog.Default())
+}
+
+func supportedTools(toolCatalog *catalog.Catalog) []ToolInfo {
+	tools := toolCatalog.Tools()
+	infos := make([]ToolInfo, 0, len(tools))
+	for _, tool := range tools {
 		var aliases []string
-		for _, aliasID := range alias.AliasesFor(provider.ToolID(name)) {
+		for _, aliasID := range toolCatalog.AliasesFor(provider.ToolID(tool.Name)) {
 			aliases = append(aliases, string(aliasID))
 		}
-		infos = append(infos, ToolInfo{Name: name, Aliases: aliases})
+		infos = append(infos, ToolInfo{
+			Name:              tool.Name,
+			Aliases:           aliases,
+			DefaultResolution: tool.DefaultResolution,
+			Providers:         tool.Providers,
+			ExtraPlugins:      tool.ExtraPlugins,
+		})
 	}
 	return infos
 }
 
+// LoadToolsCatalog returns the supported tools like SupportedTools, but fails if the tools catalog override file is invalid.
+func LoadToolsCatalog() ([]ToolInfo, error) {
+	toolCatalog, err := catalog.Get()
+	if err != nil {
+		return nil, err
+	}
+	return supportedTools(toolCatalog), nil
+}
+
 // IsSupported reports whether the given tool name is supported, resolving
 // aliases (e.g. "go", "node") to their canonical name first.
 func IsSupported(toolName string) bool {
-	canonical := string(alias.GetCanonicalToolID(provider.ToolID(toolName)))
-	return slices.Contains(canonicalToolNames(), canonical)
+	_, ok := catalog.Default().Lookup(provider.ToolID(toolName))
+	return ok
 }
 
 // nonMiseCoreExceptions lists tools in SupportedTools that are intentionally
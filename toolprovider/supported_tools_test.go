@@ -1,13 +1,17 @@
 package toolprovider
 
 import (
+	"os"
+	"path/filepath"
 	"slices"
 	"testing"
 
 	"github.com/bitrise-io/bitrise/v2/toolprovider/alias"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/catalog"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/mise"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSupportedTools_MiseCoreToolConsistency(t *testing.T) {
@@ -61,3 +65,26 @@ func TestSupportedTools_ListsAliases(t *testing.T) {
 	// Tools without aliases don't invent any.
 	assert.Nil(t, byName["ruby"])
 }
+
+func TestSupportedTools_ReflectsCatalogOverride(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "tools-catalog.yml")
+	require.NoError(t, os.WriteFile(pth, []byte(`tools:
+  tuist:
+    providers: [asdf, mise]
+    default_resolution: latest
+    extra_plugins:
+      asdf: https://github.com/tuist/asdf-tuist.git
+`), 0644))
+	toolCatalog, err := catalog.Load(pth)
+	require.NoError(t, err)
+
+	tools := supportedTools(toolCatalog)
+	idx := slices.IndexFunc(tools, func(tool ToolInfo) bool { return tool.Name == "tuist" })
+	require.NotEqual(t, -1, idx)
+	assert.Equal(t, ToolInfo{
+		Name:              "tuist",
+		DefaultResolution: "latest",
+		Providers:         []string{"asdf", "mise"},
+		ExtraPlugins:      map[string]string{"asdf": "https://github.com/tuist/asdf-tuist.git"},
+	}, tools[idx])
+}
@@ -0,0 +1,100 @@
+package toolprovider
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise/v2/log"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// toolsUsageFileName is the file in the data dir of the tool provider tracking when the installed tool versions were last activated.
+const toolsUsageFileName = "bitrise-tools-usage.yml"
+
+const toolsUsageFormatVersion = "1"
+
+type toolsUsage struct {
+	FormatVersion string `yaml:"format_version"`
+	// LastUsed maps the install dirs, relative to the data dir of the provider, to the time they were last activated.
+	LastUsed map[string]time.Time `yaml:"last_used"`
+}
+
+func readToolsUsage(dataDir string) (toolsUsage, error) {
+	usage := toolsUsage{FormatVersion: toolsUsageFormatVersion, LastUsed: map[string]time.Time{}}
+
+	content, err := os.ReadFile(filepath.Join(dataDir, toolsUsageFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return usage, nil
+	}
+	if err != nil {
+		return usage, err
+	}
+
+	if err := yaml.Unmarshal(content, &usage); err != nil {
+		return usage, fmt.Errorf("parse %s: %w", toolsUsageFileName, err)
+	}
+	if usage.LastUsed == nil {
+		usage.LastUsed = map[string]time.Time{}
+	}
+	return usage, nil
+}
+
+func (u toolsUsage) write(dataDir string) error {
+	content, err := yaml.Marshal(u)
+	if err != nil {
+		return err
+	}
+
+	// Concurrent tool setups are not synchronized, but the file is replaced atomically to never leave a partially written file behind.
+	tmpFile, err := os.CreateTemp(dataDir, toolsUsageFileName+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmpFile.Write(content); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+	return os.Rename(tmpFile.Name(), filepath.Join(dataDir, toolsUsageFileName))
+}
+
+// recordToolUsage marks the activated tool versions as used, so that `bitrise tools prune` keeps them.
+// Tracking is best effort, failures do not fail the tool setup.
+func recordToolUsage(toolProvider provider.ToolProvider, results []provider.ToolInstallResult, now time.Time, silent bool) {
+	locator, ok := toolProvider.(provider.InstallLocator)
+	if !ok || len(results) == 0 {
+		return
+	}
+
+	if err := updateToolUsage(locator, results, now); err != nil && !silent {
+		log.Debugf("[TOOLPROVIDER] Failed to record tool usage: %s", err)
+	}
+}
+
+func updateToolUsage(locator provider.InstallLocator, results []provider.ToolInstallResult, now time.Time) error {
+	dataDir := locator.DataDir()
+	usage, err := readToolsUsage(dataDir)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		paths, err := locator.InstallPaths(result)
+		if err != nil {
+			// Fast install (Nix) installs live outside of the data dir, they are not pruned
+			continue
+		}
+		usage.LastUsed[paths[0]] = now
+	}
+
+	return usage.write(dataDir)
+}
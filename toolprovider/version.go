@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/bitrise-io/bitrise/v2/models"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/catalog"
 	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
 )
 
@@ -47,3 +48,16 @@ func ParseVersionString(versionString string) (string, provider.ResolutionStrate
 
 	return plainVersion, resolutionStrategy, nil
 }
+
+// ParseToolVersionString is ParseVersionString applying the default resolution of the tool (see the tools catalog)
+// to plain versions, the ones without the :latest or :installed suffix.
+func ParseToolVersionString(toolID provider.ToolID, versionString string) (string, provider.ResolutionStrategy, error) {
+	v, strategy, err := ParseVersionString(versionString)
+	if err != nil {
+		return "", 0, err
+	}
+	if strategy == provider.ResolutionStrategyStrict {
+		strategy = catalog.Default().DefaultResolution(toolID)
+	}
+	return v, strategy, nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/bitrise-io/bitrise/v2/toolprovider/alias"
@@ -36,6 +37,18 @@ func Parse(path string) ([]ToolVersion, error) {
 		return parsePackageJSON(path)
 	case "fvm_config.json":
 		return parseFVMConfigJSON(path)
+	case "go.mod":
+		return parseGoMod(path)
+	case "rust-toolchain.toml", "rust-toolchain":
+		return parseRustToolchain(path)
+	case ".sdkmanrc":
+		return parseSDKMANRC(path)
+	case "mise.toml", ".mise.toml":
+		return parseMiseTOML(path)
+	case "pyproject.toml":
+		return parsePyprojectTOML(path)
+	case "Gemfile":
+		return parseGemfile(path)
 	default:
 		tool, err := parseSingleToolVersion(path)
 		if err != nil {
@@ -45,26 +58,43 @@ func Parse(path string) ([]ToolVersion, error) {
 	}
 }
 
+// commonVersionFiles are the version files detected in a directory, in the order of their precedence
+// (see MergeToolVersions).
+var commonVersionFiles = []string{
+	"mise.toml",
+	".mise.toml",
+	".tool-versions",
+	".ruby-version",
+	".node-version",
+	".nvmrc",
+	".python-version",
+	".java-version",
+	".go-version",
+	".bun-version",
+	".deno-version",
+	".elixir-version",
+	".erlang-version",
+	".flutter-version",
+	".swift-version",
+	".zig-version",
+	".terraform-version",
+	".kubectl-version",
+	".sdkmanrc",
+	"rust-toolchain.toml",
+	"rust-toolchain",
+	".fvmrc",
+	".fvm/fvm_config.json",
+	"package.json",
+	"go.mod",
+	"pyproject.toml",
+	"Gemfile",
+}
+
 // FindVersionFiles searches for version files in the given directory,
 // returns paths to found version files.
 func FindVersionFiles(dir string) ([]string, error) {
 	var versionFiles []string
 
-	commonVersionFiles := []string{
-		".tool-versions",
-		".ruby-version",
-		".node-version",
-		".nvmrc",
-		".python-version",
-		".java-version",
-		".go-version",
-		".terraform-version",
-		".kubectl-version",
-		".fvmrc",
-		".fvm/fvm_config.json",
-		"package.json",
-	}
-
 	for _, filename := range commonVersionFiles {
 		path := filepath.Join(dir, filename)
 		if _, err := os.Stat(path); err == nil {
@@ -354,3 +384,289 @@ func parseToolVersionsFile(path string) ([]ToolVersion, error) {
 
 	return tools, nil
 }
+
+// parseGoMod parses a go.mod file to extract the Go version. The toolchain directive is preferred over
+// the go directive, as it names the exact toolchain the module is developed with.
+func parseGoMod(path string) ([]ToolVersion, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	goVersion := ""
+	toolchainVersion := ""
+	for line := range strings.SplitSeq(string(content), "\n") {
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "go":
+			goVersion = fields[1]
+		case "toolchain":
+			// The toolchain name is go<version>, the special "default" value means the go directive applies.
+			if fields[1] != "default" {
+				toolchainVersion = strings.TrimPrefix(fields[1], "go")
+			}
+		}
+	}
+
+	version := toolchainVersion
+	if version == "" {
+		version = goVersion
+	}
+	if version == "" {
+		return nil, fmt.Errorf("%s: no go or toolchain directive found", path)
+	}
+
+	return []ToolVersion{
+		{ToolName: alias.GetCanonicalToolID("go"), Version: version},
+	}, nil
+}
+
+// parseRustToolchain parses a rust-toolchain.toml or a legacy rust-toolchain file to extract the Rust channel.
+// The channel can be a version (1.75.0) or a release channel (stable, beta, nightly).
+func parseRustToolchain(path string) ([]ToolVersion, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	channel := ""
+	if filepath.Base(path) == "rust-toolchain.toml" || strings.Contains(string(content), "[toolchain]") {
+		doc, err := parseTOML(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		channel = doc["toolchain"]["channel"].str
+	} else {
+		// The legacy rust-toolchain file contains the channel only.
+		channel = strings.TrimSpace(string(content))
+	}
+
+	if channel == "" {
+		return nil, fmt.Errorf("%s: missing toolchain channel", path)
+	}
+
+	return []ToolVersion{
+		{ToolName: "rust", Version: channel},
+	}, nil
+}
+
+// sdkmanJavaVendors maps the SDKMAN! Java vendor identifiers to the vendor prefixes of the tool providers.
+var sdkmanJavaVendors = map[string]string{
+	"tem":     "temurin",
+	"zulu":    "zulu",
+	"librca":  "liberica",
+	"amzn":    "corretto",
+	"open":    "openjdk",
+	"ms":      "microsoft",
+	"sapmchn": "sapmachine",
+	"sem":     "semeru",
+	"graalce": "graalvm-community",
+	"oracle":  "oracle",
+}
+
+// parseSDKMANRC parses an SDKMAN! .sdkmanrc file, one "<candidate>=<version>" per line.
+// Java versions with a vendor suffix (21.0.2-tem) are converted to the vendor prefixed format (temurin-21.0.2).
+func parseSDKMANRC(path string) ([]ToolVersion, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var tools []ToolVersion
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		candidate, version, ok := strings.Cut(line, "=")
+		candidate = strings.TrimSpace(candidate)
+		version = strings.TrimSpace(version)
+		if !ok || candidate == "" || version == "" {
+			return nil, fmt.Errorf("%s:%d: invalid format, expected '<candidate>=<version>'", path, i+1)
+		}
+
+		if candidate == "java" {
+			version = sdkmanJavaVersion(version)
+		}
+
+		tools = append(tools, ToolVersion{
+			ToolName: alias.GetCanonicalToolID(provider.ToolID(candidate)),
+			Version:  version,
+		})
+	}
+
+	return tools, nil
+}
+
+func sdkmanJavaVersion(version string) string {
+	idx := strings.LastIndex(version, "-")
+	if idx == -1 {
+		return version
+	}
+
+	vendor, ok := sdkmanJavaVendors[version[idx+1:]]
+	if !ok {
+		return version
+	}
+	return vendor + "-" + version[:idx]
+}
+
+// parseMiseTOML parses the [tools] table of a mise.toml or .mise.toml file. A tool can have a version,
+// a list of versions or an inline table with a version key.
+func parseMiseTOML(path string) ([]ToolVersion, error) {
+	doc, err := readTOMLFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	toolsTable := doc["tools"]
+	names := make([]string, 0, len(toolsTable))
+	for name := range toolsTable {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var tools []ToolVersion
+	for _, name := range names {
+		value := toolsTable[name]
+
+		var versions []string
+		switch {
+		case value.list != nil:
+			versions = value.list
+		case value.table != nil:
+			versions = []string{value.table["version"]}
+		default:
+			versions = []string{value.str}
+		}
+
+		for _, version := range versions {
+			if version == "" {
+				return nil, fmt.Errorf("%s: tools.%s: empty version", path, name)
+			}
+			tools = append(tools, ToolVersion{ToolName: provider.ToolID(name), Version: version})
+		}
+	}
+
+	return tools, nil
+}
+
+// parsePyprojectTOML parses a pyproject.toml file to extract the Python version constraint from
+// project.requires-python (PEP 621) or tool.poetry.dependencies.python.
+func parsePyprojectTOML(path string) ([]ToolVersion, error) {
+	doc, err := readTOMLFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	constraint := ""
+	if requiresPython := doc["project"]["requires-python"].str; requiresPython != "" {
+		constraint = convertPEP440Constraint(requiresPython)
+	} else if poetryPython := doc["tool.poetry.dependencies"]["python"].str; poetryPython != "" {
+		// Poetry constraints use the npm semver syntax, except for the comma separated ranges.
+		constraint = strings.Join(strings.Fields(strings.ReplaceAll(poetryPython, ",", " ")), " ")
+	}
+
+	if constraint == "" {
+		// No Python version requirement is common in pyproject.toml, silently skip.
+		return nil, nil
+	}
+
+	return []ToolVersion{
+		{ToolName: "python", Version: constraint, IsConstraint: true},
+	}, nil
+}
+
+// convertPEP440Constraint converts a PEP 440 version specifier (>=3.11,<3.13 or ~=3.11) to the npm semver syntax.
+func convertPEP440Constraint(specifier string) string {
+	var parts []string
+	for clause := range strings.SplitSeq(specifier, ",") {
+		clause = strings.ReplaceAll(strings.TrimSpace(clause), " ", "")
+		switch {
+		case clause == "":
+			continue
+		case strings.HasPrefix(clause, "~="):
+			// Compatible release: ~=3.11 allows 3.x from 3.11, ~=3.11.2 allows 3.11.x from 3.11.2
+			version := strings.TrimPrefix(clause, "~=")
+			if strings.Count(version, ".") >= 2 {
+				parts = append(parts, "~"+version)
+			} else {
+				parts = append(parts, "^"+version)
+			}
+		case strings.HasPrefix(clause, "==="):
+			parts = append(parts, strings.TrimPrefix(clause, "==="))
+		case strings.HasPrefix(clause, "=="):
+			parts = append(parts, strings.ReplaceAll(strings.TrimPrefix(clause, "=="), "*", "x"))
+		default:
+			parts = append(parts, clause)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseGemfile parses a Gemfile to extract the Ruby version from the ruby directive, e.g. ruby "3.3.0" or ruby "~> 3.3".
+// The ruby file: ".ruby-version" form is skipped, the referenced file is detected on its own.
+func parseGemfile(path string) ([]ToolVersion, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	for line := range strings.SplitSeq(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "ruby ") && !strings.HasPrefix(line, "ruby(") {
+			continue
+		}
+
+		args := strings.TrimSpace(strings.TrimPrefix(line, "ruby"))
+		args = strings.TrimSuffix(strings.TrimPrefix(args, "("), ")")
+		if args == "" || (args[0] != '"' && args[0] != '\'') {
+			return nil, nil
+		}
+
+		// Only the first argument is the version, the others are options like engine: "jruby".
+		quote := args[0]
+		end := strings.IndexByte(args[1:], quote)
+		if end == -1 {
+			return nil, fmt.Errorf("%s: invalid ruby directive: %s", path, line)
+		}
+		version := strings.TrimSpace(args[1 : end+1])
+		if version == "" {
+			return nil, fmt.Errorf("%s: empty ruby version", path)
+		}
+
+		if constraint, ok := convertRubyGemsConstraint(version); ok {
+			return []ToolVersion{{ToolName: "ruby", Version: constraint, IsConstraint: true}}, nil
+		}
+		return []ToolVersion{{ToolName: "ruby", Version: version}}, nil
+	}
+
+	return nil, nil
+}
+
+// convertRubyGemsConstraint converts a RubyGems requirement (~> 3.3, >= 3.2) to the npm semver syntax.
+// It returns false for plain versions.
+func convertRubyGemsConstraint(requirement string) (string, bool) {
+	if !strings.ContainsAny(requirement, "~<>=!") {
+		return "", false
+	}
+
+	if version, ok := strings.CutPrefix(requirement, "~>"); ok {
+		version = strings.TrimSpace(version)
+		// Pessimistic operator: ~> 3.3 allows 3.x from 3.3, ~> 3.3.1 allows 3.3.x from 3.3.1
+		if strings.Count(version, ".") >= 2 {
+			return "~" + version, true
+		}
+		return "^" + version, true
+	}
+
+	return strings.ReplaceAll(requirement, " ", ""), true
+}
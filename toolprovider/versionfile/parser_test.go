@@ -623,3 +623,184 @@ func TestParsePackageJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAdditionalVersionFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		want     []ToolVersion
+		wantErr  string
+	}{
+		{
+			name:     "go.mod go directive",
+			filename: "go.mod",
+			content:  "module example.com/app\n\ngo 1.22.4\n\nrequire github.com/stretchr/testify v1.9.0\n",
+			want:     []ToolVersion{{ToolName: "golang", Version: "1.22.4"}},
+		},
+		{
+			name:     "go.mod toolchain directive wins",
+			filename: "go.mod",
+			content:  "module example.com/app\n\ngo 1.22\n\ntoolchain go1.23.2 // pinned\n",
+			want:     []ToolVersion{{ToolName: "golang", Version: "1.23.2"}},
+		},
+		{
+			name:     "go.mod without go directive",
+			filename: "go.mod",
+			content:  "module example.com/app\n",
+			wantErr:  "no go or toolchain directive found",
+		},
+		{
+			name:     "rust-toolchain.toml",
+			filename: "rust-toolchain.toml",
+			content:  "[toolchain]\nchannel = \"1.75.0\" # MSRV\ncomponents = [\"rustfmt\", \"clippy\"]\n",
+			want:     []ToolVersion{{ToolName: "rust", Version: "1.75.0"}},
+		},
+		{
+			name:     "legacy rust-toolchain",
+			filename: "rust-toolchain",
+			content:  "stable\n",
+			want:     []ToolVersion{{ToolName: "rust", Version: "stable"}},
+		},
+		{
+			name:     "sdkmanrc",
+			filename: ".sdkmanrc",
+			content:  "# Enable auto-env through the sdkman_auto_env config\njava=21.0.2-tem\ngradle=8.5\nkotlin=1.9.22\n",
+			want: []ToolVersion{
+				{ToolName: "java", Version: "temurin-21.0.2"},
+				{ToolName: "gradle", Version: "8.5"},
+				{ToolName: "kotlin", Version: "1.9.22"},
+			},
+		},
+		{
+			name:     "sdkmanrc invalid line",
+			filename: ".sdkmanrc",
+			content:  "java\n",
+			wantErr:  ":1: invalid format, expected '<candidate>=<version>'",
+		},
+		{
+			name:     "mise.toml",
+			filename: "mise.toml",
+			content: `[env]
+NODE_ENV = "production"
+
+[tools]
+node = "20"
+python = ["3.11", "3.12"]
+ruby = { version = "3.3", install_env = { RUBY_CONFIGURE_OPTS = "--enable-shared" } }
+"npm:prettier" = "latest"
+`,
+			want: []ToolVersion{
+				{ToolName: "node", Version: "20"},
+				{ToolName: "npm:prettier", Version: "latest"},
+				{ToolName: "python", Version: "3.11"},
+				{ToolName: "python", Version: "3.12"},
+				{ToolName: "ruby", Version: "3.3"},
+			},
+		},
+		{
+			name:     ".mise.toml without tools",
+			filename: ".mise.toml",
+			content:  "[settings]\nexperimental = true\n",
+		},
+		{
+			name:     "pyproject.toml requires-python",
+			filename: "pyproject.toml",
+			content:  "[project]\nname = \"app\"\nrequires-python = \">=3.11, <3.13\"\ndependencies = [\n  \"requests>=2\",\n]\n",
+			want:     []ToolVersion{{ToolName: "python", Version: ">=3.11 <3.13", IsConstraint: true}},
+		},
+		{
+			name:     "pyproject.toml poetry",
+			filename: "pyproject.toml",
+			content:  "[tool.poetry.dependencies]\npython = \"^3.10\"\n",
+			want:     []ToolVersion{{ToolName: "python", Version: "^3.10", IsConstraint: true}},
+		},
+		{
+			name:     "pyproject.toml with multi-line strings",
+			filename: "pyproject.toml",
+			content: `[project]
+name = "app"
+description = """
+An app = with "quotes", [brackets]
+# and a line looking like a comment
+"""
+readme = """README.md"""
+requires-python = ">=3.11"
+
+[tool.black]
+line-length = 100
+extend-exclude = '''
+(
+  ^/build/
+  | \.venv
+)
+'''
+`,
+			want: []ToolVersion{{ToolName: "python", Version: ">=3.11", IsConstraint: true}},
+		},
+		{
+			name:     "pyproject.toml with unterminated multi-line string",
+			filename: "pyproject.toml",
+			content:  "[project]\ndescription = \"\"\"\nAn app\n",
+			wantErr:  "line 2: unterminated multi-line string",
+		},
+		{
+			name:     "pyproject.toml without python requirement",
+			filename: "pyproject.toml",
+			content:  "[build-system]\nrequires = [\"setuptools\"]\n",
+		},
+		{
+			name:     "Gemfile ruby version",
+			filename: "Gemfile",
+			content:  "source \"https://rubygems.org\"\n\nruby \"3.3.0\"\n\ngem \"fastlane\"\n",
+			want:     []ToolVersion{{ToolName: "ruby", Version: "3.3.0"}},
+		},
+		{
+			name:     "Gemfile ruby requirement",
+			filename: "Gemfile",
+			content:  "ruby '~> 3.2', engine: 'ruby'\n",
+			want:     []ToolVersion{{ToolName: "ruby", Version: "^3.2", IsConstraint: true}},
+		},
+		{
+			name:     "Gemfile ruby version file reference",
+			filename: "Gemfile",
+			content:  "ruby file: \".ruby-version\"\n",
+		},
+		{
+			name:     "bun version file",
+			filename: ".bun-version",
+			content:  "1.1.30\n",
+			want:     []ToolVersion{{ToolName: "bun", Version: "1.1.30"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.filename)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			got, err := Parse(path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertPEP440Constraint(t *testing.T) {
+	tests := map[string]string{
+		">=3.11":        ">=3.11",
+		">=3.11, <3.13": ">=3.11 <3.13",
+		"~=3.11":        "^3.11",
+		"~=3.11.2":      "~3.11.2",
+		"==3.12.*":      "3.12.x",
+		"== 3.12.1":     "3.12.1",
+	}
+	for specifier, want := range tests {
+		assert.Equal(t, want, convertPEP440Constraint(specifier), specifier)
+	}
+}
@@ -0,0 +1,81 @@
+package versionfile
+
+import (
+	"path/filepath"
+
+	"github.com/bitrise-io/bitrise/v2/toolprovider/alias"
+	"github.com/bitrise-io/bitrise/v2/toolprovider/provider"
+)
+
+// Precedence of the version files specifying the same tool, the lower wins.
+const (
+	// Tool manager configs (mise.toml, .tool-versions) are written for setting up the tools.
+	precedenceToolManagerConfig = iota
+	// Tool specific version files (.node-version, .nvmrc, .sdkmanrc, rust-toolchain.toml, .fvmrc) name a single version.
+	precedenceVersionFile
+	// Project manifests (package.json, go.mod, pyproject.toml, Gemfile) usually state the minimum supported version.
+	precedenceProjectManifest
+)
+
+// FileToolVersions are the tools parsed from a version file.
+type FileToolVersions struct {
+	Path  string
+	Tools []ToolVersion
+}
+
+// OverriddenToolVersion is a tool version ignored because a version file with higher precedence specifies the same tool.
+type OverriddenToolVersion struct {
+	ToolVersion
+	Path         string
+	OverriddenBy string
+}
+
+func precedence(path string) int {
+	switch filepath.Base(path) {
+	case "mise.toml", ".mise.toml", ".tool-versions":
+		return precedenceToolManagerConfig
+	case "package.json", "go.mod", "pyproject.toml", "Gemfile":
+		return precedenceProjectManifest
+	default:
+		return precedenceVersionFile
+	}
+}
+
+// MergeToolVersions selects the version file used for each tool when several files specify the same tool:
+//   - tool manager configs (mise.toml, .mise.toml, .tool-versions) win over tool specific version files,
+//     which win over project manifests (package.json, go.mod, pyproject.toml, Gemfile)
+//   - between files of the same kind, the first one in the list wins
+//
+// Every version of a tool is kept from the selected file (e.g. the Flutter flavors of .fvmrc).
+// The tools are returned in the order of the files.
+func MergeToolVersions(files []FileToolVersions) ([]ToolVersion, []OverriddenToolVersion) {
+	selectedFile := map[provider.ToolID]int{}
+	for fileIdx, file := range files {
+		for _, tool := range file.Tools {
+			toolID := alias.GetCanonicalToolID(tool.ToolName)
+			selectedIdx, ok := selectedFile[toolID]
+			if !ok || precedence(file.Path) < precedence(files[selectedIdx].Path) {
+				selectedFile[toolID] = fileIdx
+			}
+		}
+	}
+
+	var tools []ToolVersion
+	var overridden []OverriddenToolVersion
+	for fileIdx, file := range files {
+		for _, tool := range file.Tools {
+			selectedIdx := selectedFile[alias.GetCanonicalToolID(tool.ToolName)]
+			if selectedIdx == fileIdx {
+				tools = append(tools, tool)
+				continue
+			}
+			overridden = append(overridden, OverriddenToolVersion{
+				ToolVersion:  tool,
+				Path:         file.Path,
+				OverriddenBy: files[selectedIdx].Path,
+			})
+		}
+	}
+
+	return tools, overridden
+}
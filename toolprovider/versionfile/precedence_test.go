@@ -0,0 +1,35 @@
+package versionfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeToolVersions(t *testing.T) {
+	files := []FileToolVersions{
+		{Path: "/app/package.json", Tools: []ToolVersion{{ToolName: "nodejs", Version: ">=18", IsConstraint: true}}},
+		{Path: "/app/.nvmrc", Tools: []ToolVersion{{ToolName: "node", Version: "20.11.0"}}},
+		{Path: "/app/.fvmrc", Tools: []ToolVersion{{ToolName: "flutter", Version: "3.24.0"}, {ToolName: "flutter", Version: "3.22.0"}}},
+		{Path: "/app/.tool-versions", Tools: []ToolVersion{{ToolName: "ruby", Version: "3.3.0"}, {ToolName: "flutter", Version: "3.19.0"}}},
+		{Path: "/app/.ruby-version", Tools: []ToolVersion{{ToolName: "ruby", Version: "3.2.0"}}},
+		{Path: "/app/.python-version", Tools: []ToolVersion{{ToolName: "python", Version: "3.12"}}},
+		{Path: "/app/other/.python-version", Tools: []ToolVersion{{ToolName: "python", Version: "3.11"}}},
+	}
+
+	tools, overridden := MergeToolVersions(files)
+
+	assert.Equal(t, []ToolVersion{
+		{ToolName: "node", Version: "20.11.0"},
+		{ToolName: "ruby", Version: "3.3.0"},
+		{ToolName: "flutter", Version: "3.19.0"},
+		{ToolName: "python", Version: "3.12"},
+	}, tools)
+	assert.Equal(t, []OverriddenToolVersion{
+		{ToolVersion: ToolVersion{ToolName: "nodejs", Version: ">=18", IsConstraint: true}, Path: "/app/package.json", OverriddenBy: "/app/.nvmrc"},
+		{ToolVersion: ToolVersion{ToolName: "flutter", Version: "3.24.0"}, Path: "/app/.fvmrc", OverriddenBy: "/app/.tool-versions"},
+		{ToolVersion: ToolVersion{ToolName: "flutter", Version: "3.22.0"}, Path: "/app/.fvmrc", OverriddenBy: "/app/.tool-versions"},
+		{ToolVersion: ToolVersion{ToolName: "ruby", Version: "3.2.0"}, Path: "/app/.ruby-version", OverriddenBy: "/app/.tool-versions"},
+		{ToolVersion: ToolVersion{ToolName: "python", Version: "3.11"}, Path: "/app/other/.python-version", OverriddenBy: "/app/.python-version"},
+	}, overridden)
+}
@@ -0,0 +1,217 @@
+package versionfile
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// tomlValue is a value of the TOML subset used by version files: a string, an array of strings
+// or an inline table of strings. Other scalars (numbers, booleans) are kept as their raw string.
+type tomlValue struct {
+	str   string
+	list  []string
+	table map[string]string
+}
+
+// tomlDocument maps table names ("" for the root table) to their keys.
+type tomlDocument map[string]map[string]tomlValue
+
+// readTOMLFile reads the tables and keys of a TOML file. It supports the syntax version files use in practice,
+// not the full TOML spec: arrays of tables and the values of multi-line strings are skipped.
+func readTOMLFile(path string) (tomlDocument, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	doc, err := parseTOML(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+func parseTOML(content string) (tomlDocument, error) {
+	doc := tomlDocument{"": {}}
+	table := ""
+	skipTable := false
+
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		line := strings.TrimSpace(stripTOMLComment(lines[i]))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[[") {
+			// Arrays of tables are not used by the supported version files.
+			skipTable = true
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: invalid table header", lineNum)
+			}
+			table = normalizeTOMLKey(strings.TrimSpace(line[1 : len(line)-1]))
+			skipTable = false
+			if _, ok := doc[table]; !ok {
+				doc[table] = map[string]tomlValue{}
+			}
+			continue
+		}
+
+		key, rawValue, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNum)
+		}
+		rawValue = strings.TrimSpace(rawValue)
+
+		// Multi-line strings continue until their closing delimiter, which can be on the opening line too.
+		// Their lines can contain anything, they are not parsed and the value is skipped.
+		if delimiter := rawValue[:min(3, len(rawValue))]; delimiter == `"""` || delimiter == "'''" {
+			_, rest, _ := strings.Cut(lines[i], delimiter)
+			for !strings.Contains(rest, delimiter) {
+				if i+1 == len(lines) {
+					return nil, fmt.Errorf("line %d: unterminated multi-line string", lineNum)
+				}
+				i++
+				rest = lines[i]
+			}
+			continue
+		}
+
+		// Multi-line arrays and inline tables continue until their brackets are closed.
+		for !isTOMLValueComplete(rawValue) && i+1 < len(lines) {
+			i++
+			rawValue += " " + strings.TrimSpace(stripTOMLComment(lines[i]))
+		}
+
+		if skipTable {
+			continue
+		}
+
+		value, err := parseTOMLValue(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		doc[table][normalizeTOMLKey(strings.TrimSpace(key))] = value
+	}
+
+	return doc, nil
+}
+
+func parseTOMLValue(raw string) (tomlValue, error) {
+	switch {
+	case strings.HasPrefix(raw, "["):
+		if !strings.HasSuffix(raw, "]") {
+			return tomlValue{}, fmt.Errorf("unterminated array")
+		}
+		var list []string
+		for _, item := range splitTOMLItems(raw[1 : len(raw)-1]) {
+			list = append(list, unquoteTOMLString(item))
+		}
+		return tomlValue{list: list}, nil
+	case strings.HasPrefix(raw, "{"):
+		if !strings.HasSuffix(raw, "}") {
+			return tomlValue{}, fmt.Errorf("unterminated inline table")
+		}
+		table := map[string]string{}
+		for _, item := range splitTOMLItems(raw[1 : len(raw)-1]) {
+			key, value, ok := strings.Cut(item, "=")
+			if !ok {
+				return tomlValue{}, fmt.Errorf("invalid inline table item: %s", item)
+			}
+			table[normalizeTOMLKey(strings.TrimSpace(key))] = unquoteTOMLString(strings.TrimSpace(value))
+		}
+		return tomlValue{table: table}, nil
+	default:
+		return tomlValue{str: unquoteTOMLString(raw)}, nil
+	}
+}
+
+// splitTOMLItems splits the items of an array or inline table at the commas outside of strings and nested brackets.
+func splitTOMLItems(s string) []string {
+	var items []string
+	var quote rune
+	depth := 0
+	start := 0
+	for i, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		items = append(items, last)
+	}
+	return items
+}
+
+func isTOMLValueComplete(raw string) bool {
+	depth := 0
+	var quote rune
+	for _, c := range raw {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+func stripTOMLComment(line string) string {
+	var quote rune
+	for i, c := range line {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// normalizeTOMLKey removes the quotes of a quoted key or of the quoted parts of a dotted table name.
+func normalizeTOMLKey(key string) string {
+	parts := strings.Split(key, ".")
+	if len(parts) == 1 || strings.HasPrefix(key, `"`) && strings.HasSuffix(key, `"`) && strings.Count(key, `"`) == 2 {
+		return unquoteTOMLString(key)
+	}
+	for i, part := range parts {
+		parts[i] = unquoteTOMLString(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ".")
+}
+
+func unquoteTOMLString(s string) string {
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
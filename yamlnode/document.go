@@ -0,0 +1,114 @@
+package yamlnode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const documentStartMarker = "---"
+
+// Document is a parsed YAML document with a mapping root node.
+type Document struct {
+	Node yaml.Node
+	Root *yaml.Node
+
+	original []byte
+}
+
+// ParseMapping parses the given YAML document, which should have a mapping root.
+func ParseMapping(content []byte) (*Document, error) {
+	doc := Document{original: content}
+	if err := yaml.Unmarshal(content, &doc.Node); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if doc.Node.Kind != yaml.DocumentNode || len(doc.Node.Content) == 0 || doc.Node.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("config should be a YAML map")
+	}
+	doc.Root = doc.Node.Content[0]
+	return &doc, nil
+}
+
+// Encode serializes the document with 2 space indentation.
+// The blank lines separating the top level sections of the original document,
+// which are dropped by the YAML encoder, are added back.
+func (d *Document) Encode() ([]byte, error) {
+	clearMergeTags(&d.Node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&d.Node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	encoded := restoreSectionSeparators(d.original, d.Root, buf.Bytes())
+	if hasDocumentStartMarker(d.original) && !bytes.HasPrefix(encoded, []byte(documentStartMarker)) {
+		encoded = append([]byte(documentStartMarker+"\n"), encoded...)
+	}
+	return encoded, nil
+}
+
+// hasDocumentStartMarker returns true if the document starts with an explicit document start marker (---),
+// which is dropped by the YAML encoder.
+func hasDocumentStartMarker(content []byte) bool {
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return line == documentStartMarker || strings.HasPrefix(line, documentStartMarker+" ")
+	}
+	return false
+}
+
+// clearMergeTags drops the tag of the merge keys, the encoder would print it explicitly (`!!merge <<: *anchor`).
+func clearMergeTags(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!merge" {
+		node.Tag = ""
+	}
+	for _, child := range node.Content {
+		clearMergeTags(child)
+	}
+}
+
+func restoreSectionSeparators(original []byte, root *yaml.Node, encoded []byte) []byte {
+	originalLines := strings.Split(string(original), "\n")
+	separated := map[string]bool{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key := root.Content[i]
+		if key.Line == 0 {
+			continue
+		}
+		firstLine := key.Line - 1
+		if key.HeadComment != "" {
+			firstLine -= strings.Count(key.HeadComment, "\n") + 1
+		}
+		if firstLine > 0 && firstLine <= len(originalLines) && strings.TrimSpace(originalLines[firstLine-1]) == "" {
+			separated[key.Value] = true
+		}
+	}
+
+	lines := strings.Split(string(encoded), "\n")
+	var result []string
+	for idx, line := range lines {
+		key, _, isKey := strings.Cut(line, ":")
+		if isKey && line != "" && !strings.ContainsAny(line[:1], " #-") && separated[strings.Trim(key, `"'`)] && idx > 0 {
+			// Insert the separator above the key's head comment
+			pos := len(result)
+			for pos > 0 && strings.HasPrefix(result[pos-1], "#") {
+				pos--
+			}
+			if pos > 0 {
+				result = append(result[:pos], append([]string{""}, result[pos:]...)...)
+			}
+		}
+		result = append(result, line)
+	}
+	return []byte(strings.Join(result, "\n"))
+}
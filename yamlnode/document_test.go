@@ -0,0 +1,75 @@
+package yamlnode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_Encode(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "section separators",
+			content: `a: 1
+
+# b section
+b:
+    c: 2
+d: 3
+`,
+			want: `a: 1
+
+# b section
+b:
+  c: 2
+d: 3
+`,
+		},
+		{
+			name: "document start marker",
+			content: `# config
+---
+a: 1
+`,
+			want: `---
+# config
+a: 1
+`,
+		},
+		{
+			name: "merge keys",
+			content: `a: &a
+  b: 1
+c:
+  <<: *a
+`,
+			want: `a: &a
+  b: 1
+c:
+  <<: *a
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ParseMapping([]byte(tt.content))
+			require.NoError(t, err)
+
+			got, err := doc.Encode()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	_, err := ParseMapping([]byte("- a\n"))
+	require.EqualError(t, err, "config should be a YAML map")
+
+	_, err = ParseMapping([]byte("a: [\n"))
+	require.ErrorContains(t, err, "failed to parse config")
+}
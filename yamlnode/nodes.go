@@ -0,0 +1,93 @@
+// Package yamlnode contains helpers for editing YAML documents as yaml.v3 node trees,
+// which keeps the comments, anchors and key order of the edited document.
+package yamlnode
+
+import "gopkg.in/yaml.v3"
+
+// MappingValue returns the value node of the given key in a mapping node and the index of the key node
+// in the mapping's content, or nil and -1 if the key is not present.
+func MappingValue(mapping *yaml.Node, key string) (*yaml.Node, int) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil, -1
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1], i
+		}
+	}
+	return nil, -1
+}
+
+// RemoveMappingKey removes the key at the given index (as returned by MappingValue) and its value.
+func RemoveMappingKey(mapping *yaml.Node, idx int) {
+	mapping.Content = append(mapping.Content[:idx], mapping.Content[idx+2:]...)
+}
+
+// PrependMappingValue replaces the value of the given key, or adds the key to the beginning of the mapping.
+func PrependMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	if _, idx := MappingValue(mapping, key); idx >= 0 {
+		mapping.Content[idx+1] = value
+		return
+	}
+	mapping.Content = append([]*yaml.Node{String(key), value}, mapping.Content...)
+}
+
+// String returns a plain string scalar node.
+func String(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// QuotedString returns a double-quoted string scalar node.
+func QuotedString(value string) *yaml.Node {
+	node := String(value)
+	node.Style = yaml.DoubleQuotedStyle
+	return node
+}
+
+// StringSequence returns a sequence node of plain string scalars.
+func StringSequence(values []string) *yaml.Node {
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, value := range values {
+		seq.Content = append(seq.Content, String(value))
+	}
+	return seq
+}
+
+// IsNull returns true if the node is missing or is a null scalar.
+func IsNull(node *yaml.Node) bool {
+	return node == nil || (node.Kind == yaml.ScalarNode && node.Tag == "!!null")
+}
+
+// BlockMapping returns the given step or item value as a block style mapping node,
+// a missing value (for example `- script@1:`) is replaced by an empty mapping.
+func BlockMapping(node *yaml.Node) *yaml.Node {
+	if IsNull(node) {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", LineComment: lineComment(node)}
+	}
+	if node.Kind == yaml.MappingNode {
+		node.Style &^= yaml.FlowStyle
+	}
+	return node
+}
+
+// JoinComments joins the non-empty comments with new lines.
+func JoinComments(comments ...string) string {
+	joined := ""
+	for _, comment := range comments {
+		if comment == "" {
+			continue
+		}
+		if joined != "" {
+			joined += "\n"
+		}
+		joined += comment
+	}
+	return joined
+}
+
+func lineComment(node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	return node.LineComment
+}